@@ -0,0 +1,315 @@
+package aes
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+// FormatEnvelope1 is the ciphertext format which carries the wrapped data key:
+// version (1 byte) || wrapped key length (2 bytes) || wrapped key || nonce || sealed data.
+const FormatEnvelope1 byte = 2
+
+const wrappedKeyLengthSize = 2
+
+var (
+	_ banking.SecretFactory   = (*EnvelopeSecretFactory)(nil)
+	_ banking.HealthChecker   = (*EnvelopeSecretFactory)(nil)
+	_ banking.SecretRewrapper = (*EnvelopeSecretFactory)(nil)
+
+	// ErrNotEnvelope is the error that will be raised when ciphertext has no envelope header.
+	ErrNotEnvelope = errors.New("not an envelope")
+)
+
+// envelope represents the parsed ciphertext in FormatEnvelope1 format.
+type envelope struct {
+	wrappedKey []byte
+	data       []byte
+}
+
+func (e envelope) encode() string {
+	bb := make([]byte, 1+wrappedKeyLengthSize, 1+wrappedKeyLengthSize+len(e.wrappedKey)+len(e.data))
+
+	bb[0] = FormatEnvelope1
+	binary.BigEndian.PutUint16(bb[1:], uint16(len(e.wrappedKey)))
+
+	return hex.EncodeToString(append(append(bb, e.wrappedKey...), e.data...))
+}
+
+func decodeEnvelope(encrypted string) (envelope, error) {
+	bb, err := hex.DecodeString(encrypted)
+	if err != nil {
+		return envelope{}, errors.Wrap(err, "decode envelope")
+	}
+
+	if len(bb) < 1+wrappedKeyLengthSize || bb[0] != FormatEnvelope1 {
+		return envelope{}, errors.Wrap(ErrNotEnvelope, "decode envelope")
+	}
+
+	bb = bb[1:]
+
+	size := int(binary.BigEndian.Uint16(bb))
+	if bb = bb[wrappedKeyLengthSize:]; len(bb) < size {
+		return envelope{}, errors.Wrap(ErrNotEnvelope, "decode envelope")
+	}
+
+	return envelope{
+		wrappedKey: bb[:size],
+		data:       bb[size:],
+	}, nil
+}
+
+// EnvelopeSecretFactory represents a service initialize SecretString object by envelope encryption: every secret is
+// encrypted by its own data key, and data key is wrapped by the key management service. Leaked data key exposes the
+// single secret only, and key encryption key is rotated without re-encrypting data.
+type EnvelopeSecretFactory struct {
+	nonceGenerator NonceGenerator
+	kms            banking.KeyManagementService
+
+	legacy *SecretFactory
+}
+
+// NewEnvelopeSecretFactory returns a new EnvelopeSecretFactory instance.
+func NewEnvelopeSecretFactory(
+	nonceGenerator NonceGenerator,
+	kms banking.KeyManagementService,
+	opts ...EnvelopeSecretFactoryOption,
+) *EnvelopeSecretFactory {
+	f := &EnvelopeSecretFactory{
+		nonceGenerator: nonceGenerator,
+		kms:            kms,
+
+		legacy: nil,
+	}
+
+	for _, opt := range opts {
+		opt.apply(f)
+	}
+
+	return f
+}
+
+// CreateFromEncryptedData creates SecretString object from encrypted data.
+func (f *EnvelopeSecretFactory) CreateFromEncryptedData(
+	ctx context.Context,
+	r io.Reader,
+) (
+	banking.SecretString,
+	error,
+) {
+	return f.CreateBoundFromEncryptedData(ctx, r, banking.AssociatedData{})
+}
+
+// CreateFromDecryptedData creates SecretString object from decrypted data. Data is encrypted by a new data key.
+func (f *EnvelopeSecretFactory) CreateFromDecryptedData(
+	ctx context.Context,
+	r io.Reader,
+) (
+	banking.SecretString,
+	error,
+) {
+	return f.CreateBoundFromDecryptedData(ctx, r, banking.AssociatedData{})
+}
+
+// CreateBoundFromEncryptedData creates SecretString object from encrypted data which was bound to the associated
+// data.
+func (f *EnvelopeSecretFactory) CreateBoundFromEncryptedData(
+	ctx context.Context,
+	r io.Reader,
+	ad banking.AssociatedData,
+) (
+	banking.SecretString,
+	error,
+) {
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, errors.Wrap(err, "create from encrypted string")
+	}
+
+	env, err := decodeEnvelope(buf.String())
+	if errors.Is(err, ErrNotEnvelope) && f.legacy != nil {
+		secret, err := f.legacy.CreateBoundFromEncryptedData(ctx, buf, ad)
+		if err != nil {
+			return nil, errors.Wrap(err, "create from encrypted string")
+		}
+
+		return secret, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "create from encrypted string")
+	}
+
+	plaintext, err := f.open(ctx, env, ad.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "create from encrypted string")
+	}
+
+	return &SecretString{
+		encryptedString: buf.String(),
+		decryptedString: bytes.NewBuffer(plaintext).String(),
+	}, nil
+}
+
+// CreateBoundFromDecryptedData creates SecretString object from decrypted data and binds encrypted data to the
+// associated data. Data is encrypted by a new data key.
+func (f *EnvelopeSecretFactory) CreateBoundFromDecryptedData(
+	ctx context.Context,
+	r io.Reader,
+	ad banking.AssociatedData,
+) (
+	banking.SecretString,
+	error,
+) {
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, errors.Wrap(err, "create from decrypted data")
+	}
+
+	dataKey, err := f.kms.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "create from decrypted data")
+	}
+
+	aead, err := newAEAD(dataKey.Plaintext)
+	if err != nil {
+		return nil, errors.Wrap(err, "create from decrypted data")
+	}
+
+	nonce, err := f.nonceGenerator.GenerateNonce(ctx, aead.NonceSize())
+	if err != nil {
+		return nil, errors.Wrap(err, "create from decrypted data")
+	}
+
+	env := envelope{
+		wrappedKey: dataKey.Wrapped,
+		data:       aead.Seal(nonce, nonce, buf.Bytes(), ad.Bytes()),
+	}
+
+	return &SecretString{
+		encryptedString: env.encode(),
+		decryptedString: buf.String(),
+	}, nil
+}
+
+// NeedsRewrap returns true if data key was not wrapped by the current key encryption key or if data was encrypted
+// by the legacy factory.
+func (f *EnvelopeSecretFactory) NeedsRewrap(encrypted string) bool {
+	env, err := decodeEnvelope(encrypted)
+	if err != nil {
+		return errors.Is(err, ErrNotEnvelope) && f.legacy != nil
+	}
+
+	return f.kms.NeedsRewrap(env.wrappedKey)
+}
+
+// Rewrap wraps data key by the current key encryption key, data itself is not re-encrypted, so associated data is
+// not checked. Data which was encrypted by the legacy factory is moved into the envelope and stays bound to the same
+// associated data.
+func (f *EnvelopeSecretFactory) Rewrap(
+	ctx context.Context,
+	encrypted string,
+	ad banking.AssociatedData,
+) (
+	string,
+	error,
+) {
+	env, err := decodeEnvelope(encrypted)
+	if errors.Is(err, ErrNotEnvelope) && f.legacy != nil {
+		return f.migrate(ctx, encrypted, ad)
+	}
+
+	if err != nil {
+		return "", errors.Wrap(err, "rewrap")
+	}
+
+	if env.wrappedKey, err = f.kms.RewrapDataKey(ctx, env.wrappedKey); err != nil {
+		return "", errors.Wrap(err, "rewrap")
+	}
+
+	return env.encode(), nil
+}
+
+// CheckHealth encrypts the probe and decrypts it back, so unavailable key management service is detected.
+func (f *EnvelopeSecretFactory) CheckHealth(ctx context.Context) error {
+	encrypted, err := f.CreateFromDecryptedData(ctx, bytes.NewBufferString(healthCheckProbe))
+	if err != nil {
+		return errors.Wrap(err, "check secret factory health")
+	}
+
+	decrypted, err := f.CreateFromEncryptedData(ctx, bytes.NewBufferString(encrypted.EncryptedString()))
+	if err != nil {
+		return errors.Wrap(err, "check secret factory health")
+	}
+
+	if decrypted.DecryptedString() != healthCheckProbe {
+		return errors.Wrap(ErrSelfTestFailed, "check secret factory health")
+	}
+
+	return nil
+}
+
+func (f *EnvelopeSecretFactory) open(ctx context.Context, env envelope, additionalData []byte) ([]byte, error) {
+	key, err := f.kms.DecryptDataKey(ctx, env.wrappedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "open")
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "open")
+	}
+
+	plaintext, err := openAEAD(aead, env.data, additionalData)
+	if err != nil {
+		return nil, errors.Wrap(err, "open")
+	}
+
+	return plaintext, nil
+}
+
+func (f *EnvelopeSecretFactory) migrate(
+	ctx context.Context,
+	encrypted string,
+	ad banking.AssociatedData,
+) (
+	string,
+	error,
+) {
+	decrypted, err := f.legacy.CreateBoundFromEncryptedData(ctx, bytes.NewBufferString(encrypted), ad)
+	if err != nil {
+		return "", errors.Wrap(err, "migrate")
+	}
+
+	migrated, err := f.CreateBoundFromDecryptedData(ctx, bytes.NewBufferString(decrypted.DecryptedString()), ad)
+	if err != nil {
+		return "", errors.Wrap(err, "migrate")
+	}
+
+	return migrated.EncryptedString(), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) < CipherKeyLength {
+		return nil, errors.Wrap(ErrWrongKeyLength, "new aead")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "new aead")
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "new aead")
+	}
+
+	return aead, nil
+}
@@ -0,0 +1,20 @@
+package aes
+
+// EnvelopeSecretFactoryOption represents an option for configure EnvelopeSecretFactory instance.
+type EnvelopeSecretFactoryOption interface {
+	apply(f *EnvelopeSecretFactory)
+}
+
+type envelopeSecretFactoryOptionFunc func(f *EnvelopeSecretFactory)
+
+func (fn envelopeSecretFactoryOptionFunc) apply(f *EnvelopeSecretFactory) {
+	fn(f)
+}
+
+// WithLegacySecretFactory sets up the factory which decrypts secrets that were encrypted before envelope encryption
+// was enabled. Such secrets are moved into envelope by the re-encryption job.
+func WithLegacySecretFactory(legacy *SecretFactory) EnvelopeSecretFactoryOption {
+	return envelopeSecretFactoryOptionFunc(func(f *EnvelopeSecretFactory) {
+		f.legacy = legacy
+	})
+}
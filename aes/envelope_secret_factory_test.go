@@ -0,0 +1,131 @@
+package aes_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/aes"
+	"github.com/morozovcookie/agat-banking/aes/rand"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEnvelopeSecretFactory(
+	t *testing.T,
+	opts []aes.EnvelopeSecretFactoryOption,
+	primary string,
+	retiring ...string,
+) *aes.EnvelopeSecretFactory {
+	t.Helper()
+
+	keys := make([][]byte, 0, len(retiring))
+	for _, key := range retiring {
+		keys = append(keys, mustDecodeKey(t, key))
+	}
+
+	keyring, err := aes.NewKeyring(mustDecodeKey(t, primary), keys...)
+	require.NoError(t, err)
+
+	kms := aes.NewLocalKeyManagementService(rand.NewNonceGenerator(), keyring)
+
+	return aes.NewEnvelopeSecretFactory(rand.NewNonceGenerator(), kms, opts...)
+}
+
+func TestEnvelopeSecretFactory_CreateFromEncryptedData(t *testing.T) {
+	ctx := context.Background()
+
+	factory := newEnvelopeSecretFactory(t, nil, oldKey)
+
+	secret, err := factory.CreateFromDecryptedData(ctx, bytes.NewBufferString("super-secret-string"))
+	require.NoError(t, err)
+
+	other, err := factory.CreateFromDecryptedData(ctx, bytes.NewBufferString("super-secret-string"))
+	require.NoError(t, err)
+
+	// Every secret is encrypted by its own data key.
+	assert.NotEqual(t, secret.EncryptedString(), other.EncryptedString())
+
+	decrypted, err := factory.CreateFromEncryptedData(ctx, bytes.NewBufferString(secret.EncryptedString()))
+	require.NoError(t, err)
+	assert.Equal(t, "super-secret-string", decrypted.DecryptedString())
+
+	// Data key could not be unwrapped without key encryption key.
+	_, err = newEnvelopeSecretFactory(t, nil, newKey).
+		CreateFromEncryptedData(ctx, bytes.NewBufferString(secret.EncryptedString()))
+	assert.True(t, errors.Is(err, aes.ErrUnknownKeyID))
+
+	// Secret which is not an envelope could not be decrypted without legacy factory.
+	_, err = factory.CreateFromEncryptedData(ctx, bytes.NewBufferString("616767336b7376746c616d376276c58836970ec0fd"+
+		"86c63d74233cfcea0f36a3dd83247363b1b850152b867e1b51c7"))
+	assert.True(t, errors.Is(err, aes.ErrNotEnvelope))
+}
+
+func TestEnvelopeSecretFactory_Rewrap(t *testing.T) {
+	ctx := context.Background()
+
+	secret, err := newEnvelopeSecretFactory(t, nil, oldKey).
+		CreateFromDecryptedData(ctx, bytes.NewBufferString("super-secret-string"))
+	require.NoError(t, err)
+
+	rotated := newEnvelopeSecretFactory(t, nil, newKey, oldKey)
+	assert.True(t, rotated.NeedsRewrap(secret.EncryptedString()))
+
+	rewrapped, err := rotated.Rewrap(ctx, secret.EncryptedString(), banking.AssociatedData{})
+	require.NoError(t, err)
+	assert.False(t, rotated.NeedsRewrap(rewrapped))
+
+	// Data itself is not re-encrypted, only the data key is wrapped again. Data is hex(nonce || sealed data || tag).
+	size := 2 * (12 + len("super-secret-string") + 16)
+	assert.Equal(t, secret.EncryptedString()[len(secret.EncryptedString())-size:], rewrapped[len(rewrapped)-size:])
+
+	decrypted, err := newEnvelopeSecretFactory(t, nil, newKey).
+		CreateFromEncryptedData(ctx, bytes.NewBufferString(rewrapped))
+	require.NoError(t, err)
+	assert.Equal(t, "super-secret-string", decrypted.DecryptedString())
+}
+
+func TestEnvelopeSecretFactory_Migrate(t *testing.T) {
+	ctx := context.Background()
+
+	legacy, err := aes.NewSecretFactory(nil, bytes.NewBufferString(oldKey))
+	require.NoError(t, err)
+
+	factory := newEnvelopeSecretFactory(t, []aes.EnvelopeSecretFactoryOption{aes.WithLegacySecretFactory(legacy)},
+		newKey)
+
+	encrypted := "019469a03b616767336b7376746c616d376276c58836970ec0fd86c63d74233cfcea0f36a3dd83247363b1b850152b867e1" +
+		"b51c7"
+
+	decrypted, err := factory.CreateFromEncryptedData(ctx, bytes.NewBufferString(encrypted))
+	require.NoError(t, err)
+	assert.Equal(t, "super-secret-string", decrypted.DecryptedString())
+
+	assert.True(t, factory.NeedsRewrap(encrypted))
+
+	migrated, err := factory.Rewrap(ctx, encrypted, banking.AssociatedData{})
+	require.NoError(t, err)
+	assert.False(t, factory.NeedsRewrap(migrated))
+
+	decrypted, err = newEnvelopeSecretFactory(t, nil, newKey).
+		CreateFromEncryptedData(ctx, bytes.NewBufferString(migrated))
+	require.NoError(t, err)
+	assert.Equal(t, "super-secret-string", decrypted.DecryptedString())
+}
+
+func TestDeriveKey(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+
+	key, err := aes.DeriveKey([]byte("passphrase"), salt)
+	require.NoError(t, err)
+	assert.Len(t, key, aes.CipherKeyLength)
+
+	same, err := aes.DeriveKey([]byte("passphrase"), salt)
+	require.NoError(t, err)
+	assert.Equal(t, key, same)
+
+	_, err = aes.DeriveKey([]byte("passphrase"), salt[:8])
+	assert.True(t, errors.Is(err, aes.ErrWrongSaltLength))
+}
@@ -0,0 +1,109 @@
+package aes
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// MinSaltLength is the minimal length of salt which key is derived from passphrase with.
+	MinSaltLength = 16
+
+	passphraseKeyTime    = 3
+	passphraseKeyMemory  = 64 * 1024
+	passphraseKeyThreads = 4
+)
+
+var (
+	_ banking.KeyManagementService = (*LocalKeyManagementService)(nil)
+
+	// ErrWrongSaltLength is the error that will be raised when length of salt is less than 16 bytes.
+	ErrWrongSaltLength = errors.New("wrong salt length")
+)
+
+// LocalKeyManagementService represents a key management service which key encryption keys are stored in the local
+// keyring. Key encryption key is rotated as well as key of SecretFactory: the new key becomes the primary one and the
+// previous key stays as retiring one until every data key is rewrapped.
+type LocalKeyManagementService struct {
+	nonceGenerator NonceGenerator
+	keyring        *Keyring
+}
+
+// NewLocalKeyManagementService returns a new LocalKeyManagementService instance. Nonce generator is used for
+// generating data keys as well, so it must be backed by cryptographically secure random source.
+func NewLocalKeyManagementService(nonceGenerator NonceGenerator, keyring *Keyring) *LocalKeyManagementService {
+	return &LocalKeyManagementService{
+		nonceGenerator: nonceGenerator,
+		keyring:        keyring,
+	}
+}
+
+// GenerateDataKey returns a new data encryption key wrapped by the primary key.
+func (svc *LocalKeyManagementService) GenerateDataKey(ctx context.Context) (*banking.DataKey, error) {
+	plaintext, err := svc.nonceGenerator.GenerateNonce(ctx, CipherKeyLength)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate data key")
+	}
+
+	wrapped, err := svc.wrap(ctx, plaintext)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate data key")
+	}
+
+	return &banking.DataKey{
+		Plaintext: plaintext,
+		Wrapped:   wrapped,
+	}, nil
+}
+
+// DecryptDataKey unwraps the data encryption key by any key of the keyring.
+func (svc *LocalKeyManagementService) DecryptDataKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	plaintext, _, err := svc.keyring.open(wrapped, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypt data key")
+	}
+
+	return plaintext, nil
+}
+
+// RewrapDataKey wraps the data encryption key by the primary key.
+func (svc *LocalKeyManagementService) RewrapDataKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	plaintext, err := svc.DecryptDataKey(ctx, wrapped)
+	if err != nil {
+		return nil, errors.Wrap(err, "rewrap data key")
+	}
+
+	if wrapped, err = svc.wrap(ctx, plaintext); err != nil {
+		return nil, errors.Wrap(err, "rewrap data key")
+	}
+
+	return wrapped, nil
+}
+
+// NeedsRewrap returns true if the data encryption key was not wrapped by the primary key.
+func (svc *LocalKeyManagementService) NeedsRewrap(wrapped []byte) bool {
+	return !svc.keyring.isPrimary(wrapped)
+}
+
+func (svc *LocalKeyManagementService) wrap(ctx context.Context, plaintext []byte) ([]byte, error) {
+	nonce, err := svc.nonceGenerator.GenerateNonce(ctx, svc.keyring.nonceSize())
+	if err != nil {
+		return nil, errors.Wrap(err, "wrap")
+	}
+
+	return svc.keyring.seal(nonce, plaintext, nil), nil
+}
+
+// DeriveKey derives the key encryption key from passphrase by Argon2id, so keyring could be built without storing
+// key files. Salt is not secret, but it must be random and must be kept along with the encrypted data.
+func DeriveKey(passphrase, salt []byte) ([]byte, error) {
+	if len(salt) < MinSaltLength {
+		return nil, errors.Wrap(ErrWrongSaltLength, "derive key")
+	}
+
+	return argon2.IDKey(passphrase, salt, passphraseKeyTime, passphraseKeyMemory, passphraseKeyThreads,
+		CipherKeyLength), nil
+}
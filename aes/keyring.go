@@ -0,0 +1,226 @@
+package aes
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FormatVersion1 is the ciphertext format which carries the identifier of the key it was encrypted with:
+	// version (1 byte) || key id (4 bytes) || nonce || sealed data.
+	FormatVersion1 byte = 1
+
+	keyIDLength  = 4
+	headerLength = 1 + keyIDLength
+)
+
+var (
+	// ErrDuplicateKeyID will be raised when two keys of the same keyring have the same identifier.
+	ErrDuplicateKeyID = errors.New("duplicate key id")
+
+	// ErrUnknownKeyID will be raised when ciphertext was encrypted by key which does not belong to the keyring.
+	ErrUnknownKeyID = errors.New("unknown key id")
+
+	// ErrDecryptionFailed will be raised when ciphertext could not be decrypted by any key of the keyring.
+	ErrDecryptionFailed = errors.New("decryption failed")
+)
+
+// KeyID represents the key identifier which is stamped into ciphertext header. It is derived from the key itself, so
+// the same key has the same identifier on every replica without any additional configuration.
+type KeyID uint32
+
+func (id KeyID) String() string {
+	return fmt.Sprintf("%08x", uint32(id))
+}
+
+// keyIDOf returns the first 4 bytes of the key SHA-256 digest.
+func keyIDOf(key []byte) KeyID {
+	sum := sha256.Sum256(key)
+
+	return KeyID(binary.BigEndian.Uint32(sum[:keyIDLength]))
+}
+
+// Keyring represents a set of keys for encrypting and decrypting secrets.
+//
+// Secrets are encrypted only by the primary key. Retiring keys are used only for decrypting, so a key could be
+// rotated online: the previous primary key should stay in the keyring as retiring one until every stored secret is
+// re-encrypted by the new primary key.
+type Keyring struct {
+	primary KeyID
+
+	// ids keeps keys order, so the primary key is tried first when ciphertext has no header.
+	ids  []KeyID
+	keys map[KeyID]cipher.AEAD
+}
+
+// NewKeyring returns a new Keyring instance. Every key must be at least CipherKeyLength bytes long.
+func NewKeyring(primary []byte, retiring ...[]byte) (*Keyring, error) {
+	kr := &Keyring{
+		primary: keyIDOf(primary),
+		ids:     make([]KeyID, 0, len(retiring)+1),
+		keys:    make(map[KeyID]cipher.AEAD, len(retiring)+1),
+	}
+
+	for _, key := range append([][]byte{primary}, retiring...) {
+		if err := kr.add(key); err != nil {
+			return nil, errors.Wrap(err, "init Keyring")
+		}
+	}
+
+	return kr, nil
+}
+
+func (kr *Keyring) add(key []byte) error {
+	id := keyIDOf(key)
+	if _, ok := kr.keys[id]; ok {
+		return errors.Wrapf(ErrDuplicateKeyID, "add key %s", id)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return errors.Wrap(err, "add key")
+	}
+
+	kr.ids, kr.keys[id] = append(kr.ids, id), aead
+
+	return nil
+}
+
+// PrimaryKeyID returns the identifier of key which new secrets are encrypted with.
+func (kr *Keyring) PrimaryKeyID() KeyID {
+	return kr.primary
+}
+
+// KeyIDs returns identifiers of every key, the primary one goes first.
+func (kr *Keyring) KeyIDs() []KeyID {
+	return append([]KeyID(nil), kr.ids...)
+}
+
+func (kr *Keyring) nonceSize() int {
+	return kr.keys[kr.primary].NonceSize()
+}
+
+// seal encrypts plaintext by the primary key and prepends the header. Additional data is authenticated, but not
+// stored.
+func (kr *Keyring) seal(nonce, plaintext, additionalData []byte) []byte {
+	dst := make([]byte, headerLength, headerLength+len(nonce)+len(plaintext)+kr.keys[kr.primary].Overhead())
+
+	dst[0] = FormatVersion1
+	binary.BigEndian.PutUint32(dst[1:headerLength], uint32(kr.primary))
+
+	dst = append(dst, nonce...)
+
+	return kr.keys[kr.primary].Seal(dst, nonce, plaintext, additionalData)
+}
+
+// open decrypts ciphertext and returns identifier of key which it was encrypted with. Ciphertext which was produced
+// before keyring was introduced has no header, so every key is tried for it.
+func (kr *Keyring) open(ciphertext, additionalData []byte) ([]byte, KeyID, error) {
+	if id, ok := parseHeader(ciphertext); ok {
+		if aead, ok := kr.keys[id]; ok {
+			if plaintext, err := openAEAD(aead, ciphertext[headerLength:], additionalData); err == nil {
+				return plaintext, id, nil
+			}
+		}
+	}
+
+	for _, id := range kr.ids {
+		if plaintext, err := openAEAD(kr.keys[id], ciphertext, additionalData); err == nil {
+			return plaintext, id, nil
+		}
+	}
+
+	if id, ok := parseHeader(ciphertext); ok {
+		if _, ok = kr.keys[id]; !ok {
+			return nil, 0, errors.Wrapf(ErrUnknownKeyID, "open %s", id)
+		}
+	}
+
+	return nil, 0, errors.Wrap(ErrDecryptionFailed, "open")
+}
+
+// isPrimary returns true if ciphertext has header with the primary key identifier.
+func (kr *Keyring) isPrimary(ciphertext []byte) bool {
+	id, ok := parseHeader(ciphertext)
+
+	return ok && id == kr.primary
+}
+
+func parseHeader(ciphertext []byte) (KeyID, bool) {
+	if len(ciphertext) < headerLength || ciphertext[0] != FormatVersion1 {
+		return 0, false
+	}
+
+	return KeyID(binary.BigEndian.Uint32(ciphertext[1:headerLength])), true
+}
+
+func openAEAD(aead cipher.AEAD, data, additionalData []byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, ErrWrongCipherTextLength
+	}
+
+	return aead.Open(nil, data[:nonceSize], data[nonceSize:], additionalData) // nolint:wrapcheck
+}
+
+// LoadKey reads hex encoded key.
+func LoadKey(r io.Reader) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, errors.Wrap(err, "load key")
+	}
+
+	key, err := hex.DecodeString(string(bytes.TrimSpace(buf.Bytes())))
+	if err != nil {
+		return nil, errors.Wrap(err, "load key")
+	}
+
+	return key, nil
+}
+
+// LoadKeyFile reads hex encoded key from file.
+func LoadKeyFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "load key file")
+	}
+
+	defer file.Close()
+
+	key, err := LoadKey(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load key file %s", path)
+	}
+
+	return key, nil
+}
+
+// LoadKeyringFiles reads the primary and retiring keys from files and returns a new Keyring instance.
+func LoadKeyringFiles(primary string, retiring ...string) (*Keyring, error) {
+	keys := make([][]byte, 0, len(retiring)+1)
+
+	for _, path := range append([]string{primary}, retiring...) {
+		key, err := LoadKeyFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "load keyring files")
+		}
+
+		keys = append(keys, key)
+	}
+
+	kr, err := NewKeyring(keys[0], keys[1:]...)
+	if err != nil {
+		return nil, errors.Wrap(err, "load keyring files")
+	}
+
+	return kr, nil
+}
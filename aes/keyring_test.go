@@ -0,0 +1,90 @@
+package aes_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/aes"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	oldKey = "a7681ff138d941377c55aefb4ab667b833a823e582c91317f5b5e33c09e6891e"
+	newKey = "0f7f3fc9a2bd1f52a0fc0d7ce5d0a6cbd6e0d9d4f4ca3c86a8b6a1f8b2e7c410"
+)
+
+func newNonceGenerator() *mock.NonceGenerator {
+	generator := &mock.NonceGenerator{}
+
+	generator.On("GenerateNonce", 12).
+		Return([]byte{'a', 'g', 'g', '3', 'k', 's', 'v', 't', 'l', 'a', 'm', '7'}, (error)(nil))
+
+	return generator
+}
+
+func mustDecodeKey(t *testing.T, key string) []byte {
+	t.Helper()
+
+	bb, err := hex.DecodeString(key)
+	require.NoError(t, err)
+
+	return bb
+}
+
+func TestNewKeyring(t *testing.T) {
+	_, err := aes.NewKeyring(mustDecodeKey(t, oldKey), mustDecodeKey(t, oldKey))
+	assert.True(t, errors.Is(err, aes.ErrDuplicateKeyID))
+
+	_, err = aes.NewKeyring(mustDecodeKey(t, oldKey)[:16])
+	assert.True(t, errors.Is(err, aes.ErrWrongKeyLength))
+
+	keyring, err := aes.NewKeyring(mustDecodeKey(t, newKey), mustDecodeKey(t, oldKey))
+	require.NoError(t, err)
+
+	assert.Len(t, keyring.KeyIDs(), 2)
+	assert.Equal(t, keyring.PrimaryKeyID(), keyring.KeyIDs()[0])
+}
+
+func TestSecretFactory_Rewrap(t *testing.T) {
+	ctx := context.Background()
+
+	oldKeyring, err := aes.NewKeyring(mustDecodeKey(t, oldKey))
+	require.NoError(t, err)
+
+	rotatedKeyring, err := aes.NewKeyring(mustDecodeKey(t, newKey), mustDecodeKey(t, oldKey))
+	require.NoError(t, err)
+
+	var (
+		oldFactory     = aes.NewKeyringSecretFactory(newNonceGenerator(), oldKeyring)
+		rotatedFactory = aes.NewKeyringSecretFactory(newNonceGenerator(), rotatedKeyring)
+	)
+
+	secret, err := oldFactory.CreateFromDecryptedData(ctx, bytes.NewBufferString("super-secret-string"))
+	require.NoError(t, err)
+
+	// Secret which was encrypted before rotation is still readable by the retiring key.
+	decrypted, err := rotatedFactory.CreateFromEncryptedData(ctx, bytes.NewBufferString(secret.EncryptedString()))
+	require.NoError(t, err)
+	assert.Equal(t, "super-secret-string", decrypted.DecryptedString())
+
+	assert.False(t, oldFactory.NeedsRewrap(secret.EncryptedString()))
+	assert.True(t, rotatedFactory.NeedsRewrap(secret.EncryptedString()))
+
+	rewrapped, err := rotatedFactory.Rewrap(ctx, secret.EncryptedString(), banking.AssociatedData{})
+	require.NoError(t, err)
+	assert.False(t, rotatedFactory.NeedsRewrap(rewrapped))
+
+	// Secret which was rewrapped by the new primary key could not be read without it.
+	_, err = oldFactory.CreateFromEncryptedData(ctx, bytes.NewBufferString(rewrapped))
+	assert.True(t, errors.Is(err, aes.ErrUnknownKeyID))
+
+	// Secret which was encrypted before versioned header was introduced has to be rewrapped too.
+	assert.True(t, oldFactory.NeedsRewrap("616767336b7376746c616d376276c58836970ec0fd86c63d74233cfcea0f36a3dd83247363b1b85"+
+		"0152b867e1b51c7"))
+}
@@ -3,8 +3,6 @@ package aes
 import (
 	"bytes"
 	"context"
-	"crypto/aes"
-	"crypto/cipher"
 	"encoding/hex"
 	"io"
 
@@ -15,8 +13,16 @@ import (
 // CipherKeyLength is the required length of cipher key.
 const CipherKeyLength = 32
 
+// healthCheckProbe is the plaintext which is encrypted and decrypted during health check.
+const healthCheckProbe = "health-check"
+
 var (
-	_ banking.SecretFactory = (*SecretFactory)(nil)
+	_ banking.SecretFactory   = (*SecretFactory)(nil)
+	_ banking.HealthChecker   = (*SecretFactory)(nil)
+	_ banking.SecretRewrapper = (*SecretFactory)(nil)
+
+	// ErrSelfTestFailed is the error that will be raised when decrypted probe does not match the original one.
+	ErrSelfTestFailed = errors.New("self-test failed")
 
 	// ErrWrongCipherTextLength is the error that will be raised when length of ciphertext is less than size of nonce.
 	ErrWrongCipherTextLength = errors.New("wrong ciphertext length")
@@ -28,45 +34,54 @@ var (
 // SecretFactory represents a service initialize SecretString object.
 type SecretFactory struct {
 	nonceGenerator NonceGenerator
-	aead           cipher.AEAD
+	keyring        *Keyring
 }
 
-// NewSecretFactory returns a new SecretStringFactory instance.
+// NewSecretFactory returns a new SecretStringFactory instance which keyring consists of the single key.
 func NewSecretFactory(nonceGenerator NonceGenerator, key io.Reader) (*SecretFactory, error) {
-	buf := bytes.NewBuffer(nil)
-
-	if _, err := buf.ReadFrom(key); err != nil {
-		return nil, errors.Wrap(err, "init secret string factory")
-	}
-
-	bb, err := hex.DecodeString(buf.String())
+	bb, err := LoadKey(key)
 	if err != nil {
 		return nil, errors.Wrap(err, "init secret string factory")
 	}
 
-	if len(bb) < CipherKeyLength {
-		return nil, errors.Wrap(ErrWrongKeyLength, "init secret string factory")
-	}
-
-	block, err := aes.NewCipher(bb)
+	keyring, err := NewKeyring(bb)
 	if err != nil {
 		return nil, errors.Wrap(err, "init secret string factory")
 	}
 
-	f := &SecretFactory{
+	return NewKeyringSecretFactory(nonceGenerator, keyring), nil
+}
+
+// NewKeyringSecretFactory returns a new SecretStringFactory instance which encrypts secrets by the keyring primary key
+// and decrypts them by any key of the keyring.
+func NewKeyringSecretFactory(nonceGenerator NonceGenerator, keyring *Keyring) *SecretFactory {
+	return &SecretFactory{
 		nonceGenerator: nonceGenerator,
-		aead:           nil,
+		keyring:        keyring,
 	}
+}
 
-	if f.aead, err = cipher.NewGCM(block); err != nil {
-		return nil, errors.Wrap(err, "init secret string factory")
-	}
+// CreateFromEncryptedData creates SecretString object from encrypted data.
+func (f *SecretFactory) CreateFromEncryptedData(ctx context.Context, r io.Reader) (banking.SecretString, error) {
+	return f.CreateBoundFromEncryptedData(ctx, r, banking.AssociatedData{})
+}
 
-	return f, nil
+// CreateFromDecryptedData creates SecretString object from decrypted data. Data is always encrypted by the primary
+// key.
+func (f *SecretFactory) CreateFromDecryptedData(ctx context.Context, r io.Reader) (banking.SecretString, error) {
+	return f.CreateBoundFromDecryptedData(ctx, r, banking.AssociatedData{})
 }
 
-// CreateFromEncryptedData creates SecretString object from encrypted data.
-func (f *SecretFactory) CreateFromEncryptedData(_ context.Context, r io.Reader) (banking.SecretString, error) {
+// CreateBoundFromEncryptedData creates SecretString object from encrypted data which was bound to the associated
+// data.
+func (f *SecretFactory) CreateBoundFromEncryptedData(
+	_ context.Context,
+	r io.Reader,
+	ad banking.AssociatedData,
+) (
+	banking.SecretString,
+	error,
+) {
 	buf := new(bytes.Buffer)
 	if _, err := buf.ReadFrom(r); err != nil {
 		return nil, errors.Wrap(err, "create from encrypted string")
@@ -77,14 +92,7 @@ func (f *SecretFactory) CreateFromEncryptedData(_ context.Context, r io.Reader)
 		return nil, errors.Wrap(err, "create from encrypted string")
 	}
 
-	nonceSize := f.aead.NonceSize()
-	if len(bb) < nonceSize {
-		return nil, errors.Wrap(ErrWrongCipherTextLength, "create from encrypted string")
-	}
-
-	nonce, ciphertext := bb[:nonceSize], bb[nonceSize:]
-
-	plaintext, err := f.aead.Open(nil, nonce, ciphertext, nil)
+	plaintext, _, err := f.keyring.open(bb, ad.Bytes())
 	if err != nil {
 		return nil, errors.Wrap(err, "create from encrypted string")
 	}
@@ -95,9 +103,17 @@ func (f *SecretFactory) CreateFromEncryptedData(_ context.Context, r io.Reader)
 	}, nil
 }
 
-// CreateFromDecryptedData creates SecretString object from decrypted data.
-func (f *SecretFactory) CreateFromDecryptedData(ctx context.Context, r io.Reader) (banking.SecretString, error) {
-	nonce, err := f.nonceGenerator.GenerateNonce(ctx, f.aead.NonceSize())
+// CreateBoundFromDecryptedData creates SecretString object from decrypted data and binds encrypted data to the
+// associated data. Data is always encrypted by the primary key.
+func (f *SecretFactory) CreateBoundFromDecryptedData(
+	ctx context.Context,
+	r io.Reader,
+	ad banking.AssociatedData,
+) (
+	banking.SecretString,
+	error,
+) {
+	nonce, err := f.nonceGenerator.GenerateNonce(ctx, f.keyring.nonceSize())
 	if err != nil {
 		return nil, errors.Wrap(err, "create from decrypted data")
 	}
@@ -107,10 +123,53 @@ func (f *SecretFactory) CreateFromDecryptedData(ctx context.Context, r io.Reader
 		return nil, errors.Wrap(err, "create from decrypted data")
 	}
 
-	encrypted := f.aead.Seal(nonce, nonce, buf.Bytes(), nil)
-
 	return &SecretString{
-		encryptedString: hex.EncodeToString(encrypted),
+		encryptedString: hex.EncodeToString(f.keyring.seal(nonce, buf.Bytes(), ad.Bytes())),
 		decryptedString: buf.String(),
 	}, nil
 }
+
+// NeedsRewrap returns true if encrypted data was not encrypted by the primary key or has no versioned header.
+func (f *SecretFactory) NeedsRewrap(encrypted string) bool {
+	bb, err := hex.DecodeString(encrypted)
+	if err != nil {
+		return false
+	}
+
+	return !f.keyring.isPrimary(bb)
+}
+
+// Rewrap decrypts data by any known key and encrypts it again by the primary key. Data stays bound to the same
+// associated data.
+func (f *SecretFactory) Rewrap(ctx context.Context, encrypted string, ad banking.AssociatedData) (string, error) {
+	decrypted, err := f.CreateBoundFromEncryptedData(ctx, bytes.NewBufferString(encrypted), ad)
+	if err != nil {
+		return "", errors.Wrap(err, "rewrap")
+	}
+
+	rewrapped, err := f.CreateBoundFromDecryptedData(ctx, bytes.NewBufferString(decrypted.DecryptedString()), ad)
+	if err != nil {
+		return "", errors.Wrap(err, "rewrap")
+	}
+
+	return rewrapped.EncryptedString(), nil
+}
+
+// CheckHealth encrypts the probe and decrypts it back, so broken nonce generator or cipher is detected.
+func (f *SecretFactory) CheckHealth(ctx context.Context) error {
+	encrypted, err := f.CreateFromDecryptedData(ctx, bytes.NewBufferString(healthCheckProbe))
+	if err != nil {
+		return errors.Wrap(err, "check secret factory health")
+	}
+
+	decrypted, err := f.CreateFromEncryptedData(ctx, bytes.NewBufferString(encrypted.EncryptedString()))
+	if err != nil {
+		return errors.Wrap(err, "check secret factory health")
+	}
+
+	if decrypted.DecryptedString() != healthCheckProbe {
+		return errors.Wrap(ErrSelfTestFailed, "check secret factory health")
+	}
+
+	return nil
+}
@@ -5,9 +5,12 @@ import (
 	"context"
 	"testing"
 
+	banking "github.com/morozovcookie/agat-banking"
 	"github.com/morozovcookie/agat-banking/aes"
+	"github.com/morozovcookie/agat-banking/aes/rand"
 	"github.com/morozovcookie/agat-banking/mock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSecretFactory_CreateFromDecryptedData(t *testing.T) {
@@ -59,7 +62,7 @@ func TestSecretFactory_CreateFromDecryptedData(t *testing.T) {
 				decryptedText: "super-secret-string",
 			},
 			wants: wants{
-				encryptedString: "616767336b7376746c616d376276c58836970ec0fd86c63d74233cfcea0f36a3dd83247363b1b850152" +
+				encryptedString: "019469a03b616767336b7376746c616d376276c58836970ec0fd86c63d74233cfcea0f36a3dd83247363b1b850152" +
 					"b867e1b51c7",
 				decryptedString: "super-secret-string",
 
@@ -124,6 +127,27 @@ func TestSecretFactory_CreateFromEncryptedData(t *testing.T) {
 			fields: fields{
 				key: "a7681ff138d941377c55aefb4ab667b833a823e582c91317f5b5e33c09e6891e",
 			},
+			args: args{
+				ctx: context.Background(),
+				encryptedText: "019469a03b616767336b7376746c616d376276c58836970ec0fd86c63d74233cfcea0f36a3dd83247363" +
+					"b1b850152b867e1b51c7",
+			},
+			wants: wants{
+				encryptedString: "019469a03b616767336b7376746c616d376276c58836970ec0fd86c63d74233cfcea0f36a3dd832473" +
+					"63b1b850152b867e1b51c7",
+				decryptedString: "super-secret-string",
+
+				err: false,
+			},
+		},
+		{
+			meta: meta{
+				name:    "legacy format without header",
+				enabled: true,
+			},
+			fields: fields{
+				key: "a7681ff138d941377c55aefb4ab667b833a823e582c91317f5b5e33c09e6891e",
+			},
 			args: args{
 				ctx: context.Background(),
 				encryptedText: "616767336b7376746c616d376276c58836970ec0fd86c63d74233cfcea0f36a3dd83247363b1b850152b8" +
@@ -137,6 +161,26 @@ func TestSecretFactory_CreateFromEncryptedData(t *testing.T) {
 				err: false,
 			},
 		},
+		{
+			meta: meta{
+				name:    "unknown key",
+				enabled: true,
+			},
+			fields: fields{
+				key: "0f7f3fc9a2bd1f52a0fc0d7ce5d0a6cbd6e0d9d4f4ca3c86a8b6a1f8b2e7c410",
+			},
+			args: args{
+				ctx: context.Background(),
+				encryptedText: "019469a03b616767336b7376746c616d376276c58836970ec0fd86c63d74233cfcea0f36a3dd83247363" +
+					"b1b850152b867e1b51c7",
+			},
+			wants: wants{
+				encryptedString: "",
+				decryptedString: "",
+
+				err: true,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -149,9 +193,180 @@ func TestSecretFactory_CreateFromEncryptedData(t *testing.T) {
 			assert.NoError(t, err)
 
 			ss, err := factory.CreateFromEncryptedData(tt.args.ctx, bytes.NewBufferString(tt.args.encryptedText))
+			assert.Equal(t, tt.wants.err, err != nil)
+
+			if err != nil {
+				return
+			}
+
 			assert.Equal(t, tt.wants.encryptedString, ss.EncryptedString())
 			assert.Equal(t, tt.wants.decryptedString, ss.DecryptedString())
-			assert.Equal(t, tt.wants.err, err != nil)
 		})
 	}
 }
+
+func TestSecretFactory_CreateBoundFromEncryptedData(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		ad banking.AssociatedData
+	}
+	type wants struct {
+		err bool
+	}
+
+	ad := banking.AssociatedData{
+		Table:    "user_accounts",
+		Column:   "email_address",
+		RecordID: "1s8cy82tkt0s2ejaz4nlq3a0i",
+	}
+
+	direct, err := aes.NewSecretFactory(rand.NewNonceGenerator(), bytes.NewBufferString(oldKey))
+	require.NoError(t, err)
+
+	factories := map[string]banking.SecretFactory{
+		"direct":   direct,
+		"envelope": newEnvelopeSecretFactory(t, nil, oldKey),
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "same associated data",
+				enabled: true,
+			},
+			args: args{
+				ad: ad,
+			},
+			wants: wants{
+				err: false,
+			},
+		},
+		{
+			meta: meta{
+				name:    "another record",
+				enabled: true,
+			},
+			args: args{
+				ad: banking.AssociatedData{
+					Table:    ad.Table,
+					Column:   ad.Column,
+					RecordID: "7m0bqzl2vxrcs4a1f8dy3e9hu",
+				},
+			},
+			wants: wants{
+				err: true,
+			},
+		},
+		{
+			meta: meta{
+				name:    "another column",
+				enabled: true,
+			},
+			args: args{
+				ad: banking.AssociatedData{
+					Table:    ad.Table,
+					Column:   "username",
+					RecordID: ad.RecordID,
+				},
+			},
+			wants: wants{
+				err: true,
+			},
+		},
+		{
+			meta: meta{
+				name:    "field boundary shifted",
+				enabled: true,
+			},
+			args: args{
+				ad: banking.AssociatedData{
+					Table:    ad.Table + ad.Column,
+					Column:   "",
+					RecordID: ad.RecordID,
+				},
+			},
+			wants: wants{
+				err: true,
+			},
+		},
+		{
+			meta: meta{
+				name:    "unbound",
+				enabled: true,
+			},
+			args: args{
+				ad: banking.AssociatedData{},
+			},
+			wants: wants{
+				err: true,
+			},
+		},
+	}
+
+	for name, factory := range factories {
+		factory := factory
+
+		secret, err := factory.CreateBoundFromDecryptedData(context.Background(),
+			bytes.NewBufferString("john.doe@example.com"), ad)
+		require.NoError(t, err)
+
+		for _, tt := range tests {
+			tt := tt
+
+			t.Run(name+"/"+tt.meta.name, func(t *testing.T) {
+				if !tt.meta.enabled {
+					t.SkipNow()
+				}
+
+				decrypted, err := factory.CreateBoundFromEncryptedData(context.Background(),
+					bytes.NewBufferString(secret.EncryptedString()), tt.args.ad)
+				assert.Equal(t, tt.wants.err, err != nil)
+
+				if err != nil {
+					return
+				}
+
+				assert.Equal(t, "john.doe@example.com", decrypted.DecryptedString())
+			})
+		}
+	}
+}
+
+func TestSecretFactory_RewrapBound(t *testing.T) {
+	ctx := context.Background()
+
+	ad := banking.AssociatedData{
+		Table:    "user_accounts",
+		Column:   "email_address",
+		RecordID: "1s8cy82tkt0s2ejaz4nlq3a0i",
+	}
+
+	oldKeyring, err := aes.NewKeyring(mustDecodeKey(t, oldKey))
+	require.NoError(t, err)
+
+	rotatedKeyring, err := aes.NewKeyring(mustDecodeKey(t, newKey), mustDecodeKey(t, oldKey))
+	require.NoError(t, err)
+
+	secret, err := aes.NewKeyringSecretFactory(rand.NewNonceGenerator(), oldKeyring).
+		CreateBoundFromDecryptedData(ctx, bytes.NewBufferString("john.doe@example.com"), ad)
+	require.NoError(t, err)
+
+	rotated := aes.NewKeyringSecretFactory(rand.NewNonceGenerator(), rotatedKeyring)
+
+	_, err = rotated.Rewrap(ctx, secret.EncryptedString(), banking.AssociatedData{})
+	assert.Error(t, err)
+
+	rewrapped, err := rotated.Rewrap(ctx, secret.EncryptedString(), ad)
+	require.NoError(t, err)
+
+	decrypted, err := rotated.CreateBoundFromEncryptedData(ctx, bytes.NewBufferString(rewrapped), ad)
+	require.NoError(t, err)
+	assert.Equal(t, "john.doe@example.com", decrypted.DecryptedString())
+}
@@ -17,4 +17,13 @@ type AuthenticationService interface {
 
 	// AuthenticateUserByUsername authenticates user by username and password.
 	AuthenticateUserByUsername(ctx context.Context, username string, password SecretString) (Token, Token, error)
+
+	// RefreshToken exchanges refresh token to the new pair of access and refresh tokens.
+	RefreshToken(ctx context.Context, refreshToken SecretString) (Token, Token, error)
+
+	// SignOut expires refresh token, so it could not be used for retrieving new tokens pair anymore.
+	SignOut(ctx context.Context, refreshToken SecretString) error
+
+	// SignOutEverywhere expires every refresh token of the user account which refresh token belongs to.
+	SignOutEverywhere(ctx context.Context, refreshToken SecretString) error
 }
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"strings"
 
 	banking "github.com/morozovcookie/agat-banking"
 	"github.com/pkg/errors"
@@ -12,24 +13,50 @@ var _ banking.AuthenticationService = (*AuthenticationService)(nil)
 // AuthenticationService represents a service for managing user authentication process.
 type AuthenticationService struct {
 	userAccountService         banking.UserAccountService
+	passwordHasher             banking.PasswordHasher
 	accessTokenBuilderCreator  banking.TokenBuilderCreator
 	refreshTokenBuilderCreator banking.TokenBuilderCreator
+	refreshTokenParser         banking.TokenParser
 	tokenService               banking.TokenService
+	lockoutService             banking.LockoutService
+	transactionManager         banking.TransactionManager
+	roleService                banking.RoleService
+
+	errorHandler func(ctx context.Context, err error)
 }
 
 // NewAuthenticationService returns a new AuthenticationService instance.
 func NewAuthenticationService(
 	userAccountService banking.UserAccountService,
+	passwordHasher banking.PasswordHasher,
 	accessTokenBuilderCreator banking.TokenBuilderCreator,
 	refreshTokenBuilderCreator banking.TokenBuilderCreator,
+	refreshTokenParser banking.TokenParser,
 	tokenService banking.TokenService,
+	lockoutService banking.LockoutService,
+	transactionManager banking.TransactionManager,
+	roleService banking.RoleService,
+	opts ...AuthenticationServiceOption,
 ) *AuthenticationService {
-	return &AuthenticationService{
+	svc := &AuthenticationService{
 		userAccountService:         userAccountService,
+		passwordHasher:             passwordHasher,
 		accessTokenBuilderCreator:  accessTokenBuilderCreator,
 		refreshTokenBuilderCreator: refreshTokenBuilderCreator,
+		refreshTokenParser:         refreshTokenParser,
 		tokenService:               tokenService,
+		lockoutService:             lockoutService,
+		transactionManager:         transactionManager,
+		roleService:                roleService,
+
+		errorHandler: func(ctx context.Context, err error) {},
+	}
+
+	for _, opt := range opts {
+		opt.apply(svc)
 	}
+
+	return svc
 }
 
 // AuthenticateUserByEmail authenticates user by email address and password.
@@ -42,12 +69,9 @@ func (svc *AuthenticationService) AuthenticateUserByEmail(
 	banking.Token,
 	error,
 ) {
-	account, err := svc.userAccountService.FindUserAccountByEmailAddress(ctx, email)
-	if err != nil {
-		return nil, nil, errors.Wrap(err, "authenticate user by email")
-	}
-
-	accessToken, refreshToken, err := svc.authenticateUser(ctx, account, password)
+	accessToken, refreshToken, err := svc.authenticate(ctx, func(ctx context.Context) (*banking.UserAccount, error) {
+		return svc.userAccountService.FindUserAccountByEmailAddress(ctx, email)
+	}, password)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "authenticate user by email")
 	}
@@ -65,19 +89,185 @@ func (svc *AuthenticationService) AuthenticateUserByUsername(
 	banking.Token,
 	error,
 ) {
-	account, err := svc.userAccountService.FindUserAccountByUserName(ctx, username)
+	accessToken, refreshToken, err := svc.authenticate(ctx, func(ctx context.Context) (*banking.UserAccount, error) {
+		return svc.userAccountService.FindUserAccountByUserName(ctx, username)
+	}, password)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "authenticate user by username")
 	}
 
+	return accessToken, refreshToken, nil
+}
+
+// authenticate counts the attempt for client and user account before password comparison, so parallel attempts could
+// not pass the lockout check together and the correct password does not help while lockout is active. The attempt stays
+// counted only when password is incorrect or user account does not exist.
+func (svc *AuthenticationService) authenticate(
+	ctx context.Context,
+	findUserAccount func(ctx context.Context) (*banking.UserAccount, error),
+	password banking.SecretString,
+) (
+	banking.Token,
+	banking.Token,
+	error,
+) {
+	var clientSubjects []banking.LockoutSubject
+
+	if clientIP, ok := banking.ClientIPFromContext(ctx); ok {
+		subject := banking.ClientIPSubject(clientIP)
+
+		if err := svc.lockoutService.RegisterAttempt(ctx, subject); err != nil {
+			return nil, nil, errors.Wrap(err, "authenticate")
+		}
+
+		clientSubjects = append(clientSubjects, subject)
+	}
+
+	account, err := findUserAccount(ctx)
+	if err != nil {
+		if !errors.Is(err, banking.ErrUserAccountDoesNotExist) {
+			svc.forgetAttempts(ctx, clientSubjects...)
+		}
+
+		return nil, nil, errors.Wrap(err, "authenticate")
+	}
+
+	accountSubject := banking.AccountSubject(account.ID)
+
+	if err = svc.lockoutService.RegisterAttempt(ctx, accountSubject); err != nil {
+		svc.forgetAttempts(ctx, clientSubjects...)
+
+		return nil, nil, errors.Wrap(err, "authenticate")
+	}
+
+	if account.IsDisabled() {
+		svc.forgetAttempts(ctx, append(clientSubjects, accountSubject)...)
+
+		return nil, nil, errors.Wrap(banking.ErrUserAccountDisabled, "authenticate")
+	}
+
 	accessToken, refreshToken, err := svc.authenticateUser(ctx, account, password)
+	if errors.Is(err, banking.ErrIncorrectPassword) {
+		return nil, nil, errors.Wrap(err, "authenticate")
+	}
+
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "authenticate user by username")
+		svc.forgetAttempts(ctx, append(clientSubjects, accountSubject)...)
+
+		return nil, nil, errors.Wrap(err, "authenticate")
 	}
 
+	// Client IP address counter is not reset, only the attempt is forgotten: otherwise attacker could reset it by
+	// signing in to own account.
+	_ = svc.lockoutService.ResetFailedAttempts(ctx, accountSubject)
+
+	svc.forgetAttempts(ctx, clientSubjects...)
+
 	return accessToken, refreshToken, nil
 }
 
+// forgetAttempts uncounts the attempts which have not failed. A failed forget must not change the sign in result, the
+// attempt just stays counted.
+func (svc *AuthenticationService) forgetAttempts(ctx context.Context, subjects ...banking.LockoutSubject) {
+	for _, subject := range subjects {
+		_ = svc.lockoutService.ForgetAttempt(ctx, subject)
+	}
+}
+
+// RefreshToken exchanges refresh token to the new pair of access and refresh tokens.
+func (svc *AuthenticationService) RefreshToken(
+	ctx context.Context,
+	refreshToken banking.SecretString,
+) (
+	banking.Token,
+	banking.Token,
+	error,
+) {
+	parsed, err := svc.refreshTokenParser.ParseToken(ctx, strings.NewReader(refreshToken.DecryptedString()))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "refresh token")
+	}
+
+	stored, err := svc.tokenService.FindTokenByID(ctx, parsed.ID())
+	if errors.Is(err, banking.ErrTokenReused) {
+		return nil, nil, errors.Wrap(svc.revokeTokenFamily(ctx, parsed.ID()), "refresh token")
+	}
+
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "refresh token")
+	}
+
+	accessToken, err := svc.createAccessToken(ctx, stored.Account())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "refresh token")
+	}
+
+	nextRefreshToken, err := svc.createRefreshToken(ctx, stored.Account())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "refresh token")
+	}
+
+	// Token could be rotated by a concurrent request between find and rotate, so reuse has to be checked once again.
+	// The old token must not be expired without storing the next one, otherwise user is signed out.
+	err = svc.transactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		return svc.tokenService.RotateToken(ctx, stored.ID(), nextRefreshToken)
+	})
+	if errors.Is(err, banking.ErrTokenReused) {
+		return nil, nil, errors.Wrap(svc.revokeTokenFamily(ctx, stored.ID()), "refresh token")
+	}
+
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "refresh token")
+	}
+
+	return accessToken, nextRefreshToken, nil
+}
+
+// SignOut expires refresh token, so it could not be used for retrieving new tokens pair anymore.
+func (svc *AuthenticationService) SignOut(ctx context.Context, refreshToken banking.SecretString) error {
+	parsed, err := svc.refreshTokenParser.ParseToken(ctx, strings.NewReader(refreshToken.DecryptedString()))
+	if err != nil {
+		return errors.Wrap(err, "sign out")
+	}
+
+	if _, err = svc.tokenService.ExpireToken(ctx, parsed.ID()); err != nil {
+		return errors.Wrap(err, "sign out")
+	}
+
+	return nil
+}
+
+// SignOutEverywhere expires every refresh token of the user account which refresh token belongs to.
+func (svc *AuthenticationService) SignOutEverywhere(ctx context.Context, refreshToken banking.SecretString) error {
+	parsed, err := svc.refreshTokenParser.ParseToken(ctx, strings.NewReader(refreshToken.DecryptedString()))
+	if err != nil {
+		return errors.Wrap(err, "sign out everywhere")
+	}
+
+	// Only the token which is still valid could end the other sessions, otherwise anyone who has got an old token
+	// could sign out the user.
+	stored, err := svc.tokenService.FindTokenByID(ctx, parsed.ID())
+	if err != nil {
+		return errors.Wrap(err, "sign out everywhere")
+	}
+
+	if err = svc.tokenService.ExpireUserAccountTokens(ctx, stored.Account().ID); err != nil {
+		return errors.Wrap(err, "sign out everywhere")
+	}
+
+	return nil
+}
+
+// revokeTokenFamily expires every token from the same rotation family and always returns banking.ErrTokenReused, so
+// caller could not receive a new token pair even if revocation was failed.
+func (svc *AuthenticationService) revokeTokenFamily(ctx context.Context, id banking.ID) error {
+	if err := svc.tokenService.ExpireTokenFamily(ctx, id); err != nil {
+		return errors.Wrapf(banking.ErrTokenReused, "revoke token family: %v", err)
+	}
+
+	return errors.Wrap(banking.ErrTokenReused, "revoke token family")
+}
+
 func (svc *AuthenticationService) authenticateUser(
 	ctx context.Context,
 	account *banking.UserAccount,
@@ -87,10 +277,21 @@ func (svc *AuthenticationService) authenticateUser(
 	banking.Token,
 	error,
 ) {
-	if isSamePassword := account.ComparePassword(password); !isSamePassword {
+	isSamePassword, err := account.ComparePassword(ctx, svc.passwordHasher, password)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "authenticate user")
+	}
+
+	if !isSamePassword {
 		return nil, nil, errors.Wrap(banking.ErrIncorrectPassword, "authenticate user")
 	}
 
+	// The password was verified, so it is the only moment when the stored hash could be upgraded. A failed upgrade
+	// must not prevent user from signing in: the hash will be upgraded on the next attempt.
+	if err = svc.rehashPassword(ctx, account, password); err != nil {
+		svc.errorHandler(ctx, errors.Wrap(err, "authenticate user"))
+	}
+
 	accessToken, err := svc.createAccessToken(ctx, account)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "authenticate user")
@@ -108,6 +309,31 @@ func (svc *AuthenticationService) authenticateUser(
 	return accessToken, refreshToken, nil
 }
 
+func (svc *AuthenticationService) rehashPassword(
+	ctx context.Context,
+	account *banking.UserAccount,
+	password banking.SecretString,
+) error {
+	if !svc.passwordHasher.NeedsRehash(ctx, account.PasswordHash) {
+		return nil
+	}
+
+	passwordHash, err := svc.passwordHasher.HashPassword(ctx, password)
+	if err != nil {
+		return errors.Wrap(err, "rehash password")
+	}
+
+	if err = svc.userAccountService.UpdateUserAccountPasswordHash(ctx, account.ID, passwordHash); err != nil {
+		return errors.Wrap(err, "rehash password")
+	}
+
+	account.PasswordHash = passwordHash
+
+	return nil
+}
+
+// createAccessToken builds access token with the current user account roles, so role changes are applied not later
+// than access token is refreshed.
 func (svc *AuthenticationService) createAccessToken(
 	ctx context.Context,
 	account *banking.UserAccount,
@@ -115,8 +341,14 @@ func (svc *AuthenticationService) createAccessToken(
 	banking.Token,
 	error,
 ) {
+	roles, err := svc.roleService.FindUserAccountRoles(ctx, account.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "create access token")
+	}
+
 	token, err := svc.accessTokenBuilderCreator.CreateTokenBuilder(ctx).
 		WithAccount(account).
+		WithClaim(banking.RolesClaim, roles.Strings()).
 		Build(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "create access token")
@@ -136,7 +368,7 @@ func (svc *AuthenticationService) createRefreshToken(
 		WithAccount(account).
 		Build(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "create access token")
+		return nil, errors.Wrap(err, "create refresh token")
 	}
 
 	return token, nil
@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"context"
+)
+
+// AuthenticationServiceOption represents an option for configure AuthenticationService instance.
+type AuthenticationServiceOption interface {
+	apply(svc *AuthenticationService)
+}
+
+type authenticationServiceOptionFunc func(svc *AuthenticationService)
+
+func (fn authenticationServiceOptionFunc) apply(svc *AuthenticationService) {
+	fn(svc)
+}
+
+// WithErrorHandler sets up the function which is called with errors that must not fail the sign in (e.g. failed
+// upgrade of the stored password hash), because service could not return them to anyone.
+func WithErrorHandler(fn func(ctx context.Context, err error)) AuthenticationServiceOption {
+	return authenticationServiceOptionFunc(func(svc *AuthenticationService) {
+		svc.errorHandler = fn
+	})
+}
@@ -1 +1,968 @@
 package auth_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwt"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/auth"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newToken(tokenType banking.TokenType, account *banking.UserAccount, jti banking.ID) banking.Token {
+	value := jwt.New()
+	_ = value.Set(jwt.JwtIDKey, jti.String())
+
+	return jwx.NewToken(tokenType, account, value)
+}
+
+type fakeTokenBuilder struct {
+	banking.TokenBuilder
+
+	tokenType banking.TokenType
+	jti       banking.ID
+	account   *banking.UserAccount
+}
+
+func (builder *fakeTokenBuilder) WithAccount(sub *banking.UserAccount) banking.TokenBuilder {
+	builder.account = sub
+
+	return builder
+}
+
+func (builder *fakeTokenBuilder) WithClaim(_ string, _ interface{}) banking.TokenBuilder {
+	return builder
+}
+
+func (builder *fakeTokenBuilder) Build(_ context.Context) (banking.Token, error) {
+	return newToken(builder.tokenType, builder.account, builder.jti), nil
+}
+
+// fakeTokenBuilderCreator creates builders of tokens with the same identifier, so test could expect it.
+type fakeTokenBuilderCreator struct {
+	tokenType banking.TokenType
+	jti       banking.ID
+}
+
+func (creator fakeTokenBuilderCreator) CreateTokenBuilder(_ context.Context) banking.TokenBuilder {
+	return &fakeTokenBuilder{
+		TokenBuilder: nil,
+		tokenType:    creator.tokenType,
+		jti:          creator.jti,
+		account:      nil,
+	}
+}
+
+type fakeRoleService struct {
+	banking.RoleService
+}
+
+func (fakeRoleService) FindUserAccountRoles(_ context.Context, _ banking.ID) (banking.Roles, error) {
+	return banking.Roles{banking.RoleCashier}, nil
+}
+
+// authenticationServiceFixture contains AuthenticationService and its dependencies which are checked by tests.
+type authenticationServiceFixture struct {
+	svc     *auth.AuthenticationService
+	account *banking.UserAccount
+	parser  *mock.TokenParser
+}
+
+func newAuthenticationServiceFixture(
+	tokenService banking.TokenService,
+	lockoutService banking.LockoutService,
+) *authenticationServiceFixture {
+	account := &banking.UserAccount{
+		ID:           "account",
+		UserName:     "admin",
+		PasswordHash: "hash:password",
+	}
+
+	parser := mock.NewTokenParser()
+
+	return &authenticationServiceFixture{
+		svc: auth.NewAuthenticationService(&fakeUserAccountService{account: account}, plainPasswordHasher{},
+			fakeTokenBuilderCreator{tokenType: banking.TokenTypeAccess, jti: "access"},
+			fakeTokenBuilderCreator{tokenType: banking.TokenTypeRefresh, jti: "next"}, parser, tokenService,
+			lockoutService, fakeTransactionManager{}, fakeRoleService{}),
+		account: account,
+		parser:  parser,
+	}
+}
+
+func TestAuthenticationService_RefreshToken(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		parseErr  error
+		findErr   error
+		rotateErr error
+		familyErr error
+	}
+	type args struct {
+		refreshToken string
+	}
+	type wants struct {
+		err          error
+		refreshToken banking.ID
+		rotations    int
+		revocations  int
+	}
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "rotation",
+				enabled: true,
+			},
+			fields: fields{
+				parseErr:  nil,
+				findErr:   nil,
+				rotateErr: nil,
+				familyErr: nil,
+			},
+			args: args{
+				refreshToken: "current",
+			},
+			wants: wants{
+				err:          nil,
+				refreshToken: "next",
+				rotations:    1,
+				revocations:  0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "invalid token",
+				enabled: true,
+			},
+			fields: fields{
+				parseErr:  banking.ErrTokenInvalidSignature,
+				findErr:   nil,
+				rotateErr: nil,
+				familyErr: nil,
+			},
+			args: args{
+				refreshToken: "forged",
+			},
+			wants: wants{
+				err:          banking.ErrTokenInvalidSignature,
+				refreshToken: "",
+				rotations:    0,
+				revocations:  0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "expired token",
+				enabled: true,
+			},
+			fields: fields{
+				parseErr:  nil,
+				findErr:   banking.ErrTokenDoesNotExist,
+				rotateErr: nil,
+				familyErr: nil,
+			},
+			args: args{
+				refreshToken: "current",
+			},
+			wants: wants{
+				err:          banking.ErrTokenDoesNotExist,
+				refreshToken: "",
+				rotations:    0,
+				revocations:  0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "reuse detection",
+				enabled: true,
+			},
+			fields: fields{
+				parseErr:  nil,
+				findErr:   banking.ErrTokenReused,
+				rotateErr: nil,
+				familyErr: nil,
+			},
+			args: args{
+				refreshToken: "current",
+			},
+			wants: wants{
+				err:          banking.ErrTokenReused,
+				refreshToken: "",
+				rotations:    0,
+				revocations:  1,
+			},
+		},
+		{
+			meta: meta{
+				name:    "reuse is reported when family revocation failed",
+				enabled: true,
+			},
+			fields: fields{
+				parseErr:  nil,
+				findErr:   banking.ErrTokenReused,
+				rotateErr: nil,
+				familyErr: errors.New("connection refused"),
+			},
+			args: args{
+				refreshToken: "current",
+			},
+			wants: wants{
+				err:          banking.ErrTokenReused,
+				refreshToken: "",
+				rotations:    0,
+				revocations:  1,
+			},
+		},
+		{
+			meta: meta{
+				name:    "token rotated by concurrent request",
+				enabled: true,
+			},
+			fields: fields{
+				parseErr:  nil,
+				findErr:   nil,
+				rotateErr: banking.ErrTokenReused,
+				familyErr: nil,
+			},
+			args: args{
+				refreshToken: "current",
+			},
+			wants: wants{
+				err:          banking.ErrTokenReused,
+				refreshToken: "",
+				rotations:    1,
+				revocations:  1,
+			},
+		},
+		{
+			meta: meta{
+				name:    "rotation failed",
+				enabled: true,
+			},
+			fields: fields{
+				parseErr:  nil,
+				findErr:   nil,
+				rotateErr: errors.New("connection refused"),
+				familyErr: nil,
+			},
+			args: args{
+				refreshToken: "current",
+			},
+			wants: wants{
+				err:          nil,
+				refreshToken: "",
+				rotations:    1,
+				revocations:  0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			var (
+				tokenService = mock.NewTokenService()
+				fixture      = newAuthenticationServiceFixture(tokenService, nil)
+				parsed       = newToken(banking.TokenTypeRefresh, &banking.UserAccount{ID: "account"}, "current")
+				stored       = newToken(banking.TokenTypeRefresh, fixture.account, "current")
+			)
+
+			if tt.fields.parseErr != nil {
+				parsed = nil
+			}
+
+			if tt.fields.findErr != nil {
+				stored = nil
+			}
+
+			fixture.parser.On("ParseToken", testifymock.Anything).
+				Return(parsed, tt.fields.parseErr)
+
+			tokenService.On("FindTokenByID", banking.ID("current")).
+				Return(stored, tt.fields.findErr)
+			tokenService.On("RotateToken", banking.ID("current"), testifymock.Anything).
+				Return(tt.fields.rotateErr)
+			tokenService.On("ExpireTokenFamily", banking.ID("current")).
+				Return(tt.fields.familyErr)
+
+			accessToken, refreshToken, err := fixture.svc.RefreshToken(context.Background(),
+				plainSecret(tt.args.refreshToken))
+
+			switch {
+			case tt.wants.err != nil:
+				assert.True(t, errors.Is(err, tt.wants.err), err)
+			case tt.wants.refreshToken == "":
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+			}
+
+			if tt.wants.refreshToken == "" {
+				assert.Nil(t, accessToken)
+				assert.Nil(t, refreshToken)
+			} else {
+				require.NotNil(t, refreshToken)
+				assert.Equal(t, tt.wants.refreshToken, refreshToken.ID())
+				assert.Equal(t, fixture.account, refreshToken.Account())
+				assert.Equal(t, banking.ID("access"), accessToken.ID())
+				tokenService.AssertCalled(t, "RotateToken", banking.ID("current"), refreshToken)
+			}
+
+			tokenService.AssertNumberOfCalls(t, "RotateToken", tt.wants.rotations)
+			tokenService.AssertNumberOfCalls(t, "ExpireTokenFamily", tt.wants.revocations)
+		})
+	}
+}
+
+// rotatingTokenService keeps a single rotation family in memory and rotates tokens atomically, like the storage does.
+type rotatingTokenService struct {
+	banking.TokenService
+
+	mu      sync.Mutex
+	active  map[banking.ID]banking.Token
+	rotated map[banking.ID]struct{}
+	revoked bool
+}
+
+func newRotatingTokenService(tokens ...banking.Token) *rotatingTokenService {
+	svc := &rotatingTokenService{
+		TokenService: nil,
+		mu:           sync.Mutex{},
+		active:       make(map[banking.ID]banking.Token, len(tokens)),
+		rotated:      make(map[banking.ID]struct{}),
+		revoked:      false,
+	}
+
+	for _, token := range tokens {
+		svc.active[token.ID()] = token
+	}
+
+	return svc
+}
+
+func (svc *rotatingTokenService) FindTokenByID(_ context.Context, id banking.ID) (banking.Token, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	if _, ok := svc.rotated[id]; ok {
+		return nil, banking.ErrTokenReused
+	}
+
+	token, ok := svc.active[id]
+	if !ok {
+		return nil, banking.ErrTokenDoesNotExist
+	}
+
+	return token, nil
+}
+
+func (svc *rotatingTokenService) RotateToken(_ context.Context, id banking.ID, next banking.Token) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	if _, ok := svc.active[id]; !ok {
+		return banking.ErrTokenReused
+	}
+
+	delete(svc.active, id)
+	svc.rotated[id] = struct{}{}
+	svc.active[next.ID()] = next
+
+	return nil
+}
+
+func (svc *rotatingTokenService) ExpireTokenFamily(_ context.Context, _ banking.ID) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	for id := range svc.active {
+		delete(svc.active, id)
+		svc.rotated[id] = struct{}{}
+	}
+
+	svc.revoked = true
+
+	return nil
+}
+
+func TestAuthenticationService_RefreshTokenConcurrently(t *testing.T) {
+	const requests = 8
+
+	var (
+		stored       = newToken(banking.TokenTypeRefresh, &banking.UserAccount{ID: "account"}, "current")
+		tokenService = newRotatingTokenService(stored)
+		fixture      = newAuthenticationServiceFixture(tokenService, nil)
+
+		wg   sync.WaitGroup
+		errs = make(chan error, requests)
+	)
+
+	fixture.parser.On("ParseToken", testifymock.Anything).
+		Return(newToken(banking.TokenTypeRefresh, fixture.account, "current"), nil)
+
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, _, err := fixture.svc.RefreshToken(context.Background(), plainSecret("current"))
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	var succeeded, reused int
+
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, banking.ErrTokenReused):
+			reused++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	// Only one request could rotate the token, the others present the rotated token, so the whole family is revoked
+	// including the token which was just issued.
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, requests-1, reused)
+	assert.True(t, tokenService.revoked)
+	assert.Empty(t, tokenService.active)
+}
+
+func TestAuthenticationService_SignOut(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		parseErr  error
+		expireErr error
+	}
+	type wants struct {
+		err     error
+		expired int
+	}
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "pass",
+				enabled: true,
+			},
+			fields: fields{
+				parseErr:  nil,
+				expireErr: nil,
+			},
+			wants: wants{
+				err:     nil,
+				expired: 1,
+			},
+		},
+		{
+			meta: meta{
+				name:    "invalid token",
+				enabled: true,
+			},
+			fields: fields{
+				parseErr:  banking.ErrTokenInvalidSignature,
+				expireErr: nil,
+			},
+			wants: wants{
+				err:     banking.ErrTokenInvalidSignature,
+				expired: 0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "token does not exist",
+				enabled: true,
+			},
+			fields: fields{
+				parseErr:  nil,
+				expireErr: banking.ErrTokenDoesNotExist,
+			},
+			wants: wants{
+				err:     banking.ErrTokenDoesNotExist,
+				expired: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			var (
+				tokenService = mock.NewTokenService()
+				fixture      = newAuthenticationServiceFixture(tokenService, nil)
+				parsed       = newToken(banking.TokenTypeRefresh, fixture.account, "current")
+			)
+
+			if tt.fields.parseErr != nil {
+				parsed = nil
+			}
+
+			fixture.parser.On("ParseToken", testifymock.Anything).
+				Return(parsed, tt.fields.parseErr)
+
+			tokenService.On("ExpireToken", banking.ID("current")).
+				Return(nil, tt.fields.expireErr)
+
+			err := fixture.svc.SignOut(context.Background(), plainSecret("current"))
+			if tt.wants.err != nil {
+				assert.True(t, errors.Is(err, tt.wants.err), err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			tokenService.AssertNumberOfCalls(t, "ExpireToken", tt.wants.expired)
+		})
+	}
+}
+
+func TestAuthenticationService_SignOutEverywhere(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		parseErr error
+		findErr  error
+	}
+	type wants struct {
+		err     error
+		expired []banking.ID
+	}
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "pass",
+				enabled: true,
+			},
+			fields: fields{
+				parseErr: nil,
+				findErr:  nil,
+			},
+			wants: wants{
+				err:     nil,
+				expired: []banking.ID{"account"},
+			},
+		},
+		{
+			meta: meta{
+				name:    "invalid token",
+				enabled: true,
+			},
+			fields: fields{
+				parseErr: banking.ErrTokenInvalidSignature,
+				findErr:  nil,
+			},
+			wants: wants{
+				err:     banking.ErrTokenInvalidSignature,
+				expired: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "signed out token",
+				enabled: true,
+			},
+			fields: fields{
+				parseErr: nil,
+				findErr:  banking.ErrTokenDoesNotExist,
+			},
+			wants: wants{
+				err:     banking.ErrTokenDoesNotExist,
+				expired: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "rotated token",
+				enabled: true,
+			},
+			fields: fields{
+				parseErr: nil,
+				findErr:  banking.ErrTokenReused,
+			},
+			wants: wants{
+				err:     banking.ErrTokenReused,
+				expired: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			var (
+				tokenService = mock.NewTokenService()
+				fixture      = newAuthenticationServiceFixture(tokenService, nil)
+				parsed       = newToken(banking.TokenTypeRefresh, fixture.account, "current")
+				stored       = newToken(banking.TokenTypeRefresh, fixture.account, "current")
+			)
+
+			if tt.fields.parseErr != nil {
+				parsed = nil
+			}
+
+			if tt.fields.findErr != nil {
+				stored = nil
+			}
+
+			fixture.parser.On("ParseToken", testifymock.Anything).
+				Return(parsed, tt.fields.parseErr)
+
+			tokenService.On("FindTokenByID", banking.ID("current")).
+				Return(stored, tt.fields.findErr)
+			tokenService.On("ExpireUserAccountTokens", testifymock.Anything).
+				Return(nil)
+
+			err := fixture.svc.SignOutEverywhere(context.Background(), plainSecret("current"))
+			if tt.wants.err != nil {
+				assert.True(t, errors.Is(err, tt.wants.err), err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Only a still valid token could sign out the other sessions: rotated or signed out token must not.
+			tokenService.AssertNumberOfCalls(t, "ExpireUserAccountTokens", len(tt.wants.expired))
+
+			for _, accountID := range tt.wants.expired {
+				tokenService.AssertCalled(t, "ExpireUserAccountTokens", accountID)
+			}
+		})
+	}
+}
+
+func (svc *fakeUserAccountService) FindUserAccountByUserName(
+	_ context.Context,
+	userName string,
+) (
+	*banking.UserAccount,
+	error,
+) {
+	if svc.account == nil || svc.account.UserName != userName {
+		return nil, banking.ErrUserAccountDoesNotExist
+	}
+
+	return svc.account, nil
+}
+
+// fakeLockoutService records lockout calls and rejects attempts of locked subjects.
+type fakeLockoutService struct {
+	locked []banking.LockoutSubject
+
+	registered []banking.LockoutSubject
+	forgotten  []banking.LockoutSubject
+	reset      []banking.LockoutSubject
+}
+
+func (svc *fakeLockoutService) RegisterAttempt(_ context.Context, subject banking.LockoutSubject) error {
+	svc.registered = append(svc.registered, subject)
+
+	for _, locked := range svc.locked {
+		if locked == subject {
+			return &banking.LockedError{
+				Scope:      subject.Scope,
+				RetryAfter: time.Minute,
+			}
+		}
+	}
+
+	return nil
+}
+
+func (svc *fakeLockoutService) ForgetAttempt(_ context.Context, subject banking.LockoutSubject) error {
+	svc.forgotten = append(svc.forgotten, subject)
+
+	return nil
+}
+
+func (svc *fakeLockoutService) ResetFailedAttempts(_ context.Context, subject banking.LockoutSubject) error {
+	svc.reset = append(svc.reset, subject)
+
+	return nil
+}
+
+func TestAuthenticationService_AuthenticateUserByUsername(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		locked   []banking.LockoutSubject
+		disabled bool
+	}
+	type args struct {
+		username string
+		password string
+	}
+	type wants struct {
+		err        error
+		registered []banking.LockoutSubject
+		forgotten  []banking.LockoutSubject
+		reset      []banking.LockoutSubject
+	}
+
+	var (
+		clientSubject  = banking.ClientIPSubject("192.0.2.1")
+		accountSubject = banking.AccountSubject("account")
+	)
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "correct password",
+				enabled: true,
+			},
+			fields: fields{
+				locked:   nil,
+				disabled: false,
+			},
+			args: args{
+				username: "admin",
+				password: "password",
+			},
+			wants: wants{
+				err:        nil,
+				registered: []banking.LockoutSubject{clientSubject, accountSubject},
+				forgotten:  []banking.LockoutSubject{clientSubject},
+				reset:      []banking.LockoutSubject{accountSubject},
+			},
+		},
+		{
+			meta: meta{
+				name:    "incorrect password",
+				enabled: true,
+			},
+			fields: fields{
+				locked:   nil,
+				disabled: false,
+			},
+			args: args{
+				username: "admin",
+				password: "wrong",
+			},
+			wants: wants{
+				err:        banking.ErrIncorrectPassword,
+				registered: []banking.LockoutSubject{clientSubject, accountSubject},
+				forgotten:  nil,
+				reset:      nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "user account does not exist",
+				enabled: true,
+			},
+			fields: fields{
+				locked:   nil,
+				disabled: false,
+			},
+			args: args{
+				username: "unknown",
+				password: "password",
+			},
+			wants: wants{
+				err:        banking.ErrUserAccountDoesNotExist,
+				registered: []banking.LockoutSubject{clientSubject},
+				forgotten:  nil,
+				reset:      nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "client locked",
+				enabled: true,
+			},
+			fields: fields{
+				locked:   []banking.LockoutSubject{clientSubject},
+				disabled: false,
+			},
+			args: args{
+				username: "admin",
+				password: "password",
+			},
+			wants: wants{
+				err:        banking.ErrAccountLocked,
+				registered: []banking.LockoutSubject{clientSubject},
+				forgotten:  nil,
+				reset:      nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "user account locked",
+				enabled: true,
+			},
+			fields: fields{
+				locked:   []banking.LockoutSubject{accountSubject},
+				disabled: false,
+			},
+			args: args{
+				username: "admin",
+				password: "password",
+			},
+			wants: wants{
+				err:        banking.ErrAccountLocked,
+				registered: []banking.LockoutSubject{clientSubject, accountSubject},
+				forgotten:  []banking.LockoutSubject{clientSubject},
+				reset:      nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "user account disabled",
+				enabled: true,
+			},
+			fields: fields{
+				locked:   nil,
+				disabled: true,
+			},
+			args: args{
+				username: "admin",
+				password: "password",
+			},
+			wants: wants{
+				err:        banking.ErrUserAccountDisabled,
+				registered: []banking.LockoutSubject{clientSubject, accountSubject},
+				forgotten:  []banking.LockoutSubject{clientSubject, accountSubject},
+				reset:      nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			var (
+				tokenService   = mock.NewTokenService()
+				lockoutService = &fakeLockoutService{
+					locked:     tt.fields.locked,
+					registered: nil,
+					forgotten:  nil,
+					reset:      nil,
+				}
+				fixture = newAuthenticationServiceFixture(tokenService, lockoutService)
+				ctx     = banking.ContextWithClientIP(context.Background(), "192.0.2.1")
+			)
+
+			if tt.fields.disabled {
+				fixture.account.DisabledAt = time.Date(2021, time.November, 1, 12, 0, 0, 0, time.UTC)
+			}
+
+			tokenService.On("StoreToken", testifymock.Anything).
+				Return(nil)
+
+			_, _, err := fixture.svc.AuthenticateUserByUsername(ctx, tt.args.username, plainSecret(tt.args.password))
+			if tt.wants.err != nil {
+				assert.True(t, errors.Is(err, tt.wants.err), err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Client IP address counter is never reset, otherwise attacker could reset it by signing in to own account.
+			assert.Equal(t, tt.wants.registered, lockoutService.registered)
+			assert.Equal(t, tt.wants.forgotten, lockoutService.forgotten)
+			assert.Equal(t, tt.wants.reset, lockoutService.reset)
+		})
+	}
+}
+
+// errRehash is returned by upgradingPasswordHasher, so test could check that failed upgrade is reported.
+var errRehash = errors.New("rehash")
+
+// upgradingPasswordHasher requires to upgrade every hash, but could not produce a new one.
+type upgradingPasswordHasher struct {
+	plainPasswordHasher
+}
+
+func (upgradingPasswordHasher) HashPassword(_ context.Context, _ banking.SecretString) (string, error) {
+	return "", errRehash
+}
+
+func (upgradingPasswordHasher) NeedsRehash(_ context.Context, _ string) bool {
+	return true
+}
+
+func TestAuthenticationService_AuthenticateUserByUsername_RehashError(t *testing.T) {
+	var (
+		account = &banking.UserAccount{
+			ID:           "account",
+			UserName:     "admin",
+			PasswordHash: "hash:password",
+		}
+		tokenService = mock.NewTokenService()
+		reported     []error
+	)
+
+	tokenService.On("StoreToken", testifymock.Anything).
+		Return(nil)
+
+	svc := auth.NewAuthenticationService(&fakeUserAccountService{account: account}, upgradingPasswordHasher{},
+		fakeTokenBuilderCreator{tokenType: banking.TokenTypeAccess, jti: "access"},
+		fakeTokenBuilderCreator{tokenType: banking.TokenTypeRefresh, jti: "next"}, mock.NewTokenParser(),
+		tokenService, &fakeLockoutService{}, fakeTransactionManager{}, fakeRoleService{},
+		auth.WithErrorHandler(func(_ context.Context, err error) {
+			reported = append(reported, err)
+		}))
+
+	accessToken, refreshToken, err := svc.AuthenticateUserByUsername(context.Background(), "admin",
+		plainSecret("password"))
+	require.NoError(t, err, "failed upgrade must not prevent user from signing in")
+	assert.NotNil(t, accessToken)
+	assert.NotNil(t, refreshToken)
+	assert.Equal(t, "hash:password", account.PasswordHash)
+
+	require.Len(t, reported, 1)
+	assert.True(t, errors.Is(reported[0], errRehash), reported[0])
+}
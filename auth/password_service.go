@@ -0,0 +1,256 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"time"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+// resetTokenSize is the number of random bytes in password reset token.
+const resetTokenSize = 32
+
+var _ banking.PasswordService = (*PasswordService)(nil)
+
+// PasswordService represents a service for changing user account passwords.
+type PasswordService struct {
+	userAccountService banking.UserAccountService
+	passwordHasher     banking.PasswordHasher
+	tokenService       banking.TokenService
+	resetTokenService  banking.PasswordResetTokenService
+	lockoutService     banking.LockoutService
+	transactionManager banking.TransactionManager
+	secretFactory      banking.SecretFactory
+	generator          banking.IdentifierGenerator
+	timer              banking.Timer
+
+	resetTokenLifetime time.Duration
+}
+
+// NewPasswordService returns a new PasswordService instance.
+func NewPasswordService(
+	userAccountService banking.UserAccountService,
+	passwordHasher banking.PasswordHasher,
+	tokenService banking.TokenService,
+	resetTokenService banking.PasswordResetTokenService,
+	lockoutService banking.LockoutService,
+	transactionManager banking.TransactionManager,
+	secretFactory banking.SecretFactory,
+	generator banking.IdentifierGenerator,
+	timer banking.Timer,
+	opts ...PasswordServiceOption,
+) *PasswordService {
+	svc := &PasswordService{
+		userAccountService: userAccountService,
+		passwordHasher:     passwordHasher,
+		tokenService:       tokenService,
+		resetTokenService:  resetTokenService,
+		lockoutService:     lockoutService,
+		transactionManager: transactionManager,
+		secretFactory:      secretFactory,
+		generator:          generator,
+		timer:              timer,
+
+		resetTokenLifetime: DefaultResetTokenLifetime,
+	}
+
+	for _, opt := range opts {
+		opt.apply(svc)
+	}
+
+	return svc
+}
+
+// ChangePassword replaces password of the UserAccount with specified identifier if current password is correct.
+// Current password check is counted as sign in attempt, so stolen access token could not be used for guessing the
+// password.
+func (svc *PasswordService) ChangePassword(
+	ctx context.Context,
+	accountID banking.ID,
+	currentPassword banking.SecretString,
+	newPassword banking.SecretString,
+) error {
+	account, err := svc.findActiveUserAccount(ctx, accountID)
+	if err != nil {
+		return errors.Wrap(err, "change password")
+	}
+
+	subject := banking.AccountSubject(account.ID)
+
+	if err = svc.lockoutService.RegisterAttempt(ctx, subject); err != nil {
+		return errors.Wrap(err, "change password")
+	}
+
+	isSamePassword, err := account.ComparePassword(ctx, svc.passwordHasher, currentPassword)
+	if err != nil {
+		// A failed forget must not change the result, the attempt just stays counted.
+		_ = svc.lockoutService.ForgetAttempt(ctx, subject)
+
+		return errors.Wrap(err, "change password")
+	}
+
+	if !isSamePassword {
+		return errors.Wrap(banking.ErrIncorrectPassword, "change password")
+	}
+
+	_ = svc.lockoutService.ResetFailedAttempts(ctx, subject)
+
+	passwordHash, err := svc.passwordHasher.HashPassword(ctx, newPassword)
+	if err != nil {
+		return errors.Wrap(err, "change password")
+	}
+
+	err = svc.transactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		return svc.replacePasswordHash(ctx, account.ID, passwordHash)
+	})
+	if err != nil {
+		return errors.Wrap(err, "change password")
+	}
+
+	return nil
+}
+
+// IssuePasswordResetToken returns a new single-use secret which allows to reset password of the UserAccount with
+// specified identifier. Previously issued secrets could not be used anymore.
+func (svc *PasswordService) IssuePasswordResetToken(
+	ctx context.Context,
+	accountID banking.ID,
+) (
+	banking.SecretString,
+	time.Time,
+	error,
+) {
+	account, err := svc.findActiveUserAccount(ctx, accountID)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "issue password reset token")
+	}
+
+	secret := make([]byte, resetTokenSize)
+
+	if _, err = io.ReadFull(rand.Reader, secret); err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "issue password reset token")
+	}
+
+	id, err := svc.generator.GenerateIdentifier(ctx)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "issue password reset token")
+	}
+
+	now, err := svc.timer.Time(ctx)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "issue password reset token")
+	}
+
+	var (
+		encoded = hex.EncodeToString(secret)
+		token   = &banking.PasswordResetToken{
+			ID:         id,
+			Account:    account,
+			TokenHash:  hashResetToken(encoded),
+			CreatedAt:  now,
+			ExpiresAt:  now.Add(svc.resetTokenLifetime),
+			RedeemedAt: time.Time{},
+		}
+	)
+
+	err = svc.transactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		return svc.resetTokenService.StorePasswordResetToken(ctx, token)
+	})
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "issue password reset token")
+	}
+
+	resetToken, err := svc.secretFactory.CreateFromDecryptedData(ctx, strings.NewReader(encoded))
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "issue password reset token")
+	}
+
+	return resetToken, token.ExpiresAt, nil
+}
+
+// ResetPassword replaces password of the UserAccount which password reset token was issued for. Token is redeemed
+// only if password is replaced, so failed attempt does not burn it.
+func (svc *PasswordService) ResetPassword(
+	ctx context.Context,
+	resetToken banking.SecretString,
+	newPassword banking.SecretString,
+) error {
+	tokenHash := hashResetToken(resetToken.DecryptedString())
+
+	// Token is checked before password is hashed, so anonymous request with unknown or expired token could not make
+	// the service do slow hashing. Token could be redeemed concurrently, so it is checked again when it is redeemed.
+	if _, err := svc.resetTokenService.FindPasswordResetTokenByHash(ctx, tokenHash); err != nil {
+		return errors.Wrap(err, "reset password")
+	}
+
+	// Password is hashed before transaction is started, so slow hashing does not hold the locks.
+	passwordHash, err := svc.passwordHasher.HashPassword(ctx, newPassword)
+	if err != nil {
+		return errors.Wrap(err, "reset password")
+	}
+
+	// Redeeming locks the token row until transaction is finished, so token is used only if password is replaced.
+	err = svc.transactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		token, err := svc.resetTokenService.RedeemPasswordResetToken(ctx, tokenHash)
+		if err != nil {
+			return errors.Wrap(err, "redeem password reset token")
+		}
+
+		account, err := svc.findActiveUserAccount(ctx, token.Account.ID)
+		if err != nil {
+			return err
+		}
+
+		return svc.replacePasswordHash(ctx, account.ID, passwordHash)
+	})
+	if err != nil {
+		return errors.Wrap(err, "reset password")
+	}
+
+	return nil
+}
+
+func (svc *PasswordService) findActiveUserAccount(
+	ctx context.Context,
+	accountID banking.ID,
+) (
+	*banking.UserAccount,
+	error,
+) {
+	account, err := svc.userAccountService.FindUserAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "find active user account")
+	}
+
+	if account.IsDisabled() {
+		return nil, errors.Wrap(banking.ErrUserAccountDisabled, "find active user account")
+	}
+
+	return account, nil
+}
+
+// replacePasswordHash stores the new password hash and revokes every refresh token, so sessions which could be
+// started by somebody who knew the old password are finished.
+func (svc *PasswordService) replacePasswordHash(ctx context.Context, accountID banking.ID, passwordHash string) error {
+	if err := svc.userAccountService.UpdateUserAccountPasswordHash(ctx, accountID, passwordHash); err != nil {
+		return errors.Wrap(err, "replace password hash")
+	}
+
+	if err := svc.tokenService.ExpireUserAccountTokens(ctx, accountID); err != nil {
+		return errors.Wrap(err, "replace password hash")
+	}
+
+	return nil
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
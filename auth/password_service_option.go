@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"time"
+)
+
+// PasswordServiceOption represents an option for configure PasswordService instance.
+type PasswordServiceOption interface {
+	apply(svc *PasswordService)
+}
+
+type passwordServiceOptionFunc func(svc *PasswordService)
+
+func (fn passwordServiceOptionFunc) apply(svc *PasswordService) {
+	fn(svc)
+}
+
+// DefaultResetTokenLifetime is the default time duration which password reset token could be used since it was
+// issued.
+const DefaultResetTokenLifetime = time.Hour
+
+// WithResetTokenLifetime sets up the time duration which password reset token could be used since it was issued.
+func WithResetTokenLifetime(lifetime time.Duration) PasswordServiceOption {
+	return passwordServiceOptionFunc(func(svc *PasswordService) {
+		svc.resetTokenLifetime = lifetime
+	})
+}
@@ -0,0 +1,401 @@
+package auth_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/auth"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type plainSecret string
+
+func (s plainSecret) String() string          { return "[SECRET]" }
+func (s plainSecret) EncryptedString() string { return string(s) }
+func (s plainSecret) DecryptedString() string { return string(s) }
+
+type plainSecretFactory struct {
+	banking.SecretFactory
+}
+
+func (plainSecretFactory) CreateFromEncryptedData(_ context.Context, r io.Reader) (banking.SecretString, error) {
+	bb, err := ioutil.ReadAll(r)
+
+	return plainSecret(bb), err
+}
+
+func (plainSecretFactory) CreateFromDecryptedData(_ context.Context, r io.Reader) (banking.SecretString, error) {
+	bb, err := ioutil.ReadAll(r)
+
+	return plainSecret(bb), err
+}
+
+type plainPasswordHasher struct{}
+
+func (plainPasswordHasher) HashPassword(_ context.Context, password banking.SecretString) (string, error) {
+	return "hash:" + password.DecryptedString(), nil
+}
+
+type countingPasswordHasher struct {
+	plainPasswordHasher
+
+	hashed int
+}
+
+func (h *countingPasswordHasher) HashPassword(ctx context.Context, password banking.SecretString) (string, error) {
+	h.hashed++
+
+	return h.plainPasswordHasher.HashPassword(ctx, password)
+}
+
+func (plainPasswordHasher) ComparePassword(
+	_ context.Context,
+	encodedHash string,
+	password banking.SecretString,
+) (
+	bool,
+	error,
+) {
+	return encodedHash == "hash:"+password.DecryptedString(), nil
+}
+
+func (plainPasswordHasher) NeedsRehash(_ context.Context, _ string) bool {
+	return false
+}
+
+type fakeUserAccountService struct {
+	banking.UserAccountService
+
+	account *banking.UserAccount
+}
+
+func (svc *fakeUserAccountService) FindUserAccountByID(
+	_ context.Context,
+	id banking.ID,
+) (
+	*banking.UserAccount,
+	error,
+) {
+	if svc.account == nil || svc.account.ID != id {
+		return nil, banking.ErrUserAccountDoesNotExist
+	}
+
+	return svc.account, nil
+}
+
+func (svc *fakeUserAccountService) DisableUserAccount(_ context.Context, id banking.ID) error {
+	if svc.account == nil || svc.account.ID != id {
+		return banking.ErrUserAccountDoesNotExist
+	}
+
+	svc.account.DisabledAt = time.Date(2021, time.November, 20, 10, 0, 0, 0, time.UTC)
+
+	return nil
+}
+
+func (svc *fakeUserAccountService) UpdateUserAccountPasswordHash(
+	_ context.Context,
+	_ banking.ID,
+	passwordHash string,
+) error {
+	svc.account.PasswordHash = passwordHash
+
+	return nil
+}
+
+type fakeTokenService struct {
+	banking.TokenService
+
+	expired []banking.ID
+	err     error
+}
+
+func (svc *fakeTokenService) ExpireUserAccountTokens(_ context.Context, accountID banking.ID) error {
+	if svc.err != nil {
+		return svc.err
+	}
+
+	svc.expired = append(svc.expired, accountID)
+
+	return nil
+}
+
+type fakeResetTokenService struct {
+	tokens map[string]*banking.PasswordResetToken
+	now    time.Time
+}
+
+func (svc *fakeResetTokenService) StorePasswordResetToken(_ context.Context, token *banking.PasswordResetToken) error {
+	svc.tokens[token.TokenHash] = token
+
+	return nil
+}
+
+func (svc *fakeResetTokenService) FindPasswordResetTokenByHash(
+	_ context.Context,
+	tokenHash string,
+) (
+	*banking.PasswordResetToken,
+	error,
+) {
+	token, ok := svc.tokens[tokenHash]
+	if !ok || !token.RedeemedAt.IsZero() || !token.ExpiresAt.After(svc.now) {
+		return nil, banking.ErrPasswordResetTokenInvalid
+	}
+
+	return token, nil
+}
+
+func (svc *fakeResetTokenService) RedeemPasswordResetToken(
+	ctx context.Context,
+	tokenHash string,
+) (
+	*banking.PasswordResetToken,
+	error,
+) {
+	token, err := svc.FindPasswordResetTokenByHash(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	token.RedeemedAt = svc.now
+
+	return token, nil
+}
+
+type fakeTransactionManager struct{}
+
+func (fakeTransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type passwordServiceFixture struct {
+	svc               *auth.PasswordService
+	account           *banking.UserAccount
+	passwordHasher    *countingPasswordHasher
+	tokenService      *fakeTokenService
+	resetTokenService *fakeResetTokenService
+	lockoutService    *fakeLockoutService
+}
+
+func newPasswordServiceFixture(disabled bool) *passwordServiceFixture {
+	now := time.Date(2021, time.November, 20, 10, 0, 0, 0, time.UTC)
+
+	account := &banking.UserAccount{
+		ID:           "account",
+		PasswordHash: "hash:old",
+	}
+
+	if disabled {
+		account.DisabledAt = now
+	}
+
+	timer := mock.NewTimer()
+	timer.On("Time").Return(now, nil)
+
+	generator := mock.NewIdentifierGenerator()
+	generator.On("GenerateIdentifier").Return(banking.ID("reset"), nil)
+
+	fixture := &passwordServiceFixture{
+		svc:               nil,
+		account:           account,
+		passwordHasher:    &countingPasswordHasher{plainPasswordHasher: plainPasswordHasher{}, hashed: 0},
+		tokenService:      &fakeTokenService{},
+		resetTokenService: &fakeResetTokenService{tokens: make(map[string]*banking.PasswordResetToken), now: now},
+		lockoutService:    &fakeLockoutService{},
+	}
+
+	fixture.svc = auth.NewPasswordService(&fakeUserAccountService{account: account}, fixture.passwordHasher,
+		fixture.tokenService, fixture.resetTokenService, fixture.lockoutService, fakeTransactionManager{},
+		plainSecretFactory{}, generator, timer, auth.WithResetTokenLifetime(time.Minute))
+
+	return fixture
+}
+
+func TestPasswordService_ChangePassword(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		disabled bool
+		locked   []banking.LockoutSubject
+	}
+	type args struct {
+		currentPassword string
+	}
+	type wants struct {
+		err          error
+		passwordHash string
+		expired      []banking.ID
+		registered   []banking.LockoutSubject
+		reset        []banking.LockoutSubject
+	}
+
+	accountSubject := banking.AccountSubject("account")
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "pass",
+				enabled: true,
+			},
+			fields: fields{
+				disabled: false,
+				locked:   nil,
+			},
+			args: args{
+				currentPassword: "old",
+			},
+			wants: wants{
+				err:          nil,
+				passwordHash: "hash:new",
+				expired:      []banking.ID{"account"},
+				registered:   []banking.LockoutSubject{accountSubject},
+				reset:        []banking.LockoutSubject{accountSubject},
+			},
+		},
+		{
+			meta: meta{
+				name:    "incorrect current password",
+				enabled: true,
+			},
+			fields: fields{
+				disabled: false,
+				locked:   nil,
+			},
+			args: args{
+				currentPassword: "wrong",
+			},
+			wants: wants{
+				err:          banking.ErrIncorrectPassword,
+				passwordHash: "hash:old",
+				expired:      nil,
+				registered:   []banking.LockoutSubject{accountSubject},
+				reset:        nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "locked account",
+				enabled: true,
+			},
+			fields: fields{
+				disabled: false,
+				locked:   []banking.LockoutSubject{accountSubject},
+			},
+			args: args{
+				currentPassword: "old",
+			},
+			wants: wants{
+				err:          banking.ErrAccountLocked,
+				passwordHash: "hash:old",
+				expired:      nil,
+				registered:   []banking.LockoutSubject{accountSubject},
+				reset:        nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "disabled account",
+				enabled: true,
+			},
+			fields: fields{
+				disabled: true,
+				locked:   nil,
+			},
+			args: args{
+				currentPassword: "old",
+			},
+			wants: wants{
+				err:          banking.ErrUserAccountDisabled,
+				passwordHash: "hash:old",
+				expired:      nil,
+				registered:   nil,
+				reset:        nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			fixture := newPasswordServiceFixture(tt.fields.disabled)
+			fixture.lockoutService.locked = tt.fields.locked
+
+			err := fixture.svc.ChangePassword(context.Background(), "account", plainSecret(tt.args.currentPassword),
+				plainSecret("new"))
+			if tt.wants.err != nil {
+				assert.True(t, errors.Is(err, tt.wants.err), err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wants.passwordHash, fixture.account.PasswordHash)
+			assert.Equal(t, tt.wants.expired, fixture.tokenService.expired)
+			assert.Equal(t, tt.wants.registered, fixture.lockoutService.registered)
+			assert.Empty(t, fixture.lockoutService.forgotten)
+			assert.Equal(t, tt.wants.reset, fixture.lockoutService.reset)
+		})
+	}
+}
+
+func TestPasswordService_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+
+	fixture := newPasswordServiceFixture(false)
+
+	resetToken, expiresAt, err := fixture.svc.IssuePasswordResetToken(ctx, "account")
+	assert.NoError(t, err)
+	assert.Len(t, resetToken.DecryptedString(), 64)
+	assert.Equal(t, time.Date(2021, time.November, 20, 10, 1, 0, 0, time.UTC), expiresAt)
+
+	for hash := range fixture.resetTokenService.tokens {
+		assert.NotEqual(t, resetToken.DecryptedString(), hash, "secret must be stored hashed")
+	}
+
+	err = fixture.svc.ResetPassword(ctx, plainSecret("unknown"), plainSecret("new"))
+	assert.True(t, errors.Is(err, banking.ErrPasswordResetTokenInvalid), err)
+	assert.Equal(t, "hash:old", fixture.account.PasswordHash)
+	assert.Zero(t, fixture.passwordHasher.hashed, "password must not be hashed for unknown token")
+
+	err = fixture.svc.ResetPassword(ctx, resetToken, plainSecret("new"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hash:new", fixture.account.PasswordHash)
+	assert.Equal(t, []banking.ID{"account"}, fixture.tokenService.expired)
+
+	err = fixture.svc.ResetPassword(ctx, resetToken, plainSecret("other"))
+	assert.True(t, errors.Is(err, banking.ErrPasswordResetTokenInvalid), "token must be single-use")
+	assert.Equal(t, "hash:new", fixture.account.PasswordHash)
+	assert.Equal(t, 1, fixture.passwordHasher.hashed, "password must not be hashed for redeemed token")
+}
+
+func TestPasswordService_ResetPassword_ExpiredToken(t *testing.T) {
+	ctx := context.Background()
+
+	fixture := newPasswordServiceFixture(false)
+
+	resetToken, expiresAt, err := fixture.svc.IssuePasswordResetToken(ctx, "account")
+	assert.NoError(t, err)
+
+	fixture.resetTokenService.now = expiresAt
+
+	err = fixture.svc.ResetPassword(ctx, resetToken, plainSecret("new"))
+	assert.True(t, errors.Is(err, banking.ErrPasswordResetTokenInvalid), err)
+	assert.Equal(t, "hash:old", fixture.account.PasswordHash)
+	assert.Zero(t, fixture.passwordHasher.hashed, "password must not be hashed for expired token")
+}
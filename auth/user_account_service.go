@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+var _ banking.UserAccountService = (*UserAccountService)(nil)
+
+// UserAccountService represents a service for managing user accounts which keeps issued refresh tokens consistent
+// with the user account state. Every other operation is passed to the wrapped service as is.
+type UserAccountService struct {
+	banking.UserAccountService
+
+	tokenService       banking.TokenService
+	transactionManager banking.TransactionManager
+}
+
+// NewUserAccountService returns a new UserAccountService instance.
+func NewUserAccountService(
+	userAccountService banking.UserAccountService,
+	tokenService banking.TokenService,
+	transactionManager banking.TransactionManager,
+) *UserAccountService {
+	return &UserAccountService{
+		UserAccountService: userAccountService,
+
+		tokenService:       tokenService,
+		transactionManager: transactionManager,
+	}
+}
+
+// DisableUserAccount forbids sign in to the UserAccount with specified identifier. Issued refresh tokens are expired
+// together with disabling, so the user account could not be used anymore.
+func (svc *UserAccountService) DisableUserAccount(ctx context.Context, id banking.ID) error {
+	err := svc.transactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.UserAccountService.DisableUserAccount(ctx, id); err != nil {
+			return err // nolint:wrapcheck
+		}
+
+		if err := svc.tokenService.ExpireUserAccountTokens(ctx, id); err != nil {
+			return errors.Wrap(err, "expire user account tokens")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "disable user account")
+	}
+
+	return nil
+}
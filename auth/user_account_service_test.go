@@ -0,0 +1,123 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/auth"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAccountService_DisableUserAccount(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		expireErr error
+	}
+	type args struct {
+		id banking.ID
+	}
+	type wants struct {
+		err      error
+		disabled bool
+		expired  []banking.ID
+	}
+
+	errExpire := errors.New("expire")
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "pass",
+				enabled: true,
+			},
+			fields: fields{
+				expireErr: nil,
+			},
+			args: args{
+				id: "account",
+			},
+			wants: wants{
+				err:      nil,
+				disabled: true,
+				expired:  []banking.ID{"account"},
+			},
+		},
+		{
+			meta: meta{
+				name:    "unknown account",
+				enabled: true,
+			},
+			fields: fields{
+				expireErr: nil,
+			},
+			args: args{
+				id: "unknown",
+			},
+			wants: wants{
+				err:      banking.ErrUserAccountDoesNotExist,
+				disabled: false,
+				expired:  nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "failed token expiration",
+				enabled: true,
+			},
+			fields: fields{
+				expireErr: errExpire,
+			},
+			args: args{
+				id: "account",
+			},
+			wants: wants{
+				err: errExpire,
+				// Fake transaction manager does not roll back, real one undoes disabling.
+				disabled: true,
+				expired:  nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			var (
+				account = &banking.UserAccount{
+					ID:           "account",
+					PasswordHash: "hash:old",
+				}
+				tokenService = &fakeTokenService{
+					TokenService: nil,
+					expired:      nil,
+					err:          tt.fields.expireErr,
+				}
+				svc = auth.NewUserAccountService(&fakeUserAccountService{account: account}, tokenService,
+					fakeTransactionManager{})
+			)
+
+			err := svc.DisableUserAccount(context.Background(), tt.args.id)
+			if tt.wants.err != nil {
+				assert.True(t, errors.Is(err, tt.wants.err), err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wants.disabled, account.IsDisabled())
+			assert.Equal(t, tt.wants.expired, tokenService.expired)
+		})
+	}
+}
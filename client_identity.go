@@ -0,0 +1,34 @@
+package banking
+
+import (
+	"context"
+)
+
+// ClientIdentity represents an internal service caller which was authenticated by verified TLS client certificate.
+type ClientIdentity struct {
+	// CommonName is the subject common name of client certificate.
+	CommonName string
+
+	// DNSNames is the list of DNS subject alternative names of client certificate.
+	DNSNames []string
+
+	// URIs is the list of URI subject alternative names of client certificate (e.g. SPIFFE ID).
+	URIs []string
+
+	// SerialNumber is the serial number of client certificate in hexadecimal form.
+	SerialNumber string
+}
+
+type clientIdentityContextKey struct{}
+
+// ContextWithClientIdentity returns a copy of context which carries the verified client identity.
+func ContextWithClientIdentity(ctx context.Context, identity *ClientIdentity) context.Context {
+	return context.WithValue(ctx, clientIdentityContextKey{}, identity)
+}
+
+// ClientIdentityFromContext returns the verified client identity.
+func ClientIdentityFromContext(ctx context.Context) (*ClientIdentity, bool) {
+	identity, ok := ctx.Value(clientIdentityContextKey{}).(*ClientIdentity)
+
+	return identity, ok && identity != nil
+}
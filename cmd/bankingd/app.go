@@ -0,0 +1,744 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	stdhttp "net/http"
+	"os"
+	stdtime "time"
+
+	"github.com/go-chi/chi/v5"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/aes"
+	"github.com/morozovcookie/agat-banking/aes/rand"
+	"github.com/morozovcookie/agat-banking/auth"
+	"github.com/morozovcookie/agat-banking/hmac"
+	"github.com/morozovcookie/agat-banking/http"
+	v1 "github.com/morozovcookie/agat-banking/http/v1"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/morozovcookie/agat-banking/migrations"
+	"github.com/morozovcookie/agat-banking/nanoid"
+	"github.com/morozovcookie/agat-banking/opentelemetry/jaeger"
+	"github.com/morozovcookie/agat-banking/opentelemetry/prometheus"
+	"github.com/morozovcookie/agat-banking/password"
+	"github.com/morozovcookie/agat-banking/password/argon2"
+	"github.com/morozovcookie/agat-banking/password/bcrypt"
+	"github.com/morozovcookie/agat-banking/password/sha256"
+	"github.com/morozovcookie/agat-banking/percona"
+	"github.com/morozovcookie/agat-banking/time"
+	"github.com/morozovcookie/agat-banking/worker"
+	"github.com/morozovcookie/agat-banking/zap"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	uberzap "go.uber.org/zap"
+)
+
+const (
+	// ServiceName is the name of service which is used in traces and metrics.
+	ServiceName = "bankingd"
+
+	// TokenReaperJobName is the name of expired tokens removal job which is used in metrics.
+	TokenReaperJobName = "token_reaper"
+
+	// TokenReaperLeaseName is the name of MySQL lock which is held by the replica running token reaper.
+	TokenReaperLeaseName = "bankingd_token_reaper"
+
+	// SecretReencryptionJobName is the name of stored secrets re-encryption job which is used in metrics.
+	SecretReencryptionJobName = "secret_reencryption"
+
+	// SecretReencryptionLeaseName is the name of MySQL lock which is held by the replica running re-encryption.
+	SecretReencryptionLeaseName = "bankingd_secret_reencryption"
+
+	// ColumnEncryptionJobName is the name of plaintext column values encryption job which is used in metrics.
+	ColumnEncryptionJobName = "column_encryption"
+
+	// ColumnEncryptionLeaseName is the name of MySQL lock which is held by the replica running column encryption.
+	ColumnEncryptionLeaseName = "bankingd_column_encryption"
+)
+
+// encryptedColumn represents the table column which stores values encrypted by banking.SecretFactory.
+type encryptedColumn struct {
+	table  string
+	column string
+
+	// recordIDColumn is the column which identifies record that values are bound to. It is empty for unbound column.
+	recordIDColumn string
+
+	// blindIndexColumn is the column which stores blind index of values. Values without blind index are plaintext
+	// which are encrypted by column encryption job. It is empty for column without blind index.
+	blindIndexColumn string
+}
+
+// encryptedColumns is the list of columns which are re-encrypted by the primary key after key rotation. Every new
+// column which stores encrypted values must be listed here, otherwise retiring key could not be removed safely.
+var encryptedColumns = []encryptedColumn{
+	{
+		table:            percona.UserAccountsTable,
+		column:           percona.EmailAddressColumn,
+		recordIDColumn:   percona.UserAccountIDColumn,
+		blindIndexColumn: percona.EmailAddressIndexColumn,
+	},
+}
+
+// App represents the bankingd composition root which owns every long-living resource.
+type App struct {
+	cfg    *Config
+	logger *uberzap.Logger
+
+	tracerProvider *tracesdk.TracerProvider
+	client         *percona.Client
+	server         *http.Server
+	workers        []*worker.Worker
+}
+
+// apiHandler represents the API router and objects which are built with it, but are needed outside of it.
+type apiHandler struct {
+	handler         stdhttp.Handler
+	checks          []http.ServerOption
+	tokenService    banking.TokenService
+	secretRewrapper banking.SecretRewrapper
+	secretFactory   banking.SecretFactory
+	blindIndexer    banking.BlindIndexer
+}
+
+// NewApp connects to the database and builds the HTTP server. Every service is decorated in the order
+// percona -> zap -> jaeger -> prometheus, so metrics and traces cover logging as well.
+func NewApp(ctx context.Context, cfg *Config, logger *uberzap.Logger) (_ *App, err error) {
+	app := &App{
+		cfg:    cfg,
+		logger: logger,
+
+		tracerProvider: nil,
+		client:         nil,
+		server:         nil,
+		workers:        nil,
+	}
+
+	defer func() {
+		if err != nil {
+			app.close(ctx)
+		}
+	}()
+
+	spanExporter, err := jaeger.NewExporter(cfg.Jaeger.AgentHost, cfg.Jaeger.AgentPort)
+	if err != nil {
+		return nil, errors.Wrap(err, "init app")
+	}
+
+	app.tracerProvider = jaeger.NewProvider(spanExporter, ServiceName)
+
+	metricsExporter, err := prometheus.NewExporter()
+	if err != nil {
+		return nil, errors.Wrap(err, "init app")
+	}
+
+	var (
+		creator = zap.NewLoggerZapCreator(logger)
+		tracer  = jaeger.NewSanitizer(app.tracerProvider.Tracer(ServiceName))
+		meter   = metricsExporter.MeterProvider().Meter(ServiceName)
+	)
+
+	preparer, transactionManager, err := app.connect(ctx, creator, tracer, meter)
+	if err != nil {
+		return nil, errors.Wrap(err, "init app")
+	}
+
+	api, err := newAPIHandler(cfg, creator, tracer, preparer, transactionManager)
+	if err != nil {
+		return nil, errors.Wrap(err, "init app")
+	}
+
+	handler, err := decorateHTTPHandler(api.handler, creator, tracer, meter)
+	if err != nil {
+		return nil, errors.Wrap(err, "init app")
+	}
+
+	opts := []http.ServerOption{
+		http.WithReadTimeout(cfg.HTTP.ReadTimeout),
+		http.WithReadHeaderTimeout(cfg.HTTP.ReadHeaderTimeout),
+		http.WithWriteTimeout(cfg.HTTP.WriteTimeout),
+		http.WithIdleTimeout(cfg.HTTP.IdleTimeout),
+		http.WithDrainDelay(cfg.HTTP.DrainDelay),
+		http.WithCertificateErrorHandler(func(_ context.Context, err error) {
+			app.logger.Error("reload tls certificate", uberzap.Error(err))
+		}),
+		http.WithReadinessCheck("percona", app.client),
+		http.WithHandler(cfg.Metrics.Path, metricsExporter),
+		http.WithHandler("/", handler),
+	}
+
+	if cfg.HTTP.EnableProfiler {
+		opts = append(opts, http.WithProfiler())
+	}
+
+	trustedProxies, err := parseCIDRs(cfg.HTTP.TrustedProxies)
+	if err != nil {
+		return nil, errors.Wrap(err, "init app")
+	}
+
+	if len(trustedProxies) != 0 {
+		opts = append(opts, http.WithTrustedProxies(trustedProxies...))
+	}
+
+	app.server = http.NewServer(cfg.HTTP.Address, append(append(opts, api.checks...), tlsOptions(cfg.HTTP.TLS)...)...)
+
+	if cfg.Workers.TokenReaper.Enabled {
+		reaper, err := app.newTokenReaper(creator, meter, api.tokenService)
+		if err != nil {
+			return nil, errors.Wrap(err, "init app")
+		}
+
+		app.workers = append(app.workers, reaper)
+	}
+
+	if cfg.Workers.SecretReencryption.Enabled {
+		reencryptor, err := app.newSecretReencryptor(creator, meter, api.secretRewrapper)
+		if err != nil {
+			return nil, errors.Wrap(err, "init app")
+		}
+
+		app.workers = append(app.workers, reencryptor)
+	}
+
+	if cfg.Workers.ColumnEncryption.Enabled {
+		encryptor, err := app.newColumnEncryptor(creator, meter, api.secretFactory, api.blindIndexer)
+		if err != nil {
+			return nil, errors.Wrap(err, "init app")
+		}
+
+		app.workers = append(app.workers, encryptor)
+	}
+
+	return app, nil
+}
+
+// newTokenReaper returns the worker which removes expired refresh tokens. Only the replica which holds the lease runs
+// it.
+func (app *App) newTokenReaper(
+	creator zap.LoggerCreator,
+	meter metric.Meter,
+	tokenService banking.TokenService,
+) (
+	*worker.Worker,
+	error,
+) {
+	var job worker.Job = worker.NewTokenReaper(tokenService,
+		worker.WithReaperBatchSize(uint64(app.cfg.Workers.TokenReaper.BatchSize)))
+
+	job = zap.NewJob(creator, "TokenReaper", job)
+
+	job, err := prometheus.NewJob(job, meter, attribute.String("job", TokenReaperJobName))
+	if err != nil {
+		return nil, errors.Wrap(err, "init token reaper")
+	}
+
+	return worker.NewWorker(job, percona.NewLeaderLease(app.client, TokenReaperLeaseName),
+		worker.WithInterval(app.cfg.Workers.TokenReaper.Interval),
+		worker.WithErrorHandler(func(_ context.Context, err error) {
+			app.logger.Error("token reaper", uberzap.Error(err))
+		})), nil
+}
+
+// newSecretReencryptor returns the worker which re-encrypts stored secrets by the primary key after key rotation.
+// Only the replica which holds the lease runs it.
+func (app *App) newSecretReencryptor(
+	creator zap.LoggerCreator,
+	meter metric.Meter,
+	rewrapper banking.SecretRewrapper,
+) (
+	*worker.Worker,
+	error,
+) {
+	columns := make([]banking.EncryptedColumnService, 0, len(encryptedColumns))
+	for _, column := range encryptedColumns {
+		columns = append(columns, newEncryptedColumnService(app.client, column))
+	}
+
+	var job worker.Job = worker.NewSecretReencryptor(rewrapper, columns,
+		worker.WithReencryptionBatchSize(uint64(app.cfg.Workers.SecretReencryption.BatchSize)))
+
+	job = zap.NewJob(creator, "SecretReencryptor", job)
+
+	job, err := prometheus.NewJob(job, meter, attribute.String("job", SecretReencryptionJobName))
+	if err != nil {
+		return nil, errors.Wrap(err, "init secret reencryptor")
+	}
+
+	return worker.NewWorker(job, percona.NewLeaderLease(app.client, SecretReencryptionLeaseName),
+		worker.WithInterval(app.cfg.Workers.SecretReencryption.Interval),
+		worker.WithErrorHandler(func(_ context.Context, err error) {
+			app.logger.Error("secret reencryptor", uberzap.Error(err))
+		})), nil
+}
+
+// newColumnEncryptor returns the worker which encrypts values that were stored before column encryption was enabled.
+// Only the replica which holds the lease runs it.
+func (app *App) newColumnEncryptor(
+	creator zap.LoggerCreator,
+	meter metric.Meter,
+	secretFactory banking.SecretFactory,
+	blindIndexer banking.BlindIndexer,
+) (
+	*worker.Worker,
+	error,
+) {
+	columns := make([]worker.PlaintextColumn, 0, len(encryptedColumns))
+
+	for _, column := range encryptedColumns {
+		if column.blindIndexColumn == "" {
+			continue
+		}
+
+		columns = append(columns, worker.PlaintextColumn{
+			Table:   column.table,
+			Column:  column.column,
+			Service: newEncryptedColumnService(app.client, column),
+		})
+	}
+
+	var job worker.Job = worker.NewColumnEncryptor(secretFactory, blindIndexer, columns,
+		worker.WithEncryptionBatchSize(uint64(app.cfg.Workers.ColumnEncryption.BatchSize)))
+
+	job = zap.NewJob(creator, "ColumnEncryptor", job)
+
+	job, err := prometheus.NewJob(job, meter, attribute.String("job", ColumnEncryptionJobName))
+	if err != nil {
+		return nil, errors.Wrap(err, "init column encryptor")
+	}
+
+	return worker.NewWorker(job, percona.NewLeaderLease(app.client, ColumnEncryptionLeaseName),
+		worker.WithInterval(app.cfg.Workers.ColumnEncryption.Interval),
+		worker.WithErrorHandler(func(_ context.Context, err error) {
+			app.logger.Error("column encryptor", uberzap.Error(err))
+		})), nil
+}
+
+func newEncryptedColumnService(client *percona.Client, column encryptedColumn) *percona.EncryptedColumnService {
+	return percona.NewEncryptedColumnService(client, column.table, column.column,
+		percona.WithRecordIDColumn(column.recordIDColumn), percona.WithBlindIndexColumn(column.blindIndexColumn))
+}
+
+// Run serves requests until context is canceled (e.g. on SIGTERM), then waits for active requests and releases
+// resources.
+func (app *App) Run(ctx context.Context) error {
+	errs := make(chan error, 1)
+
+	go func() {
+		app.logger.Info("start http server", uberzap.String("address", app.cfg.HTTP.Address))
+
+		errs <- app.server.Start()
+	}()
+
+	for _, w := range app.workers {
+		go func(w *worker.Worker) {
+			if err := w.Start(ctx); err != nil {
+				app.logger.Error("worker", uberzap.Error(err))
+			}
+		}(w)
+	}
+
+	var err error
+
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		app.logger.Info("shutdown http server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), app.cfg.HTTP.ShutdownTimeout)
+		defer cancel()
+
+		err = app.server.Shutdown(shutdownCtx)
+	}
+
+	// Workers use database, so they must be stopped before it is closed.
+	stopCtx, cancel := context.WithTimeout(context.Background(), app.cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+
+	for _, w := range app.workers {
+		if stopErr := w.Stop(stopCtx); stopErr != nil {
+			app.logger.Error("stop worker", uberzap.Error(stopErr))
+		}
+	}
+
+	app.close(context.Background())
+
+	if err != nil {
+		return errors.Wrap(err, "run app")
+	}
+
+	return nil
+}
+
+// connect opens the database and returns the decorated Preparer and TransactionManager. The schema must match
+// embedded migrations.
+func (app *App) connect(
+	ctx context.Context,
+	creator zap.LoggerCreator,
+	tracer trace.Tracer,
+	meter metric.Meter,
+) (
+	percona.Preparer,
+	banking.TransactionManager,
+	error,
+) {
+	app.client = percona.NewClient(app.cfg.Percona.DSN,
+		percona.WithConnMaxLifetime(app.cfg.Percona.ConnMaxLifetime),
+		percona.WithConnMaxIdleTime(app.cfg.Percona.ConnMaxIdleTime),
+		percona.WithMaxIdleConns(app.cfg.Percona.MaxIdleConns),
+		percona.WithMaxOpenConns(app.cfg.Percona.MaxOpenConns))
+
+	if err := app.client.Connect(ctx); err != nil {
+		return nil, nil, errors.Wrap(err, "connect")
+	}
+
+	if err := percona.NewMigrator(app.client, migrations.Percona(), time.NewUTCTimer()).Verify(ctx); err != nil {
+		return nil, nil, errors.Wrap(err, "connect")
+	}
+
+	var preparer percona.Preparer = app.client
+
+	preparer = zap.NewPreparer(creator, preparer)
+	preparer = jaeger.NewPreparer(tracer, preparer, semconv.DBSystemMySQL)
+	preparer = prometheus.NewPreparer(preparer, meter, semconv.DBSystemMySQL)
+
+	var beginner percona.TxBeginner = app.client
+
+	beginner = zap.NewTxBeginner(creator, beginner)
+	beginner = jaeger.NewTxBeginner(tracer, beginner, semconv.DBSystemMySQL)
+	beginner = prometheus.NewTxBeginner(beginner, meter, semconv.DBSystemMySQL)
+
+	return preparer, percona.NewTransactionManager(beginner), nil
+}
+
+func (app *App) close(ctx context.Context) {
+	if app.client != nil {
+		if err := app.client.Close(ctx); err != nil {
+			app.logger.Error("close database", uberzap.Error(err))
+		}
+	}
+
+	if app.tracerProvider != nil {
+		if err := app.tracerProvider.Shutdown(ctx); err != nil {
+			app.logger.Error("shutdown tracer provider", uberzap.Error(err))
+		}
+	}
+}
+
+// newAPIHandler builds the router with every API handler. It also returns readiness checks of keys which handlers
+// depend on.
+func newAPIHandler(
+	cfg *Config,
+	creator zap.LoggerCreator,
+	tracer trace.Tracer,
+	preparer percona.Preparer,
+	transactionManager banking.TransactionManager,
+) (
+	*apiHandler,
+	error,
+) {
+	var (
+		timer     banking.Timer               = jaeger.NewTimer(tracer, time.NewUTCTimer())
+		generator banking.IdentifierGenerator = jaeger.NewIdentifierGenerator(tracer,
+			nanoid.NewIdentifierGenerator())
+	)
+
+	rawSecretFactory, err := newSecretFactory(cfg.Secrets, tracer)
+	if err != nil {
+		return nil, errors.Wrap(err, "init api handler")
+	}
+
+	var secretFactory banking.SecretFactory = jaeger.NewSecretFactory(tracer, rawSecretFactory)
+
+	blindIndexer, err := newBlindIndexer(cfg.Secrets.BlindIndexKeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "init api handler")
+	}
+
+	alg, err := jwx.ParseSignatureAlgorithm(cfg.Tokens.SignatureAlgorithm)
+	if err != nil {
+		return nil, errors.Wrap(err, "init api handler")
+	}
+
+	accessKeys, err := jwx.LoadKeySetFiles(alg, cfg.Tokens.Access.KeyFiles[0], cfg.Tokens.Access.KeyFiles[1:]...)
+	if err != nil {
+		return nil, errors.Wrap(err, "init api handler")
+	}
+
+	refreshKeys, err := jwx.LoadKeySetFiles(alg, cfg.Tokens.Refresh.KeyFiles[0], cfg.Tokens.Refresh.KeyFiles[1:]...)
+	if err != nil {
+		return nil, errors.Wrap(err, "init api handler")
+	}
+
+	var (
+		accessTokenBuilderCreator = jwx.NewAccessTokenBuilderCreator(
+			tokenBuilderOptions(cfg, accessKeys, cfg.Tokens.Access.Lifetime, secretFactory, timer, generator)...)
+		refreshTokenBuilderCreator = jwx.NewRefreshTokenBuilderCreator(
+			tokenBuilderOptions(cfg, refreshKeys, cfg.Tokens.Refresh.Lifetime, secretFactory, timer, generator)...)
+
+		accessTokenParser banking.TokenParser = jaeger.NewTokenParser(tracer,
+			jwx.NewAccessTokenParser(tokenParserOptions(cfg, accessKeys, secretFactory, timer)...))
+		refreshTokenParser banking.TokenParser = jaeger.NewTokenParser(tracer,
+			jwx.NewRefreshTokenParser(tokenParserOptions(cfg, refreshKeys, secretFactory, timer)...))
+	)
+
+	var userService banking.UserService = percona.NewUserService(preparer, generator, timer)
+
+	userService = zap.NewUserService(creator, userService)
+	userService = jaeger.NewUserService(tracer, userService)
+
+	var userAccountService banking.UserAccountService = percona.NewUserAccountService(preparer, generator, timer,
+		secretFactory, blindIndexer)
+
+	userAccountService = zap.NewUserAccountService(creator, userAccountService)
+	userAccountService = jaeger.NewUserAccountService(tracer, userAccountService)
+
+	var tokenService banking.TokenService = percona.NewRefreshTokenService(preparer, refreshTokenBuilderCreator,
+		timer)
+
+	tokenService = zap.NewTokenService(creator, tokenService)
+	tokenService = jaeger.NewTokenService(tracer, tokenService)
+
+	userAccountService = auth.NewUserAccountService(userAccountService, tokenService, transactionManager)
+
+	var lockoutService banking.LockoutService = percona.NewLockoutService(preparer, timer)
+
+	lockoutService = zap.NewLockoutService(creator, lockoutService)
+	lockoutService = jaeger.NewLockoutService(tracer, lockoutService)
+
+	passwordHasher := password.NewPasswordHasher(argon2.NewPasswordHasher(), bcrypt.NewPasswordHasher(),
+		sha256.NewPasswordHasher())
+
+	var roleService banking.RoleService = percona.NewRoleService(preparer, timer)
+
+	roleService = zap.NewRoleService(creator, roleService)
+	roleService = jaeger.NewRoleService(tracer, roleService)
+
+	var resetTokenService banking.PasswordResetTokenService = percona.NewPasswordResetTokenService(preparer, timer)
+
+	resetTokenService = zap.NewPasswordResetTokenService(creator, resetTokenService)
+	resetTokenService = jaeger.NewPasswordResetTokenService(tracer, resetTokenService)
+
+	var passwordService banking.PasswordService = auth.NewPasswordService(userAccountService, passwordHasher,
+		tokenService, resetTokenService, lockoutService, transactionManager, secretFactory, generator, timer,
+		auth.WithResetTokenLifetime(cfg.Tokens.PasswordReset.Lifetime))
+
+	passwordService = zap.NewPasswordService(creator, passwordService)
+	passwordService = jaeger.NewPasswordService(tracer, passwordService)
+
+	var authenticationService banking.AuthenticationService = auth.NewAuthenticationService(userAccountService,
+		passwordHasher, accessTokenBuilderCreator, refreshTokenBuilderCreator, refreshTokenParser, tokenService,
+		lockoutService, transactionManager, roleService,
+		auth.WithErrorHandler(func(ctx context.Context, err error) {
+			creator.CreateLogger(ctx, "AuthenticationService", "AuthenticateUser").Error("authenticate user",
+				uberzap.Error(err))
+		}))
+
+	authenticationService = zap.NewAuthenticationService(creator, authenticationService)
+	authenticationService = jaeger.NewAuthenticationService(tracer, authenticationService)
+
+	var (
+		authenticationMiddleware = v1.NewAuthenticationMiddleware(accessTokenParser, cfg.HTTP.Realm)
+
+		authenticationHandler = v1.NewAuthenticationHandler(authenticationService, secretFactory)
+		lockoutHandler        = v1.NewLockoutHandler(lockoutService, userAccountService, authenticationMiddleware.Handler)
+		userHandler           = v1.NewUserHandler(userService, authenticationMiddleware.Handler)
+		userAccountHandler    = v1.NewUserAccountHandler(userService, userAccountService, passwordHasher,
+			secretFactory, authenticationMiddleware.Handler)
+		roleHandler     = v1.NewRoleHandler(userAccountService, roleService, authenticationMiddleware.Handler)
+		passwordHandler = v1.NewPasswordHandler(passwordService, secretFactory, authenticationMiddleware.Handler)
+		jwksHandler     = v1.NewJWKSHandler(accessKeys)
+
+		router = chi.NewRouter()
+	)
+
+	router.Handle(v1.BasePathPrefix+v1.SignInPathPrefix, authenticationHandler)
+	router.Handle(v1.BasePathPrefix+v1.SignOutPathPrefix, authenticationHandler)
+	router.Handle(v1.BasePathPrefix+v1.RefreshTokenPathPrefix, authenticationHandler)
+	router.Handle(v1.BasePathPrefix+v1.UserAccountLockoutPathPrefix, lockoutHandler)
+	router.Handle(v1.BasePathPrefix+v1.UsersPathPrefix, userHandler)
+	router.Handle(v1.BasePathPrefix+v1.UserPathPrefix, userHandler)
+	router.Handle(v1.BasePathPrefix+v1.UserAccountsPathPrefix, userAccountHandler)
+	router.Handle(v1.BasePathPrefix+v1.UserAccountPathPrefix, userAccountHandler)
+	router.Handle(v1.BasePathPrefix+v1.UserAccountRolesPathPrefix, roleHandler)
+	router.Handle(v1.BasePathPrefix+v1.UserAccountRolePathPrefix, roleHandler)
+	router.Handle(v1.BasePathPrefix+v1.PasswordPathPrefix, passwordHandler)
+	router.Handle(v1.BasePathPrefix+v1.UserAccountPasswordResetPathPrefix, passwordHandler)
+	router.Handle(v1.BasePathPrefix+v1.PasswordResetPathPrefix, passwordHandler)
+	router.Handle(v1.JWKSPath, jwksHandler)
+
+	checks := []http.ServerOption{
+		http.WithReadinessCheck("access_token_keys", accessKeys),
+		http.WithReadinessCheck("refresh_token_keys", refreshKeys),
+		http.WithReadinessCheck("secret_factory", rawSecretFactory),
+	}
+
+	return &apiHandler{
+		handler:         router,
+		checks:          checks,
+		tokenService:    tokenService,
+		secretRewrapper: rawSecretFactory,
+		secretFactory:   secretFactory,
+		blindIndexer:    blindIndexer,
+	}, nil
+}
+
+// tlsOptions returns server options which enable TLS and, if client CA bundle is set, verification of internal
+// callers certificates.
+func tlsOptions(cfg TLSConfig) []http.ServerOption {
+	if cfg.CertFile == "" {
+		return nil
+	}
+
+	opts := []http.ServerOption{
+		http.WithTLSCertificate(cfg.CertFile, cfg.KeyFile),
+		http.WithCertificateReloadInterval(cfg.ReloadInterval),
+		http.WithTLSMinVersion(tlsVersions[cfg.MinVersion]),
+	}
+
+	if cfg.ClientCAFile == "" {
+		return opts
+	}
+
+	opts = append(opts, http.WithClientCAFile(cfg.ClientCAFile))
+
+	if cfg.RequireClientCert {
+		opts = append(opts, http.WithClientAuth(tls.RequireAndVerifyClientCert))
+	}
+
+	return opts
+}
+
+// secretFactory represents the factory which stored secrets are encrypted with.
+type secretFactory interface {
+	banking.SecretFactory
+	banking.SecretRewrapper
+	banking.HealthChecker
+}
+
+// newSecretFactory returns the factory which encrypts secrets directly by the secrets keyring or, if key management
+// service is configured, by envelope encryption. In the latter case the secrets keyring, if it is set, is used only
+// for decrypting secrets which were stored before envelope encryption was enabled.
+func newSecretFactory(cfg SecretsConfig, tracer trace.Tracer) (secretFactory, error) {
+	var legacy *aes.SecretFactory
+
+	if cfg.KeyFile != "" {
+		keyring, err := aes.LoadKeyringFiles(cfg.KeyFile, cfg.RetiringKeyFiles...)
+		if err != nil {
+			return nil, errors.Wrap(err, "init secret factory")
+		}
+
+		legacy = aes.NewKeyringSecretFactory(rand.NewNonceGenerator(), keyring)
+	}
+
+	if cfg.KMS.Type == KMSTypeNone {
+		return legacy, nil
+	}
+
+	keyring, err := aes.LoadKeyringFiles(cfg.KMS.KeyFiles[0], cfg.KMS.KeyFiles[1:]...)
+	if err != nil {
+		return nil, errors.Wrap(err, "init secret factory")
+	}
+
+	var kms banking.KeyManagementService = jaeger.NewKeyManagementService(tracer,
+		aes.NewLocalKeyManagementService(rand.NewNonceGenerator(), keyring))
+
+	opts := make([]aes.EnvelopeSecretFactoryOption, 0, 1)
+	if legacy != nil {
+		opts = append(opts, aes.WithLegacySecretFactory(legacy))
+	}
+
+	return aes.NewEnvelopeSecretFactory(rand.NewNonceGenerator(), kms, opts...), nil
+}
+
+// newBlindIndexer returns the indexer which computes blind indexes of encrypted columns.
+func newBlindIndexer(keyFile string) (*hmac.BlindIndexer, error) {
+	file, err := os.Open(keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "init blind indexer")
+	}
+
+	defer file.Close()
+
+	indexer, err := hmac.NewBlindIndexer(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "init blind indexer")
+	}
+
+	return indexer, nil
+}
+
+func tokenBuilderOptions(
+	cfg *Config,
+	signer jwx.TokenSigner,
+	lifetime stdtime.Duration,
+	factory banking.SecretFactory,
+	timer banking.Timer,
+	generator banking.IdentifierGenerator,
+) []jwx.TokenBuilderOption {
+	opts := []jwx.TokenBuilderOption{
+		jwx.WithSigner(signer),
+		jwx.WithExpiresIn(lifetime),
+		jwx.WithSecretFactory(factory),
+		jwx.WithTimer(timer),
+		jwx.WithIdentifierGenerator(generator),
+	}
+
+	if cfg.Tokens.Issuer != "" {
+		opts = append(opts, jwx.WithIssuer(cfg.Tokens.Issuer))
+	}
+
+	if cfg.Tokens.Audience != "" {
+		opts = append(opts, jwx.WithAudience(cfg.Tokens.Audience))
+	}
+
+	return opts
+}
+
+func tokenParserOptions(
+	cfg *Config,
+	verifier jwx.TokenVerifier,
+	factory banking.SecretFactory,
+	timer banking.Timer,
+) []jwx.TokenParserOption {
+	opts := []jwx.TokenParserOption{
+		jwx.WithVerifier(verifier),
+		jwx.WithClockSkew(cfg.Tokens.ClockSkew),
+		jwx.WithParserSecretFactory(factory),
+		jwx.WithParserTimer(timer),
+	}
+
+	if cfg.Tokens.Issuer != "" {
+		opts = append(opts, jwx.WithExpectedIssuer(cfg.Tokens.Issuer))
+	}
+
+	if cfg.Tokens.Audience != "" {
+		opts = append(opts, jwx.WithExpectedAudience(cfg.Tokens.Audience))
+	}
+
+	return opts
+}
+
+// decorateHTTPHandler wraps handler in the order zap -> jaeger -> prometheus.
+func decorateHTTPHandler(
+	handler stdhttp.Handler,
+	creator zap.LoggerCreator,
+	tracer trace.Tracer,
+	meter metric.Meter,
+) (
+	stdhttp.Handler,
+	error,
+) {
+	handler = zap.NewHTTPHandler(handler, creator)
+	handler = jaeger.NewHTTPHandler(tracer, handler)
+
+	handler, err := prometheus.NewHTTPHandler(handler, meter)
+	if err != nil {
+		return nil, errors.Wrap(err, "decorate http handler")
+	}
+
+	return handler, nil
+}
@@ -0,0 +1,649 @@
+package main
+
+import (
+	"crypto/tls"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/auth"
+	"github.com/morozovcookie/agat-banking/http"
+	v1 "github.com/morozovcookie/agat-banking/http/v1"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/morozovcookie/agat-banking/percona"
+	"github.com/morozovcookie/agat-banking/redact"
+	"github.com/morozovcookie/agat-banking/worker"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ConfigFileEnv is the name of environment variable which contains path to the configuration file.
+	ConfigFileEnv = "BANKINGD_CONFIG"
+
+	// DefaultHTTPAddress is the default network address which HTTP server listens on.
+	DefaultHTTPAddress = ":8080"
+
+	// DefaultShutdownTimeout is the default maximum time for finishing active requests after SIGTERM.
+	DefaultShutdownTimeout = time.Second * 15
+
+	// DefaultJaegerAgentHost is the default host of Jaeger agent.
+	DefaultJaegerAgentHost = "127.0.0.1"
+
+	// DefaultJaegerAgentPort is the default port of Jaeger agent.
+	DefaultJaegerAgentPort = "6831"
+
+	// DefaultMetricsPath is the default path of Prometheus metrics endpoint.
+	DefaultMetricsPath = "/metrics"
+
+	// KMSTypeNone disables envelope encryption, secrets are encrypted directly by the secrets keyring.
+	KMSTypeNone = ""
+
+	// KMSTypeLocal enables envelope encryption with data keys wrapped by the local keyring.
+	KMSTypeLocal = "local"
+)
+
+// ErrInvalidConfig will be raised when configuration does not pass validation.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Config represents the bankingd configuration. Values are taken from the configuration file and then overridden by
+// environment variables.
+type Config struct {
+	// HTTP is the HTTP server configuration.
+	HTTP HTTPConfig `yaml:"http"`
+
+	// Percona is the database configuration.
+	Percona PerconaConfig `yaml:"percona"`
+
+	// Tokens is the access and refresh tokens configuration.
+	Tokens TokensConfig `yaml:"tokens"`
+
+	// Secrets is the sensitive data encryption configuration.
+	Secrets SecretsConfig `yaml:"secrets"`
+
+	// Jaeger is the tracing configuration.
+	Jaeger JaegerConfig `yaml:"jaeger"`
+
+	// Metrics is the Prometheus metrics configuration.
+	Metrics MetricsConfig `yaml:"metrics"`
+
+	// Workers is the background jobs configuration.
+	Workers WorkersConfig `yaml:"workers"`
+
+	// Log is the logging configuration.
+	Log LogConfig `yaml:"log"`
+}
+
+// HTTPConfig represents the HTTP server configuration.
+type HTTPConfig struct {
+	// Address is the network address which server listens on.
+	Address string `yaml:"address"`
+
+	// ReadTimeout is the maximum duration for reading the entire request, including the body.
+	ReadTimeout time.Duration `yaml:"read_timeout"`
+
+	// ReadHeaderTimeout is the amount of time allowed to read request headers.
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+
+	// WriteTimeout is the maximum duration before timing out writes of the response.
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+
+	// IdleTimeout is the maximum amount of time to wait for the next request when keep-alives are enabled.
+	IdleTimeout time.Duration `yaml:"idle_timeout"`
+
+	// ShutdownTimeout is the maximum time for finishing active requests after SIGTERM.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	// DrainDelay is the time between readiness check failure and closing listeners after SIGTERM.
+	DrainDelay time.Duration `yaml:"drain_delay"`
+
+	// Realm is the protection space which is returned in WWW-Authenticate header.
+	Realm string `yaml:"realm"`
+
+	// EnableProfiler mounts unauthenticated pprof handlers, it must be enabled only for troubleshooting.
+	EnableProfiler bool `yaml:"enable_profiler"`
+
+	// TrustedProxies is the list of CIDRs of proxies which X-Forwarded-For and X-Real-IP headers are trusted from.
+	// Client IP address is taken from the connection when it is empty.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+
+	// TLS is the TLS configuration, server listens for plain HTTP when certificate is not set.
+	TLS TLSConfig `yaml:"tls"`
+}
+
+// TLSConfig represents the HTTP server TLS configuration.
+type TLSConfig struct {
+	// CertFile is the path to PEM certificate chain file.
+	CertFile string `yaml:"cert_file"`
+
+	// KeyFile is the path to PEM private key file.
+	KeyFile string `yaml:"key_file"`
+
+	// ReloadInterval is the minimum interval between checks of certificate files changes.
+	ReloadInterval time.Duration `yaml:"reload_interval"`
+
+	// MinVersion is the minimum TLS version that is acceptable (1.2 or 1.3).
+	MinVersion string `yaml:"min_version"`
+
+	// ClientCAFile is the path to PEM bundle of CAs which client certificates of internal callers are verified with.
+	ClientCAFile string `yaml:"client_ca_file"`
+
+	// RequireClientCert rejects connections without verified client certificate.
+	RequireClientCert bool `yaml:"require_client_cert"`
+}
+
+// tlsVersions maps the configuration value to TLS version.
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// PerconaConfig represents the database configuration.
+type PerconaConfig struct {
+	// DSN is the data source name.
+	DSN string `yaml:"dsn"`
+
+	// ConnMaxLifetime is the maximum amount of time a connection may be reused.
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+
+	// ConnMaxIdleTime is the maximum amount of time a connection may be idle.
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+
+	// MaxIdleConns is the maximum number of connections in the idle connection pool.
+	MaxIdleConns int `yaml:"max_idle_conns"`
+
+	// MaxOpenConns is the maximum number of open connections to the database.
+	MaxOpenConns int `yaml:"max_open_conns"`
+}
+
+// TokensConfig represents the access and refresh tokens configuration.
+type TokensConfig struct {
+	// SignatureAlgorithm is the name of algorithm which tokens are signed with (e.g. RS512, ES256, EdDSA).
+	SignatureAlgorithm string `yaml:"signature_algorithm"`
+
+	// Issuer is the value of iss claim.
+	Issuer string `yaml:"issuer"`
+
+	// Audience is the value of aud claim.
+	Audience string `yaml:"audience"`
+
+	// ClockSkew is the time duration which is allowed between clocks of token issuer and token parser.
+	ClockSkew time.Duration `yaml:"clock_skew"`
+
+	// Access is the access token configuration.
+	Access TokenConfig `yaml:"access"`
+
+	// Refresh is the refresh token configuration.
+	Refresh TokenConfig `yaml:"refresh"`
+
+	// PasswordReset is the password reset token configuration.
+	PasswordReset PasswordResetTokenConfig `yaml:"password_reset"`
+}
+
+// PasswordResetTokenConfig represents the password reset token configuration.
+type PasswordResetTokenConfig struct {
+	// Lifetime is the time which after password reset token could not be used.
+	Lifetime time.Duration `yaml:"lifetime"`
+}
+
+// TokenConfig represents the single token type configuration.
+type TokenConfig struct {
+	// KeyFiles is the list of PEM or JWK key files. The first one is used for signing, the others are retiring keys
+	// which are used only for verification.
+	KeyFiles []string `yaml:"key_files"`
+
+	// Lifetime is the time which after token will be invalid.
+	Lifetime time.Duration `yaml:"lifetime"`
+}
+
+// SecretsConfig represents the sensitive data encryption configuration.
+type SecretsConfig struct {
+	// KeyFile is the path to the file with hex encoded AES-256 key. Secrets are encrypted by this key. When envelope
+	// encryption is enabled, it is used only for decrypting secrets which were stored before, and the re-encryption
+	// job moves them into envelopes.
+	KeyFile string `yaml:"key_file"`
+
+	// RetiringKeyFiles is the list of files with keys which were used before rotation. They are used only for
+	// decryption until every stored secret is re-encrypted by the key from KeyFile.
+	RetiringKeyFiles []string `yaml:"retiring_key_files"`
+
+	// BlindIndexKeyFile is the path to the file with hex encoded key of blind indexes which allow looking encrypted
+	// columns up by equality. It must differ from encryption keys and could not be rotated.
+	BlindIndexKeyFile string `yaml:"blind_index_key_file"`
+
+	// KMS is the key management service configuration for envelope encryption.
+	KMS KMSConfig `yaml:"kms"`
+}
+
+// KMSConfig represents the key management service configuration. Every secret is encrypted by its own data key, and
+// data key is wrapped by the key encryption key which is owned by the key management service.
+type KMSConfig struct {
+	// Type is the key management service type. Empty type disables envelope encryption.
+	Type string `yaml:"type"`
+
+	// KeyFiles is the list of files with hex encoded key encryption keys for the local key management service. The
+	// first one wraps new data keys, the others are retiring keys which only unwrap data keys.
+	KeyFiles []string `yaml:"key_files"`
+}
+
+// JaegerConfig represents the tracing configuration.
+type JaegerConfig struct {
+	// AgentHost is the host of Jaeger agent.
+	AgentHost string `yaml:"agent_host"`
+
+	// AgentPort is the port of Jaeger agent.
+	AgentPort string `yaml:"agent_port"`
+}
+
+// WorkersConfig represents the background jobs configuration.
+type WorkersConfig struct {
+	// TokenReaper is the expired refresh tokens removal job configuration.
+	TokenReaper TokenReaperConfig `yaml:"token_reaper"`
+
+	// SecretReencryption is the stored secrets re-encryption job configuration.
+	SecretReencryption SecretReencryptionConfig `yaml:"secret_reencryption"`
+
+	// ColumnEncryption is the plaintext column values encryption job configuration.
+	ColumnEncryption ColumnEncryptionConfig `yaml:"column_encryption"`
+}
+
+// TokenReaperConfig represents the expired refresh tokens removal job configuration.
+type TokenReaperConfig struct {
+	// Enabled runs the job on this replica, it still runs only while replica holds the lease.
+	Enabled bool `yaml:"enabled"`
+
+	// Interval is the time between job runs.
+	Interval time.Duration `yaml:"interval"`
+
+	// BatchSize is the number of tokens which are removed at once.
+	BatchSize int `yaml:"batch_size"`
+}
+
+// SecretReencryptionConfig represents the stored secrets re-encryption job configuration.
+type SecretReencryptionConfig struct {
+	// Enabled runs the job on this replica, it still runs only while replica holds the lease.
+	Enabled bool `yaml:"enabled"`
+
+	// Interval is the time between job runs.
+	Interval time.Duration `yaml:"interval"`
+
+	// BatchSize is the number of rows which are read at once.
+	BatchSize int `yaml:"batch_size"`
+}
+
+// ColumnEncryptionConfig represents the plaintext column values encryption job configuration.
+type ColumnEncryptionConfig struct {
+	// Enabled runs the job on this replica, it still runs only while replica holds the lease.
+	Enabled bool `yaml:"enabled"`
+
+	// Interval is the time between job runs.
+	Interval time.Duration `yaml:"interval"`
+
+	// BatchSize is the number of rows which are read at once.
+	BatchSize int `yaml:"batch_size"`
+}
+
+// MetricsConfig represents the Prometheus metrics configuration.
+type MetricsConfig struct {
+	// Path is the path of metrics endpoint.
+	Path string `yaml:"path"`
+}
+
+// LogConfig represents the logging configuration.
+type LogConfig struct {
+	// Development enables human-readable output and debug level.
+	Development bool `yaml:"development"`
+
+	// Redaction is the policy of writing sensitive information to logs and traces: redacted, fingerprint or full.
+	// Full policy is allowed only in development.
+	Redaction string `yaml:"redaction"`
+}
+
+// NewConfig returns a new Config instance with default values.
+func NewConfig() *Config {
+	return &Config{
+		HTTP: HTTPConfig{
+			Address:           DefaultHTTPAddress,
+			ReadTimeout:       http.DefaultReadTimeout,
+			ReadHeaderTimeout: http.DefaultReadHeaderTimeout,
+			WriteTimeout:      http.DefaultWriteTimeout,
+			IdleTimeout:       http.DefaultIdleTimeout,
+			ShutdownTimeout:   DefaultShutdownTimeout,
+			DrainDelay:        http.DefaultDrainDelay,
+			Realm:             v1.DefaultRealm,
+			EnableProfiler:    false,
+			TrustedProxies:    nil,
+			TLS: TLSConfig{
+				CertFile:          "",
+				KeyFile:           "",
+				ReloadInterval:    http.DefaultCertificateReloadInterval,
+				MinVersion:        "1.2",
+				ClientCAFile:      "",
+				RequireClientCert: false,
+			},
+		},
+		Percona: PerconaConfig{
+			DSN:             "",
+			ConnMaxLifetime: percona.DefaultConnMaxLifetime,
+			ConnMaxIdleTime: percona.DefaultConnMaxIdleTime,
+			MaxIdleConns:    percona.DefaultMaxIdleConns,
+			MaxOpenConns:    percona.DefaultMaxOpenConns,
+		},
+		Tokens: TokensConfig{
+			SignatureAlgorithm: "RS512",
+			Issuer:             "",
+			Audience:           "",
+			ClockSkew:          jwx.DefaultClockSkew,
+			Access: TokenConfig{
+				KeyFiles: nil,
+				Lifetime: jwx.DefaultAccessTokenExpiresIn,
+			},
+			Refresh: TokenConfig{
+				KeyFiles: nil,
+				Lifetime: jwx.DefaultRefreshTokenExpiresIn,
+			},
+			PasswordReset: PasswordResetTokenConfig{
+				Lifetime: auth.DefaultResetTokenLifetime,
+			},
+		},
+		Secrets: SecretsConfig{
+			KeyFile:           "",
+			RetiringKeyFiles:  nil,
+			BlindIndexKeyFile: "",
+			KMS: KMSConfig{
+				Type:     KMSTypeNone,
+				KeyFiles: nil,
+			},
+		},
+		Jaeger: JaegerConfig{
+			AgentHost: DefaultJaegerAgentHost,
+			AgentPort: DefaultJaegerAgentPort,
+		},
+		Metrics: MetricsConfig{
+			Path: DefaultMetricsPath,
+		},
+		Workers: WorkersConfig{
+			TokenReaper: TokenReaperConfig{
+				Enabled:   true,
+				Interval:  worker.DefaultInterval,
+				BatchSize: worker.DefaultReaperBatchSize,
+			},
+			SecretReencryption: SecretReencryptionConfig{
+				Enabled:   true,
+				Interval:  worker.DefaultInterval,
+				BatchSize: worker.DefaultReencryptionBatchSize,
+			},
+			ColumnEncryption: ColumnEncryptionConfig{
+				Enabled:   true,
+				Interval:  worker.DefaultInterval,
+				BatchSize: worker.DefaultEncryptionBatchSize,
+			},
+		},
+		Log: LogConfig{
+			Development: false,
+			Redaction:   redact.PolicyRedacted.String(),
+		},
+	}
+}
+
+// LoadConfig reads configuration file, if path is not empty, and then applies environment variables.
+func LoadConfig(path string, lookupEnv func(key string) (string, bool)) (*Config, error) {
+	cfg := NewConfig()
+
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "load config")
+		}
+
+		defer file.Close()
+
+		decoder := yaml.NewDecoder(file)
+		decoder.KnownFields(true)
+
+		if err = decoder.Decode(cfg); err != nil {
+			return nil, errors.Wrap(err, "load config")
+		}
+	}
+
+	for _, binding := range envBindings {
+		value, ok := lookupEnv(binding.name)
+		if !ok {
+			continue
+		}
+
+		if err := binding.apply(cfg, value); err != nil {
+			return nil, errors.Wrapf(err, "load config: %s", binding.name)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "load config")
+	}
+
+	return cfg, nil
+}
+
+// envBinding describes how environment variable overrides configuration value.
+type envBinding struct {
+	name  string
+	apply func(cfg *Config, value string) error
+}
+
+var envBindings = []envBinding{
+	{"BANKINGD_HTTP_ADDRESS", stringEnv(func(cfg *Config) *string { return &cfg.HTTP.Address })},
+	{"BANKINGD_HTTP_READ_TIMEOUT", durationEnv(func(cfg *Config) *time.Duration { return &cfg.HTTP.ReadTimeout })},
+	{"BANKINGD_HTTP_READ_HEADER_TIMEOUT", durationEnv(func(cfg *Config) *time.Duration {
+		return &cfg.HTTP.ReadHeaderTimeout
+	})},
+	{"BANKINGD_HTTP_WRITE_TIMEOUT", durationEnv(func(cfg *Config) *time.Duration { return &cfg.HTTP.WriteTimeout })},
+	{"BANKINGD_HTTP_IDLE_TIMEOUT", durationEnv(func(cfg *Config) *time.Duration { return &cfg.HTTP.IdleTimeout })},
+	{"BANKINGD_HTTP_SHUTDOWN_TIMEOUT", durationEnv(func(cfg *Config) *time.Duration {
+		return &cfg.HTTP.ShutdownTimeout
+	})},
+	{"BANKINGD_HTTP_DRAIN_DELAY", durationEnv(func(cfg *Config) *time.Duration { return &cfg.HTTP.DrainDelay })},
+	{"BANKINGD_HTTP_ENABLE_PROFILER", boolEnv(func(cfg *Config) *bool { return &cfg.HTTP.EnableProfiler })},
+	{"BANKINGD_HTTP_TRUSTED_PROXIES", listEnv(func(cfg *Config) *[]string { return &cfg.HTTP.TrustedProxies })},
+	{"BANKINGD_HTTP_TLS_CERT_FILE", stringEnv(func(cfg *Config) *string { return &cfg.HTTP.TLS.CertFile })},
+	{"BANKINGD_HTTP_TLS_KEY_FILE", stringEnv(func(cfg *Config) *string { return &cfg.HTTP.TLS.KeyFile })},
+	{"BANKINGD_HTTP_TLS_MIN_VERSION", stringEnv(func(cfg *Config) *string { return &cfg.HTTP.TLS.MinVersion })},
+	{"BANKINGD_HTTP_TLS_CLIENT_CA_FILE", stringEnv(func(cfg *Config) *string { return &cfg.HTTP.TLS.ClientCAFile })},
+	{"BANKINGD_HTTP_TLS_REQUIRE_CLIENT_CERT", boolEnv(func(cfg *Config) *bool {
+		return &cfg.HTTP.TLS.RequireClientCert
+	})},
+	{PerconaDSNEnv, stringEnv(func(cfg *Config) *string { return &cfg.Percona.DSN })},
+	{"BANKINGD_PERCONA_MAX_OPEN_CONNS", intEnv(func(cfg *Config) *int { return &cfg.Percona.MaxOpenConns })},
+	{"BANKINGD_PERCONA_MAX_IDLE_CONNS", intEnv(func(cfg *Config) *int { return &cfg.Percona.MaxIdleConns })},
+	{"BANKINGD_TOKENS_SIGNATURE_ALGORITHM", stringEnv(func(cfg *Config) *string {
+		return &cfg.Tokens.SignatureAlgorithm
+	})},
+	{"BANKINGD_TOKENS_ISSUER", stringEnv(func(cfg *Config) *string { return &cfg.Tokens.Issuer })},
+	{"BANKINGD_TOKENS_AUDIENCE", stringEnv(func(cfg *Config) *string { return &cfg.Tokens.Audience })},
+	{"BANKINGD_ACCESS_TOKEN_KEY_FILES", listEnv(func(cfg *Config) *[]string { return &cfg.Tokens.Access.KeyFiles })},
+	{"BANKINGD_ACCESS_TOKEN_LIFETIME", durationEnv(func(cfg *Config) *time.Duration {
+		return &cfg.Tokens.Access.Lifetime
+	})},
+	{"BANKINGD_REFRESH_TOKEN_KEY_FILES", listEnv(func(cfg *Config) *[]string { return &cfg.Tokens.Refresh.KeyFiles })},
+	{"BANKINGD_REFRESH_TOKEN_LIFETIME", durationEnv(func(cfg *Config) *time.Duration {
+		return &cfg.Tokens.Refresh.Lifetime
+	})},
+	{"BANKINGD_PASSWORD_RESET_TOKEN_LIFETIME", durationEnv(func(cfg *Config) *time.Duration {
+		return &cfg.Tokens.PasswordReset.Lifetime
+	})},
+	{"BANKINGD_SECRETS_KEY_FILE", stringEnv(func(cfg *Config) *string { return &cfg.Secrets.KeyFile })},
+	{"BANKINGD_SECRETS_RETIRING_KEY_FILES", listEnv(func(cfg *Config) *[]string {
+		return &cfg.Secrets.RetiringKeyFiles
+	})},
+	{"BANKINGD_SECRETS_BLIND_INDEX_KEY_FILE", stringEnv(func(cfg *Config) *string {
+		return &cfg.Secrets.BlindIndexKeyFile
+	})},
+	{"BANKINGD_SECRETS_KMS_TYPE", stringEnv(func(cfg *Config) *string { return &cfg.Secrets.KMS.Type })},
+	{"BANKINGD_SECRETS_KMS_KEY_FILES", listEnv(func(cfg *Config) *[]string { return &cfg.Secrets.KMS.KeyFiles })},
+	{"BANKINGD_JAEGER_AGENT_HOST", stringEnv(func(cfg *Config) *string { return &cfg.Jaeger.AgentHost })},
+	{"BANKINGD_JAEGER_AGENT_PORT", stringEnv(func(cfg *Config) *string { return &cfg.Jaeger.AgentPort })},
+	{"BANKINGD_TOKEN_REAPER_ENABLED", boolEnv(func(cfg *Config) *bool { return &cfg.Workers.TokenReaper.Enabled })},
+	{"BANKINGD_TOKEN_REAPER_INTERVAL", durationEnv(func(cfg *Config) *time.Duration {
+		return &cfg.Workers.TokenReaper.Interval
+	})},
+	{"BANKINGD_TOKEN_REAPER_BATCH_SIZE", intEnv(func(cfg *Config) *int { return &cfg.Workers.TokenReaper.BatchSize })},
+	{"BANKINGD_SECRET_REENCRYPTION_ENABLED", boolEnv(func(cfg *Config) *bool {
+		return &cfg.Workers.SecretReencryption.Enabled
+	})},
+	{"BANKINGD_SECRET_REENCRYPTION_INTERVAL", durationEnv(func(cfg *Config) *time.Duration {
+		return &cfg.Workers.SecretReencryption.Interval
+	})},
+	{"BANKINGD_SECRET_REENCRYPTION_BATCH_SIZE", intEnv(func(cfg *Config) *int {
+		return &cfg.Workers.SecretReencryption.BatchSize
+	})},
+	{"BANKINGD_COLUMN_ENCRYPTION_ENABLED", boolEnv(func(cfg *Config) *bool {
+		return &cfg.Workers.ColumnEncryption.Enabled
+	})},
+	{"BANKINGD_COLUMN_ENCRYPTION_INTERVAL", durationEnv(func(cfg *Config) *time.Duration {
+		return &cfg.Workers.ColumnEncryption.Interval
+	})},
+	{"BANKINGD_COLUMN_ENCRYPTION_BATCH_SIZE", intEnv(func(cfg *Config) *int {
+		return &cfg.Workers.ColumnEncryption.BatchSize
+	})},
+	{"BANKINGD_LOG_DEVELOPMENT", boolEnv(func(cfg *Config) *bool { return &cfg.Log.Development })},
+	{"BANKINGD_LOG_REDACTION", stringEnv(func(cfg *Config) *string { return &cfg.Log.Redaction })},
+}
+
+func stringEnv(field func(cfg *Config) *string) func(cfg *Config, value string) error {
+	return func(cfg *Config, value string) error {
+		*field(cfg) = value
+
+		return nil
+	}
+}
+
+func listEnv(field func(cfg *Config) *[]string) func(cfg *Config, value string) error {
+	return func(cfg *Config, value string) error {
+		*field(cfg) = nil
+
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*field(cfg) = append(*field(cfg), item)
+			}
+		}
+
+		return nil
+	}
+}
+
+func durationEnv(field func(cfg *Config) *time.Duration) func(cfg *Config, value string) error {
+	return func(cfg *Config, value string) (err error) {
+		*field(cfg), err = time.ParseDuration(value)
+
+		return errors.Wrap(err, "parse duration")
+	}
+}
+
+func intEnv(field func(cfg *Config) *int) func(cfg *Config, value string) error {
+	return func(cfg *Config, value string) (err error) {
+		*field(cfg), err = strconv.Atoi(value)
+
+		return errors.Wrap(err, "parse int")
+	}
+}
+
+func boolEnv(field func(cfg *Config) *bool) func(cfg *Config, value string) error {
+	return func(cfg *Config, value string) (err error) {
+		*field(cfg), err = strconv.ParseBool(value)
+
+		return errors.Wrap(err, "parse bool")
+	}
+}
+
+// Validate returns ErrInvalidConfig which describes every invalid value.
+func (cfg *Config) Validate() error {
+	var problems []string
+
+	check := func(ok bool, problem string) {
+		if !ok {
+			problems = append(problems, problem)
+		}
+	}
+
+	check(cfg.HTTP.Address != "", "http.address is required")
+	check(cfg.HTTP.ReadTimeout > 0, "http.read_timeout must be positive")
+	check(cfg.HTTP.ReadHeaderTimeout > 0, "http.read_header_timeout must be positive")
+	check(cfg.HTTP.WriteTimeout > 0, "http.write_timeout must be positive")
+	check(cfg.HTTP.IdleTimeout > 0, "http.idle_timeout must be positive")
+	check(cfg.HTTP.ShutdownTimeout > 0, "http.shutdown_timeout must be positive")
+	check(cfg.HTTP.DrainDelay >= 0 && cfg.HTTP.DrainDelay < cfg.HTTP.ShutdownTimeout,
+		"http.drain_delay must not be negative and must be less than http.shutdown_timeout")
+	check((cfg.HTTP.TLS.CertFile == "") == (cfg.HTTP.TLS.KeyFile == ""),
+		"http.tls.cert_file and http.tls.key_file must be set together")
+	check(cfg.HTTP.TLS.ClientCAFile == "" || cfg.HTTP.TLS.CertFile != "",
+		"http.tls.client_ca_file requires http.tls.cert_file")
+	check(!cfg.HTTP.TLS.RequireClientCert || cfg.HTTP.TLS.ClientCAFile != "",
+		"http.tls.require_client_cert requires http.tls.client_ca_file")
+	check(cfg.HTTP.TLS.ReloadInterval > 0, "http.tls.reload_interval must be positive")
+
+	_, err := parseCIDRs(cfg.HTTP.TrustedProxies)
+	check(err == nil, "http.trusted_proxies must contain only CIDRs")
+
+	_, ok := tlsVersions[cfg.HTTP.TLS.MinVersion]
+	check(ok, "http.tls.min_version must be 1.2 or 1.3")
+	check(cfg.Percona.DSN != "", "percona.dsn is required")
+	check(cfg.Percona.MaxOpenConns > 0, "percona.max_open_conns must be positive")
+	check(cfg.Percona.MaxIdleConns >= 0, "percona.max_idle_conns must not be negative")
+
+	_, err = jwx.ParseSignatureAlgorithm(cfg.Tokens.SignatureAlgorithm)
+	check(err == nil, "tokens.signature_algorithm is not supported")
+	check(cfg.Tokens.ClockSkew >= 0, "tokens.clock_skew must not be negative")
+	check(len(cfg.Tokens.Access.KeyFiles) != 0, "tokens.access.key_files is required")
+	check(cfg.Tokens.Access.Lifetime > 0, "tokens.access.lifetime must be positive")
+	check(len(cfg.Tokens.Refresh.KeyFiles) != 0, "tokens.refresh.key_files is required")
+	check(cfg.Tokens.Refresh.Lifetime > cfg.Tokens.Access.Lifetime,
+		"tokens.refresh.lifetime must be greater than tokens.access.lifetime")
+	check(cfg.Tokens.PasswordReset.Lifetime > 0, "tokens.password_reset.lifetime must be positive")
+	check(cfg.Secrets.KMS.Type == KMSTypeNone || cfg.Secrets.KMS.Type == KMSTypeLocal,
+		"secrets.kms.type must be empty or local")
+	check(cfg.Secrets.KeyFile != "" || cfg.Secrets.KMS.Type != KMSTypeNone,
+		"secrets.key_file is required when secrets.kms.type is empty")
+	check(len(cfg.Secrets.KMS.KeyFiles) != 0 || cfg.Secrets.KMS.Type != KMSTypeLocal,
+		"secrets.kms.key_files is required when secrets.kms.type is local")
+	check(cfg.Secrets.BlindIndexKeyFile != "", "secrets.blind_index_key_file is required")
+	check(cfg.Metrics.Path != "", "metrics.path is required")
+	check(cfg.Workers.TokenReaper.Interval > 0, "workers.token_reaper.interval must be positive")
+	check(cfg.Workers.TokenReaper.BatchSize > 0 && cfg.Workers.TokenReaper.BatchSize <= banking.MaxPageSize,
+		"workers.token_reaper.batch_size must be positive and must not exceed 100")
+	check(cfg.Workers.SecretReencryption.Interval > 0, "workers.secret_reencryption.interval must be positive")
+	check(cfg.Workers.SecretReencryption.BatchSize > 0 &&
+		cfg.Workers.SecretReencryption.BatchSize <= banking.MaxPageSize,
+		"workers.secret_reencryption.batch_size must be positive and must not exceed 100")
+	check(cfg.Workers.ColumnEncryption.Interval > 0, "workers.column_encryption.interval must be positive")
+	check(cfg.Workers.ColumnEncryption.BatchSize > 0 &&
+		cfg.Workers.ColumnEncryption.BatchSize <= banking.MaxPageSize,
+		"workers.column_encryption.batch_size must be positive and must not exceed 100")
+
+	policy, err := redact.ParsePolicy(cfg.Log.Redaction)
+	check(err == nil, "log.redaction must be redacted, fingerprint or full")
+	check(policy != redact.PolicyFull || cfg.Log.Development, "log.redaction could be full only in development")
+
+	if len(problems) != 0 {
+		return errors.Wrap(ErrInvalidConfig, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// parseCIDRs parses the list of networks in CIDR notation (e.g. 10.0.0.0/8).
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse cidrs")
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dockerConfigFile is the configuration file which is shipped with docker image.
+const dockerConfigFile = "../../scripts/docker/bankingd/config.yaml"
+
+// requiredEnv contains values which have no defaults, so configuration without them is invalid.
+var requiredEnv = map[string]string{
+	PerconaDSNEnv:                           "user:password@tcp(localhost:3306)/banking",
+	"BANKINGD_ACCESS_TOKEN_KEY_FILES":       "access.pem",
+	"BANKINGD_REFRESH_TOKEN_KEY_FILES":      "refresh.pem",
+	"BANKINGD_SECRETS_KEY_FILE":             "secret.hex",
+	"BANKINGD_SECRETS_BLIND_INDEX_KEY_FILE": "blind_index.hex",
+}
+
+func lookupEnv(env ...map[string]string) func(key string) (string, bool) {
+	return func(key string) (string, bool) {
+		for i := len(env) - 1; i >= 0; i-- {
+			if value, ok := env[i][key]; ok {
+				return value, true
+			}
+		}
+
+		return "", false
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		file string
+		env  map[string]string
+	}
+	type wants struct {
+		check func(t *testing.T, cfg *Config)
+		err   error
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "defaults",
+				enabled: true,
+			},
+			args: args{
+				file: "",
+				env:  nil,
+			},
+			wants: wants{
+				check: func(t *testing.T, cfg *Config) {
+					t.Helper()
+
+					assert.Equal(t, DefaultHTTPAddress, cfg.HTTP.Address)
+					assert.False(t, cfg.HTTP.EnableProfiler)
+					assert.Equal(t, []string{"access.pem"}, cfg.Tokens.Access.KeyFiles)
+				},
+				err: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "docker config file",
+				enabled: true,
+			},
+			args: args{
+				file: dockerConfigFile,
+				env:  map[string]string{},
+			},
+			wants: wants{
+				check: func(t *testing.T, cfg *Config) {
+					t.Helper()
+
+					assert.Equal(t, time.Second*5, cfg.HTTP.DrainDelay)
+					assert.False(t, cfg.HTTP.EnableProfiler)
+					assert.Equal(t, "secret.hex", cfg.Secrets.KeyFile, "environment overrides file")
+				},
+				err: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "environment overrides",
+				enabled: true,
+			},
+			args: args{
+				file: dockerConfigFile,
+				env: map[string]string{
+					"BANKINGD_HTTP_ADDRESS":               ":9090",
+					"BANKINGD_HTTP_READ_TIMEOUT":          "2s",
+					"BANKINGD_HTTP_ENABLE_PROFILER":       "true",
+					"BANKINGD_PERCONA_MAX_OPEN_CONNS":     "20",
+					"BANKINGD_ACCESS_TOKEN_KEY_FILES":     " current.pem, retiring.pem ,",
+					"BANKINGD_SECRETS_RETIRING_KEY_FILES": "",
+					"BANKINGD_TOKEN_REAPER_ENABLED":       "false",
+					"BANKINGD_HTTP_TRUSTED_PROXIES":       "10.0.0.0/8,2001:db8::/32",
+				},
+			},
+			wants: wants{
+				check: func(t *testing.T, cfg *Config) {
+					t.Helper()
+
+					assert.Equal(t, ":9090", cfg.HTTP.Address)
+					assert.Equal(t, time.Second*2, cfg.HTTP.ReadTimeout)
+					assert.True(t, cfg.HTTP.EnableProfiler)
+					assert.Equal(t, 20, cfg.Percona.MaxOpenConns)
+					assert.Equal(t, []string{"current.pem", "retiring.pem"}, cfg.Tokens.Access.KeyFiles)
+					assert.Empty(t, cfg.Secrets.RetiringKeyFiles)
+					assert.False(t, cfg.Workers.TokenReaper.Enabled)
+					assert.Equal(t, []string{"10.0.0.0/8", "2001:db8::/32"}, cfg.HTTP.TrustedProxies)
+				},
+				err: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "malformed bool",
+				enabled: true,
+			},
+			args: args{
+				file: "",
+				env: map[string]string{
+					"BANKINGD_HTTP_ENABLE_PROFILER": "sometimes",
+				},
+			},
+			wants: wants{
+				check: nil,
+				err:   strconv.ErrSyntax,
+			},
+		},
+		{
+			meta: meta{
+				name:    "malformed int",
+				enabled: true,
+			},
+			args: args{
+				file: "",
+				env: map[string]string{
+					"BANKINGD_PERCONA_MAX_OPEN_CONNS": "many",
+				},
+			},
+			wants: wants{
+				check: nil,
+				err:   strconv.ErrSyntax,
+			},
+		},
+		{
+			meta: meta{
+				name:    "invalid value",
+				enabled: true,
+			},
+			args: args{
+				file: "",
+				env: map[string]string{
+					"BANKINGD_HTTP_DRAIN_DELAY": "1m",
+				},
+			},
+			wants: wants{
+				check: nil,
+				err:   ErrInvalidConfig,
+			},
+		},
+		{
+			meta: meta{
+				name:    "missing file",
+				enabled: true,
+			},
+			args: args{
+				file: "missing.yaml",
+				env:  nil,
+			},
+			wants: wants{
+				check: nil,
+				err:   os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			cfg, err := LoadConfig(tt.args.file, lookupEnv(requiredEnv, tt.args.env))
+			if tt.wants.err != nil {
+				assert.True(t, errors.Is(err, tt.wants.err), err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			tt.wants.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadConfig_UnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("http:\n  enable_profiller: true\n"), 0o600))
+
+	_, err := LoadConfig(path, lookupEnv(requiredEnv))
+	assert.Error(t, err)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		modify func(cfg *Config)
+	}
+	type wants struct {
+		problems []string
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "valid",
+				enabled: true,
+			},
+			args: args{
+				modify: func(cfg *Config) {},
+			},
+			wants: wants{
+				problems: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "tls certificate without key",
+				enabled: true,
+			},
+			args: args{
+				modify: func(cfg *Config) {
+					cfg.HTTP.TLS.CertFile = "tls.crt"
+				},
+			},
+			wants: wants{
+				problems: []string{"http.tls.cert_file and http.tls.key_file must be set together"},
+			},
+		},
+		{
+			meta: meta{
+				name:    "required client certificate without client CA",
+				enabled: true,
+			},
+			args: args{
+				modify: func(cfg *Config) {
+					cfg.HTTP.TLS.CertFile, cfg.HTTP.TLS.KeyFile = "tls.crt", "tls.key"
+					cfg.HTTP.TLS.RequireClientCert = true
+				},
+			},
+			wants: wants{
+				problems: []string{"http.tls.require_client_cert requires http.tls.client_ca_file"},
+			},
+		},
+		{
+			meta: meta{
+				name:    "client CA without certificate",
+				enabled: true,
+			},
+			args: args{
+				modify: func(cfg *Config) {
+					cfg.HTTP.TLS.ClientCAFile = "ca.crt"
+				},
+			},
+			wants: wants{
+				problems: []string{"http.tls.client_ca_file requires http.tls.cert_file"},
+			},
+		},
+		{
+			meta: meta{
+				name:    "trusted proxy is not CIDR",
+				enabled: true,
+			},
+			args: args{
+				modify: func(cfg *Config) {
+					cfg.HTTP.TrustedProxies = []string{"10.0.0.0/8", "10.0.0.1"}
+				},
+			},
+			wants: wants{
+				problems: []string{"http.trusted_proxies must contain only CIDRs"},
+			},
+		},
+		{
+			meta: meta{
+				name:    "unsupported tls version",
+				enabled: true,
+			},
+			args: args{
+				modify: func(cfg *Config) {
+					cfg.HTTP.TLS.MinVersion = "1.1"
+				},
+			},
+			wants: wants{
+				problems: []string{"http.tls.min_version must be 1.2 or 1.3"},
+			},
+		},
+		{
+			meta: meta{
+				name:    "unsupported signature algorithm",
+				enabled: true,
+			},
+			args: args{
+				modify: func(cfg *Config) {
+					cfg.Tokens.SignatureAlgorithm = "none"
+				},
+			},
+			wants: wants{
+				problems: []string{"tokens.signature_algorithm is not supported"},
+			},
+		},
+		{
+			meta: meta{
+				name:    "local kms without key files",
+				enabled: true,
+			},
+			args: args{
+				modify: func(cfg *Config) {
+					cfg.Secrets.KeyFile, cfg.Secrets.KMS.Type = "", KMSTypeLocal
+				},
+			},
+			wants: wants{
+				problems: []string{"secrets.kms.key_files is required when secrets.kms.type is local"},
+			},
+		},
+		{
+			meta: meta{
+				name:    "full redaction outside development",
+				enabled: true,
+			},
+			args: args{
+				modify: func(cfg *Config) {
+					cfg.Log.Redaction = "full"
+				},
+			},
+			wants: wants{
+				problems: []string{"log.redaction could be full only in development"},
+			},
+		},
+		{
+			meta: meta{
+				name:    "every problem is reported",
+				enabled: true,
+			},
+			args: args{
+				modify: func(cfg *Config) {
+					cfg.HTTP.Address = ""
+					cfg.Tokens.Refresh.Lifetime = cfg.Tokens.Access.Lifetime
+					cfg.Workers.TokenReaper.BatchSize = 101
+				},
+			},
+			wants: wants{
+				problems: []string{
+					"http.address is required",
+					"tokens.refresh.lifetime must be greater than tokens.access.lifetime",
+					"workers.token_reaper.batch_size must be positive and must not exceed 100",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			cfg, err := LoadConfig("", lookupEnv(requiredEnv))
+			require.NoError(t, err)
+
+			tt.args.modify(cfg)
+
+			err = cfg.Validate()
+			if len(tt.wants.problems) == 0 {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.True(t, errors.Is(err, ErrInvalidConfig), err)
+			assert.Equal(t, len(tt.wants.problems), strings.Count(err.Error(), "; ")+1)
+
+			for _, problem := range tt.wants.problems {
+				assert.Contains(t, err.Error(), problem)
+			}
+		})
+	}
+}
@@ -1,20 +1,78 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
-	stdhttp "net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/morozovcookie/agat-banking/zap"
+	"github.com/morozovcookie/agat-banking/redact"
+	"github.com/pkg/errors"
 	uberzap "go.uber.org/zap"
 )
 
+const (
+	// PerconaDSNEnv is the name of environment variable which contains Percona data source name.
+	PerconaDSNEnv = "BANKINGD_PERCONA_DSN"
+
+	// MigrateCommand is the name of subcommand which manages database schema migrations.
+	MigrateCommand = "migrate"
+)
+
 func main() {
-	_, err := uberzap.NewProduction()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	if len(os.Args) > 1 && os.Args[1] == MigrateCommand {
+		if err := migrate(ctx, os.Args[2:], os.Stdout); err != nil {
+			log.Fatalln(err)
+		}
+
+		return
+	}
+
+	configFile := flag.String("config", os.Getenv(ConfigFileEnv), "path to the configuration file")
+	flag.Parse()
+
+	cfg, err := LoadConfig(*configFile, os.LookupEnv)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	logger, err := newLogger(cfg.Log)
 	if err != nil {
 		log.Fatalln(err)
 	}
+
+	defer func() {
+		_ = logger.Sync()
+	}()
+
+	app, err := NewApp(ctx, cfg, logger)
+	if err != nil {
+		logger.Fatal("init app", uberzap.Error(err))
+	}
+
+	if err = app.Run(ctx); err != nil {
+		logger.Fatal("run app", uberzap.Error(err))
+	}
 }
 
-func makeHTTPHandler(handler stdhttp.Handler, logger *uberzap.Logger) stdhttp.Handler {
-	return zap.NewHTTPHandler(handler, logger)
+// newLogger returns the logger and replaces the global redaction policy which is used by logging and tracing
+// decorators.
+func newLogger(cfg LogConfig) (*uberzap.Logger, error) {
+	policy, err := redact.ParsePolicy(cfg.Redaction)
+	if err != nil {
+		return nil, errors.Wrap(err, "init logger")
+	}
+
+	redact.SetPolicy(policy)
+
+	if cfg.Development {
+		return uberzap.NewDevelopment() // nolint:wrapcheck
+	}
+
+	return uberzap.NewProduction() // nolint:wrapcheck
 }
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	stdtime "time"
+
+	"github.com/morozovcookie/agat-banking/migrations"
+	"github.com/morozovcookie/agat-banking/percona"
+	"github.com/morozovcookie/agat-banking/time"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrUnknownMigrateAction will be raised when migrate subcommand action is not one of up, down, status or force.
+	ErrUnknownMigrateAction = errors.New("unknown migrate action, expected one of up, down, status or force")
+
+	// ErrMissingMigrationVersion will be raised when force action is called without version.
+	ErrMissingMigrationVersion = errors.New("missing migration version")
+)
+
+// migrate runs the migrate subcommand:
+//
+//	bankingd migrate [-dsn DSN] [-lock-timeout DURATION] up [N]
+//	bankingd migrate [-dsn DSN] [-lock-timeout DURATION] down [N]
+//	bankingd migrate [-dsn DSN] status
+//	bankingd migrate [-dsn DSN] force VERSION
+func migrate(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet(MigrateCommand, flag.ContinueOnError)
+
+	var (
+		dsn         = flags.String("dsn", os.Getenv(PerconaDSNEnv), "Percona data source name")
+		lockTimeout = flags.Duration("lock-timeout", percona.DefaultMigrationLockTimeout,
+			"maximum time for waiting until other instance finishes migration")
+	)
+
+	if err := flags.Parse(args); err != nil {
+		return errors.Wrap(err, "migrate")
+	}
+
+	action, number, err := parseMigrateAction(flags.Args())
+	if err != nil {
+		return errors.Wrap(err, "migrate")
+	}
+
+	client := percona.NewClient(*dsn)
+
+	if err = client.Connect(ctx); err != nil {
+		return errors.Wrap(err, "migrate")
+	}
+
+	defer client.Close(ctx)
+
+	migrator := percona.NewMigrator(client, migrations.Percona(), time.NewUTCTimer(),
+		percona.WithMigrationLockTimeout(*lockTimeout))
+
+	switch action {
+	case "up":
+		err = migrator.Up(ctx, int(number))
+	case "down":
+		err = migrator.Down(ctx, int(number))
+	case "force":
+		err = migrator.Force(ctx, number)
+	case "status":
+		err = printMigrationStatus(ctx, migrator, out)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "migrate")
+	}
+
+	return nil
+}
+
+func parseMigrateAction(args []string) (string, uint64, error) {
+	if len(args) == 0 {
+		return "", 0, ErrUnknownMigrateAction
+	}
+
+	action := args[0]
+
+	switch action {
+	case "up", "down", "force", "status":
+	default:
+		return "", 0, errors.Wrap(ErrUnknownMigrateAction, action)
+	}
+
+	if len(args) < 2 { // nolint:gomnd
+		if action == "force" {
+			return "", 0, ErrMissingMigrationVersion
+		}
+
+		return action, 0, nil
+	}
+
+	number, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "parse migrate action")
+	}
+
+	return action, number, nil
+}
+
+func printMigrationStatus(ctx context.Context, migrator *percona.Migrator, out io.Writer) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return errors.Wrap(err, "print migration status")
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) // nolint:gomnd
+
+	_, _ = fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+
+	for _, status := range statuses {
+		appliedAt := "-"
+		if !status.AppliedAt.IsZero() {
+			appliedAt = status.AppliedAt.UTC().Format(stdtime.RFC3339)
+		}
+
+		_, _ = fmt.Fprintf(w, "%06d\t%s\t%s\t%s\n", status.Version, status.Name, status.State, appliedAt)
+	}
+
+	return errors.Wrap(w.Flush(), "print migration status")
+}
@@ -8,7 +8,9 @@ import (
 	"encoding/hex"
 	"io"
 	"log"
+	"os"
 
+	"github.com/lestrrat-go/jwx/jwa"
 	banking "github.com/morozovcookie/agat-banking"
 	"github.com/morozovcookie/agat-banking/aes"
 	"github.com/morozovcookie/agat-banking/aes/rand"
@@ -18,23 +20,32 @@ import (
 	"github.com/morozovcookie/agat-banking/time"
 )
 
+const (
+	// KeyFilesCount is the number of arguments with PEM or JWK key files: the first one for access token and the second
+	// one for refresh token. If files are not passed, keys will be generated.
+	KeyFilesCount = 2
+
+	// SignatureAlgorithmArgument is the position of optional argument with signature algorithm for loaded keys.
+	SignatureAlgorithmArgument = 3
+)
+
 func main() {
-	accessTokenPrivateKey, err := stdrsa.GenerateKey(stdrand.Reader, 4096)
-	if err != nil {
-		log.Fatalln(err)
-	}
+	var (
+		accessTokenSigner, refreshTokenSigner jwx.TokenSigner
+		err                                   error
+	)
 
-	accessTokenSigner, err := rsa.NewRS512TokenSigner(accessTokenPrivateKey)
-	if err != nil {
-		log.Fatalln(err)
-	}
+	if len(os.Args) > KeyFilesCount {
+		alg := jwa.RS512.String()
+		if len(os.Args) > SignatureAlgorithmArgument {
+			alg = os.Args[SignatureAlgorithmArgument]
+		}
 
-	refreshTokenPrivateKey, err := stdrsa.GenerateKey(stdrand.Reader, 4096)
-	if err != nil {
-		log.Fatalln(err)
+		accessTokenSigner, refreshTokenSigner, err = loadSigners(alg, os.Args[1], os.Args[2])
+	} else {
+		accessTokenSigner, refreshTokenSigner, err = generateSigners()
 	}
 
-	refreshTokenSigner, err := rsa.NewRS512TokenSigner(refreshTokenPrivateKey)
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -70,6 +81,49 @@ func main() {
 	createRefreshToken(ctx, refreshTokenSigner, secretFactory, account)
 }
 
+func loadSigners(algName, accessTokenKeyFile, refreshTokenKeyFile string) (jwx.TokenSigner, jwx.TokenSigner, error) {
+	alg, err := jwx.ParseSignatureAlgorithm(algName)
+	if err != nil {
+		return nil, nil, err // nolint:wrapcheck
+	}
+
+	accessTokenKeySet, err := jwx.LoadKeySetFiles(alg, accessTokenKeyFile)
+	if err != nil {
+		return nil, nil, err // nolint:wrapcheck
+	}
+
+	refreshTokenKeySet, err := jwx.LoadKeySetFiles(alg, refreshTokenKeyFile)
+	if err != nil {
+		return nil, nil, err // nolint:wrapcheck
+	}
+
+	return accessTokenKeySet, refreshTokenKeySet, nil
+}
+
+func generateSigners() (jwx.TokenSigner, jwx.TokenSigner, error) {
+	accessTokenPrivateKey, err := stdrsa.GenerateKey(stdrand.Reader, 4096)
+	if err != nil {
+		return nil, nil, err // nolint:wrapcheck
+	}
+
+	accessTokenSigner, err := rsa.NewRS512TokenSigner(accessTokenPrivateKey)
+	if err != nil {
+		return nil, nil, err // nolint:wrapcheck
+	}
+
+	refreshTokenPrivateKey, err := stdrsa.GenerateKey(stdrand.Reader, 4096)
+	if err != nil {
+		return nil, nil, err // nolint:wrapcheck
+	}
+
+	refreshTokenSigner, err := rsa.NewRS512TokenSigner(refreshTokenPrivateKey)
+	if err != nil {
+		return nil, nil, err // nolint:wrapcheck
+	}
+
+	return accessTokenSigner, refreshTokenSigner, nil
+}
+
 func createAccessToken(
 	ctx context.Context,
 	signer jwx.TokenSigner,
@@ -0,0 +1,19 @@
+package banking
+
+import (
+	"context"
+)
+
+// HealthChecker represents a service for checking whether a dependency could serve requests.
+type HealthChecker interface {
+	// CheckHealth returns error if dependency is not healthy.
+	CheckHealth(ctx context.Context) error
+}
+
+// HealthCheckerFunc is an adapter which allows to use the ordinary function as HealthChecker.
+type HealthCheckerFunc func(ctx context.Context) error
+
+// CheckHealth calls fn(ctx).
+func (fn HealthCheckerFunc) CheckHealth(ctx context.Context) error {
+	return fn(ctx)
+}
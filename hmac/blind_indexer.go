@@ -0,0 +1,86 @@
+package hmac
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"io"
+	"strings"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+// MinKeyLength is the minimal length of blind index key.
+const MinKeyLength = 32
+
+var (
+	_ banking.BlindIndexer = (*BlindIndexer)(nil)
+
+	// ErrWrongKeyLength is the error that will be raised when length of key is less than 32 bytes.
+	ErrWrongKeyLength = errors.New("wrong key length")
+)
+
+// BlindIndexer represents a service which computes HMAC-SHA256 blind index of secret.
+//
+// Value is trimmed and lower-cased before hashing, so lookups stay case-insensitive as they were with plaintext
+// column. Key must differ from encryption keys and could not be rotated without recomputing every stored index.
+type BlindIndexer struct {
+	key []byte
+}
+
+// NewBlindIndexer returns a new BlindIndexer instance.
+func NewBlindIndexer(key io.Reader) (*BlindIndexer, error) {
+	buf := new(bytes.Buffer)
+
+	if _, err := buf.ReadFrom(key); err != nil {
+		return nil, errors.Wrap(err, "init blind indexer")
+	}
+
+	bb, err := hex.DecodeString(strings.TrimSpace(buf.String()))
+	if err != nil {
+		return nil, errors.Wrap(err, "init blind indexer")
+	}
+
+	if len(bb) < MinKeyLength {
+		return nil, errors.Wrap(ErrWrongKeyLength, "init blind indexer")
+	}
+
+	return &BlindIndexer{
+		key: bb,
+	}, nil
+}
+
+// BlindIndex returns hex encoded HMAC-SHA256 of table, column and normalized value. Every field is prefixed by its
+// length, so field boundaries could not be shifted.
+func (bi *BlindIndexer) BlindIndex(_ context.Context, table, column, value string) (string, error) {
+	mac := hmac.New(sha256.New, bi.key)
+
+	for _, field := range []string{table, column, strings.ToLower(strings.TrimSpace(value))} {
+		if err := writeField(mac, field); err != nil {
+			return "", errors.Wrap(err, "blind index")
+		}
+	}
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func writeField(h hash.Hash, field string) error {
+	var length [4]byte
+
+	binary.BigEndian.PutUint32(length[:], uint32(len(field)))
+
+	if _, err := h.Write(length[:]); err != nil {
+		return errors.Wrap(err, "write field")
+	}
+
+	if _, err := io.WriteString(h, field); err != nil {
+		return errors.Wrap(err, "write field")
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package hmac_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/morozovcookie/agat-banking/hmac"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlindIndexer_BlindIndex(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		table  string
+		column string
+		value  string
+	}
+	type wants struct {
+		same bool
+	}
+
+	base := args{
+		table:  "user_accounts",
+		column: "email_address",
+		value:  "john.doe@example.com",
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "same value",
+				enabled: true,
+			},
+			args: base,
+			wants: wants{
+				same: true,
+			},
+		},
+		{
+			meta: meta{
+				name:    "case and spaces are ignored",
+				enabled: true,
+			},
+			args: args{
+				table:  base.table,
+				column: base.column,
+				value:  " John.Doe@Example.com ",
+			},
+			wants: wants{
+				same: true,
+			},
+		},
+		{
+			meta: meta{
+				name:    "another value",
+				enabled: true,
+			},
+			args: args{
+				table:  base.table,
+				column: base.column,
+				value:  "jane.doe@example.com",
+			},
+			wants: wants{
+				same: false,
+			},
+		},
+		{
+			meta: meta{
+				name:    "another column",
+				enabled: true,
+			},
+			args: args{
+				table:  base.table,
+				column: "username",
+				value:  base.value,
+			},
+			wants: wants{
+				same: false,
+			},
+		},
+		{
+			meta: meta{
+				name:    "field boundary shifted",
+				enabled: true,
+			},
+			args: args{
+				table:  base.table + base.column,
+				column: "",
+				value:  base.value,
+			},
+			wants: wants{
+				same: false,
+			},
+		},
+	}
+
+	indexer, err := hmac.NewBlindIndexer(bytes.NewBufferString(
+		"a7681ff138d941377c55aefb4ab667b833a823e582c91317f5b5e33c09e6891e"))
+	require.NoError(t, err)
+
+	expected, err := indexer.BlindIndex(context.Background(), base.table, base.column, base.value)
+	require.NoError(t, err)
+	assert.Len(t, expected, 64)
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			actual, err := indexer.BlindIndex(context.Background(), tt.args.table, tt.args.column, tt.args.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wants.same, expected == actual)
+		})
+	}
+}
+
+func TestNewBlindIndexer(t *testing.T) {
+	_, err := hmac.NewBlindIndexer(bytes.NewBufferString("a7681ff138d941377c55aefb4ab667b8"))
+	assert.True(t, errors.Is(err, hmac.ErrWrongKeyLength))
+}
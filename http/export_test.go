@@ -0,0 +1,19 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// ClientIdentityMiddleware puts the identity of verified client into request context.
+var ClientIdentityMiddleware = clientIdentityMiddleware
+
+// TLSConfig builds TLS configuration the same way as Start does.
+func (srv *Server) TLSConfig() (*tls.Config, error) {
+	return srv.tls.config()
+}
+
+// Handler returns the server's router.
+func (srv *Server) Handler() http.Handler {
+	return srv.router
+}
@@ -0,0 +1,215 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LivenessPath is the path of endpoint which reports whether the process should be restarted.
+	LivenessPath = "/healthz"
+
+	// ReadinessPath is the path of endpoint which reports whether the process could serve requests.
+	ReadinessPath = "/readyz"
+
+	// DrainingCheckName is the name of readiness check which fails while server is shutting down.
+	DrainingCheckName = "draining"
+)
+
+// HealthStatus represents the result of health check.
+type HealthStatus string
+
+const (
+	// HealthStatusPass means that check passed.
+	HealthStatusPass HealthStatus = "pass"
+
+	// HealthStatusFail means that check failed.
+	HealthStatusFail HealthStatus = "fail"
+)
+
+// ErrDraining will be raised by readiness check while server is shutting down.
+var ErrDraining = errors.New("server is draining")
+
+// HealthCheckResult represents the result of a single check.
+type HealthCheckResult struct {
+	// Status is the check result.
+	Status HealthStatus `json:"status"`
+
+	// Latency is the check duration in milliseconds.
+	Latency float64 `json:"latency_ms"`
+
+	// Error is the reason of failure.
+	Error string `json:"error,omitempty"`
+}
+
+// HealthResponse represents a set of data that will be returned by health endpoints.
+type HealthResponse struct {
+	// Status is the overall result, it is failed if any check failed.
+	Status HealthStatus `json:"status"`
+
+	// Checks is the result of every check by its name.
+	Checks map[string]HealthCheckResult `json:"checks"`
+}
+
+type namedHealthChecker struct {
+	name    string
+	checker banking.HealthChecker
+}
+
+var _ http.Handler = (*HealthHandler)(nil)
+
+// HealthHandler represents an HTTP handler for liveness and readiness probes.
+type HealthHandler struct {
+	router chi.Router
+
+	liveness  []namedHealthChecker
+	readiness []namedHealthChecker
+	timeout   time.Duration
+
+	draining int32
+}
+
+// NewHealthHandler returns a new HealthHandler instance.
+func NewHealthHandler(timeout time.Duration) *HealthHandler {
+	h := &HealthHandler{
+		router: chi.NewRouter(),
+
+		liveness:  make([]namedHealthChecker, 0),
+		readiness: make([]namedHealthChecker, 0),
+		timeout:   timeout,
+
+		draining: 0,
+	}
+
+	h.readiness = append(h.readiness, namedHealthChecker{
+		name:    DrainingCheckName,
+		checker: banking.HealthCheckerFunc(h.checkDraining),
+	})
+
+	h.router.Get(LivenessPath, h.handleLiveness)
+	h.router.Get(ReadinessPath, h.handleReadiness)
+
+	return h
+}
+
+// AddLivenessCheck adds the check which failure means that process should be restarted. Dependencies should not be
+// checked here: their outage must not restart every instance.
+func (h *HealthHandler) AddLivenessCheck(name string, checker banking.HealthChecker) {
+	h.liveness = append(h.liveness, namedHealthChecker{
+		name:    name,
+		checker: checker,
+	})
+}
+
+// AddReadinessCheck adds the check which failure means that process could not serve requests for now.
+func (h *HealthHandler) AddReadinessCheck(name string, checker banking.HealthChecker) {
+	h.readiness = append(h.readiness, namedHealthChecker{
+		name:    name,
+		checker: checker,
+	})
+}
+
+// SetDraining makes readiness check failed, so load balancer stops routing new requests to the instance.
+func (h *HealthHandler) SetDraining() {
+	atomic.StoreInt32(&h.draining, 1)
+}
+
+func (h *HealthHandler) checkDraining(_ context.Context) error {
+	if atomic.LoadInt32(&h.draining) != 0 {
+		return ErrDraining
+	}
+
+	return nil
+}
+
+func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.router.ServeHTTP(w, r)
+}
+
+func (h *HealthHandler) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	h.handleChecks(w, r, h.liveness)
+}
+
+func (h *HealthHandler) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	h.handleChecks(w, r, h.readiness)
+}
+
+func (h *HealthHandler) handleChecks(w http.ResponseWriter, r *http.Request, checkers []namedHealthChecker) {
+	resp := runHealthChecks(r.Context(), h.timeout, checkers)
+
+	status := http.StatusOK
+	if resp.Status != HealthStatusPass {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// runHealthChecks runs checks concurrently, so the slow dependency does not delay the others.
+func runHealthChecks(ctx context.Context, timeout time.Duration, checkers []namedHealthChecker) *HealthResponse {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var (
+		results = make([]HealthCheckResult, len(checkers))
+		wg      sync.WaitGroup
+	)
+
+	for i := range checkers {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			results[i] = runHealthCheck(ctx, checkers[i].checker)
+		}(i)
+	}
+
+	wg.Wait()
+
+	resp := &HealthResponse{
+		Status: HealthStatusPass,
+		Checks: make(map[string]HealthCheckResult, len(checkers)),
+	}
+
+	for i, checker := range checkers {
+		if results[i].Status != HealthStatusPass {
+			resp.Status = HealthStatusFail
+		}
+
+		resp.Checks[checker.name] = results[i]
+	}
+
+	return resp
+}
+
+func runHealthCheck(ctx context.Context, checker banking.HealthChecker) HealthCheckResult {
+	var (
+		now = time.Now()
+		err = checker.CheckHealth(ctx)
+
+		result = HealthCheckResult{
+			Status:  HealthStatusPass,
+			Latency: float64(time.Since(now).Microseconds()) / float64(time.Millisecond/time.Microsecond),
+			Error:   "",
+		}
+	)
+
+	if err != nil {
+		result.Status, result.Error = HealthStatusFail, err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,161 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	banking "github.com/morozovcookie/agat-banking"
+	bankinghttp "github.com/morozovcookie/agat-banking/http"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthHandler_ServeHTTP(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		err      error
+		draining bool
+	}
+	type args struct {
+		path string
+	}
+	type wants struct {
+		status int
+		checks map[string]bankinghttp.HealthStatus
+	}
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "ready",
+				enabled: true,
+			},
+			fields: fields{
+				err:      nil,
+				draining: false,
+			},
+			args: args{
+				path: bankinghttp.ReadinessPath,
+			},
+			wants: wants{
+				status: http.StatusOK,
+				checks: map[string]bankinghttp.HealthStatus{
+					"dependency":                  bankinghttp.HealthStatusPass,
+					bankinghttp.DrainingCheckName: bankinghttp.HealthStatusPass,
+				},
+			},
+		},
+		{
+			meta: meta{
+				name:    "dependency is unavailable",
+				enabled: true,
+			},
+			fields: fields{
+				err:      errors.New("connection refused"),
+				draining: false,
+			},
+			args: args{
+				path: bankinghttp.ReadinessPath,
+			},
+			wants: wants{
+				status: http.StatusServiceUnavailable,
+				checks: map[string]bankinghttp.HealthStatus{
+					"dependency":                  bankinghttp.HealthStatusFail,
+					bankinghttp.DrainingCheckName: bankinghttp.HealthStatusPass,
+				},
+			},
+		},
+		{
+			meta: meta{
+				name:    "draining",
+				enabled: true,
+			},
+			fields: fields{
+				err:      nil,
+				draining: true,
+			},
+			args: args{
+				path: bankinghttp.ReadinessPath,
+			},
+			wants: wants{
+				status: http.StatusServiceUnavailable,
+				checks: map[string]bankinghttp.HealthStatus{
+					"dependency":                  bankinghttp.HealthStatusPass,
+					bankinghttp.DrainingCheckName: bankinghttp.HealthStatusFail,
+				},
+			},
+		},
+		{
+			meta: meta{
+				name:    "alive while draining",
+				enabled: true,
+			},
+			fields: fields{
+				err:      errors.New("connection refused"),
+				draining: true,
+			},
+			args: args{
+				path: bankinghttp.LivenessPath,
+			},
+			wants: wants{
+				status: http.StatusOK,
+				checks: map[string]bankinghttp.HealthStatus{
+					"process": bankinghttp.HealthStatusPass,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.meta.name, func(t *testing.T) {
+			if !test.meta.enabled {
+				t.SkipNow()
+			}
+
+			handler := bankinghttp.NewHealthHandler(time.Second)
+			handler.AddLivenessCheck("process", banking.HealthCheckerFunc(func(_ context.Context) error {
+				return nil
+			}))
+			handler.AddReadinessCheck("dependency", banking.HealthCheckerFunc(func(_ context.Context) error {
+				return test.fields.err
+			}))
+
+			if test.fields.draining {
+				handler.SetDraining()
+			}
+
+			var (
+				w = httptest.NewRecorder()
+				r = httptest.NewRequest(http.MethodGet, test.args.path, nil)
+			)
+
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, test.wants.status, w.Code)
+
+			var resp bankinghttp.HealthResponse
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+
+			checks := make(map[string]bankinghttp.HealthStatus, len(resp.Checks))
+			for name, check := range resp.Checks {
+				checks[name] = check.Status
+			}
+
+			assert.Equal(t, test.wants.checks, checks)
+		})
+	}
+}
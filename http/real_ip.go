@@ -0,0 +1,77 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// realIPMiddleware replaces the remote address with the client IP address from X-Forwarded-For or X-Real-IP header.
+// Headers are trusted only when request comes from trusted proxy, otherwise client could choose the address which it
+// is identified by (e.g. for failed sign in attempts counting).
+func (srv *Server) realIPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ip := srv.forwardedClientIP(r); ip != "" {
+			r.RemoteAddr = ip
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// forwardedClientIP returns the client IP address which was forwarded by trusted proxy, or empty string if request
+// does not come from trusted proxy. X-Forwarded-For is read from right to left, because every proxy appends the
+// address of its peer: the first address which is not trusted proxy is the client one, the addresses before it could
+// be set by client.
+func (srv *Server) forwardedClientIP(r *http.Request) string {
+	if !srv.isTrustedProxy(remoteIP(r.RemoteAddr)) {
+		return ""
+	}
+
+	if values := r.Header.Values("X-Forwarded-For"); len(values) != 0 {
+		forwarded := strings.Split(strings.Join(values, ","), ",")
+
+		var ip net.IP
+
+		for i := len(forwarded) - 1; i >= 0; i-- {
+			if ip = net.ParseIP(strings.TrimSpace(forwarded[i])); ip == nil {
+				return ""
+			}
+
+			if !srv.isTrustedProxy(ip) {
+				break
+			}
+		}
+
+		return ip.String()
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+
+	return ""
+}
+
+func (srv *Server) isTrustedProxy(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range srv.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func remoteIP(addr string) net.IP {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	return net.ParseIP(host)
+}
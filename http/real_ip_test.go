@@ -0,0 +1,169 @@
+package http_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bankinghttp "github.com/morozovcookie/agat-banking/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_RealIP(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		trustedProxies []string
+		remoteAddr     string
+		headers        map[string]string
+	}
+	type wants struct {
+		remoteAddr string
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "without trusted proxies",
+				enabled: true,
+			},
+			args: args{
+				trustedProxies: nil,
+				remoteAddr:     "10.0.0.1:4321",
+				headers:        map[string]string{"X-Forwarded-For": "203.0.113.7", "X-Real-IP": "203.0.113.8"},
+			},
+			wants: wants{
+				remoteAddr: "10.0.0.1:4321",
+			},
+		},
+		{
+			meta: meta{
+				name:    "untrusted client",
+				enabled: true,
+			},
+			args: args{
+				trustedProxies: []string{"10.0.0.0/8"},
+				remoteAddr:     "198.51.100.1:4321",
+				headers:        map[string]string{"X-Forwarded-For": "203.0.113.7", "X-Real-IP": "203.0.113.8"},
+			},
+			wants: wants{
+				remoteAddr: "198.51.100.1:4321",
+			},
+		},
+		{
+			meta: meta{
+				name:    "forwarded by trusted proxies",
+				enabled: true,
+			},
+			args: args{
+				trustedProxies: []string{"10.0.0.0/8"},
+				remoteAddr:     "10.0.0.1:4321",
+				headers:        map[string]string{"X-Forwarded-For": "203.0.113.7, 10.0.0.2"},
+			},
+			wants: wants{
+				remoteAddr: "203.0.113.7",
+			},
+		},
+		{
+			meta: meta{
+				name:    "address set by client is ignored",
+				enabled: true,
+			},
+			args: args{
+				trustedProxies: []string{"10.0.0.0/8"},
+				remoteAddr:     "10.0.0.1:4321",
+				headers:        map[string]string{"X-Forwarded-For": "192.0.2.1, 10.0.0.3, 203.0.113.7"},
+			},
+			wants: wants{
+				remoteAddr: "203.0.113.7",
+			},
+		},
+		{
+			meta: meta{
+				name:    "every address is trusted proxy",
+				enabled: true,
+			},
+			args: args{
+				trustedProxies: []string{"10.0.0.0/8"},
+				remoteAddr:     "10.0.0.1:4321",
+				headers:        map[string]string{"X-Forwarded-For": "10.0.0.3, 10.0.0.2"},
+			},
+			wants: wants{
+				remoteAddr: "10.0.0.3",
+			},
+		},
+		{
+			meta: meta{
+				name:    "malformed forwarded address",
+				enabled: true,
+			},
+			args: args{
+				trustedProxies: []string{"10.0.0.0/8"},
+				remoteAddr:     "10.0.0.1:4321",
+				headers:        map[string]string{"X-Forwarded-For": "203.0.113.7, unknown"},
+			},
+			wants: wants{
+				remoteAddr: "10.0.0.1:4321",
+			},
+		},
+		{
+			meta: meta{
+				name:    "real ip from trusted proxy",
+				enabled: true,
+			},
+			args: args{
+				trustedProxies: []string{"10.0.0.0/8", "2001:db8::/32"},
+				remoteAddr:     "[2001:db8::1]:4321",
+				headers:        map[string]string{"X-Real-IP": "203.0.113.8"},
+			},
+			wants: wants{
+				remoteAddr: "203.0.113.8",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			networks := make([]*net.IPNet, 0, len(tt.args.trustedProxies))
+
+			for _, cidr := range tt.args.trustedProxies {
+				_, network, err := net.ParseCIDR(cidr)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				networks = append(networks, network)
+			}
+
+			var remoteAddr string
+
+			srv := bankinghttp.NewServer("",
+				bankinghttp.WithTrustedProxies(networks...),
+				bankinghttp.WithHandler("/", http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+					remoteAddr = r.RemoteAddr
+				})))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.args.remoteAddr
+
+			for key, value := range tt.args.headers {
+				r.Header.Set(key, value)
+			}
+
+			srv.Handler().ServeHTTP(httptest.NewRecorder(), r)
+
+			assert.Equal(t, tt.wants.remoteAddr, remoteAddr)
+		})
+	}
+}
@@ -2,7 +2,9 @@ package http
 
 import (
 	"context"
+	"net"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -15,6 +17,13 @@ type Server struct {
 
 	router chi.Router
 	server *http.Server
+
+	health     *HealthHandler
+	drainDelay time.Duration
+
+	tls *tlsSettings
+
+	trustedProxies []*net.IPNet
 }
 
 // NewServer returns a new Server instance.
@@ -24,10 +33,30 @@ func NewServer(addr string, opts ...ServerOption) *Server {
 		router: chi.NewRouter(),
 
 		server: nil,
+
+		health:     NewHealthHandler(DefaultHealthCheckTimeout),
+		drainDelay: DefaultDrainDelay,
+
+		tls: &tlsSettings{
+			certFile: "",
+			keyFile:  "",
+
+			reloadInterval: DefaultCertificateReloadInterval,
+			errorHandler:   func(ctx context.Context, err error) {},
+
+			minVersion:   DefaultTLSMinVersion,
+			cipherSuites: DefaultCipherSuites,
+
+			clientCAFile: "",
+			clientAuth:   DefaultClientAuth,
+		},
+
+		trustedProxies: nil,
 	}
 
-	srv.router.Use(middleware.RealIP, middleware.RequestID)
-	srv.router.Mount("/debug", middleware.Profiler())
+	srv.router.Use(srv.realIPMiddleware, middleware.RequestID, clientIdentityMiddleware)
+	srv.router.Handle(LivenessPath, srv.health)
+	srv.router.Handle(ReadinessPath, srv.health)
 
 	srv.server = &http.Server{
 		Addr:              addr,
@@ -53,17 +82,47 @@ func NewServer(addr string, opts ...ServerOption) *Server {
 }
 
 // Start listens on the TCP network address srv.Addr and then calls Serve to handle requests on incoming connections.
-// Accepted connections are configured to enable TCP keep-alives.
+// Accepted connections are configured to enable TCP keep-alives. Connections are served over TLS when certificate is
+// configured.
 func (srv *Server) Start() error {
-	if err := srv.server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+	if !srv.tls.enabled() {
+		if err := srv.server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return errors.Wrap(err, "start http server")
+		}
+
+		return nil
+	}
+
+	cfg, err := srv.tls.config()
+	if err != nil {
+		return errors.Wrap(err, "start http server")
+	}
+
+	srv.server.TLSConfig = cfg
+
+	// Certificate is returned by TLSConfig.GetCertificate, so files must not be passed here.
+	if err = srv.server.ListenAndServeTLS("", ""); !errors.Is(err, http.ErrServerClosed) {
 		return errors.Wrap(err, "start http server")
 	}
 
 	return nil
 }
 
-// Shutdown gracefully shuts down the server without interrupting any active connections.
+// Shutdown gracefully shuts down the server without interrupting any active connections. Readiness check fails
+// during the drain delay before listeners are closed, so load balancer has time to stop routing new requests.
 func (srv *Server) Shutdown(ctx context.Context) error {
+	srv.health.SetDraining()
+
+	if srv.drainDelay > 0 {
+		timer := time.NewTimer(srv.drainDelay)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
 	if err := srv.server.Shutdown(ctx); err != nil {
 		return errors.Wrap(err, "shutdown http server")
 	}
@@ -1,8 +1,14 @@
 package http
 
 import (
+	"context"
+	"crypto/tls"
+	"net"
 	"net/http"
 	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	banking "github.com/morozovcookie/agat-banking"
 )
 
 // ServerOption is the option for configure Server object.
@@ -75,3 +81,118 @@ func WithHandler(pattern string, handler http.Handler) ServerOption {
 		server.router.Mount(pattern, handler)
 	})
 }
+
+// WithTrustedProxies sets up the networks of proxies which X-Forwarded-For and X-Real-IP headers are trusted from.
+// Client IP address is taken from the connection when there are no trusted proxies.
+func WithTrustedProxies(networks ...*net.IPNet) ServerOption {
+	return serverOptionFunc(func(server *Server) {
+		server.trustedProxies = networks
+	})
+}
+
+// ProfilerPathPrefix is the path prefix of pprof handlers.
+const ProfilerPathPrefix = "/debug"
+
+// WithProfiler mounts pprof handlers to server's router. Handlers are not protected by authentication, so profiler
+// must be enabled only when server is not reachable from untrusted networks.
+func WithProfiler() ServerOption {
+	return serverOptionFunc(func(server *Server) {
+		server.router.Mount(ProfilerPathPrefix, middleware.Profiler())
+	})
+}
+
+// DefaultHealthCheckTimeout is the default maximum duration of health checks for a single probe request.
+const DefaultHealthCheckTimeout = time.Second
+
+// WithHealthCheckTimeout sets up the maximum duration of health checks for a single probe request.
+func WithHealthCheckTimeout(timeout time.Duration) ServerOption {
+	return serverOptionFunc(func(server *Server) {
+		server.health.timeout = timeout
+	})
+}
+
+// WithLivenessCheck adds the check to liveness probe.
+func WithLivenessCheck(name string, checker banking.HealthChecker) ServerOption {
+	return serverOptionFunc(func(server *Server) {
+		server.health.AddLivenessCheck(name, checker)
+	})
+}
+
+// WithReadinessCheck adds the check to readiness probe.
+func WithReadinessCheck(name string, checker banking.HealthChecker) ServerOption {
+	return serverOptionFunc(func(server *Server) {
+		server.health.AddReadinessCheck(name, checker)
+	})
+}
+
+// DefaultDrainDelay is the default duration between readiness check failure and closing listeners on shutdown.
+const DefaultDrainDelay = time.Second * 5
+
+// WithDrainDelay sets up the duration between readiness check failure and closing listeners on shutdown.
+func WithDrainDelay(delay time.Duration) ServerOption {
+	return serverOptionFunc(func(server *Server) {
+		server.drainDelay = delay
+	})
+}
+
+// WithTLSCertificate enables TLS with certificate and key from PEM files. Files are reloaded when they are changed.
+func WithTLSCertificate(certFile, keyFile string) ServerOption {
+	return serverOptionFunc(func(server *Server) {
+		server.tls.certFile, server.tls.keyFile = certFile, keyFile
+	})
+}
+
+// DefaultCertificateReloadInterval is the default minimum interval between checks of certificate files changes.
+const DefaultCertificateReloadInterval = time.Minute
+
+// WithCertificateReloadInterval sets up the minimum interval between checks of certificate files changes.
+func WithCertificateReloadInterval(interval time.Duration) ServerOption {
+	return serverOptionFunc(func(server *Server) {
+		server.tls.reloadInterval = interval
+	})
+}
+
+// WithCertificateErrorHandler sets up the function which is called with errors of certificate reload. The previous
+// certificate is served in that case, so the error could not be returned to anyone.
+func WithCertificateErrorHandler(fn func(ctx context.Context, err error)) ServerOption {
+	return serverOptionFunc(func(server *Server) {
+		server.tls.errorHandler = fn
+	})
+}
+
+// DefaultTLSMinVersion is the default minimum TLS version that is acceptable.
+const DefaultTLSMinVersion = tls.VersionTLS12
+
+// WithTLSMinVersion sets up the minimum TLS version that is acceptable.
+func WithTLSMinVersion(version uint16) ServerOption {
+	return serverOptionFunc(func(server *Server) {
+		server.tls.minVersion = version
+	})
+}
+
+// WithTLSCipherSuites sets up the list of enabled TLS 1.2 cipher suites.
+func WithTLSCipherSuites(suites ...uint16) ServerOption {
+	return serverOptionFunc(func(server *Server) {
+		server.tls.cipherSuites = suites
+	})
+}
+
+// DefaultClientAuth is the default policy for TLS client authentication when client CA bundle is configured. Client
+// certificate is optional, because only internal service callers have it.
+const DefaultClientAuth = tls.VerifyClientCertIfGiven
+
+// WithClientCAFile enables verification of client certificates against CA bundle from PEM file. Identity of verified
+// client could be retrieved with banking.ClientIdentityFromContext.
+func WithClientCAFile(caFile string) ServerOption {
+	return serverOptionFunc(func(server *Server) {
+		server.tls.clientCAFile = caFile
+	})
+}
+
+// WithClientAuth sets up the policy for TLS client authentication (e.g. tls.RequireAndVerifyClientCert for internal
+// only listener).
+func WithClientAuth(auth tls.ClientAuthType) ServerOption {
+	return serverOptionFunc(func(server *Server) {
+		server.tls.clientAuth = auth
+	})
+}
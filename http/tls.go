@@ -0,0 +1,208 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+// ErrNoClientCACertificates will be raised when client CA bundle does not contain any PEM certificate.
+var ErrNoClientCACertificates = errors.New("no client ca certificates")
+
+// certificateReloader loads the certificate and key pair and reloads it when one of the files is changed, so the
+// certificate could be rotated without restart.
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+
+	checkInterval time.Duration
+	errorHandler  func(ctx context.Context, err error)
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+	checkedAt   time.Time
+}
+
+func newCertificateReloader(
+	certFile string,
+	keyFile string,
+	checkInterval time.Duration,
+	errorHandler func(ctx context.Context, err error),
+) (
+	*certificateReloader,
+	error,
+) {
+	reloader := &certificateReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+
+		checkInterval: checkInterval,
+		errorHandler:  errorHandler,
+
+		mu:          sync.RWMutex{},
+		certificate: nil,
+		certModTime: time.Time{},
+		keyModTime:  time.Time{},
+		checkedAt:   time.Time{},
+	}
+
+	if err := reloader.reload(time.Now()); err != nil {
+		return nil, errors.Wrap(err, "init certificate reloader")
+	}
+
+	return reloader, nil
+}
+
+// GetCertificate returns the current certificate. Files are checked not often than once per check interval. The
+// previous certificate is kept if the new one could not be loaded (e.g. key file is not written yet), the error is
+// passed to error handler, because handshake must not fail.
+func (cr *certificateReloader) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	now := time.Now()
+
+	cr.mu.RLock()
+	certificate, checkedAt := cr.certificate, cr.checkedAt
+	cr.mu.RUnlock()
+
+	if now.Sub(checkedAt) < cr.checkInterval {
+		return certificate, nil
+	}
+
+	if err := cr.reload(now); err != nil {
+		cr.errorHandler(hello.Context(), err)
+	}
+
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	return cr.certificate, nil
+}
+
+func (cr *certificateReloader) reload(now time.Time) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	cr.checkedAt = now
+
+	certInfo, err := os.Stat(cr.certFile)
+	if err != nil {
+		return errors.Wrap(err, "reload certificate")
+	}
+
+	keyInfo, err := os.Stat(cr.keyFile)
+	if err != nil {
+		return errors.Wrap(err, "reload certificate")
+	}
+
+	if cr.certificate != nil && certInfo.ModTime().Equal(cr.certModTime) && keyInfo.ModTime().Equal(cr.keyModTime) {
+		return nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(cr.certFile, cr.keyFile)
+	if err != nil {
+		return errors.Wrap(err, "reload certificate")
+	}
+
+	cr.certificate, cr.certModTime, cr.keyModTime = &certificate, certInfo.ModTime(), keyInfo.ModTime()
+
+	return nil
+}
+
+// DefaultCipherSuites is the list of cipher suites which provide forward secrecy and authenticated encryption. It
+// affects TLS 1.2 only: TLS 1.3 cipher suites are not configurable.
+var DefaultCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// tlsSettings describes TLS configuration which is collected by ServerOption and built on server start, so file
+// errors could be returned to caller.
+type tlsSettings struct {
+	certFile string
+	keyFile  string
+
+	reloadInterval time.Duration
+	errorHandler   func(ctx context.Context, err error)
+
+	minVersion   uint16
+	cipherSuites []uint16
+
+	clientCAFile string
+	clientAuth   tls.ClientAuthType
+}
+
+func (s *tlsSettings) enabled() bool {
+	return s.certFile != "" || s.keyFile != ""
+}
+
+func (s *tlsSettings) config() (*tls.Config, error) {
+	reloader, err := newCertificateReloader(s.certFile, s.keyFile, s.reloadInterval, s.errorHandler)
+	if err != nil {
+		return nil, errors.Wrap(err, "build tls config")
+	}
+
+	cfg := &tls.Config{ // nolint:exhaustivestruct
+		GetCertificate:           reloader.GetCertificate,
+		MinVersion:               s.minVersion,
+		CipherSuites:             s.cipherSuites,
+		PreferServerCipherSuites: true,
+		ClientAuth:               tls.NoClientCert,
+	}
+
+	if s.clientCAFile == "" {
+		return cfg, nil
+	}
+
+	pem, err := os.ReadFile(s.clientCAFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "build tls config")
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.Wrap(ErrNoClientCACertificates, "build tls config")
+	}
+
+	cfg.ClientCAs, cfg.ClientAuth = pool, s.clientAuth
+
+	return cfg, nil
+}
+
+// clientIdentityMiddleware puts the identity of client which certificate was verified against client CA bundle into
+// request context. Unverified certificates are ignored.
+func clientIdentityMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		certificate := r.TLS.VerifiedChains[0][0]
+
+		identity := &banking.ClientIdentity{
+			CommonName:   certificate.Subject.CommonName,
+			DNSNames:     certificate.DNSNames,
+			URIs:         make([]string, 0, len(certificate.URIs)),
+			SerialNumber: certificate.SerialNumber.Text(16), // nolint:gomnd
+		}
+
+		for _, uri := range certificate.URIs {
+			identity.URIs = append(identity.URIs, uri.String())
+		}
+
+		next.ServeHTTP(w, r.WithContext(banking.ContextWithClientIdentity(r.Context(), identity)))
+	})
+}
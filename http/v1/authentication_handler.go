@@ -3,8 +3,11 @@ package v1
 import (
 	"context"
 	stdjson "encoding/json"
+	"io"
 	"net/http"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	banking "github.com/morozovcookie/agat-banking"
@@ -21,6 +24,9 @@ const (
 
 	// RefreshTokenPathPrefix is the path prefix for handling refresh user token request.
 	RefreshTokenPathPrefix = "/refresh"
+
+	// RefreshTokenCookieName is the name of cookie which stores refresh token.
+	RefreshTokenCookieName = "refresh_token"
 )
 
 var _ http.Handler = (*AuthenticationHandler)(nil)
@@ -34,15 +40,21 @@ type AuthenticationHandler struct {
 }
 
 // NewAuthenticationHandler returns a new AuthenticationHandler instance.
-func NewAuthenticationHandler(authenticationService banking.AuthenticationService) *AuthenticationHandler {
+func NewAuthenticationHandler(
+	authenticationService banking.AuthenticationService,
+	secretFactory banking.SecretFactory,
+) *AuthenticationHandler {
 	h := &AuthenticationHandler{
 		Handler: NewHandler(),
 
 		authenticationService: authenticationService,
+		secretFactory:         secretFactory,
 	}
 
 	h.router.Route(BasePathPrefix, func(r chi.Router) {
 		r.Post(SignInPathPrefix, h.handleSignIn)
+		r.Post(SignOutPathPrefix, h.handleSignOut)
+		r.Post(RefreshTokenPathPrefix, h.handleRefreshToken)
 	})
 
 	return h
@@ -59,6 +71,14 @@ type SignInRequest struct {
 	useEmailAddress bool
 }
 
+var (
+	// ErrEmptyUsername will be raised when sign in request does not contain username or email address.
+	ErrEmptyUsername = errors.New("empty username")
+
+	// ErrEmptyPassword will be raised when sign in request does not contain password.
+	ErrEmptyPassword = errors.New("empty password")
+)
+
 // EmailAddressRegex is the regular expression that will be used for validating email address.
 const EmailAddressRegex = `(?:[a-z0-9!#$%&'*+/=?^_` + "`" + `{|}~-]+(?:\.[a-z0-9!#$%&'*+/=?^_` + "`" +
 	`{|}~-]+)*|"(?:[\x01-\x08\x0b\x0c\x0e-\x1f\x21\x23-\x5b\x5d-\x7f]|\\[\x01-\x09\x0b\x0c\x0e-\x7f])*")@` +
@@ -73,8 +93,16 @@ func decodeSignInRequest(_ context.Context, factory banking.SecretFactory, r *ht
 		useEmailAddress: false,
 	}
 
-	if err := stdjson.NewDecoder(r.Body).Decode(&req); err != nil {
-		return nil, errors.Wrap(err, "decode SignInRequest")
+	if err := stdjson.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, invalidRequest(errors.Wrap(err, "decode SignInRequest"))
+	}
+
+	if req.Username == "" {
+		return nil, invalidRequest(ErrEmptyUsername)
+	}
+
+	if req.Password.IsEmpty() {
+		return nil, invalidRequest(ErrEmptyPassword)
 	}
 
 	reg := regexp.MustCompile(EmailAddressRegex)
@@ -104,26 +132,205 @@ func (h *AuthenticationHandler) handleSignIn(w http.ResponseWriter, r *http.Requ
 
 	req, err := decodeSignInRequest(ctx, h.secretFactory, r)
 	if err != nil {
-		badRequestError(ctx, w)
+		encodeError(ctx, w, err)
 
 		return
 	}
 
 	accessToken, refreshToken, err := authorize(ctx, h.authenticationService, req)
 	if err != nil {
+		encodeError(ctx, w, err)
+
 		return
 	}
 
 	resp := &SignInResponse{
-		AccessToken:  json.NewSecretString(accessToken.SecretString(), h.secretFactory),
+		AccessToken:  json.NewRevealedSecretString(accessToken.SecretString(), h.secretFactory),
 		ExpiresIn:    accessToken.Expiration().Sub(accessToken.IssuedAt()).Milliseconds(),
 		TokenType:    "Bearer",
-		RefreshToken: json.NewSecretString(refreshToken.SecretString(), h.secretFactory),
+		RefreshToken: json.NewRevealedSecretString(refreshToken.SecretString(), h.secretFactory),
 	}
 
+	putRefreshTokenIntoCookie(ctx, w, r, refreshToken)
+
 	encodeResponse(ctx, w, http.StatusOK, resp)
+}
+
+// RefreshTokenRequest represents a set of data that should be passed by user for retrieving new tokens pair.
+type RefreshTokenRequest struct {
+	// RefreshToken is the long-live JWT which was retrieved during sign in or previous refresh. It could be omitted in
+	// request body, in that case it will be taken from cookie.
+	RefreshToken banking.SecretString
+}
+
+// ErrEmptyRefreshToken will be raised when refresh token was passed neither in request body nor in cookie.
+var ErrEmptyRefreshToken = errors.New("empty refresh token")
+
+func decodeRefreshTokenRequest(
+	ctx context.Context,
+	factory banking.SecretFactory,
+	r *http.Request,
+) (
+	*RefreshTokenRequest,
+	error,
+) {
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := stdjson.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		return nil, invalidRequest(errors.Wrap(err, "decode RefreshTokenRequest"))
+	}
+
+	refreshToken, err := refreshTokenFromRequest(ctx, factory, r, body.RefreshToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode RefreshTokenRequest")
+	}
+
+	return &RefreshTokenRequest{
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// refreshTokenFromRequest returns refresh token which was passed in request body or, if it is empty, in cookie.
+func refreshTokenFromRequest(
+	ctx context.Context,
+	factory banking.SecretFactory,
+	r *http.Request,
+	value string,
+) (
+	banking.SecretString,
+	error,
+) {
+	if value == "" {
+		if cookie, err := r.Cookie(RefreshTokenCookieName); err == nil {
+			value = cookie.Value
+		}
+	}
+
+	if value == "" {
+		return nil, ErrEmptyRefreshToken
+	}
+
+	refreshToken, err := factory.CreateFromDecryptedData(ctx, strings.NewReader(value))
+	if err != nil {
+		return nil, errors.Wrap(err, "refresh token from request")
+	}
+
+	return refreshToken, nil
+}
+
+// RefreshTokenResponse represents a set of data that will be returned after successfully finished refresh process.
+type RefreshTokenResponse struct {
+	// AccessToken is the short-live JWT for accessing to data API.
+	AccessToken *json.SecretString `json:"access_token"`
+
+	// ExpiresIn is the time which after AccessToken will be invalid.
+	ExpiresIn int64 `json:"expires_in"`
+
+	// TokenType is the type of token.
+	TokenType string `json:"token_type"`
+
+	// RefreshToken is the long-live JWT for refreshing tokens pair. The previous refresh token could not be used
+	// anymore.
+	RefreshToken *json.SecretString `json:"refresh_token"`
+}
+
+func (h *AuthenticationHandler) handleRefreshToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeRefreshTokenRequest(ctx, h.secretFactory, r)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	accessToken, refreshToken, err := h.authenticationService.RefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	resp := &RefreshTokenResponse{
+		AccessToken:  json.NewRevealedSecretString(accessToken.SecretString(), h.secretFactory),
+		ExpiresIn:    accessToken.Expiration().Sub(accessToken.IssuedAt()).Milliseconds(),
+		TokenType:    "Bearer",
+		RefreshToken: json.NewRevealedSecretString(refreshToken.SecretString(), h.secretFactory),
+	}
 
 	putRefreshTokenIntoCookie(ctx, w, r, refreshToken)
+
+	encodeResponse(ctx, w, http.StatusOK, resp)
+}
+
+// SignOutRequest represents a set of data that should be passed by user for finishing session.
+type SignOutRequest struct {
+	// RefreshToken is the long-live JWT of session which should be finished. It could be omitted in request body, in
+	// that case it will be taken from cookie.
+	RefreshToken banking.SecretString
+
+	// Everywhere is the flag which means that every session of the user account should be finished.
+	Everywhere bool
+}
+
+func decodeSignOutRequest(
+	ctx context.Context,
+	factory banking.SecretFactory,
+	r *http.Request,
+) (
+	*SignOutRequest,
+	error,
+) {
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+		Everywhere   bool   `json:"everywhere"`
+	}
+
+	if err := stdjson.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		return nil, invalidRequest(errors.Wrap(err, "decode SignOutRequest"))
+	}
+
+	refreshToken, err := refreshTokenFromRequest(ctx, factory, r, body.RefreshToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode SignOutRequest")
+	}
+
+	return &SignOutRequest{
+		RefreshToken: refreshToken,
+		Everywhere:   body.Everywhere,
+	}, nil
+}
+
+func (h *AuthenticationHandler) handleSignOut(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeSignOutRequest(ctx, h.secretFactory, r)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	// Client wants to finish session, so cookie should be removed even if token could not be expired.
+	removeRefreshTokenFromCookie(ctx, w, r)
+
+	if err = signOut(ctx, h.authenticationService, req); err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func signOut(ctx context.Context, svc banking.AuthenticationService, req *SignOutRequest) error {
+	if req.Everywhere {
+		return svc.SignOutEverywhere(ctx, req.RefreshToken)
+	}
+
+	return svc.SignOut(ctx, req.RefreshToken)
 }
 
 func authorize(
@@ -136,17 +343,15 @@ func authorize(
 	error,
 ) {
 	if req.useEmailAddress {
-		// return svc.AuthenticateUserByEmail(ctx, req.Username, req.Password)
-		return svc.AuthenticateUserByEmail(ctx, req.Username, nil)
+		return svc.AuthenticateUserByEmail(ctx, req.Username, req.Password)
 	}
 
-	// return svc.AuthenticateUserByUsername(ctx, req.Username, req.Password)
-	return svc.AuthenticateUserByUsername(ctx, req.Username, nil)
+	return svc.AuthenticateUserByUsername(ctx, req.Username, req.Password)
 }
 
 func putRefreshTokenIntoCookie(_ context.Context, w http.ResponseWriter, r *http.Request, refreshToken banking.Token) {
 	cookie := &http.Cookie{
-		Name:       "refresh_token",
+		Name:       RefreshTokenCookieName,
 		Value:      refreshToken.SecretString().DecryptedString(),
 		Path:       "/",
 		Domain:     r.URL.Host,
@@ -162,3 +367,22 @@ func putRefreshTokenIntoCookie(_ context.Context, w http.ResponseWriter, r *http
 
 	http.SetCookie(w, cookie)
 }
+
+func removeRefreshTokenFromCookie(_ context.Context, w http.ResponseWriter, r *http.Request) {
+	cookie := &http.Cookie{
+		Name:       RefreshTokenCookieName,
+		Value:      "",
+		Path:       "/",
+		Domain:     r.URL.Host,
+		Expires:    time.Unix(0, 0),
+		RawExpires: "",
+		MaxAge:     -1,
+		Secure:     true,
+		HttpOnly:   true,
+		SameSite:   http.SameSiteStrictMode,
+		Raw:        "",
+		Unparsed:   nil,
+	}
+
+	http.SetCookie(w, cookie)
+}
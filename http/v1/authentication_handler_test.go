@@ -1 +1,391 @@
 package v1_test
+
+import (
+	stdjson "encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	banking "github.com/morozovcookie/agat-banking"
+	v1 "github.com/morozovcookie/agat-banking/http/v1"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func TestAuthenticationHandler_SignInErrors(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		err error
+	}
+	type args struct {
+		body string
+	}
+	type wants struct {
+		status     int
+		code       v1.ErrorCode
+		retryAfter string
+	}
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "malformed body",
+				enabled: true,
+			},
+			fields: fields{
+				err: nil,
+			},
+			args: args{
+				body: `{"username":`,
+			},
+			wants: wants{
+				status:     http.StatusBadRequest,
+				code:       v1.ErrorCodeInvalidRequest,
+				retryAfter: "",
+			},
+		},
+		{
+			meta: meta{
+				name:    "empty password",
+				enabled: true,
+			},
+			fields: fields{
+				err: nil,
+			},
+			args: args{
+				body: `{"username":"admin"}`,
+			},
+			wants: wants{
+				status:     http.StatusBadRequest,
+				code:       v1.ErrorCodeInvalidRequest,
+				retryAfter: "",
+			},
+		},
+		{
+			meta: meta{
+				name:    "null body",
+				enabled: true,
+			},
+			fields: fields{
+				err: nil,
+			},
+			args: args{
+				body: `null`,
+			},
+			wants: wants{
+				status:     http.StatusBadRequest,
+				code:       v1.ErrorCodeInvalidRequest,
+				retryAfter: "",
+			},
+		},
+		{
+			meta: meta{
+				name:    "null password",
+				enabled: true,
+			},
+			fields: fields{
+				err: nil,
+			},
+			args: args{
+				body: `{"username":"admin","password":null}`,
+			},
+			wants: wants{
+				status:     http.StatusBadRequest,
+				code:       v1.ErrorCodeInvalidRequest,
+				retryAfter: "",
+			},
+		},
+		{
+			meta: meta{
+				name:    "user account does not exist",
+				enabled: true,
+			},
+			fields: fields{
+				err: errors.Wrap(banking.ErrUserAccountDoesNotExist, "authenticate user by username"),
+			},
+			args: args{
+				body: `{"username":"admin","password":"admin"}`,
+			},
+			wants: wants{
+				status:     http.StatusNotFound,
+				code:       v1.ErrorCodeUserAccountNotFound,
+				retryAfter: "",
+			},
+		},
+		{
+			meta: meta{
+				name:    "incorrect password",
+				enabled: true,
+			},
+			fields: fields{
+				err: errors.Wrap(banking.ErrIncorrectPassword, "authenticate user by username"),
+			},
+			args: args{
+				body: `{"username":"admin","password":"admin"}`,
+			},
+			wants: wants{
+				status:     http.StatusUnauthorized,
+				code:       v1.ErrorCodeIncorrectPassword,
+				retryAfter: "",
+			},
+		},
+		{
+			meta: meta{
+				name:    "user account locked",
+				enabled: true,
+			},
+			fields: fields{
+				err: errors.Wrap(&banking.LockedError{
+					Scope:      banking.LockoutScopeAccount,
+					RetryAfter: 1500 * time.Millisecond,
+				}, "authenticate user by username"),
+			},
+			args: args{
+				body: `{"username":"admin","password":"admin"}`,
+			},
+			wants: wants{
+				status:     http.StatusLocked,
+				code:       v1.ErrorCodeAccountLocked,
+				retryAfter: "2",
+			},
+		},
+		{
+			meta: meta{
+				name:    "client locked",
+				enabled: true,
+			},
+			fields: fields{
+				err: errors.Wrap(&banking.LockedError{
+					Scope:      banking.LockoutScopeClientIP,
+					RetryAfter: time.Minute,
+				}, "authenticate user by username"),
+			},
+			args: args{
+				body: `{"username":"admin","password":"admin"}`,
+			},
+			wants: wants{
+				status:     http.StatusTooManyRequests,
+				code:       v1.ErrorCodeTooManyAttempts,
+				retryAfter: "60",
+			},
+		},
+		{
+			meta: meta{
+				name:    "internal error",
+				enabled: true,
+			},
+			fields: fields{
+				err: errors.New("connection refused"),
+			},
+			args: args{
+				body: `{"username":"admin","password":"admin"}`,
+			},
+			wants: wants{
+				status:     http.StatusInternalServerError,
+				code:       v1.ErrorCodeInternal,
+				retryAfter: "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			ss := mock.NewSecretString()
+
+			factory := mock.NewSecretFactory()
+			factory.On("CreateFromDecryptedData", testifymock.Anything).
+				Return(ss, nil)
+
+			svc := mock.NewAuthenticationService()
+			svc.On("AuthenticateUserByUsername", "admin", testifymock.Anything).
+				Return(nil, nil, tt.fields.err)
+
+			handler := middleware.RequestID(v1.NewAuthenticationHandler(svc, factory))
+
+			r := httptest.NewRequest(http.MethodPost, v1.BasePathPrefix+v1.SignInPathPrefix,
+				strings.NewReader(tt.args.body))
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			var problem v1.Problem
+
+			assert.NoError(t, stdjson.NewDecoder(w.Body).Decode(&problem))
+			assert.Equal(t, tt.wants.status, w.Code)
+			assert.Equal(t, v1.ProblemContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wants.retryAfter, w.Header().Get(v1.RetryAfterHeader))
+			assert.Equal(t, tt.wants.status, problem.Status)
+			assert.Equal(t, tt.wants.code, problem.Code)
+			assert.NotEmpty(t, problem.RequestID)
+		})
+	}
+}
+
+type issuedToken struct {
+	banking.Token
+
+	secret string
+}
+
+func (t *issuedToken) IssuedAt() time.Time {
+	return time.Date(2021, time.November, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (t *issuedToken) Expiration() time.Time {
+	return t.IssuedAt().Add(time.Hour)
+}
+
+func (t *issuedToken) SecretString() banking.SecretString {
+	return plainSecret(t.secret)
+}
+
+func TestAuthenticationHandler_RefreshToken(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		body   string
+		cookie string
+	}
+	type wants struct {
+		status       int
+		refreshToken string
+		body         string
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "token in body",
+				enabled: true,
+			},
+			args: args{
+				body:   `{"refresh_token":"body-token"}`,
+				cookie: "",
+			},
+			wants: wants{
+				status:       http.StatusOK,
+				refreshToken: "body-token",
+				body:         `"refresh_token":"next-refresh-token"`,
+			},
+		},
+		{
+			meta: meta{
+				name:    "token in cookie",
+				enabled: true,
+			},
+			args: args{
+				body:   `{}`,
+				cookie: "cookie-token",
+			},
+			wants: wants{
+				status:       http.StatusOK,
+				refreshToken: "cookie-token",
+				body:         `"refresh_token":"next-refresh-token"`,
+			},
+		},
+		{
+			meta: meta{
+				name:    "token in body takes precedence over cookie",
+				enabled: true,
+			},
+			args: args{
+				body:   `{"refresh_token":"body-token"}`,
+				cookie: "cookie-token",
+			},
+			wants: wants{
+				status:       http.StatusOK,
+				refreshToken: "body-token",
+				body:         `"refresh_token":"next-refresh-token"`,
+			},
+		},
+		{
+			meta: meta{
+				name:    "token neither in body nor in cookie",
+				enabled: true,
+			},
+			args: args{
+				body:   `{}`,
+				cookie: "",
+			},
+			wants: wants{
+				status:       http.StatusBadRequest,
+				refreshToken: "",
+				body:         `"code":"refresh_token_required"`,
+			},
+		},
+		{
+			meta: meta{
+				name:    "reused token",
+				enabled: true,
+			},
+			args: args{
+				body:   `{"refresh_token":"reused-token"}`,
+				cookie: "",
+			},
+			wants: wants{
+				status:       http.StatusUnauthorized,
+				refreshToken: "reused-token",
+				body:         `"code":"token_reused"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			svc := mock.NewAuthenticationService()
+			svc.On("RefreshToken", plainSecret("reused-token")).
+				Return(nil, nil, errors.Wrap(banking.ErrTokenReused, "refresh token"))
+			svc.On("RefreshToken", testifymock.Anything).
+				Return(&issuedToken{secret: "next-access-token"}, &issuedToken{secret: "next-refresh-token"}, nil)
+
+			r := httptest.NewRequest(http.MethodPost, v1.BasePathPrefix+v1.RefreshTokenPathPrefix,
+				strings.NewReader(tt.args.body))
+			w := httptest.NewRecorder()
+
+			if tt.args.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: v1.RefreshTokenCookieName, Value: tt.args.cookie})
+			}
+
+			v1.NewAuthenticationHandler(svc, plainSecretFactory{}).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wants.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wants.body)
+
+			if tt.wants.refreshToken == "" {
+				svc.AssertNotCalled(t, "RefreshToken", testifymock.Anything)
+
+				return
+			}
+
+			svc.AssertCalled(t, "RefreshToken", plainSecret(tt.wants.refreshToken))
+			svc.AssertNumberOfCalls(t, "RefreshToken", 1)
+		})
+	}
+}
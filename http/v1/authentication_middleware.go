@@ -0,0 +1,147 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+const (
+	// AuthorizationHeader is the name of header which contains user credentials.
+	AuthorizationHeader = "Authorization"
+
+	// WWWAuthenticateHeader is the name of header which describes how user should be authenticated.
+	WWWAuthenticateHeader = "WWW-Authenticate"
+
+	// BearerScheme is the authentication scheme for access token (RFC 6750).
+	BearerScheme = "Bearer"
+
+	// DefaultRealm is the default protection space which is returned in WWW-Authenticate header.
+	DefaultRealm = "banking"
+)
+
+type contextKey string
+
+const (
+	userAccountContextKey contextKey = "user_account"
+	tokenIDContextKey     contextKey = "token_id"
+	rolesContextKey       contextKey = "roles"
+)
+
+// UserAccountFromContext returns the authenticated user account.
+func UserAccountFromContext(ctx context.Context) (*banking.UserAccount, bool) {
+	account, ok := ctx.Value(userAccountContextKey).(*banking.UserAccount)
+
+	return account, ok
+}
+
+// TokenIDFromContext returns the unique identifier of access token which was used for authentication.
+func TokenIDFromContext(ctx context.Context) (banking.ID, bool) {
+	id, ok := ctx.Value(tokenIDContextKey).(banking.ID)
+
+	return id, ok
+}
+
+// RolesFromContext returns roles of the authenticated user account which were embedded into access token.
+func RolesFromContext(ctx context.Context) (banking.Roles, bool) {
+	roles, ok := ctx.Value(rolesContextKey).(banking.Roles)
+
+	return roles, ok
+}
+
+// AuthenticationMiddleware represents a middleware which authenticates user by Bearer access token.
+type AuthenticationMiddleware struct {
+	accessTokenParser banking.TokenParser
+	realm             string
+}
+
+// NewAuthenticationMiddleware returns a new AuthenticationMiddleware instance.
+func NewAuthenticationMiddleware(accessTokenParser banking.TokenParser, realm string) *AuthenticationMiddleware {
+	return &AuthenticationMiddleware{
+		accessTokenParser: accessTokenParser,
+		realm:             realm,
+	}
+}
+
+// Handler returns a handler which calls next handler only for authenticated user.
+func (mw *AuthenticationMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		accessToken, ok := bearerToken(r)
+		if !ok {
+			mw.challenge(ctx, w, "", "", ErrUnauthenticated)
+
+			return
+		}
+
+		token, err := mw.accessTokenParser.ParseToken(ctx, strings.NewReader(accessToken))
+		if err != nil {
+			mw.challenge(ctx, w, "invalid_token", invalidTokenDescription(err), err)
+
+			return
+		}
+
+		ctx = context.WithValue(ctx, userAccountContextKey, token.Account())
+		ctx = context.WithValue(ctx, tokenIDContextKey, token.ID())
+		ctx = context.WithValue(ctx, rolesContextKey, banking.RolesFromToken(token))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get(AuthorizationHeader)
+
+	if len(header) <= len(BearerScheme) || !strings.EqualFold(header[:len(BearerScheme)], BearerScheme) ||
+		header[len(BearerScheme)] != ' ' {
+		return "", false
+	}
+
+	token := strings.TrimSpace(header[len(BearerScheme):])
+
+	return token, token != ""
+}
+
+func invalidTokenDescription(err error) string {
+	switch {
+	case errors.Is(err, banking.ErrTokenExpired):
+		return "The access token expired"
+	case errors.Is(err, banking.ErrTokenNotYetValid):
+		return "The access token is not yet valid"
+	case errors.Is(err, banking.ErrTokenWrongType):
+		return "The token is not an access token"
+	case errors.Is(err, banking.ErrTokenInvalidSignature):
+		return "The access token signature is invalid"
+	case errors.Is(err, banking.ErrTokenInvalidIssuer), errors.Is(err, banking.ErrTokenInvalidAudience):
+		return "The access token was not issued for this service"
+	case errors.Is(err, banking.ErrTokenMalformed):
+		return "The access token is malformed"
+	}
+
+	return "The access token is invalid"
+}
+
+// challenge writes WWW-Authenticate header as described in RFC 6750 section 3. The error attributes must be omitted
+// when request did not contain credentials at all.
+func (mw *AuthenticationMiddleware) challenge(
+	ctx context.Context,
+	w http.ResponseWriter,
+	code string,
+	description string,
+	err error,
+) {
+	value := fmt.Sprintf(`%s realm=%q`, BearerScheme, mw.realm)
+
+	if code != "" {
+		value += fmt.Sprintf(`, error=%q, error_description=%q`, code, description)
+	}
+
+	w.Header().Set(WWWAuthenticateHeader, value)
+
+	encodeError(ctx, w, err)
+}
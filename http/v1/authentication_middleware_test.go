@@ -0,0 +1,155 @@
+package v1_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/jwt"
+	banking "github.com/morozovcookie/agat-banking"
+	v1 "github.com/morozovcookie/agat-banking/http/v1"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func TestAuthenticationMiddleware_Handler(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		token banking.Token
+		err   error
+	}
+	type args struct {
+		authorization string
+	}
+	type wants struct {
+		status          int
+		wwwAuthenticate string
+		account         banking.ID
+	}
+
+	value := jwt.New()
+	_ = value.Set(jwt.JwtIDKey, "jti")
+
+	token := jwx.NewToken(banking.TokenTypeAccess, &banking.UserAccount{ID: "sub"}, value)
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "pass",
+				enabled: true,
+			},
+			fields: fields{
+				token: token,
+				err:   nil,
+			},
+			args: args{
+				authorization: "Bearer access-token",
+			},
+			wants: wants{
+				status:          http.StatusOK,
+				wwwAuthenticate: "",
+				account:         "sub",
+			},
+		},
+		{
+			meta: meta{
+				name:    "no credentials",
+				enabled: true,
+			},
+			fields: fields{
+				token: nil,
+				err:   nil,
+			},
+			args: args{
+				authorization: "",
+			},
+			wants: wants{
+				status:          http.StatusUnauthorized,
+				wwwAuthenticate: `Bearer realm="banking"`,
+				account:         "",
+			},
+		},
+		{
+			meta: meta{
+				name:    "another scheme",
+				enabled: true,
+			},
+			fields: fields{
+				token: nil,
+				err:   nil,
+			},
+			args: args{
+				authorization: "Basic YWRtaW46YWRtaW4=",
+			},
+			wants: wants{
+				status:          http.StatusUnauthorized,
+				wwwAuthenticate: `Bearer realm="banking"`,
+				account:         "",
+			},
+		},
+		{
+			meta: meta{
+				name:    "expired token",
+				enabled: true,
+			},
+			fields: fields{
+				token: nil,
+				err:   errors.Wrap(banking.ErrTokenExpired, "parse token"),
+			},
+			args: args{
+				authorization: "Bearer access-token",
+			},
+			wants: wants{
+				status: http.StatusUnauthorized,
+				wwwAuthenticate: `Bearer realm="banking", error="invalid_token", ` +
+					`error_description="The access token expired"`,
+				account: "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			parser := mock.NewTokenParser()
+			parser.On("ParseToken", testifymock.Anything).
+				Return(tt.fields.token, tt.fields.err)
+
+			var account banking.ID
+
+			handler := v1.NewAuthenticationMiddleware(parser, v1.DefaultRealm).
+				Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if ua, ok := v1.UserAccountFromContext(r.Context()); ok {
+						account = ua.ID
+					}
+				}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.args.authorization != "" {
+				r.Header.Set(v1.AuthorizationHeader, tt.args.authorization)
+			}
+
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wants.status, w.Code)
+			assert.Equal(t, tt.wants.wwwAuthenticate, w.Header().Get(v1.WWWAuthenticateHeader))
+			assert.Equal(t, tt.wants.account, account)
+		})
+	}
+}
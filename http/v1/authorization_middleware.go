@@ -0,0 +1,32 @@
+package v1
+
+import (
+	"net/http"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+// RequirePermission returns a middleware which calls next handler only if any role of the authenticated user account
+// grants the permission. It must be placed after AuthenticationMiddleware.
+func RequirePermission(permission banking.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			if _, ok := UserAccountFromContext(ctx); !ok {
+				encodeError(ctx, w, ErrUnauthenticated)
+
+				return
+			}
+
+			if roles, _ := RolesFromContext(ctx); !roles.HasPermission(permission) {
+				encodeError(ctx, w, errors.Wrapf(banking.ErrPermissionDenied, "require %s", permission))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
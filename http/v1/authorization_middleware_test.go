@@ -0,0 +1,155 @@
+package v1_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/jwt"
+	banking "github.com/morozovcookie/agat-banking"
+	v1 "github.com/morozovcookie/agat-banking/http/v1"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func TestRequirePermission(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		roles interface{}
+	}
+	type args struct {
+		permission    banking.Permission
+		authorization string
+	}
+	type wants struct {
+		status int
+	}
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "granted",
+				enabled: true,
+			},
+			fields: fields{
+				roles: []string{"cashier", "accountant"},
+			},
+			args: args{
+				permission:    banking.PermissionLedgerWrite,
+				authorization: "Bearer access-token",
+			},
+			wants: wants{
+				status: http.StatusOK,
+			},
+		},
+		{
+			meta: meta{
+				name:    "denied",
+				enabled: true,
+			},
+			fields: fields{
+				roles: []string{"cashier"},
+			},
+			args: args{
+				permission:    banking.PermissionLedgerWrite,
+				authorization: "Bearer access-token",
+			},
+			wants: wants{
+				status: http.StatusForbidden,
+			},
+		},
+		{
+			meta: meta{
+				name:    "unknown role",
+				enabled: true,
+			},
+			fields: fields{
+				roles: []string{"superuser"},
+			},
+			args: args{
+				permission:    banking.PermissionAuditRead,
+				authorization: "Bearer access-token",
+			},
+			wants: wants{
+				status: http.StatusForbidden,
+			},
+		},
+		{
+			meta: meta{
+				name:    "without roles claim",
+				enabled: true,
+			},
+			fields: fields{
+				roles: nil,
+			},
+			args: args{
+				permission:    banking.PermissionLedgerRead,
+				authorization: "Bearer access-token",
+			},
+			wants: wants{
+				status: http.StatusForbidden,
+			},
+		},
+		{
+			meta: meta{
+				name:    "unauthenticated",
+				enabled: true,
+			},
+			fields: fields{
+				roles: []string{"auditor"},
+			},
+			args: args{
+				permission:    banking.PermissionAuditRead,
+				authorization: "",
+			},
+			wants: wants{
+				status: http.StatusUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			value := jwt.New()
+			_ = value.Set(jwt.JwtIDKey, "jti")
+
+			if tt.fields.roles != nil {
+				_ = value.Set(banking.RolesClaim, tt.fields.roles)
+			}
+
+			parser := mock.NewTokenParser()
+			parser.On("ParseToken", testifymock.Anything).
+				Return(jwx.NewToken(banking.TokenTypeAccess, &banking.UserAccount{ID: "sub"}, value), nil)
+
+			handler := v1.NewAuthenticationMiddleware(parser, v1.DefaultRealm).
+				Handler(v1.RequirePermission(tt.args.permission)(http.HandlerFunc(func(http.ResponseWriter,
+					*http.Request) {
+				})))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.args.authorization != "" {
+				r.Header.Set(v1.AuthorizationHeader, tt.args.authorization)
+			}
+
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wants.status, w.Code)
+		})
+	}
+}
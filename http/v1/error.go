@@ -0,0 +1,336 @@
+package v1
+
+import (
+	"context"
+	stdjson "encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ProblemContentType is the media type of error response body (RFC 7807).
+	ProblemContentType = "application/problem+json"
+
+	// ProblemTypeBlank is the problem type which means that problem has no additional semantics beyond that of the
+	// HTTP status code.
+	ProblemTypeBlank = "about:blank"
+
+	// RetryAfterHeader is the name of header which contains the number of seconds after which request could be
+	// repeated.
+	RetryAfterHeader = "Retry-After"
+)
+
+var (
+	// ErrInvalidRequest will be raised when request could not be decoded or does not pass validation.
+	ErrInvalidRequest = errors.New("invalid request")
+
+	// ErrUnauthenticated will be raised when request does not contain credentials.
+	ErrUnauthenticated = errors.New("unauthenticated")
+)
+
+// ErrorCode represents a stable machine-readable identifier of the problem.
+type ErrorCode string
+
+const (
+	// ErrorCodeInvalidRequest means that request could not be decoded or does not pass validation.
+	ErrorCodeInvalidRequest ErrorCode = "invalid_request"
+
+	// ErrorCodeRefreshTokenRequired means that refresh token was passed neither in request body nor in cookie.
+	ErrorCodeRefreshTokenRequired ErrorCode = "refresh_token_required"
+
+	// ErrorCodeUnauthenticated means that request does not contain credentials.
+	ErrorCodeUnauthenticated ErrorCode = "unauthenticated"
+
+	// ErrorCodePasswordResetTokenInvalid means that password reset token does not exist, expired or was already used.
+	ErrorCodePasswordResetTokenInvalid ErrorCode = "password_reset_token_invalid"
+
+	// ErrorCodePermissionDenied means that user account roles do not grant the permission which is required for the
+	// request.
+	ErrorCodePermissionDenied ErrorCode = "permission_denied"
+
+	// ErrorCodeRoleUnknown means that role is not one of the predefined roles.
+	ErrorCodeRoleUnknown ErrorCode = "role_unknown"
+
+	// ErrorCodeUserAccountNotFound means that user account does not exist.
+	ErrorCodeUserAccountNotFound ErrorCode = "user_account_not_found"
+
+	// ErrorCodeUserAccountAlreadyExists means that user account with the same username or email address already
+	// exists.
+	ErrorCodeUserAccountAlreadyExists ErrorCode = "user_account_already_exists"
+
+	// ErrorCodeUserAccountDisabled means that user account is disabled and could not be used.
+	ErrorCodeUserAccountDisabled ErrorCode = "user_account_disabled"
+
+	// ErrorCodeUserNotFound means that user does not exist.
+	ErrorCodeUserNotFound ErrorCode = "user_not_found"
+
+	// ErrorCodeUserHasAccounts means that user could not be deleted while it owns user accounts.
+	ErrorCodeUserHasAccounts ErrorCode = "user_has_accounts"
+
+	// ErrorCodeIncorrectPassword means that password does not match the user account password.
+	ErrorCodeIncorrectPassword ErrorCode = "incorrect_password"
+
+	// ErrorCodeAccountLocked means that sign in to the user account is not allowed for some time.
+	ErrorCodeAccountLocked ErrorCode = "account_locked"
+
+	// ErrorCodeTooManyAttempts means that sign in from the client is not allowed for some time.
+	ErrorCodeTooManyAttempts ErrorCode = "too_many_attempts"
+
+	// ErrorCodeTokenNotFound means that token does not exist or already expired.
+	ErrorCodeTokenNotFound ErrorCode = "token_not_found"
+
+	// ErrorCodeTokenReused means that refresh token was already exchanged and every token of its family is revoked.
+	ErrorCodeTokenReused ErrorCode = "token_reused"
+
+	// ErrorCodeTokenExpired means that token expired.
+	ErrorCodeTokenExpired ErrorCode = "token_expired"
+
+	// ErrorCodeTokenInvalid means that token could not be used (e.g. malformed, has invalid signature or type).
+	ErrorCodeTokenInvalid ErrorCode = "token_invalid"
+
+	// ErrorCodeInternal means that request could not be processed because of server error.
+	ErrorCodeInternal ErrorCode = "internal_error"
+)
+
+// Problem represents an error response body as described in RFC 7807.
+type Problem struct {
+	// Type is the URI reference that identifies the problem type.
+	Type string `json:"type"`
+
+	// Title is the short human-readable summary of the problem type.
+	Title string `json:"title"`
+
+	// Status is the HTTP status code.
+	Status int `json:"status"`
+
+	// Detail is the human-readable explanation specific to this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+
+	// Code is the stable machine-readable identifier of the problem.
+	Code ErrorCode `json:"code"`
+
+	// RequestID is the identifier of request which could be used for searching logs and traces.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// errorMapping describes how the error should be translated to the problem. The first mapping which target matches
+// the error wins, so more specific errors must be placed before more generic ones.
+type errorMapping struct {
+	target error
+	status int
+	code   ErrorCode
+	detail string
+}
+
+var errorMappings = []errorMapping{
+	{
+		target: ErrEmptyRefreshToken,
+		status: http.StatusBadRequest,
+		code:   ErrorCodeRefreshTokenRequired,
+		detail: "Refresh token is required",
+	},
+	{
+		target: ErrInvalidRequest,
+		status: http.StatusBadRequest,
+		code:   ErrorCodeInvalidRequest,
+		detail: "Request is invalid",
+	},
+	{
+		target: ErrUnauthenticated,
+		status: http.StatusUnauthorized,
+		code:   ErrorCodeUnauthenticated,
+		detail: "Authentication is required",
+	},
+	{
+		target: banking.ErrPasswordResetTokenInvalid,
+		status: http.StatusBadRequest,
+		code:   ErrorCodePasswordResetTokenInvalid,
+		detail: "Password reset token is invalid, expired or was already used",
+	},
+	{
+		target: banking.ErrPermissionDenied,
+		status: http.StatusForbidden,
+		code:   ErrorCodePermissionDenied,
+		detail: "Permission is denied",
+	},
+	{
+		target: banking.ErrRoleUnknown,
+		status: http.StatusNotFound,
+		code:   ErrorCodeRoleUnknown,
+		detail: "Role does not exist",
+	},
+	{
+		target: banking.ErrUserAccountDoesNotExist,
+		status: http.StatusNotFound,
+		code:   ErrorCodeUserAccountNotFound,
+		detail: "User account does not exist",
+	},
+	{
+		target: banking.ErrUserAccountAlreadyExists,
+		status: http.StatusConflict,
+		code:   ErrorCodeUserAccountAlreadyExists,
+		detail: "User account with the same username or email address already exists",
+	},
+	{
+		target: banking.ErrUserAccountDisabled,
+		status: http.StatusForbidden,
+		code:   ErrorCodeUserAccountDisabled,
+		detail: "User account is disabled",
+	},
+	{
+		target: banking.ErrUserDoesNotExist,
+		status: http.StatusNotFound,
+		code:   ErrorCodeUserNotFound,
+		detail: "User does not exist",
+	},
+	{
+		target: banking.ErrUserHasAccounts,
+		status: http.StatusConflict,
+		code:   ErrorCodeUserHasAccounts,
+		detail: "User owns user accounts",
+	},
+	{
+		target: banking.ErrIncorrectPassword,
+		status: http.StatusUnauthorized,
+		code:   ErrorCodeIncorrectPassword,
+		detail: "Password is incorrect",
+	},
+	{
+		target: banking.ErrTokenDoesNotExist,
+		status: http.StatusUnauthorized,
+		code:   ErrorCodeTokenNotFound,
+		detail: "Token does not exist",
+	},
+	{
+		target: banking.ErrTokenReused,
+		status: http.StatusUnauthorized,
+		code:   ErrorCodeTokenReused,
+		detail: "Token was already used",
+	},
+	{
+		target: banking.ErrTokenExpired,
+		status: http.StatusUnauthorized,
+		code:   ErrorCodeTokenExpired,
+		detail: "Token expired",
+	},
+	{
+		target: banking.ErrTokenNotYetValid,
+		status: http.StatusUnauthorized,
+		code:   ErrorCodeTokenInvalid,
+		detail: "Token is not yet valid",
+	},
+	{
+		target: banking.ErrTokenWrongType,
+		status: http.StatusUnauthorized,
+		code:   ErrorCodeTokenInvalid,
+		detail: "Token has wrong type",
+	},
+	{
+		target: banking.ErrTokenInvalidSignature,
+		status: http.StatusUnauthorized,
+		code:   ErrorCodeTokenInvalid,
+		detail: "Token signature is invalid",
+	},
+	{
+		target: banking.ErrTokenInvalidIssuer,
+		status: http.StatusUnauthorized,
+		code:   ErrorCodeTokenInvalid,
+		detail: "Token was issued by other service",
+	},
+	{
+		target: banking.ErrTokenInvalidAudience,
+		status: http.StatusUnauthorized,
+		code:   ErrorCodeTokenInvalid,
+		detail: "Token was issued for other service",
+	},
+	{
+		target: banking.ErrTokenMalformed,
+		status: http.StatusUnauthorized,
+		code:   ErrorCodeTokenInvalid,
+		detail: "Token is malformed",
+	},
+}
+
+// invalidRequestError wraps the decoding or validation error so it matches ErrInvalidRequest and still matches the
+// original error.
+type invalidRequestError struct {
+	cause error
+}
+
+func invalidRequest(err error) error {
+	return &invalidRequestError{
+		cause: err,
+	}
+}
+
+func (e *invalidRequestError) Error() string {
+	return ErrInvalidRequest.Error() + ": " + e.cause.Error()
+}
+
+func (e *invalidRequestError) Is(target error) bool {
+	return target == ErrInvalidRequest // nolint:errorlint,goerr113
+}
+
+func (e *invalidRequestError) Unwrap() error {
+	return e.cause
+}
+
+// newProblem translates the error to the problem. Unknown errors are translated to the internal error without any
+// detail, so implementation details are not leaked to the client.
+func newProblem(ctx context.Context, err error) *Problem {
+	problem := &Problem{
+		Type:      ProblemTypeBlank,
+		Title:     http.StatusText(http.StatusInternalServerError),
+		Status:    http.StatusInternalServerError,
+		Detail:    "",
+		Code:      ErrorCodeInternal,
+		RequestID: middleware.GetReqID(ctx),
+	}
+
+	var locked *banking.LockedError
+	if errors.As(err, &locked) {
+		problem.Status, problem.Code, problem.Detail = http.StatusLocked, ErrorCodeAccountLocked,
+			"User account is locked"
+		if locked.Scope == banking.LockoutScopeClientIP {
+			problem.Status, problem.Code, problem.Detail = http.StatusTooManyRequests, ErrorCodeTooManyAttempts,
+				"Too many failed attempts"
+		}
+
+		problem.Title = http.StatusText(problem.Status)
+
+		return problem
+	}
+
+	for _, mapping := range errorMappings {
+		if !errors.Is(err, mapping.target) {
+			continue
+		}
+
+		problem.Status, problem.Code, problem.Detail = mapping.status, mapping.code, mapping.detail
+		problem.Title = http.StatusText(problem.Status)
+
+		return problem
+	}
+
+	return problem
+}
+
+// encodeError writes the problem which the error is translated to.
+func encodeError(ctx context.Context, w http.ResponseWriter, err error) {
+	var locked *banking.LockedError
+	if errors.As(err, &locked) {
+		w.Header().Set(RetryAfterHeader, strconv.FormatInt(int64(math.Ceil(locked.RetryAfter.Seconds())), 10))
+	}
+
+	problem := newProblem(ctx, err)
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+
+	_ = stdjson.NewEncoder(w).Encode(problem)
+}
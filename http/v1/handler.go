@@ -3,9 +3,11 @@ package v1
 import (
 	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	banking "github.com/morozovcookie/agat-banking"
 )
 
 const (
@@ -30,7 +32,18 @@ func NewHandler() *Handler {
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	h.router.ServeHTTP(w, r)
+	h.router.ServeHTTP(w, r.WithContext(banking.ContextWithClientIP(r.Context(), clientIP(r))))
+}
+
+// clientIP returns the client IP address. The server replaces the remote address with the value from X-Real-IP or
+// X-Forwarded-For header of trusted proxy, so it could be without port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
 }
 
 func encodeResponse(_ context.Context, w http.ResponseWriter, status int, resp interface{}) {
@@ -38,7 +51,3 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, status int, resp i
 
 	_ = json.NewEncoder(w).Encode(resp)
 }
-
-func badRequestError(_ context.Context, w http.ResponseWriter) {
-	w.WriteHeader(http.StatusBadRequest)
-}
@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+// JWKSPath is the path for retrieving JSON Web Key Set (RFC 7517) which could be used for verifying tokens.
+const JWKSPath = "/.well-known/jwks.json"
+
+// PublicKeySource represents a source of public keys for verifying tokens.
+type PublicKeySource interface {
+	// PublicKeys returns public keys which could be shared for verifying tokens outside.
+	PublicKeys(ctx context.Context) (jwk.Set, error)
+}
+
+var _ http.Handler = (*JWKSHandler)(nil)
+
+// JWKSHandler represents an HTTP handler for publishing token verification keys.
+type JWKSHandler struct {
+	*Handler
+
+	sources []PublicKeySource
+}
+
+// NewJWKSHandler returns a new JWKSHandler instance.
+func NewJWKSHandler(sources ...PublicKeySource) *JWKSHandler {
+	h := &JWKSHandler{
+		Handler: NewHandler(),
+
+		sources: sources,
+	}
+
+	h.router.Route("/", func(r chi.Router) {
+		r.Get(JWKSPath, h.handleJWKS)
+	})
+
+	return h
+}
+
+func (h *JWKSHandler) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	keys := jwk.NewSet()
+
+	for _, source := range h.sources {
+		public, err := source.PublicKeys(ctx)
+		if err != nil {
+			encodeError(ctx, w, err)
+
+			return
+		}
+
+		for iter := public.Iterate(ctx); iter.Next(ctx); {
+			key, _ := iter.Pair().Value.(jwk.Key)
+
+			// The same key could be used for several token types, but it should be published only once.
+			if _, ok := keys.LookupKeyID(key.KeyID()); ok {
+				continue
+			}
+
+			keys.Add(key)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	encodeResponse(ctx, w, http.StatusOK, keys)
+}
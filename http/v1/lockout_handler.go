@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	banking "github.com/morozovcookie/agat-banking"
+)
+
+// UserAccountLockoutPathPrefix is the path prefix for managing the user account lockout.
+const UserAccountLockoutPathPrefix = "/accounts/{accountID}/lockout"
+
+var _ http.Handler = (*LockoutHandler)(nil)
+
+// LockoutHandler represents an HTTP handler for managing sign in lockouts.
+type LockoutHandler struct {
+	*Handler
+
+	lockoutService     banking.LockoutService
+	userAccountService banking.UserAccountService
+}
+
+// NewLockoutHandler returns a new LockoutHandler instance. Unlocking is an administrative operation, so middlewares
+// must authenticate the user and the route requires lockouts write permission.
+func NewLockoutHandler(
+	lockoutService banking.LockoutService,
+	userAccountService banking.UserAccountService,
+	middlewares ...func(http.Handler) http.Handler,
+) *LockoutHandler {
+	h := &LockoutHandler{
+		Handler: NewHandler(),
+
+		lockoutService:     lockoutService,
+		userAccountService: userAccountService,
+	}
+
+	h.router.Route(BasePathPrefix, func(r chi.Router) {
+		r.With(middlewares...).
+			With(RequirePermission(banking.PermissionLockoutsWrite)).
+			Delete(UserAccountLockoutPathPrefix, h.handleUnlockUserAccount)
+	})
+
+	return h
+}
+
+func (h *LockoutHandler) handleUnlockUserAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	account, err := h.userAccountService.FindUserAccountByID(ctx, banking.ID(chi.URLParam(r, "accountID")))
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	if err = h.lockoutService.ResetFailedAttempts(ctx, banking.AccountSubject(account.ID)); err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,168 @@
+package v1_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/jwt"
+	banking "github.com/morozovcookie/agat-banking"
+	v1 "github.com/morozovcookie/agat-banking/http/v1"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+type fakeLockoutService struct {
+	banking.LockoutService
+
+	reset []banking.LockoutSubject
+}
+
+func (svc *fakeLockoutService) ResetFailedAttempts(_ context.Context, subject banking.LockoutSubject) error {
+	svc.reset = append(svc.reset, subject)
+
+	return nil
+}
+
+type fakeExistingUserAccountService struct {
+	banking.UserAccountService
+
+	id banking.ID
+}
+
+func (svc *fakeExistingUserAccountService) FindUserAccountByID(
+	_ context.Context,
+	id banking.ID,
+) (
+	*banking.UserAccount,
+	error,
+) {
+	if id != svc.id {
+		return nil, banking.ErrUserAccountDoesNotExist
+	}
+
+	return &banking.UserAccount{ID: id}, nil
+}
+
+func TestLockoutHandler_UnlockUserAccount(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		roles     []string
+		accountID string
+	}
+	type wants struct {
+		status int
+		body   string
+		reset  int
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "unlock",
+				enabled: true,
+			},
+			args: args{
+				roles:     []string{"admin"},
+				accountID: "account-id",
+			},
+			wants: wants{
+				status: http.StatusNoContent,
+				body:   "",
+				reset:  1,
+			},
+		},
+		{
+			meta: meta{
+				name:    "unlock unknown account",
+				enabled: true,
+			},
+			args: args{
+				roles:     []string{"admin"},
+				accountID: "unknown-id",
+			},
+			wants: wants{
+				status: http.StatusNotFound,
+				body:   `"code":"user_account_not_found"`,
+				reset:  0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "unlock by cashier",
+				enabled: true,
+			},
+			args: args{
+				roles:     []string{"cashier"},
+				accountID: "account-id",
+			},
+			wants: wants{
+				status: http.StatusForbidden,
+				body:   `"code":"permission_denied"`,
+				reset:  0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "unlock without roles",
+				enabled: true,
+			},
+			args: args{
+				roles:     nil,
+				accountID: "account-id",
+			},
+			wants: wants{
+				status: http.StatusForbidden,
+				body:   `"code":"permission_denied"`,
+				reset:  0,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tt := test
+
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			svc := &fakeLockoutService{
+				LockoutService: nil,
+				reset:          nil,
+			}
+
+			value := jwt.New()
+			_ = value.Set(jwt.JwtIDKey, "jti")
+			_ = value.Set(banking.RolesClaim, tt.args.roles)
+
+			parser := mock.NewTokenParser()
+			parser.On("ParseToken", testifymock.Anything).
+				Return(jwx.NewToken(banking.TokenTypeAccess, &banking.UserAccount{ID: "sub"}, value), nil)
+
+			var (
+				w = httptest.NewRecorder()
+				r = httptest.NewRequest(http.MethodDelete, "/api/v1/accounts/"+tt.args.accountID+"/lockout", nil)
+			)
+
+			r.Header.Set(v1.AuthorizationHeader, "Bearer access-token")
+
+			v1.NewLockoutHandler(svc, &fakeExistingUserAccountService{UserAccountService: nil, id: "account-id"},
+				v1.NewAuthenticationMiddleware(parser, v1.DefaultRealm).Handler).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wants.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wants.body)
+			assert.Len(t, svc.reset, tt.wants.reset)
+		})
+	}
+}
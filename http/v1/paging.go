@@ -0,0 +1,58 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LimitQueryParam is the name of query parameter which contains the elements count on the single page.
+	LimitQueryParam = "limit"
+
+	// OffsetQueryParam is the name of query parameter which contains the elements count that should be skipped.
+	OffsetQueryParam = "offset"
+)
+
+// Page represents the position of the list page which is returned with its elements.
+type Page struct {
+	// Limit is the elements count that could be placed on the page.
+	Limit uint64 `json:"limit"`
+
+	// Offset is the elements count that were skipped.
+	Offset uint64 `json:"offset"`
+}
+
+func newPage(opts banking.FindOptions) Page {
+	return Page{
+		Limit:  opts.Limit(),
+		Offset: opts.Offset(),
+	}
+}
+
+// decodeFindOptions returns the page position from query parameters. Limit is the default page size if it is omitted
+// and it is reduced to the maximum page size if it is too large.
+func decodeFindOptions(r *http.Request) (banking.FindOptions, error) {
+	var (
+		query  = r.URL.Query()
+		values = make(map[string]uint64, 2) // nolint:gomnd
+	)
+
+	for _, name := range []string{LimitQueryParam, OffsetQueryParam} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		number, err := strconv.ParseUint(value, 10, 64) // nolint:gomnd
+		if err != nil {
+			return banking.FindOptions{}, invalidRequest(errors.Wrapf(err, "decode %s", name))
+		}
+
+		values[name] = number
+	}
+
+	return banking.NewFindOptions(values[LimitQueryParam], values[OffsetQueryParam]), nil
+}
@@ -0,0 +1,248 @@
+package v1
+
+import (
+	"context"
+	stdjson "encoding/json"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/json"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PasswordPathPrefix is the path prefix for changing password of the authenticated user account.
+	PasswordPathPrefix = "/accounts/me/password"
+
+	// UserAccountPasswordResetPathPrefix is the path prefix for issuing password reset token for the user account.
+	UserAccountPasswordResetPathPrefix = "/accounts/{accountID}/password-reset"
+
+	// PasswordResetPathPrefix is the path prefix for redeeming password reset token.
+	PasswordResetPathPrefix = "/password-reset"
+)
+
+var _ http.Handler = (*PasswordHandler)(nil)
+
+// PasswordHandler represents an HTTP handler for changing and resetting user account passwords.
+type PasswordHandler struct {
+	*Handler
+
+	passwordService banking.PasswordService
+	secretFactory   banking.SecretFactory
+}
+
+// NewPasswordHandler returns a new PasswordHandler instance. Middlewares must authenticate the user, they are not
+// applied to redeeming password reset token, because user who forgot password could not sign in.
+func NewPasswordHandler(
+	passwordService banking.PasswordService,
+	secretFactory banking.SecretFactory,
+	middlewares ...func(http.Handler) http.Handler,
+) *PasswordHandler {
+	h := &PasswordHandler{
+		Handler: NewHandler(),
+
+		passwordService: passwordService,
+		secretFactory:   secretFactory,
+	}
+
+	h.router.Route(BasePathPrefix, func(r chi.Router) {
+		r.With(middlewares...).Post(PasswordPathPrefix, h.handleChangePassword)
+		r.With(middlewares...).
+			With(RequirePermission(banking.PermissionAccountsWrite)).
+			Post(UserAccountPasswordResetPathPrefix, h.handleIssuePasswordResetToken)
+		r.Post(PasswordResetPathPrefix, h.handleResetPassword)
+	})
+
+	return h
+}
+
+// MinPasswordLength is the minimal number of characters in the new password.
+const MinPasswordLength = 8
+
+var (
+	// ErrEmptyNewPassword will be raised when request does not contain new password.
+	ErrEmptyNewPassword = errors.New("empty new password")
+
+	// ErrEmptyResetToken will be raised when request does not contain password reset token.
+	ErrEmptyResetToken = errors.New("empty reset token")
+
+	// ErrPasswordTooShort will be raised when new password contains less than MinPasswordLength characters.
+	ErrPasswordTooShort = errors.New("password too short")
+)
+
+// ChangePasswordRequest represents a set of data that should be passed by user for changing password.
+type ChangePasswordRequest struct {
+	// CurrentPassword is the password which user account has now.
+	CurrentPassword *json.SecretString `json:"current_password"`
+
+	// NewPassword is the password which user account will have.
+	NewPassword *json.SecretString `json:"new_password"`
+}
+
+func decodeChangePasswordRequest(
+	_ context.Context,
+	factory banking.SecretFactory,
+	r *http.Request,
+) (
+	*ChangePasswordRequest,
+	error,
+) {
+	req := &ChangePasswordRequest{
+		CurrentPassword: json.NewSecretString(nil, factory),
+		NewPassword:     json.NewSecretString(nil, factory),
+	}
+
+	if err := stdjson.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, invalidRequest(errors.Wrap(err, "decode ChangePasswordRequest"))
+	}
+
+	if isBlankSecret(req.CurrentPassword) {
+		return nil, invalidRequest(ErrEmptyPassword)
+	}
+
+	if err := validatePassword(req.NewPassword, ErrEmptyNewPassword); err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	return req, nil
+}
+
+// PasswordResetTokenResponse represents a set of data that will be returned after password reset token was issued.
+// Token must be passed to user by a trusted channel.
+type PasswordResetTokenResponse struct {
+	// ResetToken is the single-use secret which allows to set new password.
+	ResetToken *json.SecretString `json:"reset_token"`
+
+	// ExpiresAt is the time which after reset token could not be used.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ResetPasswordRequest represents a set of data that should be passed by user for resetting password.
+type ResetPasswordRequest struct {
+	// ResetToken is the single-use secret which was issued by administrator.
+	ResetToken *json.SecretString `json:"reset_token"`
+
+	// NewPassword is the password which user account will have.
+	NewPassword *json.SecretString `json:"new_password"`
+}
+
+func decodeResetPasswordRequest(
+	_ context.Context,
+	factory banking.SecretFactory,
+	r *http.Request,
+) (
+	*ResetPasswordRequest,
+	error,
+) {
+	req := &ResetPasswordRequest{
+		ResetToken:  json.NewSecretString(nil, factory),
+		NewPassword: json.NewSecretString(nil, factory),
+	}
+
+	if err := stdjson.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, invalidRequest(errors.Wrap(err, "decode ResetPasswordRequest"))
+	}
+
+	if isBlankSecret(req.ResetToken) {
+		return nil, invalidRequest(ErrEmptyResetToken)
+	}
+
+	if err := validatePassword(req.NewPassword, ErrEmptyNewPassword); err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	return req, nil
+}
+
+// isBlankSecret reports whether secret was omitted, was null or was an empty string.
+func isBlankSecret(secret *json.SecretString) bool {
+	return secret.IsEmpty() || secret.DecryptedString() == ""
+}
+
+// validatePassword returns error if password which is going to be set could not be accepted. Blank password is
+// reported with passed error, so client could tell which field is missing.
+func validatePassword(password *json.SecretString, errEmpty error) error {
+	if isBlankSecret(password) {
+		return errEmpty
+	}
+
+	if utf8.RuneCountInString(password.DecryptedString()) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+
+	return nil
+}
+
+// handleChangePassword changes password of the authenticated user account. Every refresh token is revoked, so refresh
+// token cookie is removed and user has to sign in again.
+func (h *PasswordHandler) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	account, ok := UserAccountFromContext(ctx)
+	if !ok {
+		encodeError(ctx, w, ErrUnauthenticated)
+
+		return
+	}
+
+	req, err := decodeChangePasswordRequest(ctx, h.secretFactory, r)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	if err = h.passwordService.ChangePassword(ctx, account.ID, req.CurrentPassword, req.NewPassword); err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	removeRefreshTokenFromCookie(ctx, w, r)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PasswordHandler) handleIssuePasswordResetToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resetToken, expiresAt, err := h.passwordService.IssuePasswordResetToken(ctx,
+		banking.ID(chi.URLParam(r, "accountID")))
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	// Reset token must not be cached by any proxy.
+	w.Header().Set("Cache-Control", "no-store")
+
+	encodeResponse(ctx, w, http.StatusCreated, &PasswordResetTokenResponse{
+		ResetToken: json.NewRevealedSecretString(resetToken, h.secretFactory),
+		ExpiresAt:  expiresAt,
+	})
+}
+
+func (h *PasswordHandler) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeResetPasswordRequest(ctx, h.secretFactory, r)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	if err = h.passwordService.ResetPassword(ctx, req.ResetToken, req.NewPassword); err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	removeRefreshTokenFromCookie(ctx, w, r)
+
+	w.WriteHeader(http.StatusNoContent)
+}
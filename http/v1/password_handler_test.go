@@ -0,0 +1,295 @@
+package v1_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwt"
+	banking "github.com/morozovcookie/agat-banking"
+	v1 "github.com/morozovcookie/agat-banking/http/v1"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+type plainSecret string
+
+func (s plainSecret) String() string          { return "[SECRET]" }
+func (s plainSecret) EncryptedString() string { return string(s) }
+func (s plainSecret) DecryptedString() string { return string(s) }
+
+type plainSecretFactory struct {
+	banking.SecretFactory
+}
+
+func (plainSecretFactory) CreateFromDecryptedData(_ context.Context, r io.Reader) (banking.SecretString, error) {
+	bb, err := ioutil.ReadAll(r)
+
+	return plainSecret(bb), err
+}
+
+type fakePasswordService struct {
+	banking.PasswordService
+
+	calls int
+	err   error
+}
+
+func (svc *fakePasswordService) ChangePassword(_ context.Context, _ banking.ID, _, _ banking.SecretString) error {
+	svc.calls++
+
+	return svc.err
+}
+
+func (svc *fakePasswordService) ResetPassword(_ context.Context, _, _ banking.SecretString) error {
+	svc.calls++
+
+	return nil
+}
+
+func TestPasswordHandler(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		target string
+		body   string
+		err    error
+	}
+	type wants struct {
+		status int
+		body   string
+		calls  int
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "change password",
+				enabled: true,
+			},
+			args: args{
+				target: "/api/v1/accounts/me/password",
+				body:   `{"current_password":"password","new_password":"new password"}`,
+				err:    nil,
+			},
+			wants: wants{
+				status: http.StatusNoContent,
+				body:   "",
+				calls:  1,
+			},
+		},
+		{
+			meta: meta{
+				name:    "change password with null body",
+				enabled: true,
+			},
+			args: args{
+				target: "/api/v1/accounts/me/password",
+				body:   `null`,
+				err:    nil,
+			},
+			wants: wants{
+				status: http.StatusBadRequest,
+				body:   `"code":"invalid_request"`,
+				calls:  0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "change password with null current password",
+				enabled: true,
+			},
+			args: args{
+				target: "/api/v1/accounts/me/password",
+				body:   `{"current_password":null,"new_password":"new password"}`,
+				err:    nil,
+			},
+			wants: wants{
+				status: http.StatusBadRequest,
+				body:   `"code":"invalid_request"`,
+				calls:  0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "change password with null new password",
+				enabled: true,
+			},
+			args: args{
+				target: "/api/v1/accounts/me/password",
+				body:   `{"current_password":"password","new_password":null}`,
+				err:    nil,
+			},
+			wants: wants{
+				status: http.StatusBadRequest,
+				body:   `"code":"invalid_request"`,
+				calls:  0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "change password with empty new password",
+				enabled: true,
+			},
+			args: args{
+				target: "/api/v1/accounts/me/password",
+				body:   `{"current_password":"password","new_password":""}`,
+				err:    nil,
+			},
+			wants: wants{
+				status: http.StatusBadRequest,
+				body:   `"code":"invalid_request"`,
+				calls:  0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "change password with short new password",
+				enabled: true,
+			},
+			args: args{
+				target: "/api/v1/accounts/me/password",
+				body:   `{"current_password":"password","new_password":"short"}`,
+				err:    nil,
+			},
+			wants: wants{
+				status: http.StatusBadRequest,
+				body:   `"code":"invalid_request"`,
+				calls:  0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "change password of locked account",
+				enabled: true,
+			},
+			args: args{
+				target: "/api/v1/accounts/me/password",
+				body:   `{"current_password":"password","new_password":"new password"}`,
+				err: &banking.LockedError{
+					Scope:      banking.LockoutScopeAccount,
+					RetryAfter: time.Minute,
+				},
+			},
+			wants: wants{
+				status: http.StatusLocked,
+				body:   `"code":"account_locked"`,
+				calls:  1,
+			},
+		},
+		{
+			meta: meta{
+				name:    "reset password",
+				enabled: true,
+			},
+			args: args{
+				target: "/api/v1/password-reset",
+				body:   `{"reset_token":"token","new_password":"new password"}`,
+				err:    nil,
+			},
+			wants: wants{
+				status: http.StatusNoContent,
+				body:   "",
+				calls:  1,
+			},
+		},
+		{
+			meta: meta{
+				name:    "reset password with null reset token",
+				enabled: true,
+			},
+			args: args{
+				target: "/api/v1/password-reset",
+				body:   `{"reset_token":null,"new_password":"new password"}`,
+				err:    nil,
+			},
+			wants: wants{
+				status: http.StatusBadRequest,
+				body:   `"code":"invalid_request"`,
+				calls:  0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "reset password with empty reset token",
+				enabled: true,
+			},
+			args: args{
+				target: "/api/v1/password-reset",
+				body:   `{"reset_token":"","new_password":"new password"}`,
+				err:    nil,
+			},
+			wants: wants{
+				status: http.StatusBadRequest,
+				body:   `"code":"invalid_request"`,
+				calls:  0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "reset password with empty new password",
+				enabled: true,
+			},
+			args: args{
+				target: "/api/v1/password-reset",
+				body:   `{"reset_token":"token","new_password":""}`,
+				err:    nil,
+			},
+			wants: wants{
+				status: http.StatusBadRequest,
+				body:   `"code":"invalid_request"`,
+				calls:  0,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tt := test
+
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			svc := &fakePasswordService{
+				PasswordService: nil,
+				calls:           0,
+				err:             tt.args.err,
+			}
+
+			value := jwt.New()
+			_ = value.Set(jwt.JwtIDKey, "jti")
+
+			parser := mock.NewTokenParser()
+			parser.On("ParseToken", testifymock.Anything).
+				Return(jwx.NewToken(banking.TokenTypeAccess, &banking.UserAccount{ID: "sub"}, value), nil)
+
+			var (
+				w = httptest.NewRecorder()
+				r = httptest.NewRequest(http.MethodPost, tt.args.target, strings.NewReader(tt.args.body))
+			)
+
+			r.Header.Set(v1.AuthorizationHeader, "Bearer access-token")
+
+			v1.NewPasswordHandler(svc, plainSecretFactory{},
+				v1.NewAuthenticationMiddleware(parser, v1.DefaultRealm).Handler).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wants.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wants.body)
+			assert.Equal(t, tt.wants.calls, svc.calls)
+		})
+	}
+}
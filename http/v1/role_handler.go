@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	banking "github.com/morozovcookie/agat-banking"
+)
+
+const (
+	// UserAccountRolesPathPrefix is the path prefix for finding roles of the user account.
+	UserAccountRolesPathPrefix = "/accounts/{accountID}/roles"
+
+	// UserAccountRolePathPrefix is the path prefix for assigning and revoking the single role of the user account.
+	UserAccountRolePathPrefix = "/accounts/{accountID}/roles/{role}"
+)
+
+var _ http.Handler = (*RoleHandler)(nil)
+
+// RoleHandler represents an HTTP handler for managing roles of user accounts.
+type RoleHandler struct {
+	*Handler
+
+	userAccountService banking.UserAccountService
+	roleService        banking.RoleService
+}
+
+// NewRoleHandler returns a new RoleHandler instance. Middlewares must authenticate the user, permissions are checked
+// by handler itself. Role changes are applied when the user account refreshes access token.
+func NewRoleHandler(
+	userAccountService banking.UserAccountService,
+	roleService banking.RoleService,
+	middlewares ...func(http.Handler) http.Handler,
+) *RoleHandler {
+	h := &RoleHandler{
+		Handler: NewHandler(),
+
+		userAccountService: userAccountService,
+		roleService:        roleService,
+	}
+
+	h.router.Route(BasePathPrefix, func(r chi.Router) {
+		r = r.With(middlewares...)
+
+		r.With(RequirePermission(banking.PermissionRolesRead)).Get(UserAccountRolesPathPrefix, h.handleFindRoles)
+		r.With(RequirePermission(banking.PermissionRolesWrite)).Put(UserAccountRolePathPrefix, h.handleAssignRole)
+		r.With(RequirePermission(banking.PermissionRolesWrite)).Delete(UserAccountRolePathPrefix, h.handleRevokeRole)
+	})
+
+	return h
+}
+
+// RolesResponse represents a set of data that will be returned for roles of the user account.
+type RolesResponse struct {
+	// Roles is the assigned roles.
+	Roles []string `json:"roles"`
+
+	// Permissions is the permissions which are granted by assigned roles.
+	Permissions []string `json:"permissions"`
+}
+
+func newRolesResponse(roles banking.Roles) *RolesResponse {
+	resp := &RolesResponse{
+		Roles:       roles.Strings(),
+		Permissions: make([]string, 0),
+	}
+
+	seen := make(map[banking.Permission]struct{})
+
+	for _, role := range roles {
+		for _, permission := range role.Permissions() {
+			if _, ok := seen[permission]; ok {
+				continue
+			}
+
+			seen[permission] = struct{}{}
+			resp.Permissions = append(resp.Permissions, permission.String())
+		}
+	}
+
+	return resp
+}
+
+func (h *RoleHandler) handleFindRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	account, err := h.userAccountService.FindUserAccountByID(ctx, banking.ID(chi.URLParam(r, "accountID")))
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	roles, err := h.roleService.FindUserAccountRoles(ctx, account.ID)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	encodeResponse(ctx, w, http.StatusOK, newRolesResponse(roles))
+}
+
+func (h *RoleHandler) handleAssignRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	account, role, err := h.decodeAccountRole(r)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	if err = h.roleService.AssignRole(ctx, account.ID, role); err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *RoleHandler) handleRevokeRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	account, role, err := h.decodeAccountRole(r)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	if err = h.roleService.RevokeRole(ctx, account.ID, role); err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *RoleHandler) decodeAccountRole(r *http.Request) (*banking.UserAccount, banking.Role, error) {
+	role, err := banking.ParseRole(chi.URLParam(r, "role"))
+	if err != nil {
+		return nil, "", err // nolint:wrapcheck
+	}
+
+	account, err := h.userAccountService.FindUserAccountByID(r.Context(), banking.ID(chi.URLParam(r, "accountID")))
+	if err != nil {
+		return nil, "", err // nolint:wrapcheck
+	}
+
+	return account, role, nil
+}
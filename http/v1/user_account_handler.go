@@ -0,0 +1,348 @@
+package v1
+
+import (
+	"context"
+	stdjson "encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/json"
+	"github.com/pkg/errors"
+)
+
+const (
+	// UserAccountsPathPrefix is the path prefix for managing user accounts.
+	UserAccountsPathPrefix = "/accounts"
+
+	// UserAccountPathPrefix is the path prefix for managing the single user account.
+	UserAccountPathPrefix = "/accounts/{accountID}"
+)
+
+var _ http.Handler = (*UserAccountHandler)(nil)
+
+// UserAccountHandler represents an HTTP handler for managing user accounts.
+type UserAccountHandler struct {
+	*Handler
+
+	userService        banking.UserService
+	userAccountService banking.UserAccountService
+	passwordHasher     banking.PasswordHasher
+	secretFactory      banking.SecretFactory
+}
+
+// NewUserAccountHandler returns a new UserAccountHandler instance. Managing user accounts is an administrative
+// operation, so middlewares must authenticate the user and every route requires accounts read or write permission.
+func NewUserAccountHandler(
+	userService banking.UserService,
+	userAccountService banking.UserAccountService,
+	passwordHasher banking.PasswordHasher,
+	secretFactory banking.SecretFactory,
+	middlewares ...func(http.Handler) http.Handler,
+) *UserAccountHandler {
+	h := &UserAccountHandler{
+		Handler: NewHandler(),
+
+		userService:        userService,
+		userAccountService: userAccountService,
+		passwordHasher:     passwordHasher,
+		secretFactory:      secretFactory,
+	}
+
+	h.router.Route(BasePathPrefix, func(r chi.Router) {
+		r = r.With(middlewares...)
+
+		r.With(RequirePermission(banking.PermissionAccountsWrite)).Post(UserAccountsPathPrefix, h.handleCreateUserAccount)
+		r.With(RequirePermission(banking.PermissionAccountsRead)).Get(UserAccountsPathPrefix, h.handleFindUserAccounts)
+		r.With(RequirePermission(banking.PermissionAccountsRead)).Get(UserAccountPathPrefix, h.handleFindUserAccount)
+		r.With(RequirePermission(banking.PermissionAccountsWrite)).Put(UserAccountPathPrefix, h.handleUpdateUserAccount)
+		r.With(RequirePermission(banking.PermissionAccountsWrite)).
+			Delete(UserAccountPathPrefix, h.handleDisableUserAccount)
+	})
+
+	return h
+}
+
+var (
+	// ErrInvalidUsername will be raised when request contains username which looks like email address, so sign in
+	// could not tell them apart.
+	ErrInvalidUsername = errors.New("invalid username")
+
+	// ErrInvalidEmailAddress will be raised when request contains malformed email address.
+	ErrInvalidEmailAddress = errors.New("invalid email address")
+
+	// ErrEmptyUserID will be raised when request does not contain identifier of user account owner.
+	ErrEmptyUserID = errors.New("empty user id")
+)
+
+// UpdateUserAccountRequest represents a set of data that should be passed for updating user account.
+type UpdateUserAccountRequest struct {
+	// Username is the user account name.
+	Username string `json:"username"`
+
+	// EmailAddress is the user email address.
+	EmailAddress string `json:"email_address"`
+}
+
+func (req *UpdateUserAccountRequest) validate() error {
+	req.Username, req.EmailAddress = strings.TrimSpace(req.Username), strings.TrimSpace(req.EmailAddress)
+
+	if req.Username == "" {
+		return invalidRequest(ErrEmptyUsername)
+	}
+
+	reg := regexp.MustCompile(EmailAddressRegex)
+
+	if reg.MatchString(req.Username) {
+		return invalidRequest(ErrInvalidUsername)
+	}
+
+	if !reg.MatchString(req.EmailAddress) {
+		return invalidRequest(ErrInvalidEmailAddress)
+	}
+
+	return nil
+}
+
+func decodeUpdateUserAccountRequest(_ context.Context, r *http.Request) (*UpdateUserAccountRequest, error) {
+	req := new(UpdateUserAccountRequest)
+
+	if err := stdjson.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, invalidRequest(errors.Wrap(err, "decode UpdateUserAccountRequest"))
+	}
+
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// CreateUserAccountRequest represents a set of data that should be passed for creating user account.
+type CreateUserAccountRequest struct {
+	UpdateUserAccountRequest
+
+	// Password is the initial user account password.
+	Password *json.SecretString `json:"password"`
+
+	// UserID is the identifier of user account owner.
+	UserID banking.ID `json:"user_id"`
+}
+
+func decodeCreateUserAccountRequest(
+	_ context.Context,
+	factory banking.SecretFactory,
+	r *http.Request,
+) (
+	*CreateUserAccountRequest,
+	error,
+) {
+	req := &CreateUserAccountRequest{
+		UpdateUserAccountRequest: UpdateUserAccountRequest{
+			Username:     "",
+			EmailAddress: "",
+		},
+		Password: json.NewSecretString(nil, factory),
+		UserID:   banking.EmptyID,
+	}
+
+	if err := stdjson.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, invalidRequest(errors.Wrap(err, "decode CreateUserAccountRequest"))
+	}
+
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := validatePassword(req.Password, ErrEmptyPassword); err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	if req.UserID == banking.EmptyID {
+		return nil, invalidRequest(ErrEmptyUserID)
+	}
+
+	return req, nil
+}
+
+// UserAccountResponse represents a set of data that will be returned for the single user account. Password hash is
+// never returned.
+type UserAccountResponse struct {
+	// ID is the user account unique identifier.
+	ID banking.ID `json:"id"`
+
+	// Username is the user account name.
+	Username string `json:"username"`
+
+	// EmailAddress is the user email address.
+	EmailAddress string `json:"email_address"`
+
+	// UserID is the identifier of user account owner.
+	UserID banking.ID `json:"user_id"`
+
+	// CreatedAt is the time when user account was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is the time when user account was updated.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+	// DisabledAt is the time when user account was disabled.
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+}
+
+func newUserAccountResponse(account *banking.UserAccount) *UserAccountResponse {
+	resp := &UserAccountResponse{
+		ID:           account.ID,
+		Username:     account.UserName,
+		EmailAddress: account.EmailAddress,
+		UserID:       banking.EmptyID,
+		CreatedAt:    account.CreatedAt,
+		UpdatedAt:    optionalTime(account.UpdateAt),
+		DisabledAt:   optionalTime(account.DisabledAt),
+	}
+
+	if account.User != nil {
+		resp.UserID = account.User.ID
+	}
+
+	return resp
+}
+
+// UserAccountsResponse represents a set of data that will be returned for the page of user accounts.
+type UserAccountsResponse struct {
+	Page
+
+	// Accounts is the page elements.
+	Accounts []*UserAccountResponse `json:"accounts"`
+}
+
+func (h *UserAccountHandler) handleCreateUserAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeCreateUserAccountRequest(ctx, h.secretFactory, r)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	user, err := h.userService.FindUserByID(ctx, req.UserID)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	passwordHash, err := h.passwordHasher.HashPassword(ctx, req.Password)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	account := &banking.UserAccount{
+		ID:           banking.EmptyID,
+		UserName:     req.Username,
+		EmailAddress: req.EmailAddress,
+		PasswordHash: passwordHash,
+		User:         user,
+		CreatedAt:    time.Time{},
+		UpdateAt:     time.Time{},
+		DisabledAt:   time.Time{},
+	}
+
+	if err = h.userAccountService.CreateUserAccount(ctx, account); err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	w.Header().Set("Location", BasePathPrefix+UserAccountsPathPrefix+"/"+account.ID.String())
+
+	encodeResponse(ctx, w, http.StatusCreated, newUserAccountResponse(account))
+}
+
+func (h *UserAccountHandler) handleFindUserAccounts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	opts, err := decodeFindOptions(r)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	aa, err := h.userAccountService.FindUserAccounts(ctx, opts)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	resp := &UserAccountsResponse{
+		Page:     newPage(opts),
+		Accounts: make([]*UserAccountResponse, 0, len(aa)),
+	}
+
+	for _, account := range aa {
+		resp.Accounts = append(resp.Accounts, newUserAccountResponse(account))
+	}
+
+	encodeResponse(ctx, w, http.StatusOK, resp)
+}
+
+func (h *UserAccountHandler) handleFindUserAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	account, err := h.userAccountService.FindUserAccountByID(ctx, banking.ID(chi.URLParam(r, "accountID")))
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	encodeResponse(ctx, w, http.StatusOK, newUserAccountResponse(account))
+}
+
+func (h *UserAccountHandler) handleUpdateUserAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeUpdateUserAccountRequest(ctx, r)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	account, err := h.userAccountService.FindUserAccountByID(ctx, banking.ID(chi.URLParam(r, "accountID")))
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	account.UserName, account.EmailAddress = req.Username, req.EmailAddress
+
+	if err = h.userAccountService.UpdateUserAccount(ctx, account); err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	encodeResponse(ctx, w, http.StatusOK, newUserAccountResponse(account))
+}
+
+func (h *UserAccountHandler) handleDisableUserAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := h.userAccountService.DisableUserAccount(ctx, banking.ID(chi.URLParam(r, "accountID"))); err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
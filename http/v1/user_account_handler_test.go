@@ -0,0 +1,196 @@
+package v1_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/jwt"
+	banking "github.com/morozovcookie/agat-banking"
+	v1 "github.com/morozovcookie/agat-banking/http/v1"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+type fakeOwnerService struct {
+	banking.UserService
+}
+
+func (svc *fakeOwnerService) FindUserByID(_ context.Context, id banking.ID) (*banking.User, error) {
+	return &banking.User{ID: id}, nil
+}
+
+type fakeCreatedUserAccountService struct {
+	banking.UserAccountService
+
+	created []*banking.UserAccount
+}
+
+func (svc *fakeCreatedUserAccountService) CreateUserAccount(_ context.Context, account *banking.UserAccount) error {
+	account.ID = "account-id"
+	svc.created = append(svc.created, account)
+
+	return nil
+}
+
+type plainPasswordHasher struct {
+	banking.PasswordHasher
+}
+
+func (plainPasswordHasher) HashPassword(_ context.Context, password banking.SecretString) (string, error) {
+	return "hash:" + password.DecryptedString(), nil
+}
+
+func TestUserAccountHandler_CreateUserAccount(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		roles []string
+		body  string
+	}
+	type wants struct {
+		status  int
+		body    string
+		created int
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "create",
+				enabled: true,
+			},
+			args: args{
+				roles: []string{"admin"},
+				body:  `{"username":"john","email_address":"john@example.com","password":"password","user_id":"user-id"}`,
+			},
+			wants: wants{
+				status:  http.StatusCreated,
+				body:    `"id":"account-id"`,
+				created: 1,
+			},
+		},
+		{
+			meta: meta{
+				name:    "create by auditor",
+				enabled: true,
+			},
+			args: args{
+				roles: []string{"auditor"},
+				body:  `{"username":"john","email_address":"john@example.com","password":"password","user_id":"user-id"}`,
+			},
+			wants: wants{
+				status:  http.StatusForbidden,
+				body:    `"code":"permission_denied"`,
+				created: 0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "null body",
+				enabled: true,
+			},
+			args: args{
+				roles: []string{"admin"},
+				body:  `null`,
+			},
+			wants: wants{
+				status:  http.StatusBadRequest,
+				body:    `"code":"invalid_request"`,
+				created: 0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "null password",
+				enabled: true,
+			},
+			args: args{
+				roles: []string{"admin"},
+				body:  `{"username":"john","email_address":"john@example.com","password":null,"user_id":"user-id"}`,
+			},
+			wants: wants{
+				status:  http.StatusBadRequest,
+				body:    `"code":"invalid_request"`,
+				created: 0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "empty password",
+				enabled: true,
+			},
+			args: args{
+				roles: []string{"admin"},
+				body:  `{"username":"john","email_address":"john@example.com","password":"","user_id":"user-id"}`,
+			},
+			wants: wants{
+				status:  http.StatusBadRequest,
+				body:    `"code":"invalid_request"`,
+				created: 0,
+			},
+		},
+		{
+			meta: meta{
+				name:    "short password",
+				enabled: true,
+			},
+			args: args{
+				roles: []string{"admin"},
+				body:  `{"username":"john","email_address":"john@example.com","password":"short","user_id":"user-id"}`,
+			},
+			wants: wants{
+				status:  http.StatusBadRequest,
+				body:    `"code":"invalid_request"`,
+				created: 0,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tt := test
+
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			svc := &fakeCreatedUserAccountService{
+				UserAccountService: nil,
+				created:            nil,
+			}
+
+			value := jwt.New()
+			_ = value.Set(jwt.JwtIDKey, "jti")
+			_ = value.Set(banking.RolesClaim, tt.args.roles)
+
+			parser := mock.NewTokenParser()
+			parser.On("ParseToken", testifymock.Anything).
+				Return(jwx.NewToken(banking.TokenTypeAccess, &banking.UserAccount{ID: "sub"}, value), nil)
+
+			var (
+				w = httptest.NewRecorder()
+				r = httptest.NewRequest(http.MethodPost, "/api/v1/accounts", strings.NewReader(tt.args.body))
+			)
+
+			r.Header.Set(v1.AuthorizationHeader, "Bearer access-token")
+
+			v1.NewUserAccountHandler(&fakeOwnerService{UserService: nil}, svc, plainPasswordHasher{},
+				plainSecretFactory{}, v1.NewAuthenticationMiddleware(parser, v1.DefaultRealm).Handler).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wants.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wants.body)
+			assert.Len(t, svc.created, tt.wants.created)
+		})
+	}
+}
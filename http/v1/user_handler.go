@@ -0,0 +1,245 @@
+package v1
+
+import (
+	"context"
+	stdjson "encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+const (
+	// UsersPathPrefix is the path prefix for managing users.
+	UsersPathPrefix = "/users"
+
+	// UserPathPrefix is the path prefix for managing the single user.
+	UserPathPrefix = "/users/{userID}"
+)
+
+var _ http.Handler = (*UserHandler)(nil)
+
+// UserHandler represents an HTTP handler for managing users.
+type UserHandler struct {
+	*Handler
+
+	userService banking.UserService
+}
+
+// NewUserHandler returns a new UserHandler instance. Managing users is an administrative operation, so middlewares
+// must authenticate the user and every route requires users read or write permission.
+func NewUserHandler(userService banking.UserService, middlewares ...func(http.Handler) http.Handler) *UserHandler {
+	h := &UserHandler{
+		Handler: NewHandler(),
+
+		userService: userService,
+	}
+
+	h.router.Route(BasePathPrefix, func(r chi.Router) {
+		r = r.With(middlewares...)
+
+		r.With(RequirePermission(banking.PermissionUsersWrite)).Post(UsersPathPrefix, h.handleCreateUser)
+		r.With(RequirePermission(banking.PermissionUsersRead)).Get(UsersPathPrefix, h.handleFindUsers)
+		r.With(RequirePermission(banking.PermissionUsersRead)).Get(UserPathPrefix, h.handleFindUser)
+		r.With(RequirePermission(banking.PermissionUsersWrite)).Put(UserPathPrefix, h.handleUpdateUser)
+		r.With(RequirePermission(banking.PermissionUsersWrite)).Delete(UserPathPrefix, h.handleDeleteUser)
+	})
+
+	return h
+}
+
+var (
+	// ErrEmptyFirstName will be raised when request does not contain user first name.
+	ErrEmptyFirstName = errors.New("empty first name")
+
+	// ErrEmptyLastName will be raised when request does not contain user last name.
+	ErrEmptyLastName = errors.New("empty last name")
+)
+
+// UserRequest represents a set of data that should be passed for creating or updating user.
+type UserRequest struct {
+	// FirstName is the user first name.
+	FirstName string `json:"first_name"`
+
+	// LastName is the user last name.
+	LastName string `json:"last_name"`
+}
+
+func decodeUserRequest(_ context.Context, r *http.Request) (*UserRequest, error) {
+	req := new(UserRequest)
+
+	if err := stdjson.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, invalidRequest(errors.Wrap(err, "decode UserRequest"))
+	}
+
+	req.FirstName, req.LastName = strings.TrimSpace(req.FirstName), strings.TrimSpace(req.LastName)
+
+	if req.FirstName == "" {
+		return nil, invalidRequest(ErrEmptyFirstName)
+	}
+
+	if req.LastName == "" {
+		return nil, invalidRequest(ErrEmptyLastName)
+	}
+
+	return req, nil
+}
+
+// UserResponse represents a set of data that will be returned for the single user.
+type UserResponse struct {
+	// ID is the user unique identifier.
+	ID banking.ID `json:"id"`
+
+	// FirstName is the user first name.
+	FirstName string `json:"first_name"`
+
+	// LastName is the user last name.
+	LastName string `json:"last_name"`
+
+	// CreatedAt is the time when user was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is the time when user was updated.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+func newUserResponse(user *banking.User) *UserResponse {
+	return &UserResponse{
+		ID:        user.ID,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: optionalTime(user.UpdatedAt),
+	}
+}
+
+// UsersResponse represents a set of data that will be returned for the page of users.
+type UsersResponse struct {
+	Page
+
+	// Users is the page elements.
+	Users []*UserResponse `json:"users"`
+}
+
+func (h *UserHandler) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeUserRequest(ctx, r)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	user := &banking.User{
+		ID:        banking.EmptyID,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		CreatedAt: time.Time{},
+		UpdatedAt: time.Time{},
+	}
+
+	if err = h.userService.CreateUser(ctx, user); err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	w.Header().Set("Location", BasePathPrefix+UsersPathPrefix+"/"+user.ID.String())
+
+	encodeResponse(ctx, w, http.StatusCreated, newUserResponse(user))
+}
+
+func (h *UserHandler) handleFindUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	opts, err := decodeFindOptions(r)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	uu, err := h.userService.FindUsers(ctx, opts)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	resp := &UsersResponse{
+		Page:  newPage(opts),
+		Users: make([]*UserResponse, 0, len(uu)),
+	}
+
+	for _, user := range uu {
+		resp.Users = append(resp.Users, newUserResponse(user))
+	}
+
+	encodeResponse(ctx, w, http.StatusOK, resp)
+}
+
+func (h *UserHandler) handleFindUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := h.userService.FindUserByID(ctx, banking.ID(chi.URLParam(r, "userID")))
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	encodeResponse(ctx, w, http.StatusOK, newUserResponse(user))
+}
+
+func (h *UserHandler) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeUserRequest(ctx, r)
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	user, err := h.userService.FindUserByID(ctx, banking.ID(chi.URLParam(r, "userID")))
+	if err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	user.FirstName, user.LastName = req.FirstName, req.LastName
+
+	if err = h.userService.UpdateUser(ctx, user); err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	encodeResponse(ctx, w, http.StatusOK, newUserResponse(user))
+}
+
+func (h *UserHandler) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := h.userService.DeleteUser(ctx, banking.ID(chi.URLParam(r, "userID"))); err != nil {
+		encodeError(ctx, w, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// optionalTime returns nil for zero time, so it is omitted in response.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
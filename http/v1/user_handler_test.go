@@ -0,0 +1,246 @@
+package v1_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/jwt"
+	banking "github.com/morozovcookie/agat-banking"
+	v1 "github.com/morozovcookie/agat-banking/http/v1"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+type fakeUserService struct {
+	banking.UserService
+
+	deleteErr error
+	opts      banking.FindOptions
+}
+
+func (svc *fakeUserService) CreateUser(_ context.Context, user *banking.User) error {
+	user.ID = "user-id"
+
+	return nil
+}
+
+func (svc *fakeUserService) FindUsers(_ context.Context, opts banking.FindOptions) ([]*banking.User, error) {
+	svc.opts = opts
+
+	return []*banking.User{}, nil
+}
+
+func (svc *fakeUserService) DeleteUser(_ context.Context, _ banking.ID) error {
+	return svc.deleteErr
+}
+
+func TestUserHandler(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		roles     []string
+		deleteErr error
+	}
+	type args struct {
+		method string
+		target string
+		body   string
+	}
+	type wants struct {
+		status int
+		body   string
+		opts   banking.FindOptions
+	}
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "create",
+				enabled: true,
+			},
+			fields: fields{
+				roles:     []string{"admin"},
+				deleteErr: nil,
+			},
+			args: args{
+				method: http.MethodPost,
+				target: "/api/v1/users",
+				body:   `{"first_name":"John","last_name":"Doe"}`,
+			},
+			wants: wants{
+				status: http.StatusCreated,
+				body:   `"id":"user-id"`,
+				opts:   banking.FindOptions{},
+			},
+		},
+		{
+			meta: meta{
+				name:    "create without last name",
+				enabled: true,
+			},
+			fields: fields{
+				roles:     []string{"admin"},
+				deleteErr: nil,
+			},
+			args: args{
+				method: http.MethodPost,
+				target: "/api/v1/users",
+				body:   `{"first_name":"John","last_name":" "}`,
+			},
+			wants: wants{
+				status: http.StatusBadRequest,
+				body:   `"code":"invalid_request"`,
+				opts:   banking.FindOptions{},
+			},
+		},
+		{
+			meta: meta{
+				name:    "list with too large limit",
+				enabled: true,
+			},
+			fields: fields{
+				roles:     []string{"admin"},
+				deleteErr: nil,
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/api/v1/users?limit=1000&offset=20",
+				body:   "",
+			},
+			wants: wants{
+				status: http.StatusOK,
+				body:   `"users":[]`,
+				opts:   banking.NewFindOptions(banking.MaxPageSize, 20),
+			},
+		},
+		{
+			meta: meta{
+				name:    "list with malformed offset",
+				enabled: true,
+			},
+			fields: fields{
+				roles:     []string{"admin"},
+				deleteErr: nil,
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/api/v1/users?offset=-1",
+				body:   "",
+			},
+			wants: wants{
+				status: http.StatusBadRequest,
+				body:   `"code":"invalid_request"`,
+				opts:   banking.FindOptions{},
+			},
+		},
+		{
+			meta: meta{
+				name:    "delete user with accounts",
+				enabled: true,
+			},
+			fields: fields{
+				roles:     []string{"admin"},
+				deleteErr: banking.ErrUserHasAccounts,
+			},
+			args: args{
+				method: http.MethodDelete,
+				target: "/api/v1/users/user-id",
+				body:   "",
+			},
+			wants: wants{
+				status: http.StatusConflict,
+				body:   `"code":"user_has_accounts"`,
+				opts:   banking.FindOptions{},
+			},
+		},
+		{
+			meta: meta{
+				name:    "create by auditor",
+				enabled: true,
+			},
+			fields: fields{
+				roles:     []string{"auditor"},
+				deleteErr: nil,
+			},
+			args: args{
+				method: http.MethodPost,
+				target: "/api/v1/users",
+				body:   `{"first_name":"John","last_name":"Doe"}`,
+			},
+			wants: wants{
+				status: http.StatusForbidden,
+				body:   `"code":"permission_denied"`,
+				opts:   banking.FindOptions{},
+			},
+		},
+		{
+			meta: meta{
+				name:    "list by cashier",
+				enabled: true,
+			},
+			fields: fields{
+				roles:     []string{"cashier"},
+				deleteErr: nil,
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/api/v1/users",
+				body:   "",
+			},
+			wants: wants{
+				status: http.StatusForbidden,
+				body:   `"code":"permission_denied"`,
+				opts:   banking.FindOptions{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tt := test
+
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			svc := &fakeUserService{
+				UserService: nil,
+				deleteErr:   tt.fields.deleteErr,
+				opts:        banking.FindOptions{},
+			}
+
+			value := jwt.New()
+			_ = value.Set(jwt.JwtIDKey, "jti")
+			_ = value.Set(banking.RolesClaim, tt.fields.roles)
+
+			parser := mock.NewTokenParser()
+			parser.On("ParseToken", testifymock.Anything).
+				Return(jwx.NewToken(banking.TokenTypeAccess, &banking.UserAccount{ID: "sub"}, value), nil)
+
+			var (
+				w = httptest.NewRecorder()
+				r = httptest.NewRequest(tt.args.method, tt.args.target, strings.NewReader(tt.args.body))
+			)
+
+			r.Header.Set(v1.AuthorizationHeader, "Bearer access-token")
+
+			v1.NewUserHandler(svc, v1.NewAuthenticationMiddleware(parser, v1.DefaultRealm).Handler).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wants.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wants.body)
+			assert.Equal(t, tt.wants.opts, svc.opts)
+		})
+	}
+}
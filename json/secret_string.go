@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 
 	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/redact"
 	"github.com/pkg/errors"
 )
 
@@ -16,9 +17,13 @@ var (
 )
 
 // SecretString represents an object that stores sensitive information.
+//
+// Sensitive information is marshaled according to the redaction policy, so request or response which is written to
+// log does not reveal it. Response fields which must carry secret to its owner are created by NewRevealedSecretString.
 type SecretString struct {
 	wrapped       banking.SecretString
 	secretFactory banking.SecretFactory
+	reveal        bool
 }
 
 // NewSecretString returns a new SecretString instance.
@@ -26,6 +31,16 @@ func NewSecretString(wrapped banking.SecretString, secretFactory banking.SecretF
 	return &SecretString{
 		wrapped:       wrapped,
 		secretFactory: secretFactory,
+		reveal:        false,
+	}
+}
+
+// NewRevealedSecretString returns a new SecretString instance which is marshaled with decrypted string.
+func NewRevealedSecretString(wrapped banking.SecretString, secretFactory banking.SecretFactory) *SecretString {
+	return &SecretString{
+		wrapped:       wrapped,
+		secretFactory: secretFactory,
+		reveal:        true,
 	}
 }
 
@@ -45,9 +60,14 @@ func (ss *SecretString) UnmarshalJSON(bb []byte) (err error) {
 }
 
 func (ss *SecretString) MarshalJSON() ([]byte, error) {
+	value := redact.Secret(ss.wrapped)
+	if ss.reveal {
+		value = ss.wrapped.DecryptedString()
+	}
+
 	buf := new(bytes.Buffer)
 
-	if err := json.NewEncoder(buf).Encode(ss.wrapped.DecryptedString()); err != nil {
+	if err := json.NewEncoder(buf).Encode(value); err != nil {
 		return nil, errors.Wrap(err, "marshal SecretString")
 	}
 
@@ -58,6 +78,12 @@ func (ss *SecretString) String() string {
 	return ss.wrapped.String()
 }
 
+// IsEmpty reports whether sensitive information was not set (e.g. field was omitted during unmarshalling or was
+// null, so pointer to SecretString was set to nil).
+func (ss *SecretString) IsEmpty() bool {
+	return ss == nil || ss.wrapped == nil
+}
+
 // EncryptedString returns sensitive information with encrypted string.
 func (ss *SecretString) EncryptedString() string {
 	return ss.wrapped.EncryptedString()
@@ -181,6 +181,7 @@ func TestSecretString_MarshalJSON(t *testing.T) {
 	}
 	type fields struct {
 		setupSecretStringFn func() *mock.SecretString
+		reveal              bool
 	}
 	type wants struct {
 		bytes []byte
@@ -194,7 +195,23 @@ func TestSecretString_MarshalJSON(t *testing.T) {
 	}{
 		{
 			meta: meta{
-				name:    "pass",
+				name:    "redacted",
+				enabled: true,
+			},
+			fields: fields{
+				setupSecretStringFn: func() *mock.SecretString {
+					return mock.NewSecretString()
+				},
+				reveal: false,
+			},
+			wants: wants{
+				bytes: bytes.NewBufferString(`"[REDACTED]"` + "\n").Bytes(),
+				err:   false,
+			},
+		},
+		{
+			meta: meta{
+				name:    "revealed",
 				enabled: true,
 			},
 			fields: fields{
@@ -206,6 +223,7 @@ func TestSecretString_MarshalJSON(t *testing.T) {
 
 					return ss
 				},
+				reveal: true,
 			},
 			wants: wants{
 				bytes: bytes.NewBufferString(`"decrypted string"` + "\n").Bytes(),
@@ -223,7 +241,13 @@ func TestSecretString_MarshalJSON(t *testing.T) {
 			var (
 				ss           = tt.fields.setupSecretStringFn()
 				secretString = NewSecretString(ss, nil)
+			)
+
+			if tt.fields.reveal {
+				secretString = NewRevealedSecretString(ss, nil)
+			}
 
+			var (
 				buf = new(bytes.Buffer)
 				err = json.NewEncoder(buf).Encode(secretString)
 			)
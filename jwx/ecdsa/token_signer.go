@@ -0,0 +1,74 @@
+package ecdsa
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"io"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidCurve will be raised when key curve does not match the signature algorithm.
+var ErrInvalidCurve = errors.New("invalid curve")
+
+var _ jwx.TokenSigner = (*ECDSATokenSigner)(nil)
+
+// ECDSATokenSigner represents a service for signing JWT.
+type ECDSATokenSigner struct {
+	alg jwa.SignatureAlgorithm
+	key jwk.Key
+}
+
+// NewES256TokenSigner returns a new ECDSATokenSigner instance which signs token with P-256 key.
+func NewES256TokenSigner(ecdsaKey *ecdsa.PrivateKey) (*ECDSATokenSigner, error) {
+	return newTokenSigner(jwa.ES256, elliptic.P256(), ecdsaKey)
+}
+
+// NewES384TokenSigner returns a new ECDSATokenSigner instance which signs token with P-384 key.
+func NewES384TokenSigner(ecdsaKey *ecdsa.PrivateKey) (*ECDSATokenSigner, error) {
+	return newTokenSigner(jwa.ES384, elliptic.P384(), ecdsaKey)
+}
+
+func newTokenSigner(
+	alg jwa.SignatureAlgorithm,
+	curve elliptic.Curve,
+	ecdsaKey *ecdsa.PrivateKey,
+) (
+	signer *ECDSATokenSigner,
+	err error,
+) {
+	if ecdsaKey.Curve != curve {
+		return nil, errors.Wrapf(ErrInvalidCurve, "init %s ECDSATokenSigner", alg)
+	}
+
+	signer = &ECDSATokenSigner{
+		alg: alg,
+		key: nil,
+	}
+
+	if signer.key, err = jwk.New(ecdsaKey); err != nil {
+		return nil, errors.Wrapf(err, "init %s ECDSATokenSigner", alg)
+	}
+
+	return signer, nil
+}
+
+// SignToken signs token.
+func (signer *ECDSATokenSigner) SignToken(_ context.Context, dst io.Writer, src jwt.Token) error {
+	signed, err := jwt.Sign(src, signer.alg, signer.key)
+	if err != nil {
+		return errors.Wrap(err, "sign token")
+	}
+
+	if _, err = io.Copy(dst, bytes.NewBuffer(signed)); err != nil {
+		return errors.Wrap(err, "sign token")
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package ecdsa
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"io"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jws"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/pkg/errors"
+)
+
+var _ jwx.TokenVerifier = (*ECDSATokenVerifier)(nil)
+
+// ECDSATokenVerifier represents a service for verifying JWT signature.
+type ECDSATokenVerifier struct {
+	alg jwa.SignatureAlgorithm
+	key jwk.Key
+}
+
+// NewES256TokenVerifier returns a new ECDSATokenVerifier instance which verifies token with P-256 key.
+func NewES256TokenVerifier(ecdsaKey *ecdsa.PublicKey) (*ECDSATokenVerifier, error) {
+	return newTokenVerifier(jwa.ES256, elliptic.P256(), ecdsaKey)
+}
+
+// NewES384TokenVerifier returns a new ECDSATokenVerifier instance which verifies token with P-384 key.
+func NewES384TokenVerifier(ecdsaKey *ecdsa.PublicKey) (*ECDSATokenVerifier, error) {
+	return newTokenVerifier(jwa.ES384, elliptic.P384(), ecdsaKey)
+}
+
+func newTokenVerifier(
+	alg jwa.SignatureAlgorithm,
+	curve elliptic.Curve,
+	ecdsaKey *ecdsa.PublicKey,
+) (
+	verifier *ECDSATokenVerifier,
+	err error,
+) {
+	if ecdsaKey.Curve != curve {
+		return nil, errors.Wrapf(ErrInvalidCurve, "init %s ECDSATokenVerifier", alg)
+	}
+
+	verifier = &ECDSATokenVerifier{
+		alg: alg,
+		key: nil,
+	}
+
+	if verifier.key, err = jwk.New(ecdsaKey); err != nil {
+		return nil, errors.Wrapf(err, "init %s ECDSATokenVerifier", alg)
+	}
+
+	return verifier, nil
+}
+
+// VerifyToken verifies signature of the signed token and writes its payload into dst.
+func (verifier *ECDSATokenVerifier) VerifyToken(_ context.Context, dst io.Writer, src io.Reader) error {
+	buf := new(bytes.Buffer)
+
+	if _, err := io.Copy(buf, src); err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	payload, err := jws.Verify(buf.Bytes(), verifier.alg, verifier.key)
+	if err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	if _, err = io.Copy(dst, bytes.NewBuffer(payload)); err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package eddsa
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"io"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/pkg/errors"
+)
+
+var _ jwx.TokenSigner = (*EdDSATokenSigner)(nil)
+
+// EdDSATokenSigner represents a service for signing JWT.
+type EdDSATokenSigner struct {
+	alg jwa.SignatureAlgorithm
+	key jwk.Key
+}
+
+// NewEdDSATokenSigner returns a new EdDSATokenSigner instance which signs token with Ed25519 key.
+func NewEdDSATokenSigner(ed25519Key ed25519.PrivateKey) (signer *EdDSATokenSigner, err error) {
+	signer = &EdDSATokenSigner{
+		alg: jwa.EdDSA,
+		key: nil,
+	}
+
+	if signer.key, err = jwk.New(ed25519Key); err != nil {
+		return nil, errors.Wrap(err, "init EdDSATokenSigner")
+	}
+
+	return signer, nil
+}
+
+// SignToken signs token.
+func (signer *EdDSATokenSigner) SignToken(_ context.Context, dst io.Writer, src jwt.Token) error {
+	signed, err := jwt.Sign(src, signer.alg, signer.key)
+	if err != nil {
+		return errors.Wrap(err, "sign token")
+	}
+
+	if _, err = io.Copy(dst, bytes.NewBuffer(signed)); err != nil {
+		return errors.Wrap(err, "sign token")
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package eddsa
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"io"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jws"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/pkg/errors"
+)
+
+var _ jwx.TokenVerifier = (*EdDSATokenVerifier)(nil)
+
+// EdDSATokenVerifier represents a service for verifying JWT signature.
+type EdDSATokenVerifier struct {
+	alg jwa.SignatureAlgorithm
+	key jwk.Key
+}
+
+// NewEdDSATokenVerifier returns a new EdDSATokenVerifier instance which verifies token with Ed25519 key.
+func NewEdDSATokenVerifier(ed25519Key ed25519.PublicKey) (verifier *EdDSATokenVerifier, err error) {
+	verifier = &EdDSATokenVerifier{
+		alg: jwa.EdDSA,
+		key: nil,
+	}
+
+	if verifier.key, err = jwk.New(ed25519Key); err != nil {
+		return nil, errors.Wrap(err, "init EdDSATokenVerifier")
+	}
+
+	return verifier, nil
+}
+
+// VerifyToken verifies signature of the signed token and writes its payload into dst.
+func (verifier *EdDSATokenVerifier) VerifyToken(_ context.Context, dst io.Writer, src io.Reader) error {
+	buf := new(bytes.Buffer)
+
+	if _, err := io.Copy(buf, src); err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	payload, err := jws.Verify(buf.Bytes(), verifier.alg, verifier.key)
+	if err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	if _, err = io.Copy(dst, bytes.NewBuffer(payload)); err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package hmac
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/pkg/errors"
+)
+
+// MinKeyLength is the minimal length of HS512 secret key in bytes (the size of SHA-512 hash output, RFC 7518 section
+// 3.2).
+const MinKeyLength = 64
+
+// ErrKeyTooShort will be raised when secret key is shorter than MinKeyLength.
+var ErrKeyTooShort = errors.New("key too short")
+
+var _ jwx.TokenSigner = (*HS512TokenSigner)(nil)
+
+// HS512TokenSigner represents a service for signing JWT. It should be used only for tokens which are exchanged between
+// internal services, because anyone who could verify token could also sign it.
+type HS512TokenSigner struct {
+	alg jwa.SignatureAlgorithm
+	key jwk.Key
+}
+
+// NewHS512TokenSigner returns a new HS512TokenSigner instance.
+func NewHS512TokenSigner(secret []byte) (signer *HS512TokenSigner, err error) {
+	if len(secret) < MinKeyLength {
+		return nil, errors.Wrap(ErrKeyTooShort, "init HS512TokenSigner")
+	}
+
+	signer = &HS512TokenSigner{
+		alg: jwa.HS512,
+		key: nil,
+	}
+
+	if signer.key, err = jwk.New(secret); err != nil {
+		return nil, errors.Wrap(err, "init HS512TokenSigner")
+	}
+
+	return signer, nil
+}
+
+// SignToken signs token.
+func (signer *HS512TokenSigner) SignToken(_ context.Context, dst io.Writer, src jwt.Token) error {
+	signed, err := jwt.Sign(src, signer.alg, signer.key)
+	if err != nil {
+		return errors.Wrap(err, "sign token")
+	}
+
+	if _, err = io.Copy(dst, bytes.NewBuffer(signed)); err != nil {
+		return errors.Wrap(err, "sign token")
+	}
+
+	return nil
+}
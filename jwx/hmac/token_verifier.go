@@ -0,0 +1,59 @@
+package hmac
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jws"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/pkg/errors"
+)
+
+var _ jwx.TokenVerifier = (*HS512TokenVerifier)(nil)
+
+// HS512TokenVerifier represents a service for verifying JWT signature.
+type HS512TokenVerifier struct {
+	alg jwa.SignatureAlgorithm
+	key jwk.Key
+}
+
+// NewHS512TokenVerifier returns a new HS512TokenVerifier instance.
+func NewHS512TokenVerifier(secret []byte) (verifier *HS512TokenVerifier, err error) {
+	if len(secret) < MinKeyLength {
+		return nil, errors.Wrap(ErrKeyTooShort, "init HS512TokenVerifier")
+	}
+
+	verifier = &HS512TokenVerifier{
+		alg: jwa.HS512,
+		key: nil,
+	}
+
+	if verifier.key, err = jwk.New(secret); err != nil {
+		return nil, errors.Wrap(err, "init HS512TokenVerifier")
+	}
+
+	return verifier, nil
+}
+
+// VerifyToken verifies signature of the signed token and writes its payload into dst.
+func (verifier *HS512TokenVerifier) VerifyToken(_ context.Context, dst io.Writer, src io.Reader) error {
+	buf := new(bytes.Buffer)
+
+	if _, err := io.Copy(buf, src); err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	payload, err := jws.Verify(buf.Bytes(), verifier.alg, verifier.key)
+	if err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	if _, err = io.Copy(dst, bytes.NewBuffer(payload)); err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package jwx
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/pkg/errors"
+)
+
+// LoadKey reads a single PEM or JWK encoded key. Algorithm is used only if key does not contain alg parameter, otherwise
+// it must be the same as in the key. HS512 secret key could be loaded only from JWK.
+func LoadKey(r io.Reader, alg jwa.SignatureAlgorithm) (jwk.Key, error) {
+	data := new(bytes.Buffer)
+
+	if _, err := io.Copy(data, r); err != nil {
+		return nil, errors.Wrap(err, "load key")
+	}
+
+	var opts []jwk.ParseOption
+	if bytes.HasPrefix(bytes.TrimSpace(data.Bytes()), []byte("-----BEGIN")) {
+		opts = append(opts, jwk.WithPEM(true))
+	}
+
+	key, err := jwk.ParseKey(data.Bytes(), opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "load key")
+	}
+
+	if key.Algorithm() == "" {
+		if err = key.Set(jwk.AlgorithmKey, alg); err != nil {
+			return nil, errors.Wrap(err, "load key")
+		}
+	}
+
+	if key.Algorithm() != alg.String() {
+		return nil, errors.Wrapf(ErrKeyAlgorithmMismatch, "load key with %s algorithm", key.Algorithm())
+	}
+
+	if err = validateKeyAlgorithm(key); err != nil {
+		return nil, errors.Wrap(err, "load key")
+	}
+
+	if key.KeyID() == "" {
+		if err = jwk.AssignKeyID(key); err != nil {
+			return nil, errors.Wrap(err, "load key")
+		}
+	}
+
+	return key, nil
+}
+
+// LoadKeyFile reads a single PEM or JWK encoded key from file.
+func LoadKeyFile(path string, alg jwa.SignatureAlgorithm) (jwk.Key, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "load key file")
+	}
+
+	defer file.Close()
+
+	key, err := LoadKey(file, alg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load key file %s", path)
+	}
+
+	return key, nil
+}
+
+// LoadKeySetFiles reads the current and retiring keys from files and returns a new KeySet instance.
+func LoadKeySetFiles(alg jwa.SignatureAlgorithm, current string, retiring ...string) (*KeySet, error) {
+	keys := make([]jwk.Key, 0, len(retiring)+1)
+
+	for _, path := range append([]string{current}, retiring...) {
+		key, err := LoadKeyFile(path, alg)
+		if err != nil {
+			return nil, errors.Wrap(err, "load key set files")
+		}
+
+		keys = append(keys, key)
+	}
+
+	set, err := NewKeySet(keys[0], keys[1:]...)
+	if err != nil {
+		return nil, errors.Wrap(err, "load key set files")
+	}
+
+	return set, nil
+}
@@ -0,0 +1,198 @@
+package jwx
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jws"
+	"github.com/lestrrat-go/jwx/jwt"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrKeyAlgorithmIsNotSet will be raised when key does not contain alg parameter.
+	ErrKeyAlgorithmIsNotSet = errors.New("key algorithm is not set")
+
+	// ErrDuplicateKeyID will be raised when two keys of the same set have the same kid parameter.
+	ErrDuplicateKeyID = errors.New("duplicate key id")
+
+	// ErrUnknownKeyID will be raised when token was signed by key which does not belong to the set.
+	ErrUnknownKeyID = errors.New("unknown key id")
+)
+
+var (
+	_ TokenSigner           = (*KeySet)(nil)
+	_ TokenVerifier         = (*KeySet)(nil)
+	_ banking.HealthChecker = (*KeySet)(nil)
+)
+
+// KeySet represents a set of keys for signing and verifying JWT of a single token type.
+//
+// Tokens are signed only by the current key, which kid is stamped into the JWS header. Retiring keys are used only for
+// verifying, so a key could be rotated without signing out every user: the previous current key should stay in the set
+// as retiring one until every token signed by it has expired.
+type KeySet struct {
+	current jwk.Key
+
+	// keys contains public keys for asymmetric algorithms and secret keys for symmetric ones.
+	keys jwk.Set
+}
+
+// NewKeySet returns a new KeySet instance.
+func NewKeySet(current jwk.Key, retiring ...jwk.Key) (*KeySet, error) {
+	set := &KeySet{
+		current: current,
+		keys:    jwk.NewSet(),
+	}
+
+	for _, key := range append([]jwk.Key{current}, retiring...) {
+		if err := set.add(key); err != nil {
+			return nil, errors.Wrap(err, "init KeySet")
+		}
+	}
+
+	return set, nil
+}
+
+func (set *KeySet) add(key jwk.Key) error {
+	if key.Algorithm() == "" {
+		return errors.Wrap(ErrKeyAlgorithmIsNotSet, "add key")
+	}
+
+	if err := validateKeyAlgorithm(key); err != nil {
+		return errors.Wrap(err, "add key")
+	}
+
+	if key.KeyID() == "" {
+		if err := jwk.AssignKeyID(key); err != nil {
+			return errors.Wrap(err, "add key")
+		}
+	}
+
+	if _, ok := set.keys.LookupKeyID(key.KeyID()); ok {
+		return errors.Wrapf(ErrDuplicateKeyID, "add key %s", key.KeyID())
+	}
+
+	if key.KeyType() != jwa.OctetSeq {
+		var err error
+
+		if key, err = key.PublicKey(); err != nil {
+			return errors.Wrap(err, "add key")
+		}
+	}
+
+	set.keys.Add(key)
+
+	return nil
+}
+
+// SignToken signs token by the current key.
+func (set *KeySet) SignToken(_ context.Context, dst io.Writer, src jwt.Token) error {
+	signed, err := jwt.Sign(src, jwa.SignatureAlgorithm(set.current.Algorithm()), set.current)
+	if err != nil {
+		return errors.Wrap(err, "sign token")
+	}
+
+	if _, err = io.Copy(dst, bytes.NewBuffer(signed)); err != nil {
+		return errors.Wrap(err, "sign token")
+	}
+
+	return nil
+}
+
+// VerifyToken verifies signature of the signed token by the key with the same kid as in the JWS header and writes its
+// payload into dst.
+func (set *KeySet) VerifyToken(_ context.Context, dst io.Writer, src io.Reader) error {
+	buf := new(bytes.Buffer)
+
+	if _, err := io.Copy(buf, src); err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	payload, err := set.verify(buf.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	if _, err = io.Copy(dst, bytes.NewBuffer(payload)); err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	return nil
+}
+
+func (set *KeySet) verify(signed []byte) ([]byte, error) {
+	msg, err := jws.Parse(signed)
+	if err != nil {
+		return nil, errors.Wrap(err, "verify")
+	}
+
+	var kid string
+	if signatures := msg.Signatures(); len(signatures) > 0 {
+		kid = signatures[0].ProtectedHeaders().KeyID()
+	}
+
+	// Tokens which were issued before key rotation was introduced do not contain kid, so every key should be tried.
+	if kid == "" {
+		payload, err := jws.VerifySet(signed, set.keys)
+
+		return payload, errors.Wrap(err, "verify")
+	}
+
+	key, ok := set.keys.LookupKeyID(kid)
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownKeyID, "verify %s", kid)
+	}
+
+	// Algorithm is taken from the key, not from the header, so it could not be substituted by the token issuer.
+	payload, err := jws.Verify(signed, jwa.SignatureAlgorithm(key.Algorithm()), key)
+	if err != nil {
+		return nil, errors.Wrap(err, "verify")
+	}
+
+	return payload, nil
+}
+
+// PublicKeys returns public keys of the set, which could be shared for verifying tokens outside.
+// Symmetric keys are never returned.
+func (set *KeySet) PublicKeys(ctx context.Context) (jwk.Set, error) {
+	public := jwk.NewSet()
+
+	for iter := set.keys.Iterate(ctx); iter.Next(ctx); {
+		key, _ := iter.Pair().Value.(jwk.Key)
+
+		if key.KeyType() == jwa.OctetSeq {
+			continue
+		}
+
+		public.Add(key)
+	}
+
+	return public, nil
+}
+
+// CheckHealth signs the probe token by the current key and verifies it, so unusable key (e.g. hardware key which
+// became unavailable) is detected before users could not sign in.
+func (set *KeySet) CheckHealth(ctx context.Context) error {
+	probe := jwt.New()
+
+	if err := probe.Set(jwt.JwtIDKey, "health-check"); err != nil {
+		return errors.Wrap(err, "check key set health")
+	}
+
+	signed := new(bytes.Buffer)
+
+	if err := set.SignToken(ctx, signed, probe); err != nil {
+		return errors.Wrap(err, "check key set health")
+	}
+
+	if err := set.VerifyToken(ctx, io.Discard, signed); err != nil {
+		return errors.Wrap(err, "check key set health")
+	}
+
+	return nil
+}
@@ -0,0 +1,327 @@
+package jwx_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	stdrsa "crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jws"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRS512Key(t *testing.T) jwk.Key {
+	t.Helper()
+
+	rsaKey, err := stdrsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	err = pem.Encode(buf, &pem.Block{
+		Type:    "RSA PRIVATE KEY",
+		Headers: nil,
+		Bytes:   x509.MarshalPKCS1PrivateKey(rsaKey),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := jwx.LoadKey(buf, jwa.RS512)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func signWithKeySet(t *testing.T, set *jwx.KeySet) []byte {
+	t.Helper()
+
+	token := jwt.New()
+	_ = token.Set(jwt.JwtIDKey, "jti")
+
+	buf := new(bytes.Buffer)
+
+	if err := set.SignToken(context.Background(), buf, token); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		previous = newRS512Key(t)
+		current  = newRS512Key(t)
+	)
+
+	before, err := jwx.NewKeySet(previous)
+	assert.NoError(t, err)
+
+	during, err := jwx.NewKeySet(current, previous)
+	assert.NoError(t, err)
+
+	after, err := jwx.NewKeySet(current)
+	assert.NoError(t, err)
+
+	oldToken := signWithKeySet(t, before)
+	newToken := signWithKeySet(t, during)
+
+	msg, err := jws.Parse(newToken)
+	assert.NoError(t, err)
+	assert.Equal(t, current.KeyID(), msg.Signatures()[0].ProtectedHeaders().KeyID())
+
+	assert.NoError(t, during.VerifyToken(ctx, new(bytes.Buffer), bytes.NewReader(oldToken)))
+	assert.NoError(t, during.VerifyToken(ctx, new(bytes.Buffer), bytes.NewReader(newToken)))
+	assert.NoError(t, after.VerifyToken(ctx, new(bytes.Buffer), bytes.NewReader(newToken)))
+	assert.ErrorIs(t, after.VerifyToken(ctx, new(bytes.Buffer), bytes.NewReader(oldToken)), jwx.ErrUnknownKeyID)
+}
+
+func TestKeySet_PublicKeys(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		previous = newRS512Key(t)
+		current  = newRS512Key(t)
+	)
+
+	set, err := jwx.NewKeySet(current, previous)
+	assert.NoError(t, err)
+
+	public, err := set.PublicKeys(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, public.Len())
+
+	for iter := public.Iterate(ctx); iter.Next(ctx); {
+		key, _ := iter.Pair().Value.(jwk.Key)
+
+		assert.IsType(t, (*stdrsa.PublicKey)(nil), rawKey(t, key))
+	}
+
+	_, err = jwx.NewKeySet(current, current)
+	assert.ErrorIs(t, err, jwx.ErrDuplicateKeyID)
+}
+
+func rawKey(t *testing.T, key jwk.Key) interface{} {
+	t.Helper()
+
+	var raw interface{}
+
+	if err := key.Raw(&raw); err != nil {
+		t.Fatal(err)
+	}
+
+	return raw
+}
+
+func TestKeySet_SignatureAlgorithms(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		alg    jwa.SignatureAlgorithm
+		rawKey func() (interface{}, error)
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "ES256",
+				enabled: true,
+			},
+			args: args{
+				alg: jwa.ES256,
+				rawKey: func() (interface{}, error) {
+					return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "ES384",
+				enabled: true,
+			},
+			args: args{
+				alg: jwa.ES384,
+				rawKey: func() (interface{}, error) {
+					return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "EdDSA",
+				enabled: true,
+			},
+			args: args{
+				alg: jwa.EdDSA,
+				rawKey: func() (interface{}, error) {
+					_, key, err := ed25519.GenerateKey(rand.Reader)
+
+					return key, err
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "HS512",
+				enabled: true,
+			},
+			args: args{
+				alg: jwa.HS512,
+				rawKey: func() (interface{}, error) {
+					key := make([]byte, 64)
+					_, err := rand.Read(key)
+
+					return key, err
+				},
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "ES256 with P-384 key",
+				enabled: true,
+			},
+			args: args{
+				alg: jwa.ES256,
+				rawKey: func() (interface{}, error) {
+					return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+				},
+			},
+			wants: wants{
+				err: jwx.ErrKeyAlgorithmMismatch,
+			},
+		},
+		{
+			meta: meta{
+				name:    "ES384 with P-256 key",
+				enabled: true,
+			},
+			args: args{
+				alg: jwa.ES384,
+				rawKey: func() (interface{}, error) {
+					return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				},
+			},
+			wants: wants{
+				err: jwx.ErrKeyAlgorithmMismatch,
+			},
+		},
+		{
+			meta: meta{
+				name:    "ES384 with P-521 public key",
+				enabled: true,
+			},
+			args: args{
+				alg: jwa.ES384,
+				rawKey: func() (interface{}, error) {
+					key, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+					if err != nil {
+						return nil, err
+					}
+
+					return &key.PublicKey, nil
+				},
+			},
+			wants: wants{
+				err: jwx.ErrKeyAlgorithmMismatch,
+			},
+		},
+		{
+			meta: meta{
+				name:    "key type mismatch",
+				enabled: true,
+			},
+			args: args{
+				alg: jwa.ES256,
+				rawKey: func() (interface{}, error) {
+					_, key, err := ed25519.GenerateKey(rand.Reader)
+
+					return key, err
+				},
+			},
+			wants: wants{
+				err: jwx.ErrKeyAlgorithmMismatch,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			raw, err := tt.args.rawKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			key, err := jwk.New(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			encoded, err := json.Marshal(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			key, err = jwx.LoadKey(bytes.NewReader(encoded), tt.args.alg)
+			assert.ErrorIs(t, err, tt.wants.err)
+
+			if tt.wants.err != nil {
+				return
+			}
+
+			set, err := jwx.NewKeySet(key)
+			assert.NoError(t, err)
+
+			signed := signWithKeySet(t, set)
+			assert.NoError(t, set.VerifyToken(context.Background(), new(bytes.Buffer), bytes.NewReader(signed)))
+
+			public, err := set.PublicKeys(context.Background())
+			assert.NoError(t, err)
+
+			if tt.args.alg == jwa.HS512 {
+				assert.Equal(t, 0, public.Len())
+			} else {
+				assert.Equal(t, 1, public.Len())
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package rsa
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"io"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jws"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/pkg/errors"
+)
+
+var _ jwx.TokenVerifier = (*RS512TokenVerifier)(nil)
+
+// RS512TokenVerifier represents a service for verifying JWT signature.
+type RS512TokenVerifier struct {
+	alg jwa.SignatureAlgorithm
+	key jwk.Key
+}
+
+// NewRS512TokenVerifier returns a new RS512TokenVerifier instance.
+func NewRS512TokenVerifier(rsaKey *rsa.PublicKey) (verifier *RS512TokenVerifier, err error) {
+	verifier = &RS512TokenVerifier{
+		alg: jwa.RS512,
+		key: nil,
+	}
+
+	if verifier.key, err = jwk.New(rsaKey); err != nil {
+		return nil, errors.Wrap(err, "init RS512TokenVerifier")
+	}
+
+	return verifier, nil
+}
+
+// VerifyToken verifies signature of the signed token and writes its payload into dst.
+func (verifier *RS512TokenVerifier) VerifyToken(_ context.Context, dst io.Writer, src io.Reader) error {
+	buf := new(bytes.Buffer)
+
+	if _, err := io.Copy(buf, src); err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	payload, err := jws.Verify(buf.Bytes(), verifier.alg, verifier.key)
+	if err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	if _, err = io.Copy(dst, bytes.NewBuffer(payload)); err != nil {
+		return errors.Wrap(err, "verify token")
+	}
+
+	return nil
+}
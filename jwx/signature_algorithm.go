@@ -0,0 +1,70 @@
+package jwx
+
+import (
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrUnsupportedSignatureAlgorithm will be raised when signature algorithm could not be used for signing token.
+	ErrUnsupportedSignatureAlgorithm = errors.New("unsupported signature algorithm")
+
+	// ErrKeyAlgorithmMismatch will be raised when key could not be used with the configured signature algorithm.
+	ErrKeyAlgorithmMismatch = errors.New("key algorithm mismatch")
+)
+
+// supportedSignatureAlgorithms maps every supported signature algorithm to the key type which it requires.
+var supportedSignatureAlgorithms = map[jwa.SignatureAlgorithm]jwa.KeyType{
+	jwa.RS512: jwa.RSA,
+	jwa.ES256: jwa.EC,
+	jwa.ES384: jwa.EC,
+	jwa.EdDSA: jwa.OKP,
+	jwa.HS512: jwa.OctetSeq,
+}
+
+// signatureAlgorithmCurves maps every supported ECDSA signature algorithm to the elliptic curve which it requires.
+var signatureAlgorithmCurves = map[jwa.SignatureAlgorithm]jwa.EllipticCurveAlgorithm{
+	jwa.ES256: jwa.P256,
+	jwa.ES384: jwa.P384,
+}
+
+// curveKey is the key which is defined on elliptic curve (both private and public ECDSA keys).
+type curveKey interface {
+	Crv() jwa.EllipticCurveAlgorithm
+}
+
+// ParseSignatureAlgorithm returns the signature algorithm by its name (e.g. from configuration).
+func ParseSignatureAlgorithm(name string) (jwa.SignatureAlgorithm, error) {
+	alg := jwa.SignatureAlgorithm(name)
+
+	if _, ok := supportedSignatureAlgorithms[alg]; !ok {
+		return "", errors.Wrapf(ErrUnsupportedSignatureAlgorithm, "parse signature algorithm %s", name)
+	}
+
+	return alg, nil
+}
+
+func validateKeyAlgorithm(key jwk.Key) error {
+	alg := jwa.SignatureAlgorithm(key.Algorithm())
+
+	keyType, ok := supportedSignatureAlgorithms[alg]
+	if !ok {
+		return errors.Wrapf(ErrUnsupportedSignatureAlgorithm, "validate key algorithm %s", alg)
+	}
+
+	if key.KeyType() != keyType {
+		return errors.Wrapf(ErrKeyAlgorithmMismatch, "validate key algorithm %s for %s key", alg, key.KeyType())
+	}
+
+	curve, ok := signatureAlgorithmCurves[alg]
+	if !ok {
+		return nil
+	}
+
+	if ecKey, ok := key.(curveKey); !ok || ecKey.Crv() != curve {
+		return errors.Wrapf(ErrKeyAlgorithmMismatch, "validate key algorithm %s for key curve", alg)
+	}
+
+	return nil
+}
@@ -76,6 +76,25 @@ func (t *Token) Account() *banking.UserAccount {
 	return t.account
 }
 
+// Issuer returns the principal that issued the token.
+func (t *Token) Issuer() string {
+	return t.value.Issuer()
+}
+
+// Audience returns the recipients that the token is intended for.
+func (t *Token) Audience() []string {
+	return t.value.Audience()
+}
+
+// Claim returns the private claim value (e.g. roles or session identifier).
+func (t *Token) Claim(name string) (interface{}, bool) {
+	if _, ok := reservedClaims[name]; ok {
+		return nil, false
+	}
+
+	return t.value.Get(name)
+}
+
 // IssuedAt returns the UTC time when token was issued.
 func (t *Token) IssuedAt() time.Time {
 	return t.value.IssuedAt()
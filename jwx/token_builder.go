@@ -9,16 +9,34 @@ import (
 	"github.com/pkg/errors"
 )
 
+// TypeKey is the name of private claim which contains the token type.
+const TypeKey = `typ`
+
+// reservedClaims contains claims which could be set up only by the dedicated TokenBuilder methods.
+var reservedClaims = map[string]struct{}{
+	jwt.AudienceKey:   {},
+	jwt.ExpirationKey: {},
+	jwt.IssuedAtKey:   {},
+	jwt.IssuerKey:     {},
+	jwt.JwtIDKey:      {},
+	jwt.NotBeforeKey:  {},
+	jwt.SubjectKey:    {},
+	TypeKey:           {},
+}
+
 var _ banking.TokenBuilder = (*TokenBuilder)(nil)
 
 // TokenBuilder represents a service for parametrized token building.
 type TokenBuilder struct {
 	tokenType banking.TokenType
 	expiresIn time.Duration
+	issuer    string
+	audience  []string
 	subject   *banking.UserAccount
 	until     time.Time
 
 	token jwt.Token
+	err   error
 
 	identifierGenerator banking.IdentifierGenerator
 	secretFactory       banking.SecretFactory
@@ -31,9 +49,13 @@ func NewTokenBuilder(tokenType banking.TokenType, opts ...TokenBuilderOption) *T
 	builder := &TokenBuilder{
 		tokenType: tokenType,
 		expiresIn: DefaultTokenExpiresIn,
+		issuer:    "",
+		audience:  nil,
 		subject:   nil,
+		until:     time.Time{},
 
 		token: jwt.New(),
+		err:   nil,
 
 		identifierGenerator: nil,
 		secretFactory:       nil,
@@ -41,7 +63,7 @@ func NewTokenBuilder(tokenType banking.TokenType, opts ...TokenBuilderOption) *T
 		timer:               nil,
 	}
 
-	_ = builder.token.Set(`typ`, tokenType.String())
+	_ = builder.token.Set(TypeKey, tokenType.String())
 
 	for _, opt := range opts {
 		opt.apply(builder)
@@ -52,39 +74,73 @@ func NewTokenBuilder(tokenType banking.TokenType, opts ...TokenBuilderOption) *T
 
 // WithID sets up the token unique identifier.
 func (builder *TokenBuilder) WithID(jti banking.ID) banking.TokenBuilder {
-	_ = builder.token.Set(jwt.JwtIDKey, jti.String())
+	builder.set(jwt.JwtIDKey, jti.String())
 
 	return builder
 }
 
 // WithAccount sets up the subject of token.
 func (builder *TokenBuilder) WithAccount(sub *banking.UserAccount) banking.TokenBuilder {
-	_ = builder.token.Set(jwt.SubjectKey, sub.ID.String())
+	builder.set(jwt.SubjectKey, sub.ID.String())
 
 	builder.subject = sub
 
 	return builder
 }
 
+// WithIssuer sets up the principal that issued the token.
+func (builder *TokenBuilder) WithIssuer(iss string) banking.TokenBuilder {
+	builder.issuer = iss
+
+	return builder
+}
+
+// WithAudience sets up the recipients that the token is intended for.
+func (builder *TokenBuilder) WithAudience(aud ...string) banking.TokenBuilder {
+	builder.audience = aud
+
+	return builder
+}
+
+// WithClaim sets up the private claim (e.g. roles or session identifier). Registered claims could not be set up this
+// way, Build will return banking.ErrTokenReservedClaim.
+func (builder *TokenBuilder) WithClaim(name string, value interface{}) banking.TokenBuilder {
+	if _, ok := reservedClaims[name]; ok {
+		builder.err = errors.Wrapf(banking.ErrTokenReservedClaim, "set up %s", name)
+
+		return builder
+	}
+
+	builder.set(name, value)
+
+	return builder
+}
+
+// WithLifetime sets up the time duration which token will be valid since it did issue. It overrides the default
+// lifetime for the single token, but is ignored if expiration was set up explicitly.
+func (builder *TokenBuilder) WithLifetime(lifetime time.Duration) banking.TokenBuilder {
+	builder.expiresIn = lifetime
+
+	return builder
+}
+
 // WithIssuedAt sets up the UTC time when token was issued.
 func (builder *TokenBuilder) WithIssuedAt(iat time.Time) banking.TokenBuilder {
-	_ = builder.token.Set(jwt.IssuerKey, iat)
+	builder.set(jwt.IssuedAtKey, iat)
 
 	return builder
 }
 
 // WithExpiration sets up the UTC time which after token will be expired.
 func (builder *TokenBuilder) WithExpiration(exp time.Time) banking.TokenBuilder {
-	_ = builder.token.Set(jwt.ExpirationKey, exp)
-
-	builder.until = exp
+	builder.set(jwt.ExpirationKey, exp)
 
 	return builder
 }
 
 // WithNotBefore sets up the time before which the token is not valid.
 func (builder *TokenBuilder) WithNotBefore(nbf time.Time) banking.TokenBuilder {
-	_ = builder.token.Set(jwt.NotBeforeKey, nbf)
+	builder.set(jwt.NotBeforeKey, nbf)
 
 	return builder
 }
@@ -97,13 +153,27 @@ func (builder *TokenBuilder) WithValidUntil(until time.Time) banking.TokenBuilde
 	return builder
 }
 
+// set keeps only the first error, so the builder methods could be chained and the error will be returned by Build.
+func (builder *TokenBuilder) set(name string, value interface{}) {
+	if err := builder.token.Set(name, value); err != nil && builder.err == nil {
+		builder.err = errors.Wrapf(err, "set up %s", name)
+	}
+}
+
 // Build creates and returns the Token.
 func (builder *TokenBuilder) Build(ctx context.Context) (banking.Token, error) {
+	if builder.err != nil {
+		return nil, errors.Wrap(builder.err, "build token")
+	}
+
+	// Order matters: exp and nbf are calculated from iat.
 	for _, fn := range []func(ctx context.Context) error{
 		builder.setUpID,
-		builder.setupIssuedAt,
+		builder.setUpIssuer,
+		builder.setUpAudience,
+		builder.setUpIssuedAt,
 		builder.setUpExpiration,
-		builder.setupUpNotBefore,
+		builder.setUpNotBefore,
 	} {
 		if err := fn(ctx); err != nil {
 			return nil, errors.Wrap(err, "build token")
@@ -112,6 +182,11 @@ func (builder *TokenBuilder) Build(ctx context.Context) (banking.Token, error) {
 
 	token := NewToken(builder.tokenType, builder.subject, builder.token)
 
+	token.until = builder.until
+	if token.until.IsZero() {
+		token.until = builder.token.Expiration()
+	}
+
 	if err := token.Sign(ctx, builder.tokenSigner, builder.secretFactory); err != nil {
 		return nil, errors.Wrap(err, "build token")
 	}
@@ -132,55 +207,47 @@ func (builder *TokenBuilder) setUpID(ctx context.Context) error {
 	return builder.token.Set(jwt.JwtIDKey, jti.String())
 }
 
-func (builder *TokenBuilder) setupIssuedAt(ctx context.Context) error {
-	if _, ok := builder.token.Get(jwt.IssuedAtKey); ok {
+func (builder *TokenBuilder) setUpIssuer(_ context.Context) error {
+	if builder.issuer == "" {
 		return nil
 	}
 
-	iat, err := builder.timer.Time(ctx)
-	if err != nil {
-		return errors.Wrap(err, "set up iat")
-	}
-
-	if err = builder.token.Set(jwt.IssuedAtKey, iat); err != nil {
-		return errors.Wrap(err, "set up iat")
-	}
+	return builder.token.Set(jwt.IssuerKey, builder.issuer)
+}
 
-	if err = builder.setUpExpiration(ctx); err != nil {
-		return errors.Wrap(err, "set up iat")
+func (builder *TokenBuilder) setUpAudience(_ context.Context) error {
+	if len(builder.audience) == 0 {
+		return nil
 	}
 
-	return builder.setupUpNotBefore(ctx)
+	return builder.token.Set(jwt.AudienceKey, builder.audience)
 }
 
-func (builder *TokenBuilder) setUpExpiration(ctx context.Context) error {
-	if _, ok := builder.token.Get(jwt.ExpirationKey); ok {
+func (builder *TokenBuilder) setUpIssuedAt(ctx context.Context) error {
+	if _, ok := builder.token.Get(jwt.IssuedAtKey); ok {
 		return nil
 	}
 
-	if err := builder.setupIssuedAt(ctx); err != nil {
-		return errors.Wrap(err, "set up exp")
+	iat, err := builder.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "set up iat")
 	}
 
-	exp := builder.token.IssuedAt().Add(builder.expiresIn)
+	return builder.token.Set(jwt.IssuedAtKey, iat)
+}
 
-	if err := builder.token.Set(jwt.ExpirationKey, exp); err != nil {
-		return errors.Wrap(err, "set up exp")
+func (builder *TokenBuilder) setUpExpiration(_ context.Context) error {
+	if _, ok := builder.token.Get(jwt.ExpirationKey); ok {
+		return nil
 	}
 
-	builder.until = exp
-
-	return nil
+	return builder.token.Set(jwt.ExpirationKey, builder.token.IssuedAt().Add(builder.expiresIn))
 }
 
-func (builder *TokenBuilder) setupUpNotBefore(ctx context.Context) error {
+func (builder *TokenBuilder) setUpNotBefore(_ context.Context) error {
 	if _, ok := builder.token.Get(jwt.NotBeforeKey); ok {
 		return nil
 	}
 
-	if err := builder.setupIssuedAt(ctx); err != nil {
-		return errors.Wrap(err, "set up nbf")
-	}
-
 	return builder.token.Set(jwt.NotBeforeKey, builder.token.IssuedAt())
 }
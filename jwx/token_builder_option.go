@@ -62,3 +62,17 @@ func WithTimer(timer banking.Timer) TokenBuilderOption {
 		builder.timer = timer
 	})
 }
+
+// WithIssuer sets up the principal that issues every token.
+func WithIssuer(iss string) TokenBuilderOption {
+	return tokenBuilderOptionFunc(func(builder *TokenBuilder) {
+		builder.issuer = iss
+	})
+}
+
+// WithAudience sets up the recipients that every token is intended for.
+func WithAudience(aud ...string) TokenBuilderOption {
+	return tokenBuilderOptionFunc(func(builder *TokenBuilder) {
+		builder.audience = aud
+	})
+}
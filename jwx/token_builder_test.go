@@ -0,0 +1,236 @@
+package jwx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func newTokenBuilderOptions(t *testing.T, now time.Time) []jwx.TokenBuilderOption {
+	t.Helper()
+
+	key, err := jwk.New([]byte("0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = key.Set(jwk.AlgorithmKey, jwa.HS512)
+
+	signer, err := jwx.NewKeySet(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timer := mock.NewTimer()
+	timer.On("Time").
+		Return(now, nil)
+
+	generator := mock.NewIdentifierGenerator()
+	generator.On("GenerateIdentifier").
+		Return(banking.ID("jti"), nil)
+
+	factory := mock.NewSecretFactory()
+	factory.On("CreateFromDecryptedData", testifymock.Anything).
+		Return(mock.NewSecretString(), nil)
+
+	return []jwx.TokenBuilderOption{
+		jwx.WithSigner(signer),
+		jwx.WithSecretFactory(factory),
+		jwx.WithIdentifierGenerator(generator),
+		jwx.WithTimer(timer),
+	}
+}
+
+func TestTokenBuilder_Build(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		build func(builder banking.TokenBuilder) banking.TokenBuilder
+	}
+	type wants struct {
+		issuedAt   time.Time
+		expiration time.Time
+		notBefore  time.Time
+		until      time.Time
+		issuer     string
+		audience   []string
+		claims     map[string]interface{}
+		err        error
+	}
+
+	var (
+		now     = time.Unix(1637000000, 0).UTC()
+		account = &banking.UserAccount{ID: "sub"}
+	)
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "defaults",
+				enabled: true,
+			},
+			args: args{
+				build: func(builder banking.TokenBuilder) banking.TokenBuilder {
+					return builder.WithAccount(account)
+				},
+			},
+			wants: wants{
+				issuedAt:   now,
+				expiration: now.Add(jwx.DefaultAccessTokenExpiresIn),
+				notBefore:  now,
+				until:      now.Add(jwx.DefaultAccessTokenExpiresIn),
+				issuer:     "",
+				audience:   nil,
+				claims:     nil,
+				err:        nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "issued at",
+				enabled: true,
+			},
+			args: args{
+				build: func(builder banking.TokenBuilder) banking.TokenBuilder {
+					return builder.WithAccount(account).
+						WithIssuedAt(now.Add(-time.Minute))
+				},
+			},
+			wants: wants{
+				issuedAt:   now.Add(-time.Minute),
+				expiration: now.Add(-time.Minute).Add(jwx.DefaultAccessTokenExpiresIn),
+				notBefore:  now.Add(-time.Minute),
+				until:      now.Add(-time.Minute).Add(jwx.DefaultAccessTokenExpiresIn),
+				issuer:     "",
+				audience:   nil,
+				claims:     nil,
+				err:        nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "full claims",
+				enabled: true,
+			},
+			args: args{
+				build: func(builder banking.TokenBuilder) banking.TokenBuilder {
+					return builder.WithAccount(account).
+						WithIssuer("bankingd").
+						WithAudience("banking", "reports").
+						WithClaim("sid", "session").
+						WithLifetime(time.Minute).
+						WithValidUntil(now.Add(time.Second))
+				},
+			},
+			wants: wants{
+				issuedAt:   now,
+				expiration: now.Add(time.Minute),
+				notBefore:  now,
+				until:      now.Add(time.Second),
+				issuer:     "bankingd",
+				audience:   []string{"banking", "reports"},
+				claims: map[string]interface{}{
+					"sid": "session",
+				},
+				err: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "explicit expiration",
+				enabled: true,
+			},
+			args: args{
+				build: func(builder banking.TokenBuilder) banking.TokenBuilder {
+					return builder.WithAccount(account).
+						WithLifetime(time.Minute).
+						WithExpiration(now.Add(time.Hour))
+				},
+			},
+			wants: wants{
+				issuedAt:   now,
+				expiration: now.Add(time.Hour),
+				notBefore:  now,
+				until:      now.Add(time.Hour),
+				issuer:     "",
+				audience:   nil,
+				claims:     nil,
+				err:        nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "reserved claim",
+				enabled: true,
+			},
+			args: args{
+				build: func(builder banking.TokenBuilder) banking.TokenBuilder {
+					return builder.WithAccount(account).
+						WithClaim("typ", "refresh")
+				},
+			},
+			wants: wants{
+				issuedAt:   time.Time{},
+				expiration: time.Time{},
+				notBefore:  time.Time{},
+				until:      time.Time{},
+				issuer:     "",
+				audience:   nil,
+				claims:     nil,
+				err:        banking.ErrTokenReservedClaim,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			ctx := context.Background()
+
+			token, err := tt.args.build(jwx.NewAccessTokenBuilderCreator(newTokenBuilderOptions(t, now)...).
+				CreateTokenBuilder(ctx)).
+				Build(ctx)
+			assert.ErrorIs(t, err, tt.wants.err)
+
+			if tt.wants.err != nil {
+				return
+			}
+
+			assert.Equal(t, banking.ID("jti"), token.ID())
+			assert.Equal(t, banking.TokenTypeAccess, token.Type())
+			assert.Equal(t, account, token.Account())
+			assert.True(t, tt.wants.issuedAt.Equal(token.IssuedAt()))
+			assert.True(t, tt.wants.expiration.Equal(token.Expiration()))
+			assert.True(t, tt.wants.notBefore.Equal(token.NotBefore()))
+			assert.True(t, tt.wants.until.Equal(token.Until()))
+			assert.Equal(t, tt.wants.issuer, token.Issuer())
+			assert.Equal(t, tt.wants.audience, token.Audience())
+
+			for name, value := range tt.wants.claims {
+				claim, ok := token.Claim(name)
+				assert.True(t, ok)
+				assert.Equal(t, value, claim)
+			}
+
+			_, ok := token.Claim("typ")
+			assert.False(t, ok)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package jwx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jws"
+	"github.com/lestrrat-go/jwx/jwt"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+var _ banking.TokenParser = (*TokenParser)(nil)
+
+// TokenParser represents a service for parsing token.
+type TokenParser struct {
+	tokenType banking.TokenType
+	clockSkew time.Duration
+	issuer    string
+	audience  string
+
+	tokenVerifier TokenVerifier
+	secretFactory banking.SecretFactory
+	timer         banking.Timer
+}
+
+// NewAccessTokenParser returns a new TokenParser instance that accepts only access token.
+func NewAccessTokenParser(opts ...TokenParserOption) *TokenParser {
+	return NewTokenParser(banking.TokenTypeAccess, opts...)
+}
+
+// NewRefreshTokenParser returns a new TokenParser instance that accepts only refresh token.
+func NewRefreshTokenParser(opts ...TokenParserOption) *TokenParser {
+	return NewTokenParser(banking.TokenTypeRefresh, opts...)
+}
+
+// NewTokenParser returns a new TokenParser instance.
+func NewTokenParser(tokenType banking.TokenType, opts ...TokenParserOption) *TokenParser {
+	parser := &TokenParser{
+		tokenType: tokenType,
+		clockSkew: DefaultClockSkew,
+		issuer:    "",
+		audience:  "",
+
+		tokenVerifier: nil,
+		secretFactory: nil,
+		timer:         nil,
+	}
+
+	for _, opt := range opts {
+		opt.apply(parser)
+	}
+
+	return parser
+}
+
+// ParseToken parse and returns a Token.
+// Return banking.ErrTokenMalformed, banking.ErrTokenInvalidSignature, banking.ErrTokenExpired,
+// banking.ErrTokenNotYetValid, banking.ErrTokenWrongType, banking.ErrTokenInvalidIssuer or
+// banking.ErrTokenInvalidAudience if token could not be accepted.
+func (parser *TokenParser) ParseToken(ctx context.Context, r io.Reader) (banking.Token, error) {
+	signed := new(bytes.Buffer)
+
+	if _, err := io.Copy(signed, r); err != nil {
+		return nil, errors.Wrap(err, "parse token")
+	}
+
+	value, err := parser.verify(ctx, signed.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "parse token")
+	}
+
+	if err = parser.validate(ctx, value); err != nil {
+		return nil, errors.Wrap(err, "parse token")
+	}
+
+	account := &banking.UserAccount{
+		ID:           banking.ID(value.Subject()),
+		UserName:     "",
+		EmailAddress: "",
+		PasswordHash: "",
+		User:         nil,
+		CreatedAt:    time.Time{},
+		UpdateAt:     time.Time{},
+	}
+
+	token := NewToken(parser.tokenType, account, value)
+	token.until = value.Expiration()
+
+	if token.secret, err = encrypt(ctx, parser.secretFactory, signed); err != nil {
+		return nil, errors.Wrap(err, "parse token")
+	}
+
+	return token, nil
+}
+
+func (parser *TokenParser) verify(ctx context.Context, signed []byte) (jwt.Token, error) {
+	if _, err := jws.Parse(signed); err != nil {
+		return nil, errors.Wrapf(banking.ErrTokenMalformed, "verify: %v", err)
+	}
+
+	payload := new(bytes.Buffer)
+
+	if err := parser.tokenVerifier.VerifyToken(ctx, payload, bytes.NewReader(signed)); err != nil {
+		return nil, errors.Wrapf(banking.ErrTokenInvalidSignature, "verify: %v", err)
+	}
+
+	// Signature was verified above, claims will be validated manually for returning typed errors.
+	value, err := jwt.Parse(payload.Bytes())
+	if err != nil {
+		return nil, errors.Wrapf(banking.ErrTokenMalformed, "verify: %v", err)
+	}
+
+	return value, nil
+}
+
+func (parser *TokenParser) validate(ctx context.Context, value jwt.Token) error {
+	if value.JwtID() == "" || value.Subject() == "" || value.Expiration().IsZero() {
+		return errors.Wrap(banking.ErrTokenMalformed, "validate")
+	}
+
+	if typ, ok := value.Get(TypeKey); !ok || typ != parser.tokenType.String() {
+		return errors.Wrap(banking.ErrTokenWrongType, "validate")
+	}
+
+	if parser.issuer != "" && value.Issuer() != parser.issuer {
+		return errors.Wrap(banking.ErrTokenInvalidIssuer, "validate")
+	}
+
+	if parser.audience != "" && !containsString(value.Audience(), parser.audience) {
+		return errors.Wrap(banking.ErrTokenInvalidAudience, "validate")
+	}
+
+	now, err := parser.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "validate")
+	}
+
+	if !now.Before(value.Expiration().Add(parser.clockSkew)) {
+		return errors.Wrap(banking.ErrTokenExpired, "validate")
+	}
+
+	if nbf := value.NotBefore(); !nbf.IsZero() && now.Add(parser.clockSkew).Before(nbf) {
+		return errors.Wrap(banking.ErrTokenNotYetValid, "validate")
+	}
+
+	if iat := value.IssuedAt(); !iat.IsZero() && now.Add(parser.clockSkew).Before(iat) {
+		return errors.Wrap(banking.ErrTokenNotYetValid, "validate")
+	}
+
+	return nil
+}
+
+func containsString(ss []string, str string) bool {
+	for _, s := range ss {
+		if s == str {
+			return true
+		}
+	}
+
+	return false
+}
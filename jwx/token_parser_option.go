@@ -0,0 +1,63 @@
+package jwx
+
+import (
+	"time"
+
+	banking "github.com/morozovcookie/agat-banking"
+)
+
+// TokenParserOption represents an option for configure TokenParser object.
+type TokenParserOption interface {
+	apply(parser *TokenParser)
+}
+
+type tokenParserOptionFunc func(parser *TokenParser)
+
+func (fn tokenParserOptionFunc) apply(parser *TokenParser) {
+	fn(parser)
+}
+
+// DefaultClockSkew is the default time duration which is allowed between clocks of token issuer and token parser.
+const DefaultClockSkew = time.Second * 30
+
+// WithClockSkew sets up the time duration which is allowed between clocks of token issuer and token parser.
+func WithClockSkew(skew time.Duration) TokenParserOption {
+	return tokenParserOptionFunc(func(parser *TokenParser) {
+		parser.clockSkew = skew
+	})
+}
+
+// WithVerifier sets up the service for verifying token signature.
+func WithVerifier(verifier TokenVerifier) TokenParserOption {
+	return tokenParserOptionFunc(func(parser *TokenParser) {
+		parser.tokenVerifier = verifier
+	})
+}
+
+// WithParserSecretFactory sets up the service for creating a banking.SecretString from token.
+func WithParserSecretFactory(factory banking.SecretFactory) TokenParserOption {
+	return tokenParserOptionFunc(func(parser *TokenParser) {
+		parser.secretFactory = factory
+	})
+}
+
+// WithParserTimer sets up the service for retrieving time value.
+func WithParserTimer(timer banking.Timer) TokenParserOption {
+	return tokenParserOptionFunc(func(parser *TokenParser) {
+		parser.timer = timer
+	})
+}
+
+// WithExpectedIssuer sets up the principal which must issue the token.
+func WithExpectedIssuer(iss string) TokenParserOption {
+	return tokenParserOptionFunc(func(parser *TokenParser) {
+		parser.issuer = iss
+	})
+}
+
+// WithExpectedAudience sets up the recipient which must be in the token audience.
+func WithExpectedAudience(aud string) TokenParserOption {
+	return tokenParserOptionFunc(func(parser *TokenParser) {
+		parser.audience = aud
+	})
+}
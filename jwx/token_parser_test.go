@@ -0,0 +1,345 @@
+package jwx_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	stdrsa "crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwt"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/jwx"
+	"github.com/morozovcookie/agat-banking/jwx/rsa"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+const rsaKeySize = 2048
+
+func signToken(t *testing.T, key *stdrsa.PrivateKey, claims map[string]interface{}) []byte {
+	t.Helper()
+
+	token := jwt.New()
+
+	for name, value := range claims {
+		if err := token.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	signer, err := rsa.NewRS512TokenSigner(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	if err = signer.SignToken(context.Background(), buf, token); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestTokenParser_ParseToken(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		tokenType banking.TokenType
+		now       time.Time
+		opts      []jwx.TokenParserOption
+	}
+	type args struct {
+		ctx    context.Context
+		key    *stdrsa.PrivateKey
+		claims map[string]interface{}
+		raw    []byte
+	}
+	type wants struct {
+		id      banking.ID
+		account banking.ID
+		err     error
+	}
+
+	key, err := stdrsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	anotherKey, err := stdrsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		now    = time.Unix(1637000000, 0).UTC()
+		claims = func(typ string, iat, exp, nbf time.Time) map[string]interface{} {
+			return map[string]interface{}{
+				jwt.JwtIDKey:      "jti",
+				jwt.SubjectKey:    "sub",
+				jwt.IssuedAtKey:   iat,
+				jwt.ExpirationKey: exp,
+				jwt.NotBeforeKey:  nbf,
+				`typ`:             typ,
+			}
+		}
+	)
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "pass",
+				enabled: true,
+			},
+			fields: fields{
+				tokenType: banking.TokenTypeRefresh,
+				now:       now,
+				opts:      nil,
+			},
+			args: args{
+				ctx:    context.Background(),
+				key:    key,
+				claims: claims("refresh", now, now.Add(time.Hour), now),
+				raw:    nil,
+			},
+			wants: wants{
+				id:      "jti",
+				account: "sub",
+				err:     nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "pass within clock skew",
+				enabled: true,
+			},
+			fields: fields{
+				tokenType: banking.TokenTypeRefresh,
+				now:       now,
+				opts:      nil,
+			},
+			args: args{
+				ctx:    context.Background(),
+				key:    key,
+				claims: claims("refresh", now.Add(time.Second*10), now.Add(time.Hour), now.Add(time.Second*10)),
+				raw:    nil,
+			},
+			wants: wants{
+				id:      "jti",
+				account: "sub",
+				err:     nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "expired",
+				enabled: true,
+			},
+			fields: fields{
+				tokenType: banking.TokenTypeRefresh,
+				now:       now,
+				opts:      nil,
+			},
+			args: args{
+				ctx:    context.Background(),
+				key:    key,
+				claims: claims("refresh", now.Add(-time.Hour), now.Add(-time.Minute), now.Add(-time.Hour)),
+				raw:    nil,
+			},
+			wants: wants{
+				id:      "",
+				account: "",
+				err:     banking.ErrTokenExpired,
+			},
+		},
+		{
+			meta: meta{
+				name:    "not yet valid",
+				enabled: true,
+			},
+			fields: fields{
+				tokenType: banking.TokenTypeRefresh,
+				now:       now,
+				opts:      nil,
+			},
+			args: args{
+				ctx:    context.Background(),
+				key:    key,
+				claims: claims("refresh", now, now.Add(time.Hour), now.Add(time.Minute)),
+				raw:    nil,
+			},
+			wants: wants{
+				id:      "",
+				account: "",
+				err:     banking.ErrTokenNotYetValid,
+			},
+		},
+		{
+			meta: meta{
+				name:    "wrong type",
+				enabled: true,
+			},
+			fields: fields{
+				tokenType: banking.TokenTypeRefresh,
+				now:       now,
+				opts:      nil,
+			},
+			args: args{
+				ctx:    context.Background(),
+				key:    key,
+				claims: claims("access", now, now.Add(time.Hour), now),
+				raw:    nil,
+			},
+			wants: wants{
+				id:      "",
+				account: "",
+				err:     banking.ErrTokenWrongType,
+			},
+		},
+		{
+			meta: meta{
+				name:    "invalid issuer",
+				enabled: true,
+			},
+			fields: fields{
+				tokenType: banking.TokenTypeRefresh,
+				now:       now,
+				opts:      []jwx.TokenParserOption{jwx.WithExpectedIssuer("bankingd")},
+			},
+			args: args{
+				ctx:    context.Background(),
+				key:    key,
+				claims: claims("refresh", now, now.Add(time.Hour), now),
+				raw:    nil,
+			},
+			wants: wants{
+				id:      "",
+				account: "",
+				err:     banking.ErrTokenInvalidIssuer,
+			},
+		},
+		{
+			meta: meta{
+				name:    "invalid audience",
+				enabled: true,
+			},
+			fields: fields{
+				tokenType: banking.TokenTypeRefresh,
+				now:       now,
+				opts:      []jwx.TokenParserOption{jwx.WithExpectedAudience("banking")},
+			},
+			args: args{
+				ctx:    context.Background(),
+				key:    key,
+				claims: claims("refresh", now, now.Add(time.Hour), now),
+				raw:    nil,
+			},
+			wants: wants{
+				id:      "",
+				account: "",
+				err:     banking.ErrTokenInvalidAudience,
+			},
+		},
+		{
+			meta: meta{
+				name:    "invalid signature",
+				enabled: true,
+			},
+			fields: fields{
+				tokenType: banking.TokenTypeRefresh,
+				now:       now,
+				opts:      nil,
+			},
+			args: args{
+				ctx:    context.Background(),
+				key:    anotherKey,
+				claims: claims("refresh", now, now.Add(time.Hour), now),
+				raw:    nil,
+			},
+			wants: wants{
+				id:      "",
+				account: "",
+				err:     banking.ErrTokenInvalidSignature,
+			},
+		},
+		{
+			meta: meta{
+				name:    "malformed",
+				enabled: true,
+			},
+			fields: fields{
+				tokenType: banking.TokenTypeRefresh,
+				now:       now,
+				opts:      nil,
+			},
+			args: args{
+				ctx:    context.Background(),
+				key:    nil,
+				claims: nil,
+				raw:    []byte("not a token"),
+			},
+			wants: wants{
+				id:      "",
+				account: "",
+				err:     banking.ErrTokenMalformed,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			timer := mock.NewTimer()
+			timer.On("Time").
+				Return(tt.fields.now, nil)
+
+			factory := mock.NewSecretFactory()
+			factory.On("CreateFromDecryptedData", testifymock.Anything).
+				Return(mock.NewSecretString(), nil)
+
+			verifier, err := rsa.NewRS512TokenVerifier(&key.PublicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opts := append([]jwx.TokenParserOption{
+				jwx.WithVerifier(verifier),
+				jwx.WithParserSecretFactory(factory),
+				jwx.WithParserTimer(timer),
+			}, tt.fields.opts...)
+
+			parser := jwx.NewTokenParser(tt.fields.tokenType, opts...)
+
+			raw := tt.args.raw
+			if raw == nil {
+				raw = signToken(t, tt.args.key, tt.args.claims)
+			}
+
+			token, err := parser.ParseToken(tt.args.ctx, bytes.NewReader(raw))
+			assert.ErrorIs(t, err, tt.wants.err)
+
+			if tt.wants.err != nil {
+				assert.Nil(t, token)
+
+				return
+			}
+
+			assert.Equal(t, tt.wants.id, token.ID())
+			assert.Equal(t, tt.wants.account, token.Account().ID)
+			assert.Equal(t, tt.fields.tokenType, token.Type())
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package jwx
+
+import (
+	"context"
+	"io"
+)
+
+// TokenVerifier represents a service for verifying JWT signature.
+type TokenVerifier interface {
+	// VerifyToken verifies signature of the signed token and writes its payload into dst.
+	VerifyToken(ctx context.Context, dst io.Writer, src io.Reader) error
+}
@@ -0,0 +1,32 @@
+package banking
+
+import (
+	"context"
+)
+
+// DataKey represents the data encryption key which is generated for every encrypted record.
+type DataKey struct {
+	// Plaintext is the key which data is encrypted with. It must never be stored.
+	Plaintext []byte
+
+	// Wrapped is the key encrypted by the key encryption key. It is stored along with encrypted data.
+	Wrapped []byte
+}
+
+// KeyManagementService represents a service which owns key encryption keys and wraps data encryption keys by them.
+// Key encryption keys never leave the service, so it could be backed by a local keyring as well as by a remote
+// service (e.g. Vault transit secrets engine).
+type KeyManagementService interface {
+	// GenerateDataKey returns a new data encryption key wrapped by the current key encryption key.
+	GenerateDataKey(ctx context.Context) (*DataKey, error)
+
+	// DecryptDataKey unwraps the data encryption key.
+	DecryptDataKey(ctx context.Context, wrapped []byte) ([]byte, error)
+
+	// RewrapDataKey wraps the data encryption key by the current key encryption key. Data which was encrypted by the
+	// data encryption key is not changed.
+	RewrapDataKey(ctx context.Context, wrapped []byte) ([]byte, error)
+
+	// NeedsRewrap returns true if the data encryption key was not wrapped by the current key encryption key.
+	NeedsRewrap(wrapped []byte) bool
+}
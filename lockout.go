@@ -0,0 +1,112 @@
+package banking
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrAccountLocked will be raised when sign in is not allowed because of too many failed attempts.
+var ErrAccountLocked = errors.New("account locked")
+
+// LockoutScope represents an enum which describes what was locked after failed sign in attempts.
+type LockoutScope int
+
+const (
+	// LockoutScopeUnknown is the default LockoutScope value.
+	LockoutScopeUnknown LockoutScope = iota
+
+	// LockoutScopeAccount means that sign in is not allowed for the single user account from any client.
+	LockoutScopeAccount
+
+	// LockoutScopeClientIP means that sign in is not allowed from the single client IP address for any user account.
+	LockoutScopeClientIP
+)
+
+func (ls LockoutScope) String() string {
+	if ls == LockoutScopeAccount {
+		return "account"
+	}
+
+	if ls == LockoutScopeClientIP {
+		return "client_ip"
+	}
+
+	return ""
+}
+
+// LockoutSubject represents an object which failed sign in attempts are counted for.
+type LockoutSubject struct {
+	// Scope is the type of subject.
+	Scope LockoutScope
+
+	// Key is the subject identifier (e.g. user account identifier or client IP address).
+	Key string
+}
+
+// AccountSubject returns the LockoutSubject for user account.
+func AccountSubject(id ID) LockoutSubject {
+	return LockoutSubject{
+		Scope: LockoutScopeAccount,
+		Key:   id.String(),
+	}
+}
+
+// ClientIPSubject returns the LockoutSubject for client IP address.
+func ClientIPSubject(ip string) LockoutSubject {
+	return LockoutSubject{
+		Scope: LockoutScopeClientIP,
+		Key:   ip,
+	}
+}
+
+// LockedError is the error which describes the active lockout. It matches ErrAccountLocked with errors.Is.
+type LockedError struct {
+	// Scope is the type of locked subject.
+	Scope LockoutScope
+
+	// RetryAfter is the time duration which after sign in will be allowed again.
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s: %s locked, retry after %s", ErrAccountLocked, e.Scope, e.RetryAfter)
+}
+
+// Is reports whether target is ErrAccountLocked.
+func (e *LockedError) Is(target error) bool {
+	return target == ErrAccountLocked // nolint:errorlint,goerr113
+}
+
+// LockoutService represents a service for tracking failed sign in attempts.
+type LockoutService interface {
+	// RegisterAttempt counts the sign in attempt before password comparison. Return LockedError if subject is locked
+	// or the attempt exceeds threshold, then subject is locked with exponential backoff. Attempt is counted before it is
+	// checked, so parallel attempts could not pass the check together. A single attempt is allowed after lockout
+	// expires, lockout is escalated unless the attempt is forgotten or failed attempts are reset.
+	RegisterAttempt(ctx context.Context, subject LockoutSubject) error
+
+	// ForgetAttempt uncounts the registered attempt which has not failed (e.g. password was correct) and lifts the
+	// lockout which was escalated for it.
+	ForgetAttempt(ctx context.Context, subject LockoutSubject) error
+
+	// ResetFailedAttempts removes lockout and forgets failed sign in attempts (e.g. after successful sign in or when
+	// administrator unlocks the user account).
+	ResetFailedAttempts(ctx context.Context, subject LockoutSubject) error
+}
+
+type clientIPContextKey struct{}
+
+// ContextWithClientIP returns a copy of context which carries the client IP address.
+func ContextWithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPContextKey{}, ip)
+}
+
+// ClientIPFromContext returns the client IP address.
+func ClientIPFromContext(ctx context.Context) (string, bool) {
+	ip, ok := ctx.Value(clientIPContextKey{}).(string)
+
+	return ip, ok && ip != ""
+}
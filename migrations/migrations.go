@@ -0,0 +1,18 @@
+// Package migrations contains database schema migrations which are embedded into the binary.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed percona/*.sql
+var files embed.FS
+
+// Percona returns the Percona MySQL Database System migrations.
+func Percona() fs.FS {
+	// The directory is embedded, so it always exists.
+	sub, _ := fs.Sub(files, "percona")
+
+	return sub
+}
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE user_accounts MODIFY COLUMN password_hash VARCHAR(255) NOT NULL COMMENT 'user hashed password';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE user_accounts MODIFY COLUMN password_hash VARCHAR(255) NOT NULL COMMENT 'user password hash encoded with PHC
+string format ($<id>[$v=<version>][$<params>]$<salt>$<hash>), bare hex encoded SHA-256 is the legacy format which will
+be upgraded on the next successful sign in';
+
+COMMIT;
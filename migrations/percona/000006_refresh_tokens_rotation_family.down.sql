@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE refresh_tokens DROP INDEX token_family_id_hash_idx;
+
+ALTER TABLE refresh_tokens DROP COLUMN token_replaced_by, DROP COLUMN token_family_id;
+
+COMMIT;
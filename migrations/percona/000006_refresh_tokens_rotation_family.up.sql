@@ -0,0 +1,15 @@
+BEGIN;
+
+ALTER TABLE refresh_tokens
+    ADD COLUMN token_family_id VARCHAR(64) NOT NULL DEFAULT '' COMMENT 'identifier of the first token in the rotation
+family' AFTER token_user_account_id,
+    ADD COLUMN token_replaced_by VARCHAR(64) COMMENT 'identifier of the token which replaced this one during rotation'
+AFTER token_valid_until;
+
+UPDATE refresh_tokens SET token_family_id = token_id WHERE token_family_id = '';
+
+ALTER TABLE refresh_tokens
+    ADD INDEX token_family_id_hash_idx USING HASH (token_family_id) COMMENT 'use this for revoking the whole rotation
+family';
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TABLE login_lockouts (
+    row_id BIGINT AUTO_INCREMENT NOT NULL COMMENT 'record unique identifier',
+
+    lockout_scope   VARCHAR(16)  NOT NULL COMMENT 'type of subject (account or client_ip)',
+    lockout_key     VARCHAR(255) NOT NULL COMMENT 'subject identifier (user account identifier or client IP address)',
+    failed_attempts INT          NOT NULL COMMENT 'number of failed sign in attempts in a row',
+    last_failed_at  BIGINT       NOT NULL COMMENT 'time of the last failed sign in attempt',
+    locked_until    BIGINT       NOT NULL COMMENT 'time which after sign in will be allowed again (0 if not locked)',
+
+    created_at BIGINT NOT NULL COMMENT 'time when record was stored',
+    updated_at BIGINT COMMENT 'time when record was updated',
+
+    PRIMARY KEY (row_id DESC),
+
+    UNIQUE INDEX lockout_scope_key_btree_idx USING BTREE (lockout_scope, lockout_key) COMMENT 'use this for finding
+lockout of the specific subject'
+) COMMENT='stores failed sign in attempts and temporary lockouts' ENGINE=InnoDB;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE refresh_tokens
+    DROP INDEX token_user_account_id_btree_idx,
+    DROP INDEX token_id_unique_idx,
+    ADD INDEX token_id_hash_idx USING HASH (token_id) COMMENT 'use this for finding specific token, not value from
+token_value column';
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+-- ExpireToken used to insert a copy of the token instead of updating it, so the copies have to be merged before the
+-- unique index is created: the oldest row is kept with the earliest validity time of all copies.
+UPDATE refresh_tokens AS t
+    JOIN (
+        SELECT MIN(row_id) AS row_id, MIN(token_valid_until) AS token_valid_until
+        FROM refresh_tokens
+        GROUP BY token_id
+        HAVING COUNT(*) > 1
+    ) AS d ON t.row_id = d.row_id
+SET t.token_valid_until = d.token_valid_until;
+
+DELETE t FROM refresh_tokens AS t
+    JOIN refresh_tokens AS o ON t.token_id = o.token_id AND t.row_id > o.row_id;
+
+ALTER TABLE refresh_tokens
+    DROP INDEX token_id_hash_idx,
+    ADD UNIQUE INDEX token_id_unique_idx USING HASH (token_id) COMMENT 'use this for finding specific token, not value
+from token_value column',
+    ADD INDEX token_user_account_id_btree_idx USING BTREE (token_user_account_id, token_valid_until) COMMENT 'use this
+for finding and revoking user account tokens';
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+ALTER TABLE user_accounts
+    DROP INDEX user_id_hash_idx,
+    DROP INDEX account_id_unique_idx,
+    DROP INDEX email_address_unique_idx,
+    DROP INDEX username_unique_idx,
+    ADD INDEX account_id_hash_idx USING HASH (account_id),
+    ADD INDEX email_address_hash_idx USING HASH (email_address),
+    ADD INDEX username_hash_idx USING HASH (username),
+    DROP COLUMN disabled_at;
+
+ALTER TABLE users
+    DROP INDEX user_id_unique_idx,
+    ADD INDEX user_id_hash_idx USING HASH (user_id);
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+ALTER TABLE users
+    DROP INDEX user_id_hash_idx,
+    ADD UNIQUE INDEX user_id_unique_idx USING HASH (user_id) COMMENT 'use this for finding specific user';
+
+ALTER TABLE user_accounts
+    ADD COLUMN disabled_at BIGINT COMMENT 'time when user account was disabled (NULL for active user account)'
+AFTER user_id,
+    DROP INDEX username_hash_idx,
+    DROP INDEX email_address_hash_idx,
+    DROP INDEX account_id_hash_idx,
+    ADD UNIQUE INDEX username_unique_idx USING HASH (username) COMMENT 'username could be used by single account',
+    ADD UNIQUE INDEX email_address_unique_idx USING HASH (email_address) COMMENT 'email address could be used by
+single account',
+    ADD UNIQUE INDEX account_id_unique_idx USING HASH (account_id) COMMENT 'use this for finding specific account',
+    ADD INDEX user_id_hash_idx USING HASH (user_id) COMMENT 'use this for finding accounts of specific user';
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE user_account_roles (
+    row_id BIGINT AUTO_INCREMENT NOT NULL COMMENT 'record unique identifier',
+
+    account_id VARCHAR(64) NOT NULL COMMENT 'user account which role is assigned to',
+    role_name  VARCHAR(32) NOT NULL COMMENT 'assigned role (admin, cashier, accountant or auditor)',
+
+    created_at BIGINT NOT NULL COMMENT 'time when role was assigned',
+
+    PRIMARY KEY (row_id DESC),
+
+    UNIQUE INDEX account_id_role_name_btree_idx USING BTREE (account_id, role_name) COMMENT 'use this for finding
+roles of specific account'
+) COMMENT='stores roles which are assigned to user accounts' ENGINE=InnoDB;
+
+INSERT INTO user_account_roles (account_id, role_name, created_at)
+VALUES ('1s8cy82t3uiythh1on5vag79jx5737uii14xjbhdn5tn0a4g0psnnhytczqsihny', 'admin', 1635185552499);
+
+COMMIT;
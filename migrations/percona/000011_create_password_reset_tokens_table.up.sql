@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE password_reset_tokens (
+    row_id BIGINT AUTO_INCREMENT NOT NULL COMMENT 'record unique identifier',
+
+    token_id    VARCHAR(64) NOT NULL COMMENT 'password reset token unique identifier',
+    token_hash  CHAR(64)    NOT NULL COMMENT 'hex encoded SHA-256 hash of the secret',
+    account_id  VARCHAR(64) NOT NULL COMMENT 'user account which password could be reset',
+    expires_at  BIGINT      NOT NULL COMMENT 'time which after token could not be used',
+    redeemed_at BIGINT COMMENT 'time when token was used (NULL for unused token)',
+
+    created_at BIGINT NOT NULL COMMENT 'time when token was issued',
+
+    PRIMARY KEY (row_id DESC),
+
+    UNIQUE INDEX token_hash_unique_idx USING HASH (token_hash) COMMENT 'use this for redeeming token',
+    INDEX account_id_hash_idx USING HASH (account_id) COMMENT 'use this for invalidating tokens of specific account'
+) COMMENT='stores single-use tokens for resetting user account password' ENGINE=InnoDB;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Encrypted email addresses could not be decrypted by SQL, so this migration keeps them as is. Roll back only before
+-- the column encryption job has encrypted any email address.
+ALTER TABLE user_accounts
+    DROP INDEX email_address_index_unique_idx,
+    DROP COLUMN email_address_index,
+    MODIFY COLUMN email_address VARCHAR(255) NOT NULL COMMENT 'user email address',
+    ADD UNIQUE INDEX email_address_unique_idx USING HASH (email_address) COMMENT 'email address could be used by
+single account';
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Existing email addresses stay in plaintext without blind index, they are encrypted by the column encryption job
+-- which is run by bankingd.
+ALTER TABLE user_accounts
+    MODIFY COLUMN email_address VARCHAR(1024) NOT NULL COMMENT 'user email address encrypted by secret factory and
+bound to account_id, plaintext if email_address_index is NULL',
+    ADD COLUMN email_address_index CHAR(64) COMMENT 'hex encoded HMAC-SHA256 blind index of email address (NULL for
+plaintext email address)' AFTER email_address,
+    DROP INDEX email_address_unique_idx,
+    ADD UNIQUE INDEX email_address_index_unique_idx USING HASH (email_address_index) COMMENT 'use this for finding
+account by email address, email address could be used by single account';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE refresh_tokens
+    DROP INDEX token_expiration_btree_idx,
+    ADD INDEX token_valid_until_btree_idx USING BTREE (token_valid_until) COMMENT 'use this for finding expired tokens';
+
+COMMIT;
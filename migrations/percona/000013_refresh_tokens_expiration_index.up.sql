@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE refresh_tokens
+    DROP INDEX token_valid_until_btree_idx,
+    ADD INDEX token_expiration_btree_idx USING BTREE (token_expiration) COMMENT 'use this for finding expired tokens,
+rotated and revoked tokens are kept until expiration for reuse detection';
+
+COMMIT;
@@ -0,0 +1,81 @@
+package mock
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ banking.AuthenticationService = (*AuthenticationService)(nil)
+
+// AuthenticationService represents a service for authenticate user.
+type AuthenticationService struct {
+	mock.Mock
+}
+
+// NewAuthenticationService returns a new AuthenticationService instance.
+func NewAuthenticationService() *AuthenticationService {
+	return &AuthenticationService{}
+}
+
+// AuthenticateUserByEmail authenticates user by email address and password.
+func (svc *AuthenticationService) AuthenticateUserByEmail(
+	_ context.Context,
+	email string,
+	password banking.SecretString,
+) (
+	banking.Token,
+	banking.Token,
+	error,
+) {
+	args := svc.Called(email, password)
+
+	return tokenPair(args)
+}
+
+// AuthenticateUserByUsername authenticates user by username and password.
+func (svc *AuthenticationService) AuthenticateUserByUsername(
+	_ context.Context,
+	username string,
+	password banking.SecretString,
+) (
+	banking.Token,
+	banking.Token,
+	error,
+) {
+	args := svc.Called(username, password)
+
+	return tokenPair(args)
+}
+
+// RefreshToken exchanges refresh token to the new pair of access and refresh tokens.
+func (svc *AuthenticationService) RefreshToken(
+	_ context.Context,
+	refreshToken banking.SecretString,
+) (
+	banking.Token,
+	banking.Token,
+	error,
+) {
+	args := svc.Called(refreshToken)
+
+	return tokenPair(args)
+}
+
+// SignOut expires refresh token.
+func (svc *AuthenticationService) SignOut(_ context.Context, refreshToken banking.SecretString) error {
+	return svc.Called(refreshToken).Error(0)
+}
+
+// SignOutEverywhere expires every refresh token of user account.
+func (svc *AuthenticationService) SignOutEverywhere(_ context.Context, refreshToken banking.SecretString) error {
+	return svc.Called(refreshToken).Error(0)
+}
+
+func tokenPair(args mock.Arguments) (banking.Token, banking.Token, error) {
+	accessToken, _ := args.Get(0).(banking.Token)
+	refreshToken, _ := args.Get(1).(banking.Token)
+
+	return accessToken, refreshToken, args.Error(2) // nolint:gomnd
+}
@@ -33,3 +33,33 @@ func (factory *SecretFactory) CreateFromDecryptedData(_ context.Context, r io.Re
 
 	return args.Get(0).(banking.SecretString), args.Error(1)
 }
+
+// CreateBoundFromEncryptedData creates SecretString object from encrypted data which was bound to the associated
+// data.
+func (factory *SecretFactory) CreateBoundFromEncryptedData(
+	_ context.Context,
+	r io.Reader,
+	ad banking.AssociatedData,
+) (
+	banking.SecretString,
+	error,
+) {
+	args := factory.Called(r, ad)
+
+	return args.Get(0).(banking.SecretString), args.Error(1)
+}
+
+// CreateBoundFromDecryptedData creates SecretString object from decrypted data and binds encrypted data to the
+// associated data.
+func (factory *SecretFactory) CreateBoundFromDecryptedData(
+	_ context.Context,
+	r io.Reader,
+	ad banking.AssociatedData,
+) (
+	banking.SecretString,
+	error,
+) {
+	args := factory.Called(r, ad)
+
+	return args.Get(0).(banking.SecretString), args.Error(1)
+}
@@ -0,0 +1,30 @@
+package mock
+
+import (
+	"context"
+	"io"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ banking.TokenParser = (*TokenParser)(nil)
+
+// TokenParser represents a service for parsing token.
+type TokenParser struct {
+	mock.Mock
+}
+
+// NewTokenParser returns a new TokenParser instance.
+func NewTokenParser() *TokenParser {
+	return &TokenParser{}
+}
+
+// ParseToken parse and returns a Token.
+func (parser *TokenParser) ParseToken(_ context.Context, r io.Reader) (banking.Token, error) {
+	args := parser.Called(r)
+
+	token, _ := args.Get(0).(banking.Token)
+
+	return token, args.Error(1)
+}
@@ -0,0 +1,89 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ banking.TokenService = (*TokenService)(nil)
+
+// TokenService represents a service for managing token data.
+type TokenService struct {
+	mock.Mock
+}
+
+// NewTokenService returns a new TokenService instance.
+func NewTokenService() *TokenService {
+	return &TokenService{}
+}
+
+// StoreToken stores a single Token.
+func (svc *TokenService) StoreToken(_ context.Context, token banking.Token) error {
+	return svc.Called(token).Error(0)
+}
+
+// ExpireToken expires single Token.
+func (svc *TokenService) ExpireToken(_ context.Context, id banking.ID) (banking.Token, error) {
+	args := svc.Called(id)
+
+	token, _ := args.Get(0).(banking.Token)
+
+	return token, args.Error(1)
+}
+
+// FindTokenByID returns a single Token.
+func (svc *TokenService) FindTokenByID(_ context.Context, id banking.ID) (banking.Token, error) {
+	args := svc.Called(id)
+
+	token, _ := args.Get(0).(banking.Token)
+
+	return token, args.Error(1)
+}
+
+// RotateToken expires Token with specified identifier and stores the next Token from the same rotation family.
+func (svc *TokenService) RotateToken(_ context.Context, id banking.ID, next banking.Token) error {
+	return svc.Called(id, next).Error(0)
+}
+
+// ExpireTokenFamily expires every Token from the same rotation family as Token with specified identifier.
+func (svc *TokenService) ExpireTokenFamily(_ context.Context, id banking.ID) error {
+	return svc.Called(id).Error(0)
+}
+
+// ExpireUserAccountTokens expires every Token of the user account with specified identifier.
+func (svc *TokenService) ExpireUserAccountTokens(_ context.Context, accountID banking.ID) error {
+	return svc.Called(accountID).Error(0)
+}
+
+// ExpireTokensIssuedBefore expires every Token which was issued before specified time.
+func (svc *TokenService) ExpireTokensIssuedBefore(_ context.Context, before time.Time) error {
+	return svc.Called(before).Error(0)
+}
+
+// FindUserAccountTokens returns active tokens of the user account with specified identifier.
+func (svc *TokenService) FindUserAccountTokens(
+	_ context.Context,
+	accountID banking.ID,
+	opts banking.FindOptions,
+) (
+	[]banking.Token,
+	error,
+) {
+	args := svc.Called(accountID, opts)
+
+	tokens, _ := args.Get(0).([]banking.Token)
+
+	return tokens, args.Error(1)
+}
+
+// RemoveExpiredTokens removes expired tokens.
+func (svc *TokenService) RemoveExpiredTokens(_ context.Context, opts banking.FindOptions) ([]banking.Token, error) {
+	args := svc.Called(opts)
+
+	tokens, _ := args.Get(0).([]banking.Token)
+
+	return tokens, args.Error(1)
+}
@@ -41,7 +41,7 @@ func (svc *AuthenticationService) AuthenticateUserByEmail(
 	banking.Token,
 	error,
 ) {
-	attrs := append(svc.attrs, attribute.String("email", email))
+	attrs := append(svc.attrs, sensitiveAttribute("email", email))
 
 	ctx, span := svc.tracer.Start(ctx, "AuthenticationService.AuthenticateUserByEmail",
 		trace.WithAttributes(attrs...))
@@ -86,3 +86,59 @@ func (svc *AuthenticationService) AuthenticateUserByUsername(
 
 	return accessToken, refreshToken, nil
 }
+
+// RefreshToken exchanges refresh token to the new pair of access and refresh tokens.
+func (svc *AuthenticationService) RefreshToken(
+	ctx context.Context,
+	refreshToken banking.SecretString,
+) (
+	banking.Token,
+	banking.Token,
+	error,
+) {
+	ctx, span := svc.tracer.Start(ctx, "AuthenticationService.RefreshToken", trace.WithAttributes(svc.attrs...))
+	defer span.End()
+
+	accessToken, nextRefreshToken, err := svc.wrapped.RefreshToken(ctx, refreshToken)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, nil, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return accessToken, nextRefreshToken, nil
+}
+
+// SignOut expires refresh token, so it could not be used for retrieving new tokens pair anymore.
+func (svc *AuthenticationService) SignOut(ctx context.Context, refreshToken banking.SecretString) error {
+	ctx, span := svc.tracer.Start(ctx, "AuthenticationService.SignOut", trace.WithAttributes(svc.attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.SignOut(ctx, refreshToken); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
+
+// SignOutEverywhere expires every refresh token of the user account which refresh token belongs to.
+func (svc *AuthenticationService) SignOutEverywhere(ctx context.Context, refreshToken banking.SecretString) error {
+	ctx, span := svc.tracer.Start(ctx, "AuthenticationService.SignOutEverywhere", trace.WithAttributes(svc.attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.SignOutEverywhere(ctx, refreshToken); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
@@ -6,12 +6,12 @@ import (
 	"go.opentelemetry.io/otel/sdk/trace"
 )
 
-// NewExporter returns a new NewExporter instance.
-func NewExporter() (trace.SpanExporter, error) {
+// NewExporter returns a new NewExporter instance which sends spans to the Jaeger agent.
+func NewExporter(agentHost, agentPort string) (trace.SpanExporter, error) {
 	exporter, err := jaeger.New(
 		jaeger.WithAgentEndpoint(
-			jaeger.WithAgentHost("127.0.0.1"),
-			jaeger.WithAgentPort("6831")))
+			jaeger.WithAgentHost(agentHost),
+			jaeger.WithAgentPort(agentPort)))
 	if err != nil {
 		return nil, errors.Wrap(err, "init exporter")
 	}
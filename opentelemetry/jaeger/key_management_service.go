@@ -0,0 +1,89 @@
+package jaeger
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ banking.KeyManagementService = (*KeyManagementService)(nil)
+
+// KeyManagementService represents a service which wraps data encryption keys. Keys are never put into span
+// attributes.
+type KeyManagementService struct {
+	tracer  trace.Tracer
+	wrapped banking.KeyManagementService
+	attrs   []attribute.KeyValue
+}
+
+// NewKeyManagementService returns a new KeyManagementService instance.
+func NewKeyManagementService(
+	tracer trace.Tracer,
+	svc banking.KeyManagementService,
+	attrs ...attribute.KeyValue,
+) *KeyManagementService {
+	return &KeyManagementService{
+		tracer:  tracer,
+		wrapped: svc,
+		attrs:   attrs,
+	}
+}
+
+// GenerateDataKey returns a new data encryption key wrapped by the current key encryption key.
+func (svc *KeyManagementService) GenerateDataKey(ctx context.Context) (*banking.DataKey, error) {
+	ctx, span := svc.tracer.Start(ctx, "KeyManagementService.GenerateDataKey", trace.WithAttributes(svc.attrs...))
+	defer span.End()
+
+	key, err := svc.wrapped.GenerateDataKey(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return key, nil
+}
+
+// DecryptDataKey unwraps the data encryption key.
+func (svc *KeyManagementService) DecryptDataKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	ctx, span := svc.tracer.Start(ctx, "KeyManagementService.DecryptDataKey", trace.WithAttributes(svc.attrs...))
+	defer span.End()
+
+	key, err := svc.wrapped.DecryptDataKey(ctx, wrapped)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return key, nil
+}
+
+// RewrapDataKey wraps the data encryption key by the current key encryption key.
+func (svc *KeyManagementService) RewrapDataKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	ctx, span := svc.tracer.Start(ctx, "KeyManagementService.RewrapDataKey", trace.WithAttributes(svc.attrs...))
+	defer span.End()
+
+	rewrapped, err := svc.wrapped.RewrapDataKey(ctx, wrapped)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return rewrapped, nil
+}
+
+// NeedsRewrap returns true if the data encryption key was not wrapped by the current key encryption key.
+func (svc *KeyManagementService) NeedsRewrap(wrapped []byte) bool {
+	return svc.wrapped.NeedsRewrap(wrapped)
+}
@@ -0,0 +1,82 @@
+package jaeger
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ banking.LockoutService = (*LockoutService)(nil)
+
+// LockoutService represents a service for tracking failed sign in attempts.
+type LockoutService struct {
+	tracer  trace.Tracer
+	wrapped banking.LockoutService
+	attrs   []attribute.KeyValue
+}
+
+// NewLockoutService returns a new LockoutService instance.
+func NewLockoutService(tracer trace.Tracer, svc banking.LockoutService, attrs ...attribute.KeyValue) *LockoutService {
+	return &LockoutService{
+		tracer:  tracer,
+		wrapped: svc,
+		attrs:   attrs,
+	}
+}
+
+// RegisterAttempt counts the sign in attempt before password comparison.
+func (svc *LockoutService) RegisterAttempt(ctx context.Context, subject banking.LockoutSubject) error {
+	attrs := append(svc.attrs, attribute.Stringer("scope", subject.Scope))
+
+	ctx, span := svc.tracer.Start(ctx, "LockoutService.RegisterAttempt", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.RegisterAttempt(ctx, subject); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
+
+// ForgetAttempt uncounts the registered attempt which has not failed.
+func (svc *LockoutService) ForgetAttempt(ctx context.Context, subject banking.LockoutSubject) error {
+	attrs := append(svc.attrs, attribute.Stringer("scope", subject.Scope))
+
+	ctx, span := svc.tracer.Start(ctx, "LockoutService.ForgetAttempt", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.ForgetAttempt(ctx, subject); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
+
+// ResetFailedAttempts removes lockout and forgets failed sign in attempts.
+func (svc *LockoutService) ResetFailedAttempts(ctx context.Context, subject banking.LockoutSubject) error {
+	attrs := append(svc.attrs, attribute.Stringer("scope", subject.Scope))
+
+	ctx, span := svc.tracer.Start(ctx, "LockoutService.ResetFailedAttempts", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.ResetFailedAttempts(ctx, subject); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
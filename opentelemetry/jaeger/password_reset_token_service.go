@@ -0,0 +1,105 @@
+package jaeger
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ banking.PasswordResetTokenService = (*PasswordResetTokenService)(nil)
+
+// PasswordResetTokenService represents a service for managing PasswordResetToken data.
+type PasswordResetTokenService struct {
+	tracer  trace.Tracer
+	wrapped banking.PasswordResetTokenService
+	attrs   []attribute.KeyValue
+}
+
+// NewPasswordResetTokenService returns a new PasswordResetTokenService instance.
+func NewPasswordResetTokenService(
+	tracer trace.Tracer,
+	svc banking.PasswordResetTokenService,
+	attrs ...attribute.KeyValue,
+) *PasswordResetTokenService {
+	return &PasswordResetTokenService{
+		tracer:  tracer,
+		wrapped: svc,
+		attrs:   attrs,
+	}
+}
+
+// StorePasswordResetToken stores a new PasswordResetToken.
+func (svc *PasswordResetTokenService) StorePasswordResetToken(
+	ctx context.Context,
+	token *banking.PasswordResetToken,
+) error {
+	attrs := append(svc.attrs, attribute.Stringer("token_id", token.ID),
+		attribute.Stringer("account_id", token.Account.ID))
+
+	ctx, span := svc.tracer.Start(ctx, "PasswordResetTokenService.StorePasswordResetToken",
+		trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.StorePasswordResetToken(ctx, token); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
+
+// FindPasswordResetTokenByHash returns PasswordResetToken with specified hash which could be redeemed.
+func (svc *PasswordResetTokenService) FindPasswordResetTokenByHash(
+	ctx context.Context,
+	tokenHash string,
+) (
+	*banking.PasswordResetToken,
+	error,
+) {
+	ctx, span := svc.tracer.Start(ctx, "PasswordResetTokenService.FindPasswordResetTokenByHash",
+		trace.WithAttributes(svc.attrs...))
+	defer span.End()
+
+	token, err := svc.wrapped.FindPasswordResetTokenByHash(ctx, tokenHash)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+	span.SetAttributes(attribute.Stringer("token_id", token.ID), attribute.Stringer("account_id", token.Account.ID))
+
+	return token, nil
+}
+
+// RedeemPasswordResetToken marks PasswordResetToken with specified hash as used and returns it.
+func (svc *PasswordResetTokenService) RedeemPasswordResetToken(
+	ctx context.Context,
+	tokenHash string,
+) (
+	*banking.PasswordResetToken,
+	error,
+) {
+	ctx, span := svc.tracer.Start(ctx, "PasswordResetTokenService.RedeemPasswordResetToken",
+		trace.WithAttributes(svc.attrs...))
+	defer span.End()
+
+	token, err := svc.wrapped.RedeemPasswordResetToken(ctx, tokenHash)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+	span.SetAttributes(attribute.Stringer("token_id", token.ID), attribute.Stringer("account_id", token.Account.ID))
+
+	return token, nil
+}
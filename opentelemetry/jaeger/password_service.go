@@ -0,0 +1,103 @@
+package jaeger
+
+import (
+	"context"
+	"time"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ banking.PasswordService = (*PasswordService)(nil)
+
+// PasswordService represents a service for changing user account passwords.
+type PasswordService struct {
+	tracer  trace.Tracer
+	wrapped banking.PasswordService
+	attrs   []attribute.KeyValue
+}
+
+// NewPasswordService returns a new PasswordService instance.
+func NewPasswordService(
+	tracer trace.Tracer,
+	svc banking.PasswordService,
+	attrs ...attribute.KeyValue,
+) *PasswordService {
+	return &PasswordService{
+		tracer:  tracer,
+		wrapped: svc,
+		attrs:   attrs,
+	}
+}
+
+// ChangePassword replaces password of the UserAccount with specified identifier if current password is correct.
+func (svc *PasswordService) ChangePassword(
+	ctx context.Context,
+	accountID banking.ID,
+	currentPassword banking.SecretString,
+	newPassword banking.SecretString,
+) error {
+	attrs := append(svc.attrs, attribute.Stringer("account_id", accountID))
+
+	ctx, span := svc.tracer.Start(ctx, "PasswordService.ChangePassword", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.ChangePassword(ctx, accountID, currentPassword, newPassword); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
+
+// IssuePasswordResetToken returns a new single-use secret which allows to reset password of the UserAccount with
+// specified identifier.
+func (svc *PasswordService) IssuePasswordResetToken(
+	ctx context.Context,
+	accountID banking.ID,
+) (
+	banking.SecretString,
+	time.Time,
+	error,
+) {
+	attrs := append(svc.attrs, attribute.Stringer("account_id", accountID))
+
+	ctx, span := svc.tracer.Start(ctx, "PasswordService.IssuePasswordResetToken", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	resetToken, expiresAt, err := svc.wrapped.IssuePasswordResetToken(ctx, accountID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, time.Time{}, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return resetToken, expiresAt, nil
+}
+
+// ResetPassword replaces password of the UserAccount which password reset token was issued for.
+func (svc *PasswordService) ResetPassword(
+	ctx context.Context,
+	resetToken banking.SecretString,
+	newPassword banking.SecretString,
+) error {
+	ctx, span := svc.tracer.Start(ctx, "PasswordService.ResetPassword", trace.WithAttributes(svc.attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.ResetPassword(ctx, resetToken, newPassword); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
@@ -7,13 +7,13 @@ import (
 )
 
 // NewProvider configure and returns a new trace.TracerProvider instance.
-func NewProvider(exporter tracesdk.SpanExporter) *tracesdk.TracerProvider {
+func NewProvider(exporter tracesdk.SpanExporter, serviceName string) *tracesdk.TracerProvider {
 	return tracesdk.NewTracerProvider(
 		tracesdk.WithBatcher(exporter),
 		tracesdk.WithResource(
 			resource.NewWithAttributes(
 				semconv.SchemaURL,
-				semconv.ServiceNameKey.String("opentelemetry-jaeger"),
+				semconv.ServiceNameKey.String(serviceName),
 				semconv.ServiceVersionKey.String("1.0.0"),
 				semconv.DeploymentEnvironmentKey.String("production"))))
 }
@@ -0,0 +1,84 @@
+package jaeger
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ banking.RoleService = (*RoleService)(nil)
+
+// RoleService represents a service for managing roles which are assigned to user accounts.
+type RoleService struct {
+	tracer  trace.Tracer
+	wrapped banking.RoleService
+	attrs   []attribute.KeyValue
+}
+
+// NewRoleService returns a new RoleService instance.
+func NewRoleService(tracer trace.Tracer, svc banking.RoleService, attrs ...attribute.KeyValue) *RoleService {
+	return &RoleService{
+		tracer:  tracer,
+		wrapped: svc,
+		attrs:   attrs,
+	}
+}
+
+// FindUserAccountRoles returns roles which are assigned to the UserAccount with specified identifier.
+func (svc *RoleService) FindUserAccountRoles(ctx context.Context, accountID banking.ID) (banking.Roles, error) {
+	attrs := append(svc.attrs, attribute.Stringer("account_id", accountID))
+
+	ctx, span := svc.tracer.Start(ctx, "RoleService.FindUserAccountRoles", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	roles, err := svc.wrapped.FindUserAccountRoles(ctx, accountID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+	span.SetAttributes(attribute.StringSlice("roles", roles.Strings()))
+
+	return roles, nil
+}
+
+// AssignRole grants role to the UserAccount with specified identifier.
+func (svc *RoleService) AssignRole(ctx context.Context, accountID banking.ID, role banking.Role) error {
+	attrs := append(svc.attrs, attribute.Stringer("account_id", accountID), attribute.Stringer("role", role))
+
+	ctx, span := svc.tracer.Start(ctx, "RoleService.AssignRole", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.AssignRole(ctx, accountID, role); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
+
+// RevokeRole takes role away from the UserAccount with specified identifier.
+func (svc *RoleService) RevokeRole(ctx context.Context, accountID banking.ID, role banking.Role) error {
+	attrs := append(svc.attrs, attribute.Stringer("account_id", accountID), attribute.Stringer("role", role))
+
+	ctx, span := svc.tracer.Start(ctx, "RoleService.RevokeRole", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.RevokeRole(ctx, accountID, role); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
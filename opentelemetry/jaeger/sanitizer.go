@@ -0,0 +1,149 @@
+package jaeger
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/redact"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// sensitiveKeys is the list of attribute keys which values are replaced according to the redaction policy. Key
+// matches if it is equal to the one from the list or ends with it after dot (e.g. "user.email").
+var sensitiveKeys = []string{
+	"email",
+	"email_address",
+	"password",
+	"secret",
+	"token",
+	"access_token",
+	"refresh_token",
+	"reset_token",
+}
+
+var (
+	_ trace.Tracer = (*Sanitizer)(nil)
+	_ trace.Span   = (*sanitizedSpan)(nil)
+)
+
+// Sanitizer represents a tracer which replaces values of sensitive span attributes according to the redaction
+// policy. It is the safety net for attributes which were not written by secretAttributes or tokenAttributes.
+type Sanitizer struct {
+	wrapped trace.Tracer
+}
+
+// NewSanitizer returns a new Sanitizer instance.
+func NewSanitizer(tracer trace.Tracer) *Sanitizer {
+	return &Sanitizer{
+		wrapped: tracer,
+	}
+}
+
+// Start creates a span and a context.Context containing the newly-created span.
+func (s *Sanitizer) Start(
+	ctx context.Context,
+	spanName string,
+	opts ...trace.SpanStartOption,
+) (
+	context.Context,
+	trace.Span,
+) {
+	cfg := trace.NewSpanStartConfig(opts...)
+
+	sanitizedOpts := []trace.SpanStartOption{
+		trace.WithAttributes(SanitizeAttributes(cfg.Attributes()...)...),
+		trace.WithTimestamp(cfg.Timestamp()),
+		trace.WithLinks(cfg.Links()...),
+		trace.WithSpanKind(cfg.SpanKind()),
+	}
+
+	if cfg.NewRoot() {
+		sanitizedOpts = append(sanitizedOpts, trace.WithNewRoot())
+	}
+
+	ctx, span := s.wrapped.Start(ctx, spanName, sanitizedOpts...)
+	span = &sanitizedSpan{Span: span}
+
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type sanitizedSpan struct {
+	trace.Span
+}
+
+// SetAttributes sets kv as attributes of the Span.
+func (span *sanitizedSpan) SetAttributes(kv ...attribute.KeyValue) {
+	span.Span.SetAttributes(SanitizeAttributes(kv...)...)
+}
+
+// SanitizeAttributes returns attributes which values of sensitive keys are replaced according to the redaction
+// policy.
+func SanitizeAttributes(attrs ...attribute.KeyValue) []attribute.KeyValue {
+	sanitized := make([]attribute.KeyValue, 0, len(attrs))
+
+	for _, attr := range attrs {
+		if isSensitiveKey(attr.Key) {
+			if value := attr.Value.Emit(); !redact.IsRedacted(value) {
+				attr = attr.Key.String(redact.String(value))
+			}
+		}
+
+		sanitized = append(sanitized, attr)
+	}
+
+	return sanitized
+}
+
+func isSensitiveKey(key attribute.Key) bool {
+	name := string(key)
+	if idx := strings.LastIndex(name, "."); idx != -1 {
+		name = name[idx+1:]
+	}
+
+	for _, sensitiveKey := range sensitiveKeys {
+		if name == sensitiveKey {
+			return true
+		}
+	}
+
+	return false
+}
+
+// secretAttributes returns attributes of SecretString which value is written according to the redaction policy.
+func secretAttributes(key string, secret banking.SecretString) []attribute.KeyValue {
+	if secret == nil {
+		return nil
+	}
+
+	return []attribute.KeyValue{
+		attribute.String(key+".value", redact.Secret(secret)),
+	}
+}
+
+// tokenAttributes returns attributes with Token identifier, type and expiration. Token value is added only if the
+// redaction policy is not redacted.
+func tokenAttributes(key string, token banking.Token) []attribute.KeyValue {
+	if token == nil {
+		return nil
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.Stringer(key+".id", token.ID()),
+		attribute.Stringer(key+".type", token.Type()),
+		attribute.String(key+".expiration", token.Expiration().Format(time.RFC3339Nano)),
+	}
+
+	if redact.CurrentPolicy() != redact.PolicyRedacted {
+		attrs = append(attrs, attribute.String(key+".value", redact.Secret(token.SecretString())))
+	}
+
+	return attrs
+}
+
+// sensitiveAttribute returns attribute which value is written according to the redaction policy.
+func sensitiveAttribute(key, value string) attribute.KeyValue {
+	return attribute.String(key, redact.String(value))
+}
@@ -0,0 +1,101 @@
+package jaeger_test
+
+import (
+	"testing"
+
+	"github.com/morozovcookie/agat-banking/opentelemetry/jaeger"
+	"github.com/morozovcookie/agat-banking/redact"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestSanitizeAttributes(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		policy redact.Policy
+		attrs  []attribute.KeyValue
+	}
+	type wants struct {
+		attrs []attribute.KeyValue
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "redacted",
+				enabled: true,
+			},
+			args: args{
+				policy: redact.PolicyRedacted,
+				attrs: []attribute.KeyValue{
+					attribute.String("email_address", "user@example.com"),
+					attribute.String("request.password", "secret"),
+					attribute.String("account_id", "1"),
+					attribute.String("token.id", "2"),
+				},
+			},
+			wants: wants{
+				attrs: []attribute.KeyValue{
+					attribute.String("email_address", redact.Redacted),
+					attribute.String("request.password", redact.Redacted),
+					attribute.String("account_id", "1"),
+					attribute.String("token.id", "2"),
+				},
+			},
+		},
+		{
+			meta: meta{
+				name:    "already redacted",
+				enabled: true,
+			},
+			args: args{
+				policy: redact.PolicyFingerprint,
+				attrs: []attribute.KeyValue{
+					attribute.String("email", redact.Fingerprint("user@example.com")),
+				},
+			},
+			wants: wants{
+				attrs: []attribute.KeyValue{
+					attribute.String("email", redact.Fingerprint("user@example.com")),
+				},
+			},
+		},
+		{
+			meta: meta{
+				name:    "full",
+				enabled: true,
+			},
+			args: args{
+				policy: redact.PolicyFull,
+				attrs: []attribute.KeyValue{
+					attribute.String("email", "user@example.com"),
+				},
+			},
+			wants: wants{
+				attrs: []attribute.KeyValue{
+					attribute.String("email", "user@example.com"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			redact.SetPolicy(tt.args.policy)
+			defer redact.SetPolicy(redact.PolicyRedacted)
+
+			assert.Equal(t, tt.wants.attrs, jaeger.SanitizeAttributes(tt.args.attrs...))
+		})
+	}
+}
@@ -42,7 +42,7 @@ func (factory *SecretFactory) CreateFromEncryptedData(ctx context.Context, r io.
 	}
 
 	span.SetStatus(codes.Ok, "")
-	span.SetAttributes(attribute.Stringer("secret", secret))
+	span.SetAttributes(secretAttributes("secret", secret)...)
 
 	return secret, nil
 }
@@ -61,7 +61,71 @@ func (factory *SecretFactory) CreateFromDecryptedData(ctx context.Context, r io.
 	}
 
 	span.SetStatus(codes.Ok, "")
-	span.SetAttributes(attribute.Stringer("secret", secret))
+	span.SetAttributes(secretAttributes("secret", secret)...)
 
 	return secret, nil
 }
+
+// CreateBoundFromEncryptedData creates SecretString object from encrypted data which was bound to the associated
+// data.
+func (factory *SecretFactory) CreateBoundFromEncryptedData(
+	ctx context.Context,
+	r io.Reader,
+	ad banking.AssociatedData,
+) (
+	banking.SecretString,
+	error,
+) {
+	attrs := append(factory.attrs, associatedDataAttributes(ad)...)
+
+	ctx, span := factory.tracer.Start(ctx, "SecretFactory.CreateBoundFromEncryptedData", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	secret, err := factory.wrapped.CreateBoundFromEncryptedData(ctx, r, ad)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+	span.SetAttributes(secretAttributes("secret", secret)...)
+
+	return secret, nil
+}
+
+// CreateBoundFromDecryptedData creates SecretString object from decrypted data and binds encrypted data to the
+// associated data.
+func (factory *SecretFactory) CreateBoundFromDecryptedData(
+	ctx context.Context,
+	r io.Reader,
+	ad banking.AssociatedData,
+) (
+	banking.SecretString,
+	error,
+) {
+	attrs := append(factory.attrs, associatedDataAttributes(ad)...)
+
+	ctx, span := factory.tracer.Start(ctx, "SecretFactory.CreateBoundFromDecryptedData", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	secret, err := factory.wrapped.CreateBoundFromDecryptedData(ctx, r, ad)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+	span.SetAttributes(secretAttributes("secret", secret)...)
+
+	return secret, nil
+}
+
+func associatedDataAttributes(ad banking.AssociatedData) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("ad.table", ad.Table),
+		attribute.String("ad.column", ad.Column),
+		attribute.String("ad.record_id", ad.RecordID),
+	}
+}
@@ -30,33 +30,73 @@ func NewTokenBuilder(tracer trace.Tracer, builder banking.TokenBuilder, attrs ..
 
 // WithID sets up the token unique identifier.
 func (builder *TokenBuilder) WithID(jti banking.ID) banking.TokenBuilder {
-	return builder.wrapped.WithID(jti)
+	builder.wrapped = builder.wrapped.WithID(jti)
+
+	return builder
 }
 
 // WithAccount sets up the subject of token.
 func (builder *TokenBuilder) WithAccount(sub *banking.UserAccount) banking.TokenBuilder {
-	return builder.wrapped.WithAccount(sub)
+	builder.wrapped = builder.wrapped.WithAccount(sub)
+
+	return builder
+}
+
+// WithIssuer sets up the principal that issued the token.
+func (builder *TokenBuilder) WithIssuer(iss string) banking.TokenBuilder {
+	builder.wrapped = builder.wrapped.WithIssuer(iss)
+
+	return builder
+}
+
+// WithAudience sets up the recipients that the token is intended for.
+func (builder *TokenBuilder) WithAudience(aud ...string) banking.TokenBuilder {
+	builder.wrapped = builder.wrapped.WithAudience(aud...)
+
+	return builder
+}
+
+// WithClaim sets up the private claim (e.g. roles or session identifier).
+func (builder *TokenBuilder) WithClaim(name string, value interface{}) banking.TokenBuilder {
+	builder.wrapped = builder.wrapped.WithClaim(name, value)
+
+	return builder
+}
+
+// WithLifetime sets up the time duration which token will be valid since it did issue.
+func (builder *TokenBuilder) WithLifetime(lifetime time.Duration) banking.TokenBuilder {
+	builder.wrapped = builder.wrapped.WithLifetime(lifetime)
+
+	return builder
 }
 
 // WithIssuedAt sets up the UTC time when token was issued.
 func (builder *TokenBuilder) WithIssuedAt(iat time.Time) banking.TokenBuilder {
-	return builder.wrapped.WithIssuedAt(iat)
+	builder.wrapped = builder.wrapped.WithIssuedAt(iat)
+
+	return builder
 }
 
 // WithExpiration sets up the UTC time which after token will be expired.
 func (builder *TokenBuilder) WithExpiration(exp time.Time) banking.TokenBuilder {
-	return builder.wrapped.WithExpiration(exp)
+	builder.wrapped = builder.wrapped.WithExpiration(exp)
+
+	return builder
 }
 
 // WithNotBefore sets up the time before which the token is not valid.
 func (builder *TokenBuilder) WithNotBefore(nbf time.Time) banking.TokenBuilder {
-	return builder.wrapped.WithNotBefore(nbf)
+	builder.wrapped = builder.wrapped.WithNotBefore(nbf)
+
+	return builder
 }
 
 // WithValidUntil sets up the time which after token will be invalid.
 // NOTE: Expiration is the constant parameter, but ValidUntil could be changed (e.g. when user signing out).
 func (builder *TokenBuilder) WithValidUntil(until time.Time) banking.TokenBuilder {
-	return builder.wrapped.WithValidUntil(until)
+	builder.wrapped = builder.wrapped.WithValidUntil(until)
+
+	return builder
 }
 
 // Build creates and returns the Token.
@@ -39,7 +39,7 @@ func (parser *TokenParser) ParseToken(ctx context.Context, r io.Reader) (banking
 	}
 
 	span.SetStatus(codes.Ok, "")
-	span.SetAttributes(attribute.Stringer("token", token))
+	span.SetAttributes(tokenAttributes("token", token)...)
 
 	return token, nil
 }
@@ -2,6 +2,7 @@ package jaeger
 
 import (
 	"context"
+	"time"
 
 	banking "github.com/morozovcookie/agat-banking"
 	"go.opentelemetry.io/otel/attribute"
@@ -27,7 +28,7 @@ func NewTokenService(tracer trace.Tracer, svc banking.TokenService, attrs ...att
 
 // StoreToken stores a single Token.
 func (svc *TokenService) StoreToken(ctx context.Context, token banking.Token) error {
-	attrs := append(svc.attrs, attribute.Stringer("token", token))
+	attrs := append(svc.attrs, tokenAttributes("token", token)...)
 
 	ctx, span := svc.tracer.Start(ctx, "TokenService.StoreToken", trace.WithAttributes(attrs...))
 	defer span.End()
@@ -59,7 +60,7 @@ func (svc *TokenService) ExpireToken(ctx context.Context, id banking.ID) (bankin
 	}
 
 	span.SetStatus(codes.Ok, "")
-	span.SetAttributes(attribute.Stringer("token", token))
+	span.SetAttributes(tokenAttributes("token", token)...)
 
 	return token, nil
 }
@@ -79,11 +80,113 @@ func (svc *TokenService) FindTokenByID(ctx context.Context, id banking.ID) (bank
 	}
 
 	span.SetStatus(codes.Ok, "")
-	span.SetAttributes(attribute.Stringer("token", token))
+	span.SetAttributes(tokenAttributes("token", token)...)
 
 	return token, nil
 }
 
+// RotateToken expires Token with specified identifier and stores the next Token from the same rotation family.
+func (svc *TokenService) RotateToken(ctx context.Context, id banking.ID, next banking.Token) error {
+	attrs := append(svc.attrs, attribute.Stringer("id", id))
+	attrs = append(attrs, tokenAttributes("next", next)...)
+
+	ctx, span := svc.tracer.Start(ctx, "TokenService.RotateToken", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.RotateToken(ctx, id, next); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
+
+// ExpireTokenFamily expires every Token from the same rotation family as Token with specified identifier.
+func (svc *TokenService) ExpireTokenFamily(ctx context.Context, id banking.ID) error {
+	attrs := append(svc.attrs, attribute.Stringer("id", id))
+
+	ctx, span := svc.tracer.Start(ctx, "TokenService.ExpireTokenFamily", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.ExpireTokenFamily(ctx, id); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
+
+// ExpireUserAccountTokens expires every Token of the user account with specified identifier.
+func (svc *TokenService) ExpireUserAccountTokens(ctx context.Context, accountID banking.ID) error {
+	attrs := append(svc.attrs, attribute.Stringer("account_id", accountID))
+
+	ctx, span := svc.tracer.Start(ctx, "TokenService.ExpireUserAccountTokens", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.ExpireUserAccountTokens(ctx, accountID); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
+
+// ExpireTokensIssuedBefore expires every Token which was issued before specified time (e.g. when signing key was
+// compromised).
+func (svc *TokenService) ExpireTokensIssuedBefore(ctx context.Context, before time.Time) error {
+	attrs := append(svc.attrs, attribute.String("before", before.Format(time.RFC3339Nano)))
+
+	ctx, span := svc.tracer.Start(ctx, "TokenService.ExpireTokensIssuedBefore", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.ExpireTokensIssuedBefore(ctx, before); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
+
+// FindUserAccountTokens returns active tokens of the user account with specified identifier, the most recently
+// issued first.
+func (svc *TokenService) FindUserAccountTokens(
+	ctx context.Context,
+	accountID banking.ID,
+	opts banking.FindOptions,
+) (
+	[]banking.Token,
+	error,
+) {
+	attrs := append(svc.attrs, attribute.Stringer("account_id", accountID),
+		attribute.Int64("offset", int64(opts.Offset())), attribute.Int64("limit", int64(opts.Limit())))
+
+	ctx, span := svc.tracer.Start(ctx, "TokenService.FindUserAccountTokens", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	tt, err := svc.wrapped.FindUserAccountTokens(ctx, accountID, opts)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return tt, nil
+}
+
 // RemoveExpiredTokens removes expired tokens.
 // Return tokens list after remove.
 func (svc *TokenService) RemoveExpiredTokens(ctx context.Context, opts banking.FindOptions) ([]banking.Token, error) {
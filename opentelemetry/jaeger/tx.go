@@ -46,6 +46,24 @@ func (tx *tx) PrepareContext(ctx context.Context, query string) (percona.Stmt, e
 	return newStmt(tx.tracer, res, tx.attrs...), nil
 }
 
+// ExecContext executes a query without arguments by the text protocol.
+func (tx *tx) ExecContext(ctx context.Context, query string) error {
+	attrs := append(tx.attrs, opentelemetry.SQLAttributesFromQuery(query)...)
+
+	ctx, span := tx.tracer.Start(ctx, "Tx.ExecContext", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := tx.wrapped.ExecContext(ctx, query); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
+
 // Commit commits the transaction.
 func (tx *tx) Commit(ctx context.Context) error {
 	ctx, span := tx.tracer.Start(ctx, "Tx.Commit", trace.WithAttributes(tx.attrs...))
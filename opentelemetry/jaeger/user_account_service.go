@@ -39,7 +39,7 @@ func (svc *UserAccountService) FindUserAccountByEmailAddress(
 	*banking.UserAccount,
 	error,
 ) {
-	attrs := append(svc.attrs, attribute.String("email_address", emailAddress))
+	attrs := append(svc.attrs, sensitiveAttribute("email_address", emailAddress))
 
 	ctx, span := svc.tracer.Start(ctx, "UserAccountService.FindUserAccountByEmailAddress",
 		trace.WithAttributes(attrs...))
@@ -86,3 +86,132 @@ func (svc *UserAccountService) FindUserAccountByUserName(
 
 	return account, nil
 }
+
+// UpdateUserAccountPasswordHash replaces UserAccount.PasswordHash of the UserAccount with specified identifier.
+func (svc *UserAccountService) UpdateUserAccountPasswordHash(
+	ctx context.Context,
+	id banking.ID,
+	passwordHash string,
+) error {
+	attrs := append(svc.attrs, attribute.Stringer("account_id", id))
+
+	ctx, span := svc.tracer.Start(ctx, "UserAccountService.UpdateUserAccountPasswordHash",
+		trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.UpdateUserAccountPasswordHash(ctx, id, passwordHash); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
+
+// CreateUserAccount stores a new UserAccount with already hashed password. UserAccount.ID and UserAccount.CreatedAt
+// are set on success.
+// Return banking.ErrUserAccountAlreadyExists if username or email address is already used.
+func (svc *UserAccountService) CreateUserAccount(ctx context.Context, account *banking.UserAccount) error {
+	attrs := append(svc.attrs, attribute.String("username", account.UserName))
+
+	ctx, span := svc.tracer.Start(ctx, "UserAccountService.CreateUserAccount", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.CreateUserAccount(ctx, account); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+	span.SetAttributes(attribute.Stringer("account_id", account.ID))
+
+	return nil
+}
+
+// FindUserAccountByID returns UserAccount by UserAccount.ID.
+func (svc *UserAccountService) FindUserAccountByID(ctx context.Context, id banking.ID) (*banking.UserAccount, error) {
+	attrs := append(svc.attrs, attribute.Stringer("account_id", id))
+
+	ctx, span := svc.tracer.Start(ctx, "UserAccountService.FindUserAccountByID", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	account, err := svc.wrapped.FindUserAccountByID(ctx, id)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+	span.SetAttributes(attribute.Stringer("user_id", account.User.ID))
+
+	return account, nil
+}
+
+// FindUserAccounts returns the page of user accounts in order of creation.
+func (svc *UserAccountService) FindUserAccounts(
+	ctx context.Context,
+	opts banking.FindOptions,
+) (
+	[]*banking.UserAccount,
+	error,
+) {
+	attrs := append(svc.attrs, attribute.Int64("offset", int64(opts.Offset())),
+		attribute.Int64("limit", int64(opts.Limit())))
+
+	ctx, span := svc.tracer.Start(ctx, "UserAccountService.FindUserAccounts", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	aa, err := svc.wrapped.FindUserAccounts(ctx, opts)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return aa, nil
+}
+
+// UpdateUserAccount replaces UserAccount.UserName and UserAccount.EmailAddress. UserAccount.UpdateAt is set on
+// success.
+// Return banking.ErrUserAccountAlreadyExists if username or email address is already used.
+func (svc *UserAccountService) UpdateUserAccount(ctx context.Context, account *banking.UserAccount) error {
+	attrs := append(svc.attrs, attribute.Stringer("account_id", account.ID))
+
+	ctx, span := svc.tracer.Start(ctx, "UserAccountService.UpdateUserAccount", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.UpdateUserAccount(ctx, account); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
+
+// DisableUserAccount forbids sign in to the UserAccount with specified identifier. Disabled user account is kept, so
+// its history is not lost.
+func (svc *UserAccountService) DisableUserAccount(ctx context.Context, id banking.ID) error {
+	attrs := append(svc.attrs, attribute.Stringer("account_id", id))
+
+	ctx, span := svc.tracer.Start(ctx, "UserAccountService.DisableUserAccount", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.DisableUserAccount(ctx, id); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package jaeger
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ banking.UserService = (*UserService)(nil)
+
+// UserService represents a service for managing User data.
+type UserService struct {
+	tracer  trace.Tracer
+	wrapped banking.UserService
+	attrs   []attribute.KeyValue
+}
+
+// NewUserService returns a new UserService instance.
+func NewUserService(tracer trace.Tracer, svc banking.UserService, attrs ...attribute.KeyValue) *UserService {
+	return &UserService{
+		tracer:  tracer,
+		wrapped: svc,
+		attrs:   attrs,
+	}
+}
+
+// CreateUser stores a new User. User.ID and User.CreatedAt are set on success.
+func (svc *UserService) CreateUser(ctx context.Context, user *banking.User) error {
+	ctx, span := svc.tracer.Start(ctx, "UserService.CreateUser", trace.WithAttributes(svc.attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.CreateUser(ctx, user); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+	span.SetAttributes(attribute.Stringer("user_id", user.ID))
+
+	return nil
+}
+
+// FindUserByID returns User by User.ID.
+func (svc *UserService) FindUserByID(ctx context.Context, id banking.ID) (*banking.User, error) {
+	attrs := append(svc.attrs, attribute.Stringer("user_id", id))
+
+	ctx, span := svc.tracer.Start(ctx, "UserService.FindUserByID", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	user, err := svc.wrapped.FindUserByID(ctx, id)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return user, nil
+}
+
+// FindUsers returns the page of users in order of creation.
+func (svc *UserService) FindUsers(ctx context.Context, opts banking.FindOptions) ([]*banking.User, error) {
+	attrs := append(svc.attrs, attribute.Int64("offset", int64(opts.Offset())),
+		attribute.Int64("limit", int64(opts.Limit())))
+
+	ctx, span := svc.tracer.Start(ctx, "UserService.FindUsers", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	uu, err := svc.wrapped.FindUsers(ctx, opts)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return uu, nil
+}
+
+// UpdateUser replaces User.FirstName and User.LastName. User.UpdatedAt is set on success.
+func (svc *UserService) UpdateUser(ctx context.Context, user *banking.User) error {
+	attrs := append(svc.attrs, attribute.Stringer("user_id", user.ID))
+
+	ctx, span := svc.tracer.Start(ctx, "UserService.UpdateUser", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.UpdateUser(ctx, user); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
+
+// DeleteUser removes User with specified identifier.
+// Return banking.ErrUserHasAccounts if User still owns user accounts.
+func (svc *UserService) DeleteUser(ctx context.Context, id banking.ID) error {
+	attrs := append(svc.attrs, attribute.Stringer("user_id", id))
+
+	ctx, span := svc.tracer.Start(ctx, "UserService.DeleteUser", trace.WithAttributes(attrs...))
+	defer span.End()
+
+	if err := svc.wrapped.DeleteUser(ctx, id); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
+		return err // nolint:wrapcheck
+	}
+
+	span.SetStatus(codes.Ok, "")
+
+	return nil
+}
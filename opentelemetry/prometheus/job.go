@@ -0,0 +1,78 @@
+package prometheus
+
+import (
+	"context"
+	"time"
+
+	"github.com/morozovcookie/agat-banking/worker"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+var _ worker.Job = (*Job)(nil)
+
+// Job represents a unit of background work.
+type Job struct {
+	wrapped worker.Job
+
+	attrs []attribute.KeyValue
+
+	runDuration    metric.Int64Histogram
+	processedCount metric.Int64Counter
+	failedCount    metric.Int64Counter
+}
+
+// NewJob returns a new Job instance. Job name should be passed in attributes to distinguish jobs.
+func NewJob(job worker.Job, meter metric.Meter, attrs ...attribute.KeyValue) (wrapper *Job, err error) {
+	wrapper = &Job{
+		wrapped: job,
+
+		attrs: attrs,
+
+		runDuration:    metric.Int64Histogram{},
+		processedCount: metric.Int64Counter{},
+		failedCount:    metric.Int64Counter{},
+	}
+
+	wrapper.runDuration, err = meter.NewInt64Histogram("worker.job.duration",
+		metric.WithDescription("measures the duration of the background job run"),
+		metric.WithUnit(unit.Milliseconds))
+	if err != nil {
+		return nil, errors.Wrap(err, "init job")
+	}
+
+	wrapper.processedCount, err = meter.NewInt64Counter("worker.job.processed",
+		metric.WithDescription("measures the number of items processed by the background job (e.g. rows deleted)"),
+		metric.WithUnit(unit.Dimensionless))
+	if err != nil {
+		return nil, errors.Wrap(err, "init job")
+	}
+
+	wrapper.failedCount, err = meter.NewInt64Counter("worker.job.failed",
+		metric.WithDescription("measures the number of failed background job runs"),
+		metric.WithUnit(unit.Dimensionless))
+	if err != nil {
+		return nil, errors.Wrap(err, "init job")
+	}
+
+	return wrapper, nil
+}
+
+// Run does the work and returns the number of processed items.
+func (job *Job) Run(ctx context.Context) (int64, error) {
+	var (
+		now            = time.Now()
+		processed, err = job.wrapped.Run(ctx)
+	)
+
+	job.runDuration.Record(ctx, time.Since(now).Milliseconds(), job.attrs...)
+	job.processedCount.Add(ctx, processed, job.attrs...)
+
+	if err != nil {
+		job.failedCount.Add(ctx, 1, job.attrs...)
+	}
+
+	return processed, err // nolint:wrapcheck
+}
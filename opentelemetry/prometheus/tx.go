@@ -62,6 +62,11 @@ func (tx *tx) PrepareContext(ctx context.Context, query string) (percona.Stmt, e
 	return newStmt(res, query, tx.meter, tx.attrs...)
 }
 
+// ExecContext executes a query without arguments by the text protocol.
+func (tx *tx) ExecContext(ctx context.Context, query string) error {
+	return tx.wrapped.ExecContext(ctx, query) // nolint:wrapcheck
+}
+
 // Commit commits the transaction.
 func (tx *tx) Commit(ctx context.Context) error {
 	var (
@@ -0,0 +1,29 @@
+package banking
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrUnsupportedPasswordHash is the error that will be raised when encoded password hash was produced by an
+	// algorithm which is not supported by PasswordHasher.
+	ErrUnsupportedPasswordHash = errors.New("unsupported password hash")
+
+	// ErrMalformedPasswordHash is the error that will be raised when encoded password hash could not be decoded.
+	ErrMalformedPasswordHash = errors.New("malformed password hash")
+)
+
+// PasswordHasher represents a service for hashing and verifying user passwords.
+type PasswordHasher interface {
+	// HashPassword returns encoded password hash.
+	HashPassword(ctx context.Context, password SecretString) (string, error)
+
+	// ComparePassword returns true if passed password matches encoded password hash.
+	ComparePassword(ctx context.Context, encodedHash string, password SecretString) (bool, error)
+
+	// NeedsRehash returns true if encoded password hash was produced by another algorithm or with weaker parameters
+	// than PasswordHasher currently uses.
+	NeedsRehash(ctx context.Context, encodedHash string) bool
+}
@@ -0,0 +1,172 @@
+package argon2
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"io"
+	"strconv"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/password"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/argon2"
+)
+
+// ID is the algorithm identifier which will be placed into PHC string.
+const ID = "argon2id"
+
+const (
+	memoryParam      = "m"
+	iterationsParam  = "t"
+	parallelismParam = "p"
+)
+
+// maxMemory is the maximal amount of memory (in KiB) which decoded hash could require, so a damaged stored hash could
+// not make the service allocate gigabytes for a single comparison.
+const maxMemory = 1024 * 1024
+
+var _ banking.PasswordHasher = (*PasswordHasher)(nil)
+
+// PasswordHasher represents a service for hashing and verifying user passwords with argon2id algorithm.
+type PasswordHasher struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	saltLength  uint32
+	keyLength   uint32
+}
+
+// NewPasswordHasher returns a new PasswordHasher instance.
+func NewPasswordHasher(opts ...PasswordHasherOption) *PasswordHasher {
+	hasher := &PasswordHasher{
+		memory:      DefaultMemory,
+		iterations:  DefaultIterations,
+		parallelism: DefaultParallelism,
+		saltLength:  DefaultSaltLength,
+		keyLength:   DefaultKeyLength,
+	}
+
+	for _, opt := range opts {
+		opt.apply(hasher)
+	}
+
+	return hasher
+}
+
+// HashPassword returns encoded password hash.
+func (h *PasswordHasher) HashPassword(_ context.Context, password banking.SecretString) (string, error) {
+	salt := make([]byte, h.saltLength)
+
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", errors.Wrap(err, "hash password")
+	}
+
+	hash := argon2.IDKey([]byte(password.DecryptedString()), salt, h.iterations, h.memory, h.parallelism,
+		h.keyLength)
+
+	return h.encodeHash(salt, hash), nil
+}
+
+func (h *PasswordHasher) encodeHash(salt, hash []byte) string {
+	phc := &password.PHCString{
+		ID:      ID,
+		Version: argon2.Version,
+		Params: []password.PHCParam{
+			{Name: memoryParam, Value: strconv.FormatUint(uint64(h.memory), 10)},
+			{Name: iterationsParam, Value: strconv.FormatUint(uint64(h.iterations), 10)},
+			{Name: parallelismParam, Value: strconv.FormatUint(uint64(h.parallelism), 10)},
+		},
+		Salt: salt,
+		Hash: hash,
+	}
+
+	return phc.String()
+}
+
+// ComparePassword returns true if passed password matches encoded password hash.
+func (h *PasswordHasher) ComparePassword(
+	_ context.Context,
+	encodedHash string,
+	password banking.SecretString,
+) (
+	bool,
+	error,
+) {
+	phc, params, err := decodeHash(encodedHash)
+	if err != nil {
+		return false, errors.Wrap(err, "compare password")
+	}
+
+	hash := argon2.IDKey([]byte(password.DecryptedString()), phc.Salt, params.iterations, params.memory,
+		params.parallelism, uint32(len(phc.Hash)))
+
+	return subtle.ConstantTimeCompare(hash, phc.Hash) == 1, nil
+}
+
+// NeedsRehash returns true if encoded password hash was produced by another algorithm or with weaker parameters
+// than PasswordHasher currently uses.
+func (h *PasswordHasher) NeedsRehash(_ context.Context, encodedHash string) bool {
+	phc, params, err := decodeHash(encodedHash)
+	if err != nil {
+		return true
+	}
+
+	return params.memory < h.memory ||
+		params.iterations < h.iterations ||
+		params.parallelism < h.parallelism ||
+		uint32(len(phc.Salt)) < h.saltLength ||
+		uint32(len(phc.Hash)) < h.keyLength
+}
+
+type hashParams struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+func decodeHash(encodedHash string) (*password.PHCString, *hashParams, error) {
+	phc, err := password.ParsePHCString(encodedHash)
+	if errors.Is(err, banking.ErrMalformedPasswordHash) {
+		return nil, nil, errors.Wrap(banking.ErrUnsupportedPasswordHash, "decode hash")
+	}
+
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "decode hash")
+	}
+
+	if phc.ID != ID {
+		return nil, nil, errors.Wrap(banking.ErrUnsupportedPasswordHash, "decode hash")
+	}
+
+	if phc.Version != argon2.Version || len(phc.Salt) == 0 || len(phc.Hash) == 0 {
+		return nil, nil, errors.Wrap(banking.ErrMalformedPasswordHash, "decode hash")
+	}
+
+	params := new(hashParams)
+
+	memory, err := phc.UintParam(memoryParam, 32) // nolint:gomnd
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "decode hash")
+	}
+
+	iterations, err := phc.UintParam(iterationsParam, 32) // nolint:gomnd
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "decode hash")
+	}
+
+	parallelism, err := phc.UintParam(parallelismParam, 8) // nolint:gomnd
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "decode hash")
+	}
+
+	params.memory, params.iterations, params.parallelism = uint32(memory), uint32(iterations), uint8(parallelism)
+
+	// Algorithm panics if there are no passes or threads, and requires at least 8 KiB of memory per thread.
+	if params.iterations < 1 || params.parallelism < 1 ||
+		params.memory < 8*uint32(params.parallelism) || params.memory > maxMemory {
+		return nil, nil, errors.Wrap(banking.ErrMalformedPasswordHash, "decode hash")
+	}
+
+	return phc, params, nil
+}
@@ -0,0 +1,62 @@
+package argon2
+
+// PasswordHasherOption represents an option for configure PasswordHasher object.
+type PasswordHasherOption interface {
+	apply(hasher *PasswordHasher)
+}
+
+type passwordHasherOptionFunc func(hasher *PasswordHasher)
+
+func (fn passwordHasherOptionFunc) apply(hasher *PasswordHasher) {
+	fn(hasher)
+}
+
+// DefaultMemory is the default amount of memory (in KiB) used by the algorithm.
+const DefaultMemory = 64 * 1024
+
+// WithMemory sets up the amount of memory (in KiB) used by the algorithm.
+func WithMemory(memory uint32) PasswordHasherOption {
+	return passwordHasherOptionFunc(func(hasher *PasswordHasher) {
+		hasher.memory = memory
+	})
+}
+
+// DefaultIterations is the default number of passes over the memory.
+const DefaultIterations = 3
+
+// WithIterations sets up the number of passes over the memory.
+func WithIterations(iterations uint32) PasswordHasherOption {
+	return passwordHasherOptionFunc(func(hasher *PasswordHasher) {
+		hasher.iterations = iterations
+	})
+}
+
+// DefaultParallelism is the default number of threads used by the algorithm.
+const DefaultParallelism = 2
+
+// WithParallelism sets up the number of threads used by the algorithm.
+func WithParallelism(parallelism uint8) PasswordHasherOption {
+	return passwordHasherOptionFunc(func(hasher *PasswordHasher) {
+		hasher.parallelism = parallelism
+	})
+}
+
+// DefaultSaltLength is the default length of random salt in bytes.
+const DefaultSaltLength = 16
+
+// WithSaltLength sets up the length of random salt in bytes.
+func WithSaltLength(length uint32) PasswordHasherOption {
+	return passwordHasherOptionFunc(func(hasher *PasswordHasher) {
+		hasher.saltLength = length
+	})
+}
+
+// DefaultKeyLength is the default length of generated hash in bytes.
+const DefaultKeyLength = 32
+
+// WithKeyLength sets up the length of generated hash in bytes.
+func WithKeyLength(length uint32) PasswordHasherOption {
+	return passwordHasherOptionFunc(func(hasher *PasswordHasher) {
+		hasher.keyLength = length
+	})
+}
@@ -0,0 +1,307 @@
+package argon2_test
+
+import (
+	"context"
+	"testing"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/morozovcookie/agat-banking/password/argon2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSecretString(str string) *mock.SecretString {
+	ss := mock.NewSecretString()
+
+	ss.On("DecryptedString").
+		Return(str)
+
+	return ss
+}
+
+func TestPasswordHasher_ComparePassword(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		opts []argon2.PasswordHasherOption
+	}
+	type args struct {
+		ctx         context.Context
+		encodedHash string
+		password    string
+	}
+	type wants struct {
+		isSame bool
+		err    error
+	}
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "pass",
+				enabled: true,
+			},
+			fields: fields{
+				opts: nil,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "$argon2id$v=19$m=1024,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$s8Nc1kOHsE/hml8vXqBJ81iTpHs43VB7/nU5hGednCk",
+				password:    "admin",
+			},
+			wants: wants{
+				isSame: true,
+				err:    nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "incorrect password",
+				enabled: true,
+			},
+			fields: fields{
+				opts: nil,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "$argon2id$v=19$m=1024,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$s8Nc1kOHsE/hml8vXqBJ81iTpHs43VB7/nU5hGednCk",
+				password:    "not-admin",
+			},
+			wants: wants{
+				isSame: false,
+				err:    nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "unsupported hash",
+				enabled: true,
+			},
+			fields: fields{
+				opts: nil,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "8c6976e5b5410415bde908bd4dee15dfb167a9c873fc4bb8a81f6f2ab448a918",
+				password:    "admin",
+			},
+			wants: wants{
+				isSame: false,
+				err:    banking.ErrUnsupportedPasswordHash,
+			},
+		},
+		{
+			meta: meta{
+				name:    "malformed hash",
+				enabled: true,
+			},
+			fields: fields{
+				opts: nil,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "$argon2id$v=19$m=1024,t=1$c29tZXNhbHRzb21lc2FsdA$s8Nc1kOHsE/hml8vXqBJ81iTpHs43VB7/nU5hGednCk",
+				password:    "admin",
+			},
+			wants: wants{
+				isSame: false,
+				err:    banking.ErrMalformedPasswordHash,
+			},
+		},
+		{
+			meta: meta{
+				name:    "zero iterations",
+				enabled: true,
+			},
+			fields: fields{
+				opts: nil,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "$argon2id$v=19$m=1024,t=0,p=1$c29tZXNhbHRzb21lc2FsdA$s8Nc1kOHsE/hml8vXqBJ81iTpHs43VB7/nU5hGednCk",
+				password:    "admin",
+			},
+			wants: wants{
+				isSame: false,
+				err:    banking.ErrMalformedPasswordHash,
+			},
+		},
+		{
+			meta: meta{
+				name:    "zero parallelism",
+				enabled: true,
+			},
+			fields: fields{
+				opts: nil,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "$argon2id$v=19$m=1024,t=1,p=0$c29tZXNhbHRzb21lc2FsdA$s8Nc1kOHsE/hml8vXqBJ81iTpHs43VB7/nU5hGednCk",
+				password:    "admin",
+			},
+			wants: wants{
+				isSame: false,
+				err:    banking.ErrMalformedPasswordHash,
+			},
+		},
+		{
+			meta: meta{
+				name:    "not enough memory",
+				enabled: true,
+			},
+			fields: fields{
+				opts: nil,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "$argon2id$v=19$m=8,t=1,p=2$c29tZXNhbHRzb21lc2FsdA$s8Nc1kOHsE/hml8vXqBJ81iTpHs43VB7/nU5hGednCk",
+				password:    "admin",
+			},
+			wants: wants{
+				isSame: false,
+				err:    banking.ErrMalformedPasswordHash,
+			},
+		},
+		{
+			meta: meta{
+				name:    "too much memory",
+				enabled: true,
+			},
+			fields: fields{
+				opts: nil,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "$argon2id$v=19$m=4294967295,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$s8Nc1kOHsE/hml8vXqBJ81iTpHs43VB7/nU5hGednCk",
+				password:    "admin",
+			},
+			wants: wants{
+				isSame: false,
+				err:    banking.ErrMalformedPasswordHash,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			hasher := argon2.NewPasswordHasher(tt.fields.opts...)
+
+			isSame, err := hasher.ComparePassword(tt.args.ctx, tt.args.encodedHash, newSecretString(tt.args.password))
+			assert.Equal(t, tt.wants.isSame, isSame)
+			assert.ErrorIs(t, err, tt.wants.err)
+		})
+	}
+}
+
+func TestPasswordHasher_HashPassword(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		hasher = argon2.NewPasswordHasher(argon2.WithMemory(1024), argon2.WithIterations(1),
+			argon2.WithParallelism(1))
+	)
+
+	encodedHash, err := hasher.HashPassword(ctx, newSecretString("admin"))
+	assert.NoError(t, err)
+	assert.Regexp(t, `^\$argon2id\$v=19\$m=1024,t=1,p=1\$[A-Za-z0-9+/]{22}\$[A-Za-z0-9+/]{43}$`, encodedHash)
+
+	isSame, err := hasher.ComparePassword(ctx, encodedHash, newSecretString("admin"))
+	assert.NoError(t, err)
+	assert.True(t, isSame)
+
+	assert.False(t, hasher.NeedsRehash(ctx, encodedHash))
+}
+
+func TestPasswordHasher_NeedsRehash(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		ctx         context.Context
+		encodedHash string
+	}
+	type wants struct {
+		needsRehash bool
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "same parameters",
+				enabled: true,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "$argon2id$v=19$m=65536,t=3,p=2$c29tZXNhbHRzb21lc2FsdA$s8Nc1kOHsE/hml8vXqBJ81iTpHs43VB7/nU5hGednCk",
+			},
+			wants: wants{
+				needsRehash: false,
+			},
+		},
+		{
+			meta: meta{
+				name:    "weaker parameters",
+				enabled: true,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "$argon2id$v=19$m=1024,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$s8Nc1kOHsE/hml8vXqBJ81iTpHs43VB7/nU5hGednCk",
+			},
+			wants: wants{
+				needsRehash: true,
+			},
+		},
+		{
+			meta: meta{
+				name:    "legacy sha256",
+				enabled: true,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "8c6976e5b5410415bde908bd4dee15dfb167a9c873fc4bb8a81f6f2ab448a918",
+			},
+			wants: wants{
+				needsRehash: true,
+			},
+		},
+		{
+			meta: meta{
+				name:    "bcrypt",
+				enabled: true,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "$2a$04$tkd9VpjruxpRXRJNrWDxje23q63adMZ2lsjR6DaMAE4n2U137CliW",
+			},
+			wants: wants{
+				needsRehash: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			needsRehash := argon2.NewPasswordHasher().NeedsRehash(tt.args.ctx, tt.args.encodedHash)
+			assert.Equal(t, tt.wants.needsRehash, needsRehash)
+		})
+	}
+}
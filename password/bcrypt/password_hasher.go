@@ -0,0 +1,93 @@
+package bcrypt
+
+import (
+	"context"
+	"strings"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Prefixes is the list of identifiers which could be placed at the beginning of bcrypt hash.
+var Prefixes = []string{"$2a$", "$2b$", "$2y$"}
+
+var _ banking.PasswordHasher = (*PasswordHasher)(nil)
+
+// PasswordHasher represents a service for hashing and verifying user passwords with bcrypt algorithm.
+type PasswordHasher struct {
+	cost int
+}
+
+// NewPasswordHasher returns a new PasswordHasher instance.
+func NewPasswordHasher(opts ...PasswordHasherOption) *PasswordHasher {
+	hasher := &PasswordHasher{
+		cost: DefaultCost,
+	}
+
+	for _, opt := range opts {
+		opt.apply(hasher)
+	}
+
+	return hasher
+}
+
+// HashPassword returns encoded password hash.
+func (h *PasswordHasher) HashPassword(_ context.Context, password banking.SecretString) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password.DecryptedString()), h.cost)
+	if err != nil {
+		return "", errors.Wrap(err, "hash password")
+	}
+
+	return string(hash), nil
+}
+
+// ComparePassword returns true if passed password matches encoded password hash.
+func (h *PasswordHasher) ComparePassword(
+	_ context.Context,
+	encodedHash string,
+	password banking.SecretString,
+) (
+	bool,
+	error,
+) {
+	if !isSupported(encodedHash) {
+		return false, errors.Wrap(banking.ErrUnsupportedPasswordHash, "compare password")
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password.DecryptedString()))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, errors.Wrapf(banking.ErrMalformedPasswordHash, "compare password: %s", err)
+	}
+
+	return true, nil
+}
+
+// NeedsRehash returns true if encoded password hash was produced by another algorithm or with weaker parameters
+// than PasswordHasher currently uses.
+func (h *PasswordHasher) NeedsRehash(_ context.Context, encodedHash string) bool {
+	if !isSupported(encodedHash) {
+		return true
+	}
+
+	cost, err := bcrypt.Cost([]byte(encodedHash))
+	if err != nil {
+		return true
+	}
+
+	return cost < h.cost
+}
+
+func isSupported(encodedHash string) bool {
+	for _, prefix := range Prefixes {
+		if strings.HasPrefix(encodedHash, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
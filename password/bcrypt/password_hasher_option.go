@@ -0,0 +1,26 @@
+package bcrypt
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasherOption represents an option for configure PasswordHasher object.
+type PasswordHasherOption interface {
+	apply(hasher *PasswordHasher)
+}
+
+type passwordHasherOptionFunc func(hasher *PasswordHasher)
+
+func (fn passwordHasherOptionFunc) apply(hasher *PasswordHasher) {
+	fn(hasher)
+}
+
+// DefaultCost is the default cost of the algorithm.
+const DefaultCost = bcrypt.DefaultCost + 2
+
+// WithCost sets up the cost of the algorithm.
+func WithCost(cost int) PasswordHasherOption {
+	return passwordHasherOptionFunc(func(hasher *PasswordHasher) {
+		hasher.cost = cost
+	})
+}
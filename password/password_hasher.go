@@ -0,0 +1,69 @@
+package password
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+var _ banking.PasswordHasher = (*PasswordHasher)(nil)
+
+// PasswordHasher represents a service for hashing and verifying user passwords with several algorithms.
+//
+// New passwords are always hashed with the primary hasher. Verification is delegated to the first hasher (starting
+// with the primary) which supports algorithm of the stored hash, so hashes produced by the legacy algorithms still could
+// be verified and then transparently rehashed.
+type PasswordHasher struct {
+	primary   banking.PasswordHasher
+	fallbacks []banking.PasswordHasher
+}
+
+// NewPasswordHasher returns a new PasswordHasher instance.
+func NewPasswordHasher(primary banking.PasswordHasher, fallbacks ...banking.PasswordHasher) *PasswordHasher {
+	return &PasswordHasher{
+		primary:   primary,
+		fallbacks: fallbacks,
+	}
+}
+
+// HashPassword returns encoded password hash.
+func (h *PasswordHasher) HashPassword(ctx context.Context, password banking.SecretString) (string, error) {
+	encodedHash, err := h.primary.HashPassword(ctx, password)
+	if err != nil {
+		return "", errors.Wrap(err, "hash password")
+	}
+
+	return encodedHash, nil
+}
+
+// ComparePassword returns true if passed password matches encoded password hash.
+func (h *PasswordHasher) ComparePassword(
+	ctx context.Context,
+	encodedHash string,
+	password banking.SecretString,
+) (
+	bool,
+	error,
+) {
+	for _, hasher := range append([]banking.PasswordHasher{h.primary}, h.fallbacks...) {
+		isSame, err := hasher.ComparePassword(ctx, encodedHash, password)
+		if errors.Is(err, banking.ErrUnsupportedPasswordHash) {
+			continue
+		}
+
+		if err != nil {
+			return false, errors.Wrap(err, "compare password")
+		}
+
+		return isSame, nil
+	}
+
+	return false, errors.Wrap(banking.ErrUnsupportedPasswordHash, "compare password")
+}
+
+// NeedsRehash returns true if encoded password hash was produced by another algorithm or with weaker parameters
+// than primary hasher currently uses.
+func (h *PasswordHasher) NeedsRehash(ctx context.Context, encodedHash string) bool {
+	return h.primary.NeedsRehash(ctx, encodedHash)
+}
@@ -0,0 +1,250 @@
+package password_test
+
+import (
+	"context"
+	"testing"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/morozovcookie/agat-banking/password"
+	"github.com/morozovcookie/agat-banking/password/argon2"
+	"github.com/morozovcookie/agat-banking/password/bcrypt"
+	"github.com/morozovcookie/agat-banking/password/sha256"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordHasher_ComparePassword(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		ctx         context.Context
+		encodedHash string
+		password    string
+	}
+	type wants struct {
+		isSame      bool
+		needsRehash bool
+		err         error
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "argon2id",
+				enabled: true,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "$argon2id$v=19$m=1024,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$s8Nc1kOHsE/hml8vXqBJ81iTpHs43VB7/nU5hGednCk",
+				password:    "admin",
+			},
+			wants: wants{
+				isSame:      true,
+				needsRehash: false,
+				err:         nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "bcrypt",
+				enabled: true,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "$2a$04$tkd9VpjruxpRXRJNrWDxje23q63adMZ2lsjR6DaMAE4n2U137CliW",
+				password:    "admin",
+			},
+			wants: wants{
+				isSame:      true,
+				needsRehash: true,
+				err:         nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "legacy sha256",
+				enabled: true,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "8c6976e5b5410415bde908bd4dee15dfb167a9c873fc4bb8a81f6f2ab448a918",
+				password:    "admin",
+			},
+			wants: wants{
+				isSame:      true,
+				needsRehash: true,
+				err:         nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "legacy sha256 incorrect password",
+				enabled: true,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "8c6976e5b5410415bde908bd4dee15dfb167a9c873fc4bb8a81f6f2ab448a918",
+				password:    "not-admin",
+			},
+			wants: wants{
+				isSame:      false,
+				needsRehash: true,
+				err:         nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "unsupported hash",
+				enabled: true,
+			},
+			args: args{
+				ctx:         context.Background(),
+				encodedHash: "$scrypt$ln=16,r=8,p=1$aM15713r3Xsvxbi31lqr1Q$nFNh2CVHVjNldFVKDHDlm4CbdRSCdEBsjjJxD+iCs5E",
+				password:    "admin",
+			},
+			wants: wants{
+				isSame:      false,
+				needsRehash: true,
+				err:         banking.ErrUnsupportedPasswordHash,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			var (
+				hasher = password.NewPasswordHasher(
+					argon2.NewPasswordHasher(argon2.WithMemory(1024), argon2.WithIterations(1),
+						argon2.WithParallelism(1)),
+					bcrypt.NewPasswordHasher(),
+					sha256.NewPasswordHasher())
+
+				ss = mock.NewSecretString()
+			)
+
+			ss.On("DecryptedString").
+				Return(tt.args.password)
+
+			isSame, err := hasher.ComparePassword(tt.args.ctx, tt.args.encodedHash, ss)
+			assert.Equal(t, tt.wants.isSame, isSame)
+			assert.ErrorIs(t, err, tt.wants.err)
+
+			assert.Equal(t, tt.wants.needsRehash, hasher.NeedsRehash(tt.args.ctx, tt.args.encodedHash))
+		})
+	}
+}
+
+func TestParsePHCString(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		encodedHash string
+	}
+	type wants struct {
+		phc *password.PHCString
+		err error
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "pass",
+				enabled: true,
+			},
+			args: args{
+				encodedHash: "$argon2id$v=19$m=1024,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$c29tZWhhc2g",
+			},
+			wants: wants{
+				phc: &password.PHCString{
+					ID:      "argon2id",
+					Version: 19,
+					Params: []password.PHCParam{
+						{Name: "m", Value: "1024"},
+						{Name: "t", Value: "1"},
+						{Name: "p", Value: "1"},
+					},
+					Salt: []byte("somesaltsomesalt"),
+					Hash: []byte("somehash"),
+				},
+				err: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "identifier only",
+				enabled: true,
+			},
+			args: args{
+				encodedHash: "$argon2id",
+			},
+			wants: wants{
+				phc: &password.PHCString{
+					ID:      "argon2id",
+					Version: 0,
+					Params:  nil,
+					Salt:    nil,
+					Hash:    nil,
+				},
+				err: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "not a phc string",
+				enabled: true,
+			},
+			args: args{
+				encodedHash: "8c6976e5b5410415bde908bd4dee15dfb167a9c873fc4bb8a81f6f2ab448a918",
+			},
+			wants: wants{
+				phc: nil,
+				err: banking.ErrMalformedPasswordHash,
+			},
+		},
+		{
+			meta: meta{
+				name:    "too many fields",
+				enabled: true,
+			},
+			args: args{
+				encodedHash: "$argon2id$v=19$m=1024,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$c29tZWhhc2g$c29tZWhhc2g",
+			},
+			wants: wants{
+				phc: nil,
+				err: banking.ErrMalformedPasswordHash,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			phc, err := password.ParsePHCString(tt.args.encodedHash)
+			assert.Equal(t, tt.wants.phc, phc)
+			assert.ErrorIs(t, err, tt.wants.err)
+
+			if err == nil {
+				assert.Equal(t, tt.args.encodedHash, phc.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package password
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+const (
+	phcSeparator          = "$"
+	phcParamsSeparator    = ","
+	phcKeyValueSeparator  = "="
+	phcVersionParamPrefix = "v" + phcKeyValueSeparator
+)
+
+// PHCParam represents a single algorithm parameter of PHC string.
+type PHCParam struct {
+	// Name is the parameter name.
+	Name string
+
+	// Value is the parameter value.
+	Value string
+}
+
+// PHCString represents a password hash encoded with PHC string format:
+//
+//	$<id>[$v=<version>][$<param>=<value>(,<param>=<value>)*][$<salt>[$<hash>]]
+//
+// Salt and hash are encoded with the standard base64 encoding without padding.
+type PHCString struct {
+	// ID is the identifier of the hashing algorithm.
+	ID string
+
+	// Version is the version of the hashing algorithm. Zero value means that version was not specified.
+	Version int
+
+	// Params is the ordered list of the hashing algorithm parameters.
+	Params []PHCParam
+
+	// Salt is the salt which was used for hashing.
+	Salt []byte
+
+	// Hash is the password hash.
+	Hash []byte
+}
+
+// Param returns the value of parameter with specified name.
+func (phc *PHCString) Param(name string) (string, bool) {
+	for _, param := range phc.Params {
+		if param.Name == name {
+			return param.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// UintParam returns the value of parameter with specified name as an unsigned integer.
+func (phc *PHCString) UintParam(name string, bitSize int) (uint64, error) {
+	str, ok := phc.Param(name)
+	if !ok {
+		return 0, errors.Wrapf(banking.ErrMalformedPasswordHash, "missed %s parameter", name)
+	}
+
+	val, err := strconv.ParseUint(str, 10, bitSize)
+	if err != nil {
+		return 0, errors.Wrapf(banking.ErrMalformedPasswordHash, "parse %s parameter: %s", name, err)
+	}
+
+	return val, nil
+}
+
+func (phc *PHCString) String() string {
+	var sb strings.Builder
+
+	_, _ = sb.WriteString(phcSeparator + phc.ID)
+
+	if phc.Version != 0 {
+		_, _ = sb.WriteString(phcSeparator + phcVersionParamPrefix + strconv.Itoa(phc.Version))
+	}
+
+	if len(phc.Params) != 0 {
+		params := make([]string, 0, len(phc.Params))
+
+		for _, param := range phc.Params {
+			params = append(params, param.Name+phcKeyValueSeparator+param.Value)
+		}
+
+		_, _ = sb.WriteString(phcSeparator + strings.Join(params, phcParamsSeparator))
+	}
+
+	if phc.Salt == nil {
+		return sb.String()
+	}
+
+	_, _ = sb.WriteString(phcSeparator + base64.RawStdEncoding.EncodeToString(phc.Salt))
+
+	if phc.Hash != nil {
+		_, _ = sb.WriteString(phcSeparator + base64.RawStdEncoding.EncodeToString(phc.Hash))
+	}
+
+	return sb.String()
+}
+
+// ParsePHCString decodes password hash encoded with PHC string format.
+func ParsePHCString(encodedHash string) (*PHCString, error) {
+	parts := strings.Split(encodedHash, phcSeparator)
+	if len(parts) < 2 || parts[0] != "" || parts[1] == "" { // nolint:gomnd
+		return nil, errors.Wrap(banking.ErrMalformedPasswordHash, "parse phc string")
+	}
+
+	phc := &PHCString{
+		ID:      parts[1],
+		Version: 0,
+		Params:  nil,
+		Salt:    nil,
+		Hash:    nil,
+	}
+
+	parts = parts[2:]
+
+	if len(parts) != 0 && strings.HasPrefix(parts[0], phcVersionParamPrefix) {
+		version, err := strconv.Atoi(strings.TrimPrefix(parts[0], phcVersionParamPrefix))
+		if err != nil {
+			return nil, errors.Wrap(banking.ErrMalformedPasswordHash, "parse phc string")
+		}
+
+		phc.Version, parts = version, parts[1:]
+	}
+
+	if len(parts) != 0 && strings.Contains(parts[0], phcKeyValueSeparator) {
+		for _, param := range strings.Split(parts[0], phcParamsSeparator) {
+			kv := strings.SplitN(param, phcKeyValueSeparator, 2) // nolint:gomnd
+			if len(kv) != 2 || kv[0] == "" {                     // nolint:gomnd
+				return nil, errors.Wrap(banking.ErrMalformedPasswordHash, "parse phc string")
+			}
+
+			phc.Params = append(phc.Params, PHCParam{Name: kv[0], Value: kv[1]})
+		}
+
+		parts = parts[1:]
+	}
+
+	var err error
+
+	if len(parts) != 0 {
+		if phc.Salt, err = base64.RawStdEncoding.DecodeString(parts[0]); err != nil {
+			return nil, errors.Wrap(banking.ErrMalformedPasswordHash, "parse phc string")
+		}
+
+		parts = parts[1:]
+	}
+
+	if len(parts) != 0 {
+		if phc.Hash, err = base64.RawStdEncoding.DecodeString(parts[0]); err != nil {
+			return nil, errors.Wrap(banking.ErrMalformedPasswordHash, "parse phc string")
+		}
+
+		parts = parts[1:]
+	}
+
+	if len(parts) != 0 {
+		return nil, errors.Wrap(banking.ErrMalformedPasswordHash, "parse phc string")
+	}
+
+	return phc, nil
+}
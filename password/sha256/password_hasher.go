@@ -0,0 +1,57 @@
+package sha256
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+// ErrHashingIsNotAllowed is the error that will be raised on attempt to hash password with legacy algorithm.
+var ErrHashingIsNotAllowed = errors.New("hashing with legacy algorithm is not allowed")
+
+var _ banking.PasswordHasher = (*PasswordHasher)(nil)
+
+// PasswordHasher represents a service for verifying user passwords which were hashed with bare unsalted SHA-256 (e.g.
+// by cmd/sha256 utility).
+//
+// It is the legacy format, so PasswordHasher could be used only for verifying existing hashes, which must be rehashed
+// right after successful verification.
+type PasswordHasher struct{}
+
+// NewPasswordHasher returns a new PasswordHasher instance.
+func NewPasswordHasher() *PasswordHasher {
+	return &PasswordHasher{}
+}
+
+// HashPassword always returns ErrHashingIsNotAllowed.
+func (*PasswordHasher) HashPassword(_ context.Context, _ banking.SecretString) (string, error) {
+	return "", errors.Wrap(ErrHashingIsNotAllowed, "hash password")
+}
+
+// ComparePassword returns true if passed password matches encoded password hash.
+func (*PasswordHasher) ComparePassword(
+	_ context.Context,
+	encodedHash string,
+	password banking.SecretString,
+) (
+	bool,
+	error,
+) {
+	expected, err := hex.DecodeString(encodedHash)
+	if err != nil || len(expected) != sha256.Size {
+		return false, errors.Wrap(banking.ErrUnsupportedPasswordHash, "compare password")
+	}
+
+	actual := sha256.Sum256([]byte(password.DecryptedString()))
+
+	return subtle.ConstantTimeCompare(expected, actual[:]) == 1, nil
+}
+
+// NeedsRehash always returns true.
+func (*PasswordHasher) NeedsRehash(_ context.Context, _ string) bool {
+	return true
+}
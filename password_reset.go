@@ -0,0 +1,63 @@
+package banking
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrPasswordResetTokenInvalid will be raised when password reset token does not exist, expired or was already used.
+var ErrPasswordResetTokenInvalid = errors.New("password reset token invalid")
+
+// PasswordResetToken represents a single-use secret which allows to set a new password without knowing the current
+// one. Only the secret hash is stored, so stolen database could not be used for taking over user accounts.
+type PasswordResetToken struct {
+	// ID is the password reset token unique identifier.
+	ID ID
+
+	// Account is the user account which password could be reset.
+	Account *UserAccount
+
+	// TokenHash is the hex encoded SHA-256 hash of the secret. Secret is random enough, so slow password hashing is
+	// not required and hash could be used for finding the token.
+	TokenHash string
+
+	// CreatedAt is the time when password reset token was issued.
+	CreatedAt time.Time
+
+	// ExpiresAt is the time which after password reset token could not be used.
+	ExpiresAt time.Time
+
+	// RedeemedAt is the time when password reset token was used, it is zero for unused token.
+	RedeemedAt time.Time
+}
+
+// PasswordResetTokenService represents a service for managing PasswordResetToken data.
+type PasswordResetTokenService interface {
+	// StorePasswordResetToken stores a new PasswordResetToken. Previously issued tokens of the same user account are
+	// invalidated, so only the last one could be used.
+	StorePasswordResetToken(ctx context.Context, token *PasswordResetToken) error
+
+	// FindPasswordResetTokenByHash returns PasswordResetToken with specified hash which could be redeemed.
+	// Return ErrPasswordResetTokenInvalid if token does not exist, expired or was already used.
+	FindPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+
+	// RedeemPasswordResetToken marks PasswordResetToken with specified hash as used and returns it.
+	// Return ErrPasswordResetTokenInvalid if token does not exist, expired or was already used.
+	RedeemPasswordResetToken(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+}
+
+// PasswordService represents a service for changing user account passwords. Any successful password change revokes
+// every refresh token of the user account.
+type PasswordService interface {
+	// ChangePassword replaces password of the UserAccount with specified identifier if current password is correct.
+	ChangePassword(ctx context.Context, accountID ID, currentPassword, newPassword SecretString) error
+
+	// IssuePasswordResetToken returns a new single-use secret which allows to reset password of the UserAccount with
+	// specified identifier, and the time which after secret could not be used.
+	IssuePasswordResetToken(ctx context.Context, accountID ID) (SecretString, time.Time, error)
+
+	// ResetPassword replaces password of the UserAccount which password reset token was issued for.
+	ResetPassword(ctx context.Context, resetToken, newPassword SecretString) error
+}
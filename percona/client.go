@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/go-sql-driver/mysql"
+	banking "github.com/morozovcookie/agat-banking"
 	"github.com/pkg/errors"
 )
 
@@ -21,8 +22,9 @@ const (
 )
 
 var (
-	_ TxBeginner = (*Client)(nil)
-	_ Preparer   = (*Client)(nil)
+	_ TxBeginner            = (*Client)(nil)
+	_ Preparer              = (*Client)(nil)
+	_ banking.HealthChecker = (*Client)(nil)
 )
 
 // Client represents an object for basic manipulation with Percona MySQL Database System.
@@ -108,6 +110,15 @@ func (c *Client) PingContext(ctx context.Context) error {
 	return nil
 }
 
+// CheckHealth verifies that database is reachable.
+func (c *Client) CheckHealth(ctx context.Context) error {
+	if err := c.PingContext(ctx); err != nil {
+		return errors.Wrap(err, "check percona health")
+	}
+
+	return nil
+}
+
 // BeginTx starts a transaction.
 func (c *Client) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
 	res, err := c.db.BeginTx(ctx, opts)
@@ -134,6 +145,10 @@ func (c *Client) PrepareContext(ctx context.Context, query string) (Stmt, error)
 
 // Close closes database connection.
 func (c *Client) Close(_ context.Context) error {
+	if c.db == nil {
+		return nil
+	}
+
 	if err := c.db.Close(); err != nil {
 		return errors.Wrap(err, "percona close")
 	}
@@ -0,0 +1,270 @@
+package percona
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+var (
+	_ banking.EncryptedColumnService = (*EncryptedColumnService)(nil)
+	_ banking.PlaintextColumnService = (*EncryptedColumnService)(nil)
+)
+
+// EncryptedColumnService represents a service for re-encrypting values of the single encrypted column. Table must
+// have the row_id column.
+type EncryptedColumnService struct {
+	preparer Preparer
+
+	table  string
+	column string
+
+	recordIDColumn   string
+	blindIndexColumn string
+}
+
+// NewEncryptedColumnService returns a new EncryptedColumnService instance. Table and column names are put into query
+// as is, so they must never come from user input.
+func NewEncryptedColumnService(
+	preparer Preparer,
+	table string,
+	column string,
+	opts ...EncryptedColumnServiceOption,
+) *EncryptedColumnService {
+	svc := &EncryptedColumnService{
+		preparer: preparer,
+
+		table:  table,
+		column: column,
+
+		recordIDColumn:   "",
+		blindIndexColumn: "",
+	}
+
+	for _, opt := range opts {
+		opt.apply(svc)
+	}
+
+	return svc
+}
+
+// FindEncryptedValues returns the page of non-NULL values which are stored in rows with identifier greater than
+// afterRowID, in order of row identifier. Plaintext values of column with blind index are skipped.
+func (svc *EncryptedColumnService) FindEncryptedValues(
+	ctx context.Context,
+	afterRowID uint64,
+	opts banking.FindOptions,
+) (
+	[]banking.EncryptedValue,
+	error,
+) {
+	builder := svc.selectValues(afterRowID, opts)
+	if svc.blindIndexColumn != "" {
+		builder = builder.Where(squirrel.NotEq{
+			svc.blindIndexColumn: nil,
+		})
+	}
+
+	vv, err := svc.findValues(ctx, builder, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "find encrypted values")
+	}
+
+	return vv, nil
+}
+
+// ReplaceEncryptedValue replaces value only if it was not changed since it was read. Returns false if value was
+// changed concurrently.
+func (svc *EncryptedColumnService) ReplaceEncryptedValue(
+	ctx context.Context,
+	value banking.EncryptedValue,
+	encrypted string,
+) (
+	bool,
+	error,
+) {
+	query, args, err := squirrel.Update(svc.table).
+		Set(svc.column, encrypted).
+		Where(squirrel.Eq{
+			"row_id":   value.RowID,
+			svc.column: value.Value,
+		}).
+		ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "replace encrypted value")
+	}
+
+	affected, err := svc.exec(ctx, query, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "replace encrypted value")
+	}
+
+	return affected > 0, nil
+}
+
+// FindPlaintextValues returns the page of values without blind index which are stored in rows with identifier
+// greater than afterRowID, in order of row identifier. Column without blind index has no plaintext values.
+func (svc *EncryptedColumnService) FindPlaintextValues(
+	ctx context.Context,
+	afterRowID uint64,
+	opts banking.FindOptions,
+) (
+	[]banking.PlaintextValue,
+	error,
+) {
+	if svc.blindIndexColumn == "" {
+		return nil, nil
+	}
+
+	builder := svc.selectValues(afterRowID, opts).
+		Where(squirrel.Eq{
+			svc.blindIndexColumn: nil,
+		})
+
+	vv, err := svc.findValues(ctx, builder, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "find plaintext values")
+	}
+
+	pp := make([]banking.PlaintextValue, 0, len(vv))
+	for _, value := range vv {
+		pp = append(pp, banking.PlaintextValue(value))
+	}
+
+	return pp, nil
+}
+
+// EncryptPlaintextValue replaces plaintext value by encrypted one and sets up its blind index, only if value was not
+// changed since it was read. Returns false if value was changed concurrently.
+func (svc *EncryptedColumnService) EncryptPlaintextValue(
+	ctx context.Context,
+	value banking.PlaintextValue,
+	encrypted string,
+	blindIndex string,
+) (
+	bool,
+	error,
+) {
+	query, args, err := squirrel.Update(svc.table).
+		Set(svc.column, encrypted).
+		Set(svc.blindIndexColumn, blindIndex).
+		Where(squirrel.Eq{
+			"row_id":             value.RowID,
+			svc.column:           value.Value,
+			svc.blindIndexColumn: nil,
+		}).
+		ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "encrypt plaintext value")
+	}
+
+	affected, err := svc.exec(ctx, query, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "encrypt plaintext value")
+	}
+
+	return affected > 0, nil
+}
+
+func (svc *EncryptedColumnService) selectValues(afterRowID uint64, opts banking.FindOptions) squirrel.SelectBuilder {
+	columns := []string{"row_id", svc.column}
+	if svc.recordIDColumn != "" {
+		columns = append(columns, svc.recordIDColumn)
+	}
+
+	return squirrel.Select(columns...).
+		From(svc.table).
+		Where(squirrel.Gt{
+			"row_id": afterRowID,
+		}).
+		Where(squirrel.NotEq{
+			svc.column: nil,
+		}).
+		OrderBy("row_id ASC").
+		Limit(opts.Limit())
+}
+
+func (svc *EncryptedColumnService) findValues(
+	ctx context.Context,
+	builder squirrel.SelectBuilder,
+	opts banking.FindOptions,
+) (
+	[]banking.EncryptedValue,
+	error,
+) {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "find values")
+	}
+
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "find values")
+	}
+
+	defer stmt.Close(ctx)
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "find values")
+	}
+
+	defer rows.Close()
+
+	vv := make([]banking.EncryptedValue, 0, opts.Limit())
+
+	for rows.Next() {
+		value, err := svc.scanValue(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "find values")
+		}
+
+		vv = append(vv, value)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "find values")
+	}
+
+	return vv, nil
+}
+
+// scanValue reads the row and, if column is bound, restores associated data which value is bound to.
+func (svc *EncryptedColumnService) scanValue(scanner squirrel.RowScanner) (banking.EncryptedValue, error) {
+	var value banking.EncryptedValue
+
+	if svc.recordIDColumn == "" {
+		err := scanner.Scan(&value.RowID, &value.Value)
+
+		return value, err // nolint:wrapcheck
+	}
+
+	value.AssociatedData.Table, value.AssociatedData.Column = svc.table, svc.column
+
+	err := scanner.Scan(&value.RowID, &value.Value, &value.AssociatedData.RecordID)
+
+	return value, err // nolint:wrapcheck
+}
+
+func (svc *EncryptedColumnService) exec(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	defer stmt.Close(ctx)
+
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	return affected, nil
+}
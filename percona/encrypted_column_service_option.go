@@ -0,0 +1,28 @@
+package percona
+
+// EncryptedColumnServiceOption represents an option for configure EncryptedColumnService instance.
+type EncryptedColumnServiceOption interface {
+	apply(svc *EncryptedColumnService)
+}
+
+type encryptedColumnServiceOptionFunc func(svc *EncryptedColumnService)
+
+func (fn encryptedColumnServiceOptionFunc) apply(svc *EncryptedColumnService) {
+	fn(svc)
+}
+
+// WithRecordIDColumn sets up the column which contains identifier of record that values are bound to. Values of such
+// column are re-encrypted with associated data which consists of table, column and record identifier.
+func WithRecordIDColumn(column string) EncryptedColumnServiceOption {
+	return encryptedColumnServiceOptionFunc(func(svc *EncryptedColumnService) {
+		svc.recordIDColumn = column
+	})
+}
+
+// WithBlindIndexColumn sets up the column which contains blind index of value. Value without blind index is treated
+// as plaintext which was stored before column encryption was enabled.
+func WithBlindIndexColumn(column string) EncryptedColumnServiceOption {
+	return encryptedColumnServiceOptionFunc(func(svc *EncryptedColumnService) {
+		svc.blindIndexColumn = column
+	})
+}
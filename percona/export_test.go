@@ -0,0 +1,9 @@
+package percona
+
+// UpStatements returns statements of up migration.
+func (migration *Migration) UpStatements() []string {
+	return migration.up
+}
+
+// PreparerFromContext returns the active transaction if there is any, otherwise the fallback.
+var PreparerFromContext = preparerFromContext
@@ -0,0 +1,110 @@
+package percona
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// LeaderLease represents a MySQL named lock which could be held by a single service replica at a time. The lock is
+// bound to a dedicated connection, so it is released by server as soon as the replica dies.
+type LeaderLease struct {
+	client *Client
+	name   string
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// NewLeaderLease returns a new LeaderLease instance.
+func NewLeaderLease(client *Client, name string) *LeaderLease {
+	return &LeaderLease{
+		client: client,
+		name:   name,
+
+		mu:   sync.Mutex{},
+		conn: nil,
+	}
+}
+
+// Acquire tries to acquire the lease without waiting and returns true if it is held by this replica. The lease which
+// is already held is verified, because it is lost when connection is broken.
+func (l *LeaderLease) Acquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		held, err := l.isHeld(ctx)
+		if err != nil {
+			l.closeConn()
+
+			return false, errors.Wrap(err, "acquire leader lease")
+		}
+
+		if held {
+			return true, nil
+		}
+
+		l.closeConn()
+	}
+
+	conn, err := l.client.db.Conn(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "acquire leader lease")
+	}
+
+	var acquired sql.NullInt64
+
+	if err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", l.name).Scan(&acquired); err != nil {
+		_ = conn.Close()
+
+		return false, errors.Wrap(err, "acquire leader lease")
+	}
+
+	if !acquired.Valid || acquired.Int64 != 1 {
+		_ = conn.Close()
+
+		return false, nil
+	}
+
+	l.conn = conn
+
+	return true, nil
+}
+
+// Release releases the lease if it is held by this replica.
+func (l *LeaderLease) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	defer l.closeConn()
+
+	var released sql.NullInt64
+
+	if err := l.conn.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", l.name).Scan(&released); err != nil {
+		return errors.Wrap(err, "release leader lease")
+	}
+
+	return nil
+}
+
+func (l *LeaderLease) isHeld(ctx context.Context) (bool, error) {
+	var held sql.NullBool
+
+	if err := l.conn.QueryRowContext(ctx, "SELECT IS_USED_LOCK(?) = CONNECTION_ID()", l.name).Scan(&held); err != nil {
+		return false, errors.Wrap(err, "check leader lease")
+	}
+
+	return held.Valid && held.Bool, nil
+}
+
+func (l *LeaderLease) closeConn() {
+	_ = l.conn.Close()
+	l.conn = nil
+}
@@ -0,0 +1,273 @@
+package percona
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+// maxBackoffShift limits the exponent of lockout duration, so it could not overflow.
+const maxBackoffShift = 30
+
+var _ banking.LockoutService = (*LockoutService)(nil)
+
+// LockoutService represents a service for tracking failed sign in attempts.
+type LockoutService struct {
+	preparer Preparer
+
+	timer banking.Timer
+
+	maxFailedAttempts    map[banking.LockoutScope]int
+	baseLockoutDuration  time.Duration
+	maxLockoutDuration   time.Duration
+	failedAttemptsWindow time.Duration
+}
+
+// NewLockoutService returns a new LockoutService instance.
+func NewLockoutService(preparer Preparer, timer banking.Timer, opts ...LockoutServiceOption) *LockoutService {
+	svc := &LockoutService{
+		preparer: preparer,
+
+		timer: timer,
+
+		maxFailedAttempts: map[banking.LockoutScope]int{
+			banking.LockoutScopeAccount:  DefaultMaxAccountFailedAttempts,
+			banking.LockoutScopeClientIP: DefaultMaxClientIPFailedAttempts,
+		},
+		baseLockoutDuration:  DefaultBaseLockoutDuration,
+		maxLockoutDuration:   DefaultMaxLockoutDuration,
+		failedAttemptsWindow: DefaultFailedAttemptsWindow,
+	}
+
+	for _, opt := range opts {
+		opt.apply(svc)
+	}
+
+	return svc
+}
+
+// RegisterAttempt counts the sign in attempt before password comparison. It returns banking.LockedError if subject is
+// locked or the attempt exceeds threshold, then subject is locked with exponential backoff. Attempt is counted before
+// it is checked, so parallel attempts could not pass the check together. When lockout expires, a single attempt is
+// allowed and subject is locked for the doubled duration until the attempt is forgotten or failed attempts are reset,
+// so the correct password still helps after lockout while the failed one escalates it.
+func (svc *LockoutService) RegisterAttempt(ctx context.Context, subject banking.LockoutSubject) error {
+	now, err := svc.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "register attempt")
+	}
+
+	var (
+		nowMillis   = banking.TimeToMilliseconds(now)
+		windowStart = banking.TimeToMilliseconds(now.Add(-svc.failedAttemptsWindow))
+	)
+
+	// Attempts are not counted while subject is locked. Counter and expired lockout are forgotten when the previous
+	// attempt is out of window. MySQL evaluates assignments from left to right, so locked_until could be updated first:
+	// it is changed only when it is not in the future, and failed_attempts must be calculated before last_failed_at
+	// is updated.
+	query, args, err := squirrel.Insert("login_lockouts").
+		Columns("lockout_scope", "lockout_key", "failed_attempts", "last_failed_at", "locked_until", "created_at").
+		Values(subject.Scope.String(), subject.Key, 1, nowMillis, 0, nowMillis).
+		Suffix("ON DUPLICATE KEY UPDATE "+
+			"locked_until = IF(locked_until <= ? AND last_failed_at < ?, 0, locked_until), "+
+			"failed_attempts = IF(locked_until > ?, failed_attempts, "+
+			"IF(last_failed_at < ?, 1, failed_attempts + 1)), "+
+			"last_failed_at = IF(locked_until > ?, last_failed_at, VALUES(last_failed_at)), "+
+			"updated_at = VALUES(created_at)", nowMillis, windowStart, nowMillis, windowStart, nowMillis).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "register attempt")
+	}
+
+	if _, err = svc.exec(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "register attempt")
+	}
+
+	// Parallel attempt could be counted between update and select, so the attempt could be rejected because of the
+	// later one. It is safe: the attempt which exceeds threshold is allowed only after expired lockout.
+	attempts, lockedUntil, err := svc.findLockout(ctx, subject)
+	if err != nil {
+		return errors.Wrap(err, "register attempt")
+	}
+
+	if retryAfter := banking.MillisecondsToTime(lockedUntil).Sub(now); retryAfter > 0 {
+		return errors.Wrap(&banking.LockedError{
+			Scope:      subject.Scope,
+			RetryAfter: retryAfter,
+		}, "register attempt")
+	}
+
+	duration := svc.lockoutDuration(subject.Scope, attempts)
+	if duration == 0 {
+		return nil
+	}
+
+	// Subject is locked before the attempt after expired lockout is allowed, so parallel attempts are rejected. The
+	// lockout is replaced only if it was not changed since select, so only one of parallel attempts is allowed.
+	lockout := squirrel.Eq{
+		"lockout_scope": subject.Scope.String(),
+		"lockout_key":   subject.Key,
+	}
+
+	if lockedUntil != 0 {
+		lockout["locked_until"] = lockedUntil
+	}
+
+	query, args, err = squirrel.Update("login_lockouts").
+		Set("locked_until", banking.TimeToMilliseconds(now.Add(duration))).
+		Where(lockout).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "register attempt")
+	}
+
+	affected, err := svc.exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "register attempt")
+	}
+
+	if lockedUntil != 0 && affected != 0 {
+		return nil
+	}
+
+	return errors.Wrap(&banking.LockedError{
+		Scope:      subject.Scope,
+		RetryAfter: duration,
+	}, "register attempt")
+}
+
+// ForgetAttempt uncounts the registered attempt which has not failed (e.g. password was correct). If the attempt was
+// allowed after expired lockout, the lockout which was set for it is lifted.
+func (svc *LockoutService) ForgetAttempt(ctx context.Context, subject banking.LockoutSubject) error {
+	now, err := svc.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "forget attempt")
+	}
+
+	nowMillis := banking.TimeToMilliseconds(now)
+
+	// Lockout is lifted to the current time, not to zero: the next attempt is allowed after expired lockout too.
+	query, args, err := squirrel.Update("login_lockouts").
+		Set("locked_until", squirrel.Expr("IF(failed_attempts > ? AND locked_until > ?, ?, locked_until)",
+			svc.maxFailedAttempts[subject.Scope]+1, nowMillis, nowMillis)).
+		Set("failed_attempts", squirrel.Expr("IF(failed_attempts > 0, failed_attempts - 1, 0)")).
+		Set("updated_at", nowMillis).
+		Where(squirrel.Eq{
+			"lockout_scope": subject.Scope.String(),
+			"lockout_key":   subject.Key,
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "forget attempt")
+	}
+
+	if _, err = svc.exec(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "forget attempt")
+	}
+
+	return nil
+}
+
+// lockoutDuration returns zero while the number of attempts does not exceed threshold, then the base duration which is
+// doubled on every next attempt.
+func (svc *LockoutService) lockoutDuration(scope banking.LockoutScope, attempts int) time.Duration {
+	exceeded := attempts - svc.maxFailedAttempts[scope]
+	if exceeded <= 0 {
+		return 0
+	}
+
+	if exceeded > maxBackoffShift {
+		exceeded = maxBackoffShift
+	}
+
+	duration := svc.baseLockoutDuration << (exceeded - 1)
+	if duration > svc.maxLockoutDuration || duration <= 0 {
+		return svc.maxLockoutDuration
+	}
+
+	return duration
+}
+
+func (svc *LockoutService) findLockout(
+	ctx context.Context,
+	subject banking.LockoutSubject,
+) (
+	int,
+	int64,
+	error,
+) {
+	query, args, err := squirrel.Select("failed_attempts", "locked_until").
+		From("login_lockouts").
+		Where(squirrel.Eq{
+			"lockout_scope": subject.Scope.String(),
+			"lockout_key":   subject.Key,
+		}).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "find lockout")
+	}
+
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "find lockout")
+	}
+
+	defer stmt.Close(ctx)
+
+	var (
+		attempts    int
+		lockedUntil int64
+	)
+
+	if err = stmt.QueryRowContext(ctx, args...).Scan(&attempts, &lockedUntil); err != nil {
+		return 0, 0, errors.Wrap(err, "find lockout")
+	}
+
+	return attempts, lockedUntil, nil
+}
+
+// ResetFailedAttempts removes lockout and forgets failed sign in attempts (e.g. after successful sign in or when
+// administrator unlocks the user account).
+func (svc *LockoutService) ResetFailedAttempts(ctx context.Context, subject banking.LockoutSubject) error {
+	query, args, err := squirrel.Delete("login_lockouts").
+		Where(squirrel.Eq{
+			"lockout_scope": subject.Scope.String(),
+			"lockout_key":   subject.Key,
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "reset failed attempts")
+	}
+
+	if _, err = svc.exec(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "reset failed attempts")
+	}
+
+	return nil
+}
+
+func (svc *LockoutService) exec(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	defer stmt.Close(ctx)
+
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,71 @@
+package percona
+
+import (
+	"time"
+
+	banking "github.com/morozovcookie/agat-banking"
+)
+
+// LockoutServiceOption represents an option for configure LockoutService instance.
+type LockoutServiceOption interface {
+	apply(svc *LockoutService)
+}
+
+type lockoutServiceOptionFunc func(svc *LockoutService)
+
+func (fn lockoutServiceOptionFunc) apply(svc *LockoutService) {
+	fn(svc)
+}
+
+// DefaultMaxAccountFailedAttempts is the number of failed sign in attempts which after user account will be locked.
+const DefaultMaxAccountFailedAttempts = 5
+
+// WithMaxAccountFailedAttempts sets up the number of failed sign in attempts which after user account will be locked.
+func WithMaxAccountFailedAttempts(attempts int) LockoutServiceOption {
+	return lockoutServiceOptionFunc(func(svc *LockoutService) {
+		svc.maxFailedAttempts[banking.LockoutScopeAccount] = attempts
+	})
+}
+
+// DefaultMaxClientIPFailedAttempts is the number of failed sign in attempts which after client IP address will be
+// locked. It is greater than the user account threshold, because a lot of users could share the same address.
+const DefaultMaxClientIPFailedAttempts = 20
+
+// WithMaxClientIPFailedAttempts sets up the number of failed sign in attempts which after client IP address will be
+// locked.
+func WithMaxClientIPFailedAttempts(attempts int) LockoutServiceOption {
+	return lockoutServiceOptionFunc(func(svc *LockoutService) {
+		svc.maxFailedAttempts[banking.LockoutScopeClientIP] = attempts
+	})
+}
+
+// DefaultBaseLockoutDuration is the lockout duration after the first exceeding of failed attempts threshold. Every
+// next failed attempt doubles it.
+const DefaultBaseLockoutDuration = time.Minute
+
+// WithBaseLockoutDuration sets up the lockout duration after the first exceeding of failed attempts threshold.
+func WithBaseLockoutDuration(duration time.Duration) LockoutServiceOption {
+	return lockoutServiceOptionFunc(func(svc *LockoutService) {
+		svc.baseLockoutDuration = duration
+	})
+}
+
+// DefaultMaxLockoutDuration is the maximum lockout duration.
+const DefaultMaxLockoutDuration = time.Hour
+
+// WithMaxLockoutDuration sets up the maximum lockout duration.
+func WithMaxLockoutDuration(duration time.Duration) LockoutServiceOption {
+	return lockoutServiceOptionFunc(func(svc *LockoutService) {
+		svc.maxLockoutDuration = duration
+	})
+}
+
+// DefaultFailedAttemptsWindow is the time duration which after the last failed attempt the counter starts again.
+const DefaultFailedAttemptsWindow = time.Hour * 24
+
+// WithFailedAttemptsWindow sets up the time duration which after the last failed attempt the counter starts again.
+func WithFailedAttemptsWindow(window time.Duration) LockoutServiceOption {
+	return lockoutServiceOptionFunc(func(svc *LockoutService) {
+		svc.failedAttemptsWindow = window
+	})
+}
@@ -0,0 +1,433 @@
+package percona_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/percona"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is the timer which time is moved by test.
+type fakeClock struct {
+	now time.Time
+}
+
+func (clock *fakeClock) Time(_ context.Context) (time.Time, error) {
+	return clock.now, nil
+}
+
+// lockoutRow is the login_lockouts table row.
+type lockoutRow struct {
+	failedAttempts int64
+	lastFailedAt   int64
+	lockedUntil    int64
+}
+
+// lockoutsTable is the in-memory login_lockouts table which supports only queries of LockoutService. Upsert is
+// applied the same way as ON DUPLICATE KEY UPDATE clause of the query does.
+type lockoutsTable struct {
+	rows map[banking.LockoutSubject]*lockoutRow
+}
+
+var subjectPattern = regexp.MustCompile(`WHERE (locked_until = \? AND )?lockout_key = \? AND lockout_scope = \?`)
+
+func (table *lockoutsTable) subject(query string, args []driver.Value) (banking.LockoutSubject, error) {
+	if !subjectPattern.MatchString(query) || len(args) < 2 {
+		return banking.LockoutSubject{}, errors.Errorf("unexpected query %q", query)
+	}
+
+	var (
+		key   = args[len(args)-2].(string)
+		scope = args[len(args)-1].(string)
+	)
+
+	if scope == banking.LockoutScopeClientIP.String() {
+		return banking.ClientIPSubject(key), nil
+	}
+
+	return banking.AccountSubject(banking.ID(key)), nil
+}
+
+func (table *lockoutsTable) handle(query string, args []driver.Value) (*fakeResult, error) {
+	if strings.HasPrefix(query, "INSERT INTO login_lockouts") {
+		return table.upsert(args), nil
+	}
+
+	subject, err := table.subject(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	row, ok := table.rows[subject]
+
+	res := &fakeResult{
+		columns:  nil,
+		rows:     nil,
+		affected: 0,
+	}
+
+	switch {
+	case strings.HasPrefix(query, "SELECT failed_attempts, locked_until"):
+		res.columns = []string{"failed_attempts", "locked_until"}
+		if ok {
+			res.rows = [][]driver.Value{{row.failedAttempts, row.lockedUntil}}
+		}
+	case strings.HasPrefix(query, "UPDATE login_lockouts SET locked_until = ? WHERE locked_until = ?"):
+		if ok && row.lockedUntil == args[1].(int64) {
+			row.lockedUntil = args[0].(int64)
+			res.affected = 1
+		}
+	case strings.HasPrefix(query, "UPDATE login_lockouts SET locked_until = ?"):
+		if ok {
+			row.lockedUntil = args[0].(int64)
+			res.affected = 1
+		}
+	case strings.HasPrefix(query, "UPDATE login_lockouts SET locked_until = IF(failed_attempts > ?"):
+		if ok {
+			if now := args[1].(int64); row.failedAttempts > args[0].(int64) && row.lockedUntil > now {
+				row.lockedUntil = now
+			}
+
+			if row.failedAttempts > 0 {
+				row.failedAttempts--
+			}
+
+			res.affected = 1
+		}
+	case strings.HasPrefix(query, "DELETE FROM login_lockouts"):
+		if ok {
+			delete(table.rows, subject)
+			res.affected = 1
+		}
+	default:
+		return nil, errors.Errorf("unexpected query %q", query)
+	}
+
+	return res, nil
+}
+
+func (table *lockoutsTable) upsert(args []driver.Value) *fakeResult {
+	var (
+		subject = banking.AccountSubject(banking.ID(args[1].(string)))
+
+		now         = args[3].(int64)
+		lockedAfter = args[6].(int64)
+		windowStart = args[7].(int64)
+	)
+
+	if args[0].(string) == banking.LockoutScopeClientIP.String() {
+		subject = banking.ClientIPSubject(args[1].(string))
+	}
+
+	row, ok := table.rows[subject]
+
+	switch {
+	case !ok:
+		table.rows[subject] = &lockoutRow{failedAttempts: 1, lastFailedAt: now, lockedUntil: 0}
+	case row.lockedUntil > lockedAfter:
+	case row.lastFailedAt < windowStart:
+		row.failedAttempts, row.lastFailedAt, row.lockedUntil = 1, now, 0
+	default:
+		row.failedAttempts, row.lastFailedAt = row.failedAttempts+1, now
+	}
+
+	return &fakeResult{
+		columns:  nil,
+		rows:     nil,
+		affected: 1,
+	}
+}
+
+type lockoutAction int
+
+const (
+	registerAttempt lockoutAction = iota
+	forgetAttempt
+	resetFailedAttempts
+)
+
+// lockoutStep is the LockoutService call which is made after the specified time since the test start.
+type lockoutStep struct {
+	after  time.Duration
+	action lockoutAction
+}
+
+func register(after time.Duration) lockoutStep {
+	return lockoutStep{after: after, action: registerAttempt}
+}
+
+func forget(after time.Duration) lockoutStep {
+	return lockoutStep{after: after, action: forgetAttempt}
+}
+
+func reset(after time.Duration) lockoutStep {
+	return lockoutStep{after: after, action: resetFailedAttempts}
+}
+
+func TestLockoutService_RegisterAttempt(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		subject banking.LockoutSubject
+		steps   []lockoutStep
+	}
+	type wants struct {
+		retryAfter []time.Duration
+	}
+
+	var (
+		account = banking.AccountSubject("account")
+		client  = banking.ClientIPSubject("192.0.2.1")
+	)
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "attempt exceeding threshold is rejected",
+				enabled: true,
+			},
+			args: args{
+				subject: account,
+				steps:   []lockoutStep{register(0), register(0), register(0), register(0)},
+			},
+			wants: wants{
+				retryAfter: []time.Duration{0, 0, 0, time.Minute},
+			},
+		},
+		{
+			meta: meta{
+				name:    "client IP address threshold",
+				enabled: true,
+			},
+			args: args{
+				subject: client,
+				steps:   []lockoutStep{register(0), register(0), register(0), register(0), register(0), register(0)},
+			},
+			wants: wants{
+				retryAfter: []time.Duration{0, 0, 0, 0, 0, time.Minute},
+			},
+		},
+		{
+			meta: meta{
+				name:    "failed attempt after lockout doubles duration up to maximum",
+				enabled: true,
+			},
+			args: args{
+				subject: account,
+				steps: []lockoutStep{
+					register(0), register(0), register(0), register(0),
+					register(time.Minute), register(time.Minute),
+					register(3 * time.Minute), register(3 * time.Minute),
+					register(7 * time.Minute), register(7 * time.Minute),
+					register(15 * time.Minute), register(15 * time.Minute),
+					register(25 * time.Minute), register(25 * time.Minute),
+				},
+			},
+			wants: wants{
+				retryAfter: []time.Duration{
+					0, 0, 0, time.Minute,
+					0, 2 * time.Minute,
+					0, 4 * time.Minute,
+					0, 8 * time.Minute,
+					0, 10 * time.Minute,
+					0, 10 * time.Minute,
+				},
+			},
+		},
+		{
+			meta: meta{
+				name:    "correct password after lockout unlocks account",
+				enabled: true,
+			},
+			args: args{
+				subject: account,
+				steps: []lockoutStep{
+					register(0), register(0), register(0), register(0),
+					register(time.Minute), reset(time.Minute),
+					register(time.Minute), register(time.Minute), register(time.Minute), register(time.Minute),
+				},
+			},
+			wants: wants{
+				retryAfter: []time.Duration{
+					0, 0, 0, time.Minute,
+					0, 0,
+					0, 0, 0, time.Minute,
+				},
+			},
+		},
+		{
+			meta: meta{
+				name:    "forgotten attempt after lockout lifts lockout",
+				enabled: true,
+			},
+			args: args{
+				subject: client,
+				steps: []lockoutStep{
+					register(0), register(0), register(0), register(0), register(0), register(0),
+					register(time.Minute), forget(time.Minute),
+					register(time.Minute), register(time.Minute),
+				},
+			},
+			wants: wants{
+				retryAfter: []time.Duration{
+					0, 0, 0, 0, 0, time.Minute,
+					0, 0,
+					0, 2 * time.Minute,
+				},
+			},
+		},
+		{
+			meta: meta{
+				name:    "forgotten attempt does not lift lockout of parallel attempt",
+				enabled: true,
+			},
+			args: args{
+				subject: account,
+				steps: []lockoutStep{
+					register(0), register(0), register(0), register(0), forget(0),
+					register(30 * time.Second),
+				},
+			},
+			wants: wants{
+				retryAfter: []time.Duration{0, 0, 0, time.Minute, 0, 30 * time.Second},
+			},
+		},
+		{
+			meta: meta{
+				name:    "attempts are not counted while locked",
+				enabled: true,
+			},
+			args: args{
+				subject: account,
+				steps: []lockoutStep{
+					register(0), register(0), register(0), register(0),
+					register(30 * time.Second), register(45 * time.Second),
+					register(time.Minute), register(time.Minute),
+				},
+			},
+			wants: wants{
+				retryAfter: []time.Duration{
+					0, 0, 0, time.Minute,
+					30 * time.Second, 15 * time.Second,
+					0, 2 * time.Minute,
+				},
+			},
+		},
+		{
+			meta: meta{
+				name:    "counter starts again out of window",
+				enabled: true,
+			},
+			args: args{
+				subject: account,
+				steps: []lockoutStep{
+					register(0), register(0), register(0),
+					register(2 * time.Hour), register(2 * time.Hour), register(2 * time.Hour), register(2 * time.Hour),
+				},
+			},
+			wants: wants{
+				retryAfter: []time.Duration{0, 0, 0, 0, 0, 0, time.Minute},
+			},
+		},
+		{
+			meta: meta{
+				name:    "forgotten attempt is not counted",
+				enabled: true,
+			},
+			args: args{
+				subject: account,
+				steps: []lockoutStep{
+					register(0), register(0), register(0),
+					forget(0),
+					register(0), register(0),
+				},
+			},
+			wants: wants{
+				retryAfter: []time.Duration{0, 0, 0, 0, 0, time.Minute},
+			},
+		},
+		{
+			meta: meta{
+				name:    "reset removes lockout",
+				enabled: true,
+			},
+			args: args{
+				subject: account,
+				steps: []lockoutStep{
+					register(0), register(0), register(0), register(0),
+					reset(0),
+					register(0),
+				},
+			},
+			wants: wants{
+				retryAfter: []time.Duration{0, 0, 0, time.Minute, 0, 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			var (
+				ctx   = context.Background()
+				start = time.Date(2021, time.November, 1, 12, 0, 0, 0, time.UTC)
+				clock = &fakeClock{now: start}
+				table = &lockoutsTable{rows: make(map[banking.LockoutSubject]*lockoutRow)}
+			)
+
+			svc := percona.NewLockoutService(newDBPreparer(table.handle), clock,
+				percona.WithMaxAccountFailedAttempts(3),
+				percona.WithMaxClientIPFailedAttempts(5),
+				percona.WithBaseLockoutDuration(time.Minute),
+				percona.WithMaxLockoutDuration(10*time.Minute),
+				percona.WithFailedAttemptsWindow(time.Hour))
+
+			require.Len(t, tt.wants.retryAfter, len(tt.args.steps))
+
+			for i, step := range tt.args.steps {
+				clock.now = start.Add(step.after)
+
+				var err error
+
+				switch step.action {
+				case registerAttempt:
+					err = svc.RegisterAttempt(ctx, tt.args.subject)
+				case forgetAttempt:
+					err = svc.ForgetAttempt(ctx, tt.args.subject)
+				case resetFailedAttempts:
+					err = svc.ResetFailedAttempts(ctx, tt.args.subject)
+				}
+
+				if tt.wants.retryAfter[i] == 0 {
+					assert.NoError(t, err, "step %d", i)
+
+					continue
+				}
+
+				var locked *banking.LockedError
+
+				if assert.True(t, errors.As(err, &locked), "step %d: %v", i, err) {
+					assert.Equal(t, tt.args.subject.Scope, locked.Scope, "step %d", i)
+					assert.Equal(t, tt.wants.retryAfter[i], locked.RetryAfter, "step %d", i)
+				}
+			}
+		})
+	}
+}
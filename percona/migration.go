@@ -0,0 +1,218 @@
+package percona
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrMalformedMigration will be raised when migration file name does not follow the
+	// <version>_<name>.<up|down>.sql format or when one of migration directions is missing.
+	ErrMalformedMigration = errors.New("malformed migration")
+
+	// ErrDuplicateMigrationVersion will be raised when several migrations have the same version.
+	ErrDuplicateMigrationVersion = errors.New("duplicate migration version")
+)
+
+// migrationFileRegex is the regular expression for migration file name.
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration represents a single database schema change.
+type Migration struct {
+	// Version is the migration unique sequential number.
+	Version uint64
+
+	// Name is the migration human-readable name.
+	Name string
+
+	// Checksum is the SHA-256 of up migration file which is used for detecting schema drift.
+	Checksum string
+
+	up   []string
+	down []string
+}
+
+// LoadMigrations reads migrations from the file system and returns them sorted by version.
+func LoadMigrations(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, errors.Wrap(err, "load migrations")
+	}
+
+	migrations := make(map[uint64]*Migration, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		if err = loadMigrationFile(fsys, entry.Name(), migrations); err != nil {
+			return nil, errors.Wrap(err, "load migrations")
+		}
+	}
+
+	result := make([]*Migration, 0, len(migrations))
+
+	for _, migration := range migrations {
+		if migration.Checksum == "" || migration.down == nil {
+			return nil, errors.Wrapf(ErrMalformedMigration, "load migrations: %d_%s", migration.Version,
+				migration.Name)
+		}
+
+		result = append(result, migration)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
+}
+
+func loadMigrationFile(fsys fs.FS, name string, migrations map[uint64]*Migration) error {
+	matches := migrationFileRegex.FindStringSubmatch(name)
+	if matches == nil {
+		return errors.Wrap(ErrMalformedMigration, name)
+	}
+
+	version, err := strconv.ParseUint(matches[1], 10, 64)
+	if err != nil {
+		return errors.Wrap(ErrMalformedMigration, name)
+	}
+
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return errors.Wrap(err, name)
+	}
+
+	migration, ok := migrations[version]
+	if !ok {
+		migration = &Migration{
+			Version:  version,
+			Name:     matches[2],
+			Checksum: "",
+			up:       nil,
+			down:     nil,
+		}
+
+		migrations[version] = migration
+	}
+
+	if migration.Name != matches[2] {
+		return errors.Wrap(ErrDuplicateMigrationVersion, name)
+	}
+
+	statements := splitStatements(string(content))
+
+	if matches[3] == "down" {
+		if migration.down != nil {
+			return errors.Wrap(ErrDuplicateMigrationVersion, name)
+		}
+
+		migration.down = statements
+
+		return nil
+	}
+
+	if migration.Checksum != "" {
+		return errors.Wrap(ErrDuplicateMigrationVersion, name)
+	}
+
+	checksum := sha256.Sum256(content)
+
+	migration.Checksum, migration.up = hex.EncodeToString(checksum[:]), statements
+
+	return nil
+}
+
+// splitStatements splits migration into separate statements, because driver does not allow multiple statements in a
+// single query. Transaction control statements are skipped: migrator runs every migration in its own transaction.
+// Comments are skipped too, so quotes and semicolons inside them do not break splitting.
+func splitStatements(content string) []string {
+	var (
+		statements = make([]string, 0)
+		current    strings.Builder
+		quote      byte
+		escaped    bool
+	)
+
+	flush := func() {
+		statement := strings.TrimSpace(current.String())
+		current.Reset()
+
+		switch strings.ToUpper(statement) {
+		case "", "BEGIN", "START TRANSACTION", "COMMIT":
+			return
+		}
+
+		statements = append(statements, statement)
+	}
+
+	// Special characters are ASCII, so content could be scanned by bytes: they never occur inside multibyte UTF-8
+	// sequence.
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0 && c == '\\':
+			escaped = true
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			// Quoted text is copied as is, comment markers inside it are not comments.
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ';':
+			flush()
+
+			continue
+		default:
+			if n := commentLength(content[i:]); n > 0 {
+				// Comment is replaced with space, so tokens around it are not glued.
+				current.WriteByte(' ')
+
+				i += n - 1
+
+				continue
+			}
+		}
+
+		current.WriteByte(c)
+	}
+
+	flush()
+
+	return statements
+}
+
+// commentLength returns the length of comment which content starts with, or zero if it does not start with comment.
+// Line break is not a part of line comment. MySQL executes /*! */ comments and reads optimizer hints from /*+ */
+// comments, so they are not comments here.
+func commentLength(content string) int {
+	switch {
+	case strings.HasPrefix(content, "#"),
+		strings.HasPrefix(content, "--") && (len(content) == 2 || content[2] <= ' '):
+		if n := strings.IndexByte(content, '\n'); n >= 0 {
+			return n
+		}
+
+		return len(content)
+	case strings.HasPrefix(content, "/*") && !strings.HasPrefix(content, "/*!") && !strings.HasPrefix(content, "/*+"):
+		if n := strings.Index(content[2:], "*/"); n >= 0 {
+			return n + 4 // nolint:gomnd
+		}
+
+		return len(content)
+	}
+
+	return 0
+}
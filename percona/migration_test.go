@@ -0,0 +1,177 @@
+package percona_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/morozovcookie/agat-banking/migrations"
+	"github.com/morozovcookie/agat-banking/percona"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		fsys fstest.MapFS
+	}
+	type wants struct {
+		versions   []uint64
+		statements [][]string
+		err        error
+	}
+
+	file := func(content string) *fstest.MapFile {
+		return &fstest.MapFile{
+			Data: []byte(content),
+		}
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "pass",
+				enabled: true,
+			},
+			args: args{
+				fsys: fstest.MapFS{
+					"000002_second.up.sql":   file("BEGIN;\nALTER TABLE t ADD COLUMN c INT COMMENT 'a;b';\nCOMMIT;\n"),
+					"000002_second.down.sql": file("BEGIN;\nALTER TABLE t DROP COLUMN c;\nCOMMIT;\n"),
+					"000001_first.up.sql":    file("CREATE TABLE t (id INT);"),
+					"000001_first.down.sql":  file("DROP TABLE t;"),
+				},
+			},
+			wants: wants{
+				versions: []uint64{1, 2},
+				statements: [][]string{
+					{"CREATE TABLE t (id INT)"},
+					{"ALTER TABLE t ADD COLUMN c INT COMMENT 'a;b'"},
+				},
+				err: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "comments",
+				enabled: true,
+			},
+			args: args{
+				fsys: fstest.MapFS{
+					"000001_first.up.sql": file("-- It's the first migration; nothing is split here.\n" +
+						"BEGIN;\n" +
+						"# Don't split here; really.\n" +
+						"CREATE TABLE t (\n" +
+						"    id INT -- identifier; it's unique\n" +
+						") COMMENT 'not -- a comment; # either';\n" +
+						"/* Block comment with 'quote; and ; */\n" +
+						"INSERT INTO t VALUES (1/* one; */, 2--1);\n" +
+						"/*!40101 SET NAMES utf8mb4 */;\n" +
+						"COMMIT; -- done"),
+					"000001_first.down.sql": file("DROP TABLE t; /* unterminated"),
+				},
+			},
+			wants: wants{
+				versions: []uint64{1},
+				statements: [][]string{
+					{
+						"CREATE TABLE t (\n    id INT  \n) COMMENT 'not -- a comment; # either'",
+						"INSERT INTO t VALUES (1 , 2--1)",
+						"/*!40101 SET NAMES utf8mb4 */",
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "missing down migration",
+				enabled: true,
+			},
+			args: args{
+				fsys: fstest.MapFS{
+					"000001_first.up.sql": file("CREATE TABLE t (id INT);"),
+				},
+			},
+			wants: wants{
+				versions:   nil,
+				statements: nil,
+				err:        percona.ErrMalformedMigration,
+			},
+		},
+		{
+			meta: meta{
+				name:    "malformed file name",
+				enabled: true,
+			},
+			args: args{
+				fsys: fstest.MapFS{
+					"first.up.sql": file("CREATE TABLE t (id INT);"),
+				},
+			},
+			wants: wants{
+				versions:   nil,
+				statements: nil,
+				err:        percona.ErrMalformedMigration,
+			},
+		},
+		{
+			meta: meta{
+				name:    "duplicate version",
+				enabled: true,
+			},
+			args: args{
+				fsys: fstest.MapFS{
+					"000001_first.up.sql":   file("CREATE TABLE t (id INT);"),
+					"000001_first.down.sql": file("DROP TABLE t;"),
+					"000001_other.up.sql":   file("CREATE TABLE o (id INT);"),
+				},
+			},
+			wants: wants{
+				versions:   nil,
+				statements: nil,
+				err:        percona.ErrDuplicateMigrationVersion,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			loaded, err := percona.LoadMigrations(tt.args.fsys)
+			assert.ErrorIs(t, err, tt.wants.err)
+
+			var (
+				versions   []uint64
+				statements [][]string
+			)
+
+			for _, migration := range loaded {
+				versions = append(versions, migration.Version)
+				statements = append(statements, migration.UpStatements())
+			}
+
+			assert.Equal(t, tt.wants.versions, versions)
+			assert.Equal(t, tt.wants.statements, statements)
+		})
+	}
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	loaded, err := percona.LoadMigrations(migrations.Percona())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, loaded)
+
+	for i, migration := range loaded {
+		assert.Equal(t, uint64(i+1), migration.Version)
+		assert.Len(t, migration.Checksum, 64)
+	}
+}
@@ -0,0 +1,666 @@
+package percona
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/go-sql-driver/mysql"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+// mysqlErrNoSuchTable is the MySQL error number which is returned when table does not exist.
+const mysqlErrNoSuchTable = 1146
+
+var (
+	// ErrSchemaDrift will be raised when applied migrations do not match migrations which are embedded into the binary.
+	ErrSchemaDrift = errors.New("schema drift")
+
+	// ErrDirtySchema will be raised when the previous migration failed in the middle. The schema should be fixed
+	// manually and then the version should be forced.
+	ErrDirtySchema = errors.New("dirty schema")
+
+	// ErrMigrationLockTimeout will be raised when other instance holds the migration lock for too long.
+	ErrMigrationLockTimeout = errors.New("migration lock timeout")
+
+	// ErrUnknownMigrationVersion will be raised when forced version does not match any migration.
+	ErrUnknownMigrationVersion = errors.New("unknown migration version")
+)
+
+// MigrationState represents an enum which describes the state of migration in the database.
+type MigrationState int
+
+const (
+	// MigrationStatePending means that migration is not applied yet.
+	MigrationStatePending MigrationState = iota
+
+	// MigrationStateApplied means that migration is applied.
+	MigrationStateApplied
+
+	// MigrationStateDirty means that migration failed in the middle.
+	MigrationStateDirty
+
+	// MigrationStateModified means that migration file was changed after migration had been applied.
+	MigrationStateModified
+
+	// MigrationStateMissing means that migration is applied, but the binary does not know about it (e.g. the binary
+	// is older than the database schema).
+	MigrationStateMissing
+)
+
+func (ms MigrationState) String() string {
+	switch ms {
+	case MigrationStatePending:
+		return "pending"
+	case MigrationStateApplied:
+		return "applied"
+	case MigrationStateDirty:
+		return "dirty"
+	case MigrationStateModified:
+		return "modified"
+	case MigrationStateMissing:
+		return "missing"
+	}
+
+	return ""
+}
+
+// MigrationStatus represents the state of the single migration.
+type MigrationStatus struct {
+	// Version is the migration unique sequential number.
+	Version uint64
+
+	// Name is the migration human-readable name.
+	Name string
+
+	// State is the migration state in the database.
+	State MigrationState
+
+	// AppliedAt is the time when migration was applied. It is zero for pending migration.
+	AppliedAt time.Time
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	dirty     bool
+	appliedAt int64
+}
+
+// execer represents an object which executes queries on the same connection (e.g. *sql.Conn or *sql.Tx).
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Migrator represents a service for applying database schema migrations.
+type Migrator struct {
+	client *Client
+	fsys   fs.FS
+
+	timer banking.Timer
+
+	table       string
+	legacyTable string
+	lockName    string
+	lockTimeout time.Duration
+}
+
+// NewMigrator returns a new Migrator instance.
+func NewMigrator(client *Client, fsys fs.FS, timer banking.Timer, opts ...MigratorOption) *Migrator {
+	m := &Migrator{
+		client: client,
+		fsys:   fsys,
+
+		timer: timer,
+
+		table:       DefaultMigrationsTable,
+		legacyTable: DefaultLegacyMigrationsTable,
+		lockName:    DefaultMigrationLockName,
+		lockTimeout: DefaultMigrationLockTimeout,
+	}
+
+	for _, opt := range opts {
+		opt.apply(m)
+	}
+
+	return m
+}
+
+// Up applies the number of pending migrations in ascending order. Every pending migration is applied when steps is
+// not positive.
+func (m *Migrator) Up(ctx context.Context, steps int) error {
+	err := m.withLock(ctx, func(ctx context.Context, conn *sql.Conn, migrations []*Migration) error {
+		statuses, err := m.status(ctx, conn, migrations)
+		if err != nil {
+			return err
+		}
+
+		if err = checkDrift(statuses, MigrationStatePending); err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			if statuses[migration.Version].State != MigrationStatePending {
+				continue
+			}
+
+			if err = m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+
+			if steps--; steps == 0 {
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "migrate up")
+	}
+
+	return nil
+}
+
+// Down reverts the number of applied migrations in descending order. The last applied migration is reverted when
+// steps is not positive.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	err := m.withLock(ctx, func(ctx context.Context, conn *sql.Conn, migrations []*Migration) error {
+		statuses, err := m.status(ctx, conn, migrations)
+		if err != nil {
+			return err
+		}
+
+		if err = checkDrift(statuses, MigrationStatePending); err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+			if statuses[migrations[i].Version].State != MigrationStateApplied {
+				continue
+			}
+
+			if err = m.revert(ctx, conn, migrations[i]); err != nil {
+				return err
+			}
+
+			steps--
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "migrate down")
+	}
+
+	return nil
+}
+
+// Force marks every migration up to the version as applied with the current checksum and every next migration as
+// pending without running them. It is used for recovering after failed migration once the schema was fixed manually.
+func (m *Migrator) Force(ctx context.Context, version uint64) error {
+	err := m.withLock(ctx, func(ctx context.Context, conn *sql.Conn, migrations []*Migration) error {
+		known := version == 0
+
+		for _, migration := range migrations {
+			known = known || migration.Version == version
+		}
+
+		if !known {
+			return errors.Wrapf(ErrUnknownMigrationVersion, "%d", version)
+		}
+
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return errors.Wrap(err, "begin")
+		}
+
+		if err = m.force(ctx, tx, migrations, version); err != nil {
+			_ = tx.Rollback()
+
+			return err
+		}
+
+		return errors.Wrap(tx.Commit(), "commit")
+	})
+	if err != nil {
+		return errors.Wrap(err, "migrate force")
+	}
+
+	return nil
+}
+
+// Status returns the state of every known and every applied migration sorted by version.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations(m.fsys)
+	if err != nil {
+		return nil, errors.Wrap(err, "migrate status")
+	}
+
+	conn, err := m.client.db.Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "migrate status")
+	}
+
+	defer conn.Close()
+
+	statuses, err := m.status(ctx, conn, migrations)
+	if err != nil {
+		return nil, errors.Wrap(err, "migrate status")
+	}
+
+	return sortStatuses(statuses), nil
+}
+
+// Verify returns ErrSchemaDrift when database schema does not match migrations which are embedded into the binary. It
+// should be called during startup, so the service does not run against unexpected schema.
+func (m *Migrator) Verify(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return errors.Wrap(err, "verify schema")
+	}
+
+	byVersion := make(map[uint64]MigrationStatus, len(statuses))
+	for _, status := range statuses {
+		byVersion[status.Version] = status
+	}
+
+	if err = checkDrift(byVersion); err != nil {
+		return errors.Wrap(err, "verify schema")
+	}
+
+	return nil
+}
+
+// withLock runs fn on the dedicated connection which holds the advisory lock, so concurrent instances could not
+// migrate at the same time. The lock is bound to the connection and is released even if the process dies.
+func (m *Migrator) withLock(
+	ctx context.Context,
+	fn func(ctx context.Context, conn *sql.Conn, migrations []*Migration) error,
+) error {
+	migrations, err := LoadMigrations(m.fsys)
+	if err != nil {
+		return err
+	}
+
+	conn, err := m.client.db.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "acquire connection")
+	}
+
+	defer conn.Close()
+
+	var acquired sql.NullInt64
+
+	err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", m.lockName, int64(m.lockTimeout.Seconds())).
+		Scan(&acquired)
+	if err != nil {
+		return errors.Wrap(err, "acquire lock")
+	}
+
+	if !acquired.Valid || acquired.Int64 != 1 {
+		return ErrMigrationLockTimeout
+	}
+
+	defer func() {
+		var released sql.NullInt64
+
+		_ = conn.QueryRowContext(context.Background(), "SELECT RELEASE_LOCK(?)", m.lockName).Scan(&released)
+	}()
+
+	if err = m.ensureTable(ctx, conn, migrations); err != nil {
+		return err
+	}
+
+	return fn(ctx, conn, migrations)
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, conn *sql.Conn, migrations []*Migration) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+    version    BIGINT UNSIGNED NOT NULL COMMENT 'migration unique sequential number',
+    name       VARCHAR(255) NOT NULL COMMENT 'migration human-readable name',
+    checksum   CHAR(64) NOT NULL COMMENT 'SHA-256 of up migration file',
+    dirty      BOOLEAN NOT NULL COMMENT 'migration failed in the middle',
+    applied_at BIGINT NOT NULL COMMENT 'time when migration was applied',
+
+    PRIMARY KEY (version)
+) COMMENT='stores applied schema migrations' ENGINE=InnoDB`, m.table)
+
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return errors.Wrap(err, "ensure table")
+	}
+
+	applied, err := m.findAppliedMigrations(ctx, conn)
+	if err != nil {
+		return errors.Wrap(err, "ensure table")
+	}
+
+	if len(applied) != 0 {
+		return nil
+	}
+
+	if err = m.adoptLegacyVersion(ctx, conn, migrations); err != nil {
+		return errors.Wrap(err, "ensure table")
+	}
+
+	return nil
+}
+
+// adoptLegacyVersion records migrations which were applied by golang-migrate tool.
+func (m *Migrator) adoptLegacyVersion(ctx context.Context, conn *sql.Conn, migrations []*Migration) error {
+	query, args, err := squirrel.Select("version", "dirty").
+		From(m.legacyTable).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "adopt legacy version")
+	}
+
+	var (
+		version uint64
+		dirty   bool
+	)
+
+	err = conn.QueryRowContext(ctx, query, args...).Scan(&version, &dirty)
+	if isNoSuchTable(err) || errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "adopt legacy version")
+	}
+
+	if dirty {
+		return errors.Wrapf(ErrDirtySchema, "adopt legacy version %d", version)
+	}
+
+	if err = m.force(ctx, conn, migrations, version); err != nil {
+		return errors.Wrap(err, "adopt legacy version")
+	}
+
+	return nil
+}
+
+func (m *Migrator) force(ctx context.Context, e execer, migrations []*Migration, version uint64) error {
+	now, err := m.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "force")
+	}
+
+	query, args, err := squirrel.Delete(m.table).
+		Where(squirrel.Or{
+			squirrel.Gt{"version": version},
+			squirrel.Eq{"dirty": true},
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "force")
+	}
+
+	if _, err = e.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "force")
+	}
+
+	for _, migration := range migrations {
+		if migration.Version > version {
+			break
+		}
+
+		query, args, err = squirrel.Insert(m.table).
+			Columns("version", "name", "checksum", "dirty", "applied_at").
+			Values(migration.Version, migration.Name, migration.Checksum, false, banking.TimeToMilliseconds(now)).
+			Suffix("ON DUPLICATE KEY UPDATE name = VALUES(name), checksum = VALUES(checksum)").
+			ToSql()
+		if err != nil {
+			return errors.Wrap(err, "force")
+		}
+
+		if _, err = e.ExecContext(ctx, query, args...); err != nil {
+			return errors.Wrap(err, "force")
+		}
+	}
+
+	return nil
+}
+
+// apply runs up migration. The version is recorded as dirty before running statements, because MySQL commits DDL
+// statements implicitly and failed migration could not be rolled back completely.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	now, err := m.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "apply %d_%s", migration.Version, migration.Name)
+	}
+
+	query, args, err := squirrel.Insert(m.table).
+		Columns("version", "name", "checksum", "dirty", "applied_at").
+		Values(migration.Version, migration.Name, migration.Checksum, true, banking.TimeToMilliseconds(now)).
+		ToSql()
+	if err != nil {
+		return errors.Wrapf(err, "apply %d_%s", migration.Version, migration.Name)
+	}
+
+	if _, err = conn.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrapf(err, "apply %d_%s", migration.Version, migration.Name)
+	}
+
+	query, args, err = squirrel.Update(m.table).
+		Set("dirty", false).
+		Where(squirrel.Eq{"version": migration.Version}).
+		ToSql()
+	if err != nil {
+		return errors.Wrapf(err, "apply %d_%s", migration.Version, migration.Name)
+	}
+
+	if err = runInTx(ctx, conn, migration.up, query, args...); err != nil {
+		return errors.Wrapf(err, "apply %d_%s", migration.Version, migration.Name)
+	}
+
+	return nil
+}
+
+// revert runs down migration.
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	query, args, err := squirrel.Update(m.table).
+		Set("dirty", true).
+		Where(squirrel.Eq{"version": migration.Version}).
+		ToSql()
+	if err != nil {
+		return errors.Wrapf(err, "revert %d_%s", migration.Version, migration.Name)
+	}
+
+	if _, err = conn.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrapf(err, "revert %d_%s", migration.Version, migration.Name)
+	}
+
+	query, args, err = squirrel.Delete(m.table).
+		Where(squirrel.Eq{"version": migration.Version}).
+		ToSql()
+	if err != nil {
+		return errors.Wrapf(err, "revert %d_%s", migration.Version, migration.Name)
+	}
+
+	if err = runInTx(ctx, conn, migration.down, query, args...); err != nil {
+		return errors.Wrapf(err, "revert %d_%s", migration.Version, migration.Name)
+	}
+
+	return nil
+}
+
+// runInTx runs migration statements and then the query which records the migration result in a single transaction.
+func runInTx(ctx context.Context, conn *sql.Conn, statements []string, query string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "begin")
+	}
+
+	for _, statement := range statements {
+		if _, err = tx.ExecContext(ctx, statement); err != nil {
+			_ = tx.Rollback()
+
+			return errors.Wrap(err, "exec")
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		_ = tx.Rollback()
+
+		return errors.Wrap(err, "exec")
+	}
+
+	return errors.Wrap(tx.Commit(), "commit")
+}
+
+func (m *Migrator) status(
+	ctx context.Context,
+	e execer,
+	migrations []*Migration,
+) (
+	map[uint64]MigrationStatus,
+	error,
+) {
+	applied, err := m.findAppliedMigrations(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[uint64]MigrationStatus, len(migrations)+len(applied))
+
+	for _, migration := range migrations {
+		statuses[migration.Version] = MigrationStatus{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			State:     MigrationStatePending,
+			AppliedAt: time.Time{},
+		}
+	}
+
+	for version, record := range applied {
+		status := MigrationStatus{
+			Version:   version,
+			Name:      record.name,
+			State:     MigrationStateApplied,
+			AppliedAt: banking.MillisecondsToTime(record.appliedAt),
+		}
+
+		switch known, ok := statuses[version]; {
+		case record.dirty:
+			status.State = MigrationStateDirty
+		case !ok:
+			status.State = MigrationStateMissing
+		case known.Name != record.name || migrationChecksum(migrations, version) != record.checksum:
+			status.State = MigrationStateModified
+		}
+
+		statuses[version] = status
+	}
+
+	return statuses, nil
+}
+
+func (m *Migrator) findAppliedMigrations(ctx context.Context, e execer) (map[uint64]appliedMigration, error) {
+	query, args, err := squirrel.Select("version", "name", "checksum", "dirty", "applied_at").
+		From(m.table).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "find applied migrations")
+	}
+
+	rows, err := e.QueryContext(ctx, query, args...)
+	if isNoSuchTable(err) {
+		return map[uint64]appliedMigration{}, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "find applied migrations")
+	}
+
+	defer rows.Close()
+
+	applied := make(map[uint64]appliedMigration)
+
+	for rows.Next() {
+		var (
+			version uint64
+			record  appliedMigration
+		)
+
+		if err = rows.Scan(&version, &record.name, &record.checksum, &record.dirty, &record.appliedAt); err != nil {
+			return nil, errors.Wrap(err, "find applied migrations")
+		}
+
+		applied[version] = record
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "find applied migrations")
+	}
+
+	return applied, nil
+}
+
+// checkDrift returns error when migration is in unexpected state. Applied migrations are always expected.
+func checkDrift(statuses map[uint64]MigrationStatus, allowed ...MigrationState) error {
+	for _, status := range sortStatuses(statuses) {
+		if status.State == MigrationStateApplied || containsState(allowed, status.State) {
+			continue
+		}
+
+		if status.State == MigrationStateDirty {
+			return errors.Wrapf(ErrDirtySchema, "migration %d_%s", status.Version, status.Name)
+		}
+
+		return errors.Wrapf(ErrSchemaDrift, "migration %d_%s is %s", status.Version, status.Name, status.State)
+	}
+
+	return nil
+}
+
+func containsState(states []MigrationState, state MigrationState) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortStatuses(statuses map[uint64]MigrationStatus) []MigrationStatus {
+	result := make([]MigrationStatus, 0, len(statuses))
+	for _, status := range statuses {
+		result = append(result, status)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result
+}
+
+func migrationChecksum(migrations []*Migration, version uint64) string {
+	for _, migration := range migrations {
+		if migration.Version == version {
+			return migration.Checksum
+		}
+	}
+
+	return ""
+}
+
+func isNoSuchTable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrNoSuchTable
+}
@@ -0,0 +1,57 @@
+package percona
+
+import (
+	"time"
+)
+
+// MigratorOption represents an option for configure Migrator instance.
+type MigratorOption interface {
+	apply(m *Migrator)
+}
+
+type migratorOptionFunc func(m *Migrator)
+
+func (fn migratorOptionFunc) apply(m *Migrator) {
+	fn(m)
+}
+
+// DefaultMigrationsTable is the name of table which stores applied migrations.
+const DefaultMigrationsTable = "schema_versions"
+
+// WithMigrationsTable sets up the name of table which stores applied migrations.
+func WithMigrationsTable(table string) MigratorOption {
+	return migratorOptionFunc(func(m *Migrator) {
+		m.table = table
+	})
+}
+
+// DefaultLegacyMigrationsTable is the name of table which is used by golang-migrate tool. Versions from it are adopted
+// when migrator runs for the first time, so already migrated databases are not migrated again.
+const DefaultLegacyMigrationsTable = "schema_migrations"
+
+// WithLegacyMigrationsTable sets up the name of table which is used by golang-migrate tool.
+func WithLegacyMigrationsTable(table string) MigratorOption {
+	return migratorOptionFunc(func(m *Migrator) {
+		m.legacyTable = table
+	})
+}
+
+// DefaultMigrationLockName is the name of advisory lock which is held during migration.
+const DefaultMigrationLockName = "banking_schema_migration"
+
+// WithMigrationLockName sets up the name of advisory lock which is held during migration.
+func WithMigrationLockName(name string) MigratorOption {
+	return migratorOptionFunc(func(m *Migrator) {
+		m.lockName = name
+	})
+}
+
+// DefaultMigrationLockTimeout is the maximum time for waiting until other instance releases the lock.
+const DefaultMigrationLockTimeout = time.Minute
+
+// WithMigrationLockTimeout sets up the maximum time for waiting until other instance releases the lock.
+func WithMigrationLockTimeout(timeout time.Duration) MigratorOption {
+	return migratorOptionFunc(func(m *Migrator) {
+		m.lockTimeout = timeout
+	})
+}
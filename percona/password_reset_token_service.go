@@ -0,0 +1,224 @@
+package percona
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+var _ banking.PasswordResetTokenService = (*PasswordResetTokenService)(nil)
+
+// PasswordResetTokenService represents a service for managing PasswordResetToken data.
+type PasswordResetTokenService struct {
+	preparer Preparer
+
+	timer banking.Timer
+}
+
+// NewPasswordResetTokenService returns a new PasswordResetTokenService instance.
+func NewPasswordResetTokenService(preparer Preparer, timer banking.Timer) *PasswordResetTokenService {
+	return &PasswordResetTokenService{
+		preparer: preparer,
+
+		timer: timer,
+	}
+}
+
+// StorePasswordResetToken stores a new PasswordResetToken. Previously issued tokens of the same user account are
+// invalidated, so only the last one could be used. It should be called within transaction.
+func (svc *PasswordResetTokenService) StorePasswordResetToken(
+	ctx context.Context,
+	token *banking.PasswordResetToken,
+) error {
+	createdAt := banking.TimeToMilliseconds(token.CreatedAt)
+
+	query, args, err := squirrel.Update("password_reset_tokens").
+		Set("expires_at", createdAt).
+		Where(squirrel.And{
+			squirrel.Eq{
+				"account_id":  token.Account.ID.String(),
+				"redeemed_at": nil,
+			},
+			squirrel.Gt{
+				"expires_at": createdAt,
+			},
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "store password reset token")
+	}
+
+	if _, err = svc.exec(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "store password reset token")
+	}
+
+	query, args, err = squirrel.Insert("password_reset_tokens").
+		Columns("token_id", "token_hash", "account_id", "expires_at", "created_at").
+		Values(token.ID.String(), token.TokenHash, token.Account.ID.String(),
+			banking.TimeToMilliseconds(token.ExpiresAt), createdAt).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "store password reset token")
+	}
+
+	if _, err = svc.exec(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "store password reset token")
+	}
+
+	return nil
+}
+
+// FindPasswordResetTokenByHash returns PasswordResetToken with specified hash which could be redeemed.
+// Return banking.ErrPasswordResetTokenInvalid if token does not exist, expired or was already used.
+func (svc *PasswordResetTokenService) FindPasswordResetTokenByHash(
+	ctx context.Context,
+	tokenHash string,
+) (
+	*banking.PasswordResetToken,
+	error,
+) {
+	now, err := svc.timer.Time(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "find password reset token by hash")
+	}
+
+	token, err := svc.findPasswordResetTokenByHash(ctx, tokenHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "find password reset token by hash")
+	}
+
+	if !token.RedeemedAt.IsZero() || !token.ExpiresAt.After(now) {
+		return nil, errors.Wrap(banking.ErrPasswordResetTokenInvalid, "find password reset token by hash")
+	}
+
+	return token, nil
+}
+
+// RedeemPasswordResetToken marks PasswordResetToken with specified hash as used and returns it.
+// Return banking.ErrPasswordResetTokenInvalid if token does not exist, expired or was already used.
+func (svc *PasswordResetTokenService) RedeemPasswordResetToken(
+	ctx context.Context,
+	tokenHash string,
+) (
+	*banking.PasswordResetToken,
+	error,
+) {
+	now, err := svc.timer.Time(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "redeem password reset token")
+	}
+
+	nowMillis := banking.TimeToMilliseconds(now)
+
+	// Token is checked and marked by the single statement, so it could not be redeemed twice by concurrent requests.
+	query, args, err := squirrel.Update("password_reset_tokens").
+		Set("redeemed_at", nowMillis).
+		Where(squirrel.And{
+			squirrel.Eq{
+				"token_hash":  tokenHash,
+				"redeemed_at": nil,
+			},
+			squirrel.Gt{
+				"expires_at": nowMillis,
+			},
+		}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "redeem password reset token")
+	}
+
+	affected, err := svc.exec(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "redeem password reset token")
+	}
+
+	if affected == 0 {
+		return nil, errors.Wrap(banking.ErrPasswordResetTokenInvalid, "redeem password reset token")
+	}
+
+	token, err := svc.findPasswordResetTokenByHash(ctx, tokenHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "redeem password reset token")
+	}
+
+	return token, nil
+}
+
+func (svc *PasswordResetTokenService) findPasswordResetTokenByHash(
+	ctx context.Context,
+	tokenHash string,
+) (
+	*banking.PasswordResetToken,
+	error,
+) {
+	query, args, err := squirrel.Select("token_id", "account_id", "expires_at", "redeemed_at", "created_at").
+		From("password_reset_tokens").
+		Where(squirrel.Eq{
+			"token_hash": tokenHash,
+		}).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "find password reset token")
+	}
+
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "find password reset token")
+	}
+
+	defer stmt.Close(ctx)
+
+	var (
+		token = &banking.PasswordResetToken{
+			Account:   &banking.UserAccount{},
+			TokenHash: tokenHash,
+		}
+		expiresAt  int64
+		redeemedAt sql.NullInt64
+		createdAt  int64
+	)
+
+	err = stmt.QueryRowContext(ctx, args...).Scan(&token.ID, &token.Account.ID, &expiresAt, &redeemedAt, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(banking.ErrPasswordResetTokenInvalid, "find password reset token")
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "find password reset token")
+	}
+
+	token.CreatedAt = time.Unix(0, banking.MillisecondsToNanoseconds(createdAt))
+	token.ExpiresAt = time.Unix(0, banking.MillisecondsToNanoseconds(expiresAt))
+
+	if redeemedAt.Valid {
+		token.RedeemedAt = time.Unix(0, banking.MillisecondsToNanoseconds(redeemedAt.Int64))
+	}
+
+	return token, nil
+}
+
+func (svc *PasswordResetTokenService) exec(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	defer stmt.Close(ctx)
+
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	return affected, nil
+}
@@ -3,6 +3,7 @@ package percona
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	banking "github.com/morozovcookie/agat-banking"
@@ -39,27 +40,52 @@ func NewRefreshTokenService(
 
 // StoreToken stores a single Token.
 func (svc *RefreshTokenService) StoreToken(ctx context.Context, token banking.Token) error {
-	return svc.storeToken(ctx, token, banking.TimeToMilliseconds(token.Until()))
+	return svc.storeToken(ctx, token, token.ID())
 }
 
 // ExpireToken expires single Token.
 // Return the new Token state after update.
 func (svc *RefreshTokenService) ExpireToken(ctx context.Context, id banking.ID) (banking.Token, error) {
-	token, err := svc.FindTokenByID(ctx, id)
+	if _, err := svc.FindTokenByID(ctx, id); err != nil {
+		return nil, errors.Wrap(err, "expire token")
+	}
+
+	now, err := svc.timer.Time(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "expire token")
 	}
 
-	if err = svc.storeToken(ctx, token, InvalidTokenTime); err != nil {
+	query, args, err := squirrel.Update("refresh_tokens").
+		Set("token_valid_until", InvalidTokenTime).
+		Set("updated_at", banking.TimeToMilliseconds(now)).
+		Where(squirrel.Eq{
+			"token_id": id.String(),
+		}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "expire token")
+	}
+
+	if _, err = svc.exec(ctx, query, args...); err != nil {
+		return nil, errors.Wrap(err, "expire token")
+	}
+
+	token, _, err := svc.findToken(ctx, id)
+	if err != nil {
 		return nil, errors.Wrap(err, "expire token")
 	}
 
 	return token, nil
 }
 
-func (svc *RefreshTokenService) storeToken(ctx context.Context, token banking.Token, until int64) error {
+func (svc *RefreshTokenService) storeToken(
+	ctx context.Context,
+	token banking.Token,
+	familyID banking.ID,
+) error {
 	var (
 		tokenID       = token.ID().String()
+		until         = banking.TimeToMilliseconds(token.Until())
 		userAccountID = token.Account().ID.String()
 		issuedAt      = banking.TimeToMilliseconds(token.IssuedAt())
 		expiration    = banking.TimeToMilliseconds(token.Expiration())
@@ -72,15 +98,16 @@ func (svc *RefreshTokenService) storeToken(ctx context.Context, token banking.To
 	}
 
 	query, args, err := squirrel.Insert("refresh_tokens").
-		Columns("token_id", "token_user_account_id", "token_issued_at", "token_expiration",
+		Columns("token_id", "token_user_account_id", "token_family_id", "token_issued_at", "token_expiration",
 			"token_valid_until", "token_value", "created_at").
-		Values(tokenID, userAccountID, issuedAt, expiration, until, value, createdAt).
+		Values(tokenID, userAccountID, familyID.String(), issuedAt, expiration, until, value,
+			banking.TimeToMilliseconds(createdAt)).
 		ToSql()
 	if err != nil {
 		return errors.Wrap(err, "store token")
 	}
 
-	tokenStmt, err := svc.preparer.PrepareContext(ctx, query)
+	tokenStmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
 	if err != nil {
 		return errors.Wrap(err, "store token")
 	}
@@ -95,9 +122,42 @@ func (svc *RefreshTokenService) storeToken(ctx context.Context, token banking.To
 }
 
 // FindTokenByID returns a single Token.
+// Return banking.ErrTokenReused if Token was already rotated.
 func (svc *RefreshTokenService) FindTokenByID(ctx context.Context, id banking.ID) (banking.Token, error) {
+	token, replacedBy, err := svc.findToken(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "find token by id")
+	}
+
+	if replacedBy.Valid {
+		return nil, errors.Wrap(banking.ErrTokenReused, "find token by id")
+	}
+
+	now, err := svc.timer.Time(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "find token by id")
+	}
+
+	if now.After(token.Until()) {
+		return nil, errors.Wrap(banking.ErrTokenDoesNotExist, "find token by id")
+	}
+
+	return token, nil
+}
+
+// findToken returns Token in any state and the identifier of Token which replaced it during rotation.
+func (svc *RefreshTokenService) findToken(
+	ctx context.Context,
+	id banking.ID,
+) (
+	banking.Token,
+	sql.NullString,
+	error,
+) {
+	var replacedBy sql.NullString
+
 	query, args, err := squirrel.Select("token_id", "token_user_account_id", "token_issued_at",
-		"token_expiration", "token_valid_until").
+		"token_expiration", "token_valid_until", "token_replaced_by").
 		From("refresh_tokens").
 		Where(squirrel.Eq{
 			"token_id": id.String(),
@@ -105,46 +165,293 @@ func (svc *RefreshTokenService) FindTokenByID(ctx context.Context, id banking.ID
 		Limit(1).
 		ToSql()
 	if err != nil {
-		return nil, errors.Wrap(err, "find token by id")
+		return nil, replacedBy, errors.Wrap(err, "find token")
 	}
 
-	tokenStmt, err := svc.preparer.PrepareContext(ctx, query)
+	tokenStmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
 	if err != nil {
-		return nil, errors.Wrap(err, "find token by id")
+		return nil, replacedBy, errors.Wrap(err, "find token")
 	}
 
 	defer tokenStmt.Close(ctx)
 
 	userAccountStmt, err := svc.createUserAccountStmt(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "find token by id")
+		return nil, replacedBy, errors.Wrap(err, "find token")
 	}
 
 	defer userAccountStmt.Close(ctx)
 
-	token, err := svc.scanTokenRow(ctx, tokenStmt.QueryRowContext(ctx, args...), userAccountStmt)
+	token, err := svc.scanTokenRow(ctx, tokenStmt.QueryRowContext(ctx, args...), userAccountStmt, &replacedBy)
 	if errors.Is(err, sql.ErrNoRows) {
-		return nil, errors.Wrap(banking.ErrTokenDoesNotExist, "find token by id")
+		return nil, replacedBy, errors.Wrap(banking.ErrTokenDoesNotExist, "find token")
 	}
 
 	if err != nil {
-		return nil, errors.Wrap(err, "find token by id")
+		return nil, replacedBy, errors.Wrap(err, "find token")
+	}
+
+	return token, replacedBy, nil
+}
+
+// RotateToken expires Token with specified identifier and stores the next Token from the same rotation family.
+// Return banking.ErrTokenReused if Token was already rotated.
+func (svc *RefreshTokenService) RotateToken(ctx context.Context, id banking.ID, next banking.Token) error {
+	familyID, err := svc.findTokenFamilyID(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "rotate token")
 	}
 
 	now, err := svc.timer.Time(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "find token by id")
+		return errors.Wrap(err, "rotate token")
 	}
 
-	if now.After(token.Until()) {
-		return nil, errors.Wrap(banking.ErrTokenDoesNotExist, "find token by id")
+	query, args, err := squirrel.Update("refresh_tokens").
+		Set("token_valid_until", InvalidTokenTime).
+		Set("token_replaced_by", next.ID().String()).
+		Set("updated_at", banking.TimeToMilliseconds(now)).
+		Where(squirrel.Eq{
+			"token_id":          id.String(),
+			"token_replaced_by": nil,
+		}).
+		Where(squirrel.Gt{
+			"token_valid_until": banking.TimeToMilliseconds(now),
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "rotate token")
 	}
 
-	return token, nil
+	affected, err := svc.exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "rotate token")
+	}
+
+	// Nothing was updated, so token was rotated or expired by a concurrent request. FindTokenByID will tell which one.
+	if affected == 0 {
+		if _, err = svc.FindTokenByID(ctx, id); err == nil {
+			err = banking.ErrTokenDoesNotExist
+		}
+
+		return errors.Wrap(err, "rotate token")
+	}
+
+	if err = svc.storeToken(ctx, next, familyID); err != nil {
+		return errors.Wrap(err, "rotate token")
+	}
+
+	return nil
+}
+
+// ExpireTokenFamily expires every Token from the same rotation family as Token with specified identifier.
+func (svc *RefreshTokenService) ExpireTokenFamily(ctx context.Context, id banking.ID) error {
+	familyID, err := svc.findTokenFamilyID(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "expire token family")
+	}
+
+	now, err := svc.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "expire token family")
+	}
+
+	query, args, err := squirrel.Update("refresh_tokens").
+		Set("token_valid_until", InvalidTokenTime).
+		Set("updated_at", banking.TimeToMilliseconds(now)).
+		Where(squirrel.Eq{
+			"token_family_id": familyID.String(),
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "expire token family")
+	}
+
+	if _, err = svc.exec(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "expire token family")
+	}
+
+	return nil
+}
+
+// ExpireUserAccountTokens expires every Token of the user account with specified identifier.
+func (svc *RefreshTokenService) ExpireUserAccountTokens(ctx context.Context, accountID banking.ID) error {
+	now, err := svc.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "expire user account tokens")
+	}
+
+	query, args, err := squirrel.Update("refresh_tokens").
+		Set("token_valid_until", InvalidTokenTime).
+		Set("updated_at", banking.TimeToMilliseconds(now)).
+		Where(squirrel.Eq{
+			"token_user_account_id": accountID.String(),
+		}).
+		Where(squirrel.Gt{
+			"token_valid_until": banking.TimeToMilliseconds(now),
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "expire user account tokens")
+	}
+
+	if _, err = svc.exec(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "expire user account tokens")
+	}
+
+	return nil
+}
+
+// ExpireTokensIssuedBefore expires every Token which was issued before specified time (e.g. when signing key was
+// compromised).
+func (svc *RefreshTokenService) ExpireTokensIssuedBefore(ctx context.Context, before time.Time) error {
+	now, err := svc.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "expire tokens issued before")
+	}
+
+	query, args, err := squirrel.Update("refresh_tokens").
+		Set("token_valid_until", InvalidTokenTime).
+		Set("updated_at", banking.TimeToMilliseconds(now)).
+		Where(squirrel.Lt{
+			"token_issued_at": banking.TimeToMilliseconds(before),
+		}).
+		Where(squirrel.Gt{
+			"token_valid_until": banking.TimeToMilliseconds(now),
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "expire tokens issued before")
+	}
+
+	if _, err = svc.exec(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "expire tokens issued before")
+	}
+
+	return nil
+}
+
+// FindUserAccountTokens returns active tokens of the user account with specified identifier, the most recently issued
+// first.
+func (svc *RefreshTokenService) FindUserAccountTokens(
+	ctx context.Context,
+	accountID banking.ID,
+	opts banking.FindOptions,
+) (
+	[]banking.Token,
+	error,
+) {
+	now, err := svc.timer.Time(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "find user account tokens")
+	}
+
+	query, args, err := squirrel.Select("token_id", "token_user_account_id", "token_issued_at",
+		"token_expiration", "token_valid_until").
+		From("refresh_tokens").
+		Where(squirrel.Eq{
+			"token_user_account_id": accountID.String(),
+			"token_replaced_by":     nil,
+		}).
+		Where(squirrel.Gt{
+			"token_valid_until": banking.TimeToMilliseconds(now),
+		}).
+		OrderBy("token_issued_at DESC", "row_id DESC").
+		Limit(opts.Limit()).
+		Offset(opts.Offset()).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "find user account tokens")
+	}
+
+	tokenStmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "find user account tokens")
+	}
+
+	defer tokenStmt.Close(ctx)
+
+	rows, err := tokenStmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "find user account tokens")
+	}
+
+	defer rows.Close()
+
+	userAccountStmt, err := svc.createUserAccountStmt(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "find user account tokens")
+	}
+
+	defer userAccountStmt.Close(ctx)
+
+	tt, err := svc.scanTokenRows(ctx, rows, userAccountStmt, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "find user account tokens")
+	}
+
+	return tt, nil
+}
+
+func (svc *RefreshTokenService) findTokenFamilyID(ctx context.Context, id banking.ID) (banking.ID, error) {
+	query, args, err := squirrel.Select("token_family_id").
+		From("refresh_tokens").
+		Where(squirrel.Eq{
+			"token_id": id.String(),
+		}).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return banking.EmptyID, errors.Wrap(err, "find token family id")
+	}
+
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return banking.EmptyID, errors.Wrap(err, "find token family id")
+	}
+
+	defer stmt.Close(ctx)
+
+	var familyID string
+
+	err = stmt.QueryRowContext(ctx, args...).Scan(&familyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return banking.EmptyID, errors.Wrap(banking.ErrTokenDoesNotExist, "find token family id")
+	}
+
+	if err != nil {
+		return banking.EmptyID, errors.Wrap(err, "find token family id")
+	}
+
+	return banking.ID(familyID), nil
+}
+
+func (svc *RefreshTokenService) exec(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	defer stmt.Close(ctx)
+
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	return affected, nil
 }
 
 // RemoveExpiredTokens removes expired tokens.
 // Return tokens list after remove.
+//
+// Token is removed only after its expiration, not after its validity time: rotated and revoked tokens must be kept
+// until they could be parsed, otherwise replaying the stolen rotated token would not be detected as reuse.
 func (svc *RefreshTokenService) RemoveExpiredTokens(
 	ctx context.Context,
 	opts banking.FindOptions,
@@ -171,7 +478,7 @@ func (svc *RefreshTokenService) findExpiredTokens(
 	[]banking.Token,
 	error,
 ) {
-	until, err := svc.timer.Time(ctx)
+	now, err := svc.timer.Time(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "find expired tokens")
 	}
@@ -181,7 +488,7 @@ func (svc *RefreshTokenService) findExpiredTokens(
 		Distinct().
 		From("refresh_tokens").
 		Where(squirrel.Lt{
-			"token_valid_until": banking.TimeToMilliseconds(until),
+			"token_expiration": banking.TimeToMilliseconds(now),
 		}).
 		Limit(opts.Limit()).
 		ToSql()
@@ -189,7 +496,7 @@ func (svc *RefreshTokenService) findExpiredTokens(
 		return nil, errors.Wrap(err, "find expired tokens")
 	}
 
-	tokenStmt, err := svc.preparer.PrepareContext(ctx, query)
+	tokenStmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
 	if err != nil {
 		return nil, errors.Wrap(err, "find expired tokens")
 	}
@@ -249,6 +556,7 @@ func (svc *RefreshTokenService) scanTokenRow(
 	ctx context.Context,
 	scanner squirrel.RowScanner,
 	userAccountStmt Stmt,
+	dest ...interface{},
 ) (
 	banking.Token,
 	error,
@@ -261,7 +569,7 @@ func (svc *RefreshTokenService) scanTokenRow(
 		until int64
 	)
 
-	if err := scanner.Scan(&jti, &sub, &iat, &exp, &until); err != nil {
+	if err := scanner.Scan(append([]interface{}{&jti, &sub, &iat, &exp, &until}, dest...)...); err != nil {
 		return nil, errors.Wrap(err, "scan token row")
 	}
 
@@ -284,8 +592,10 @@ func (svc *RefreshTokenService) scanTokenRow(
 	return token, nil
 }
 
+// createUserAccountStmt returns statement which reads owner of token. Email address is encrypted and it is not needed
+// for token, so it is not read.
 func (svc *RefreshTokenService) createUserAccountStmt(ctx context.Context) (Stmt, error) {
-	query, _, err := squirrel.Select("account_id", "username", "email_address", "password_hash", "user_id").
+	query, _, err := squirrel.Select("username", "password_hash", "user_id").
 		From("user_accounts").
 		Where(squirrel.Expr("account_id = ?")).
 		Limit(1).
@@ -294,7 +604,7 @@ func (svc *RefreshTokenService) createUserAccountStmt(ctx context.Context) (Stmt
 		return nil, errors.Wrap(err, "create user accounts stmt")
 	}
 
-	return svc.preparer.PrepareContext(ctx, query)
+	return preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
 }
 
 func fetchUserAccount(ctx context.Context, stmt Stmt, accountID string) (*banking.UserAccount, error) {
@@ -303,10 +613,9 @@ func fetchUserAccount(ctx context.Context, stmt Stmt, accountID string) (*bankin
 	account := new(banking.UserAccount)
 	account.ID = banking.ID(accountID)
 
-	err := stmt.QueryRowContext(ctx, accountID).Scan(&account.UserName, &account.EmailAddress, &account.PasswordHash,
-		&userID)
+	err := stmt.QueryRowContext(ctx, accountID).Scan(&account.UserName, &account.PasswordHash, &userID)
 	if err != nil {
-		return nil, errors.Wrap(err, "find token by id")
+		return nil, errors.Wrap(err, "fetch user account")
 	}
 
 	account.User = new(banking.User)
@@ -331,7 +640,7 @@ func (svc *RefreshTokenService) removeExpiredTokens(ctx context.Context, tt []ba
 		return errors.Wrap(err, "remove expired tokens")
 	}
 
-	tokenStmt, err := svc.preparer.PrepareContext(ctx, query)
+	tokenStmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
 	if err != nil {
 		return errors.Wrap(err, "remove expired tokens")
 	}
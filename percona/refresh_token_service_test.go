@@ -1 +1,371 @@
 package percona_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/morozovcookie/agat-banking/percona"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResult is the result of query which is executed by fakeDriver.
+type fakeResult struct {
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+}
+
+// fakeHandler executes query against in-memory data.
+type fakeHandler func(query string, args []driver.Value) (*fakeResult, error)
+
+// fakeDriver is the database/sql driver which passes every query to handler, so services could be tested without
+// database server.
+type fakeDriver struct {
+	handler fakeHandler
+}
+
+func (d *fakeDriver) Open(_ string) (driver.Conn, error) {
+	return &fakeConn{handler: d.handler}, nil
+}
+
+func (d *fakeDriver) Connect(_ context.Context) (driver.Conn, error) {
+	return d.Open("")
+}
+
+func (d *fakeDriver) Driver() driver.Driver {
+	return d
+}
+
+type fakeConn struct {
+	handler fakeHandler
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeDriverStmt{query: query, handler: c.handler}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeDriverStmt struct {
+	query   string
+	handler fakeHandler
+}
+
+func (s *fakeDriverStmt) Close() error {
+	return nil
+}
+
+func (s *fakeDriverStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeDriverStmt) Exec(args []driver.Value) (driver.Result, error) {
+	res, err := s.handler(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(res.affected), nil
+}
+
+func (s *fakeDriverStmt) Query(args []driver.Value) (driver.Rows, error) {
+	res, err := s.handler(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fakeRows{columns: res.columns, rows: res.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	pos     int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[r.pos])
+	r.pos++
+
+	return nil
+}
+
+// dbPreparer creates statements of database which is opened with fakeDriver.
+type dbPreparer struct {
+	db *sql.DB
+}
+
+func newDBPreparer(handler fakeHandler) *dbPreparer {
+	return &dbPreparer{
+		db: sql.OpenDB(&fakeDriver{handler: handler}),
+	}
+}
+
+func (p *dbPreparer) PrepareContext(ctx context.Context, query string) (percona.Stmt, error) {
+	s, err := p.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err // nolint:wrapcheck
+	}
+
+	return &dbStmt{Stmt: s}, nil
+}
+
+type dbStmt struct {
+	*sql.Stmt
+}
+
+func (s *dbStmt) Close(_ context.Context) error {
+	return s.Stmt.Close()
+}
+
+type fakeToken struct {
+	banking.Token
+
+	id banking.ID
+}
+
+func (t *fakeToken) ID() banking.ID {
+	return t.id
+}
+
+type fakeTokenBuilder struct {
+	banking.TokenBuilder
+
+	id banking.ID
+}
+
+func (b *fakeTokenBuilder) WithID(jti banking.ID) banking.TokenBuilder {
+	b.id = jti
+
+	return b
+}
+
+func (b *fakeTokenBuilder) WithAccount(_ *banking.UserAccount) banking.TokenBuilder {
+	return b
+}
+
+func (b *fakeTokenBuilder) WithIssuedAt(_ time.Time) banking.TokenBuilder {
+	return b
+}
+
+func (b *fakeTokenBuilder) WithExpiration(_ time.Time) banking.TokenBuilder {
+	return b
+}
+
+func (b *fakeTokenBuilder) WithValidUntil(_ time.Time) banking.TokenBuilder {
+	return b
+}
+
+func (b *fakeTokenBuilder) Build(_ context.Context) (banking.Token, error) {
+	return &fakeToken{id: b.id}, nil
+}
+
+type fakeTokenBuilderCreator struct{}
+
+func (fakeTokenBuilderCreator) CreateTokenBuilder(_ context.Context) banking.TokenBuilder {
+	return &fakeTokenBuilder{}
+}
+
+// tokenRow is the refresh_tokens table row.
+type tokenRow struct {
+	id         string
+	expiration int64
+	validUntil int64
+}
+
+// tokensTable is the in-memory refresh_tokens table which supports only queries of token reaper.
+type tokensTable struct {
+	rows []tokenRow
+}
+
+var lessThanPattern = regexp.MustCompile(`WHERE (\w+) < \?`)
+
+func (table *tokensTable) handle(query string, args []driver.Value) (*fakeResult, error) {
+	if regexp.MustCompile(`^SELECT username`).MatchString(query) {
+		return &fakeResult{
+			columns:  []string{"username", "password_hash", "user_id"},
+			rows:     [][]driver.Value{{"username", "password hash", "user id"}},
+			affected: 0,
+		}, nil
+	}
+
+	if regexp.MustCompile(`^DELETE FROM refresh_tokens`).MatchString(query) {
+		return table.delete(args), nil
+	}
+
+	match := lessThanPattern.FindStringSubmatch(query)
+	if match == nil {
+		return nil, errors.Errorf("unexpected query %q", query)
+	}
+
+	res := &fakeResult{
+		columns:  []string{"token_id", "token_user_account_id", "token_issued_at", "token_expiration", "token_valid_until"},
+		rows:     nil,
+		affected: 0,
+	}
+
+	for _, row := range table.rows {
+		value := row.expiration
+		if match[1] == "token_valid_until" {
+			value = row.validUntil
+		}
+
+		if value < args[0].(int64) {
+			res.rows = append(res.rows, []driver.Value{row.id, "account id", int64(0), row.expiration, row.validUntil})
+		}
+	}
+
+	return res, nil
+}
+
+func (table *tokensTable) delete(args []driver.Value) *fakeResult {
+	removed := make(map[string]struct{}, len(args))
+	for _, arg := range args {
+		removed[arg.(string)] = struct{}{}
+	}
+
+	kept := table.rows[:0]
+
+	for _, row := range table.rows {
+		if _, ok := removed[row.id]; !ok {
+			kept = append(kept, row)
+		}
+	}
+
+	affected := int64(len(table.rows) - len(kept))
+	table.rows = kept
+
+	return &fakeResult{
+		columns:  nil,
+		rows:     nil,
+		affected: affected,
+	}
+}
+
+func (table *tokensTable) ids() []string {
+	idd := make([]string, 0, len(table.rows))
+	for _, row := range table.rows {
+		idd = append(idd, row.id)
+	}
+
+	sort.Strings(idd)
+
+	return idd
+}
+
+func TestRefreshTokenService_RemoveExpiredTokens(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		rows []tokenRow
+	}
+	type wants struct {
+		removed []string
+		kept    []string
+	}
+
+	var (
+		now    = time.Date(2021, time.November, 1, 12, 0, 0, 0, time.UTC)
+		past   = banking.TimeToMilliseconds(now.Add(-time.Hour))
+		future = banking.TimeToMilliseconds(now.Add(time.Hour))
+	)
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "expired tokens are removed",
+				enabled: true,
+			},
+			fields: fields{
+				rows: []tokenRow{
+					{id: "expired", expiration: past, validUntil: past},
+					{id: "active", expiration: future, validUntil: future},
+				},
+			},
+			wants: wants{
+				removed: []string{"expired"},
+				kept:    []string{"active"},
+			},
+		},
+		{
+			meta: meta{
+				name:    "rotated and revoked tokens are kept until expiration",
+				enabled: true,
+			},
+			fields: fields{
+				rows: []tokenRow{
+					{id: "rotated", expiration: future, validUntil: percona.InvalidTokenTime},
+					{id: "signed out", expiration: future, validUntil: banking.TimeToMilliseconds(now.Add(-time.Minute))},
+					{id: "rotated and expired", expiration: past, validUntil: percona.InvalidTokenTime},
+				},
+			},
+			wants: wants{
+				removed: []string{"rotated and expired"},
+				kept:    []string{"rotated", "signed out"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			var (
+				table = &tokensTable{rows: tt.fields.rows}
+				timer = mock.NewTimer()
+			)
+
+			timer.On("Time").
+				Return(now, (error)(nil))
+
+			svc := percona.NewRefreshTokenService(newDBPreparer(table.handle), fakeTokenBuilderCreator{}, timer)
+
+			removed, err := svc.RemoveExpiredTokens(context.Background(), banking.NewFindOptions(banking.MaxPageSize, 0))
+			require.NoError(t, err)
+
+			removedIDs := make([]string, 0, len(removed))
+			for _, token := range removed {
+				removedIDs = append(removedIDs, token.ID().String())
+			}
+
+			assert.Equal(t, tt.wants.removed, removedIDs)
+			assert.Equal(t, tt.wants.kept, table.ids())
+		})
+	}
+}
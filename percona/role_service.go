@@ -0,0 +1,137 @@
+package percona
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+var _ banking.RoleService = (*RoleService)(nil)
+
+// RoleService represents a service for managing roles which are assigned to user accounts.
+type RoleService struct {
+	preparer Preparer
+
+	timer banking.Timer
+}
+
+// NewRoleService returns a new RoleService instance.
+func NewRoleService(preparer Preparer, timer banking.Timer) *RoleService {
+	return &RoleService{
+		preparer: preparer,
+
+		timer: timer,
+	}
+}
+
+// FindUserAccountRoles returns roles which are assigned to the UserAccount with specified identifier. Roles which are
+// not known anymore are skipped.
+func (svc *RoleService) FindUserAccountRoles(ctx context.Context, accountID banking.ID) (banking.Roles, error) {
+	query, args, err := squirrel.Select("role_name").
+		From("user_account_roles").
+		Where(squirrel.Eq{
+			"account_id": accountID.String(),
+		}).
+		OrderBy("role_name ASC").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "find user account roles")
+	}
+
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "find user account roles")
+	}
+
+	defer stmt.Close(ctx)
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "find user account roles")
+	}
+
+	defer rows.Close()
+
+	roles := make(banking.Roles, 0)
+
+	for rows.Next() {
+		var name string
+
+		if err = rows.Scan(&name); err != nil {
+			return nil, errors.Wrap(err, "find user account roles")
+		}
+
+		role, err := banking.ParseRole(name)
+		if err != nil {
+			continue
+		}
+
+		roles = append(roles, role)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "find user account roles")
+	}
+
+	return roles, nil
+}
+
+// AssignRole grants role to the UserAccount with specified identifier. Assigning the same role twice is not an error.
+func (svc *RoleService) AssignRole(ctx context.Context, accountID banking.ID, role banking.Role) error {
+	now, err := svc.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "assign role")
+	}
+
+	query, args, err := squirrel.Insert("user_account_roles").
+		Columns("account_id", "role_name", "created_at").
+		Values(accountID.String(), role.String(), banking.TimeToMilliseconds(now)).
+		Suffix("ON DUPLICATE KEY UPDATE role_name = VALUES(role_name)").
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "assign role")
+	}
+
+	if err = svc.exec(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "assign role")
+	}
+
+	return nil
+}
+
+// RevokeRole takes role away from the UserAccount with specified identifier. Revoking role which is not assigned is
+// not an error.
+func (svc *RoleService) RevokeRole(ctx context.Context, accountID banking.ID, role banking.Role) error {
+	query, args, err := squirrel.Delete("user_account_roles").
+		Where(squirrel.Eq{
+			"account_id": accountID.String(),
+			"role_name":  role.String(),
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "revoke role")
+	}
+
+	if err = svc.exec(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "revoke role")
+	}
+
+	return nil
+}
+
+func (svc *RoleService) exec(ctx context.Context, query string, args ...interface{}) error {
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return errors.Wrap(err, "exec")
+	}
+
+	defer stmt.Close(ctx)
+
+	if _, err = stmt.ExecContext(ctx, args...); err != nil {
+		return errors.Wrap(err, "exec")
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package percona
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+const (
+	// mysqlErrLockDeadlock is the MySQL error number which is returned when transaction was chosen as deadlock victim.
+	mysqlErrLockDeadlock = 1213
+
+	// mysqlErrLockWaitTimeout is the MySQL error number which is returned when row lock was not acquired in time.
+	mysqlErrLockWaitTimeout = 1205
+)
+
+type txContextKey struct{}
+
+// txState is the active transaction and the number of nested WithinTransaction calls which it is used by.
+type txState struct {
+	tx    Tx
+	depth int
+}
+
+func contextWithTx(ctx context.Context, state *txState) context.Context {
+	return context.WithValue(ctx, txContextKey{}, state)
+}
+
+func txFromContext(ctx context.Context) (*txState, bool) {
+	state, ok := ctx.Value(txContextKey{}).(*txState)
+
+	return state, ok && state != nil
+}
+
+// preparerFromContext returns the active transaction if there is any, otherwise the fallback, so services become a
+// part of transaction transparently.
+func preparerFromContext(ctx context.Context, fallback Preparer) Preparer {
+	if state, ok := txFromContext(ctx); ok {
+		return state.tx
+	}
+
+	return fallback
+}
+
+var _ banking.TransactionManager = (*TransactionManager)(nil)
+
+// TransactionManager represents a service for running multi-step operations in a single transaction.
+type TransactionManager struct {
+	beginner TxBeginner
+
+	isolation  sql.IsolationLevel
+	maxRetries int
+	retryDelay time.Duration
+}
+
+// NewTransactionManager returns a new TransactionManager instance.
+func NewTransactionManager(beginner TxBeginner, opts ...TransactionManagerOption) *TransactionManager {
+	tm := &TransactionManager{
+		beginner: beginner,
+
+		isolation:  DefaultIsolationLevel,
+		maxRetries: DefaultMaxTransactionRetries,
+		retryDelay: DefaultTransactionRetryDelay,
+	}
+
+	for _, opt := range opts {
+		opt.apply(tm)
+	}
+
+	return tm
+}
+
+// WithinTransaction calls fn with context which carries the active transaction. The whole transaction is repeated
+// when it is failed because of deadlock or lock wait timeout, so fn must not have side effects outside of database.
+// Nested call creates a savepoint and is never repeated by itself: MySQL rolls back the whole transaction on deadlock.
+func (tm *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if state, ok := txFromContext(ctx); ok {
+		if err := tm.withinSavepoint(ctx, state, fn); err != nil {
+			return errors.Wrap(err, "within transaction")
+		}
+
+		return nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := tm.withinTransaction(ctx, fn)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= tm.maxRetries || !isRetryableTxError(err) {
+			return errors.Wrap(err, "within transaction")
+		}
+
+		if err = tm.wait(ctx, attempt); err != nil {
+			return errors.Wrap(err, "within transaction")
+		}
+	}
+}
+
+func (tm *TransactionManager) withinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := tm.beginner.BeginTx(ctx, &sql.TxOptions{
+		Isolation: tm.isolation,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return errors.Wrap(err, "begin transaction")
+	}
+
+	if err = fn(contextWithTx(ctx, &txState{tx: tx, depth: 0})); err != nil {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			return errors.Wrapf(err, "rollback transaction: %v", rollbackErr)
+		}
+
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "commit transaction")
+	}
+
+	return nil
+}
+
+func (tm *TransactionManager) withinSavepoint(
+	ctx context.Context,
+	state *txState,
+	fn func(ctx context.Context) error,
+) error {
+	var (
+		nested    = &txState{tx: state.tx, depth: state.depth + 1}
+		savepoint = "sp_" + strconv.Itoa(nested.depth)
+	)
+
+	// Savepoint statements are not supported by prepared statement protocol, so they are sent as plain text.
+	if err := state.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return errors.Wrap(err, "create savepoint")
+	}
+
+	if err := fn(contextWithTx(ctx, nested)); err != nil {
+		if rollbackErr := state.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); rollbackErr != nil {
+			return errors.Wrapf(err, "rollback to savepoint: %v", rollbackErr)
+		}
+
+		return err
+	}
+
+	if err := state.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+		return errors.Wrap(err, "release savepoint")
+	}
+
+	return nil
+}
+
+// wait sleeps before the next attempt. Delay is doubled on every attempt, so transactions which are deadlocked with
+// each other are not repeated at the same time.
+func (tm *TransactionManager) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(tm.retryDelay << attempt)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "wait")
+	}
+}
+
+func isRetryableTxError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+
+	return errors.As(err, &mysqlErr) &&
+		(mysqlErr.Number == mysqlErrLockDeadlock || mysqlErr.Number == mysqlErrLockWaitTimeout)
+}
@@ -0,0 +1,49 @@
+package percona
+
+import (
+	"database/sql"
+	"time"
+)
+
+// TransactionManagerOption represents an option for configure TransactionManager instance.
+type TransactionManagerOption interface {
+	apply(tm *TransactionManager)
+}
+
+type transactionManagerOptionFunc func(tm *TransactionManager)
+
+func (fn transactionManagerOptionFunc) apply(tm *TransactionManager) {
+	fn(tm)
+}
+
+// DefaultIsolationLevel is the default transaction isolation level (the InnoDB default).
+const DefaultIsolationLevel = sql.LevelRepeatableRead
+
+// WithIsolationLevel sets up the transaction isolation level.
+func WithIsolationLevel(level sql.IsolationLevel) TransactionManagerOption {
+	return transactionManagerOptionFunc(func(tm *TransactionManager) {
+		tm.isolation = level
+	})
+}
+
+// DefaultMaxTransactionRetries is the default number of times which transaction is repeated after deadlock or lock wait
+// timeout.
+const DefaultMaxTransactionRetries = 3
+
+// WithMaxTransactionRetries sets up the number of times which transaction is repeated after deadlock or lock wait
+// timeout.
+func WithMaxTransactionRetries(retries int) TransactionManagerOption {
+	return transactionManagerOptionFunc(func(tm *TransactionManager) {
+		tm.maxRetries = retries
+	})
+}
+
+// DefaultTransactionRetryDelay is the default delay before the first repeat of transaction.
+const DefaultTransactionRetryDelay = time.Millisecond * 20
+
+// WithTransactionRetryDelay sets up the delay before the first repeat of transaction, it is doubled on every next one.
+func WithTransactionRetryDelay(delay time.Duration) TransactionManagerOption {
+	return transactionManagerOptionFunc(func(tm *TransactionManager) {
+		tm.retryDelay = delay
+	})
+}
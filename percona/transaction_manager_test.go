@@ -0,0 +1,264 @@
+package percona_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/morozovcookie/agat-banking/percona"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errPrepared is returned by fakeTx, because transaction manager must not prepare statements.
+var errPrepared = errors.New("statement must not be prepared")
+
+type fakeTx struct {
+	log *[]string
+}
+
+func (tx *fakeTx) PrepareContext(_ context.Context, _ string) (percona.Stmt, error) {
+	return nil, errPrepared
+}
+
+func (tx *fakeTx) ExecContext(_ context.Context, query string) error {
+	*tx.log = append(*tx.log, query)
+
+	return nil
+}
+
+func (tx *fakeTx) Commit(_ context.Context) error {
+	*tx.log = append(*tx.log, "COMMIT")
+
+	return nil
+}
+
+func (tx *fakeTx) Rollback(_ context.Context) error {
+	*tx.log = append(*tx.log, "ROLLBACK")
+
+	return nil
+}
+
+type fakeTxBeginner struct {
+	log []string
+}
+
+func (beginner *fakeTxBeginner) BeginTx(_ context.Context, _ *sql.TxOptions) (percona.Tx, error) {
+	beginner.log = append(beginner.log, "BEGIN")
+
+	return &fakeTx{log: &beginner.log}, nil
+}
+
+func TestTransactionManager_WithinTransaction(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		errs   []error
+		nested error
+	}
+	type wants struct {
+		log []string
+		err bool
+	}
+
+	var (
+		deadlock    = &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+		lockTimeout = &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}
+		duplicate   = &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}
+	)
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "commit",
+				enabled: true,
+			},
+			args: args{
+				errs:   []error{nil},
+				nested: nil,
+			},
+			wants: wants{
+				log: []string{"BEGIN", "SAVEPOINT sp_1", "RELEASE SAVEPOINT sp_1", "COMMIT"},
+				err: false,
+			},
+		},
+		{
+			meta: meta{
+				name:    "retry after deadlock and lock wait timeout",
+				enabled: true,
+			},
+			args: args{
+				errs:   []error{deadlock, lockTimeout, nil},
+				nested: nil,
+			},
+			wants: wants{
+				log: []string{
+					"BEGIN", "SAVEPOINT sp_1", "RELEASE SAVEPOINT sp_1", "ROLLBACK",
+					"BEGIN", "SAVEPOINT sp_1", "RELEASE SAVEPOINT sp_1", "ROLLBACK",
+					"BEGIN", "SAVEPOINT sp_1", "RELEASE SAVEPOINT sp_1", "COMMIT",
+				},
+				err: false,
+			},
+		},
+		{
+			meta: meta{
+				name:    "no retry for other errors",
+				enabled: true,
+			},
+			args: args{
+				errs:   []error{duplicate},
+				nested: nil,
+			},
+			wants: wants{
+				log: []string{"BEGIN", "SAVEPOINT sp_1", "RELEASE SAVEPOINT sp_1", "ROLLBACK"},
+				err: true,
+			},
+		},
+		{
+			meta: meta{
+				name:    "nested error is rolled back to savepoint",
+				enabled: true,
+			},
+			args: args{
+				errs:   []error{nil},
+				nested: errors.New("nested"),
+			},
+			wants: wants{
+				log: []string{"BEGIN", "SAVEPOINT sp_1", "ROLLBACK TO SAVEPOINT sp_1", "COMMIT"},
+				err: false,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.meta.name, func(t *testing.T) {
+			if !test.meta.enabled {
+				t.SkipNow()
+			}
+
+			var (
+				beginner = new(fakeTxBeginner)
+				tm       = percona.NewTransactionManager(beginner, percona.WithTransactionRetryDelay(time.Microsecond))
+				attempt  = 0
+			)
+
+			err := tm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+				// Nested error is handled by outer function, so only the savepoint is rolled back.
+				_ = tm.WithinTransaction(ctx, func(_ context.Context) error {
+					return test.args.nested
+				})
+
+				err := test.args.errs[attempt]
+				attempt++
+
+				return err
+			})
+
+			assert.Equal(t, test.wants.err, err != nil)
+			assert.Equal(t, test.wants.log, beginner.log)
+		})
+	}
+}
+
+// perconaTestDSNEnv is the name of environment variable which contains DSN of the database for tests which require
+// real server. Tests create and drop their own tables.
+const perconaTestDSNEnv = "PERCONA_TEST_DSN"
+
+// TestTransactionManager_WithinTransaction_Percona checks savepoints on the real server, because the server does not
+// support every statement by the prepared statement protocol.
+func TestTransactionManager_WithinTransaction_Percona(t *testing.T) {
+	dsn, ok := os.LookupEnv(perconaTestDSNEnv)
+	if !ok || dsn == "" {
+		t.Skipf("%s is not set", perconaTestDSNEnv)
+	}
+
+	var (
+		ctx    = context.Background()
+		client = percona.NewClient(dsn)
+		table  = "transaction_manager_test_" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	)
+
+	require.NoError(t, client.Connect(ctx))
+	t.Cleanup(func() { _ = client.Close(ctx) })
+
+	exec := func(ctx context.Context, query string, args ...interface{}) error {
+		stmt, err := percona.PreparerFromContext(ctx, client).PrepareContext(ctx, query)
+		if err != nil {
+			return err // nolint:wrapcheck
+		}
+
+		defer stmt.Close(ctx)
+
+		_, err = stmt.ExecContext(ctx, args...)
+
+		return err // nolint:wrapcheck
+	}
+
+	require.NoError(t, exec(ctx, "CREATE TABLE "+table+" (id INT NOT NULL PRIMARY KEY) ENGINE = InnoDB"))
+	t.Cleanup(func() { assert.NoError(t, exec(ctx, "DROP TABLE "+table)) })
+
+	var (
+		tm        = percona.NewTransactionManager(client)
+		errNested = errors.New("nested")
+		insert    = "INSERT INTO " + table + " (id) VALUES (?)"
+	)
+
+	err := tm.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := exec(ctx, insert, 1); err != nil {
+			return err
+		}
+
+		err := tm.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := exec(ctx, insert, 2); err != nil {
+				return err
+			}
+
+			return errNested
+		})
+		if !errors.Is(err, errNested) {
+			return errors.Wrap(err, "nested transaction must fail with its own error")
+		}
+
+		// Savepoint is released, so the next nested transaction could reuse its name.
+		return tm.WithinTransaction(ctx, func(ctx context.Context) error {
+			return exec(ctx, insert, 3)
+		})
+	})
+	require.NoError(t, err)
+
+	stmt, err := client.PrepareContext(ctx, "SELECT id FROM "+table+" ORDER BY id")
+	require.NoError(t, err)
+
+	defer stmt.Close(ctx)
+
+	rows, err := stmt.QueryContext(ctx)
+	require.NoError(t, err)
+
+	defer rows.Close()
+
+	var ids []int
+
+	for rows.Next() {
+		var id int
+
+		require.NoError(t, rows.Scan(&id))
+
+		ids = append(ids, id)
+	}
+
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []int{1, 3}, ids, "nested rollback must be undone while outer transaction is committed")
+}
@@ -11,6 +11,10 @@ import (
 type Tx interface {
 	Preparer
 
+	// ExecContext executes a query without arguments by the text protocol. It is used for statements which could not
+	// be prepared (e.g. SAVEPOINT).
+	ExecContext(ctx context.Context, query string) error
+
 	// Commit commits the transaction.
 	Commit(ctx context.Context) error
 
@@ -40,6 +44,16 @@ func (tx *tx) PrepareContext(ctx context.Context, query string) (Stmt, error) {
 	}, nil
 }
 
+// ExecContext executes a query without arguments by the text protocol.
+func (tx *tx) ExecContext(ctx context.Context, query string) error {
+	// Driver sends query without arguments as is, it prepares only queries with arguments.
+	if _, err := tx.Tx.ExecContext(ctx, query); err != nil {
+		return errors.Wrap(err, "tx exec")
+	}
+
+	return nil
+}
+
 // Commit commits the transaction.
 func (tx *tx) Commit(_ context.Context) error {
 	return tx.Tx.Commit()
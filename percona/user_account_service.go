@@ -1,26 +1,66 @@
 package percona
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"time"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/go-sql-driver/mysql"
 	banking "github.com/morozovcookie/agat-banking"
+	bankingsql "github.com/morozovcookie/agat-banking/sql"
 	"github.com/pkg/errors"
 )
 
 var _ banking.UserAccountService = (*UserAccountService)(nil)
 
+const (
+	// mysqlErrDupEntry is the MySQL error number which is returned when unique index value is already used.
+	mysqlErrDupEntry = 1062
+
+	// UserAccountsTable is the name of table which stores user accounts.
+	UserAccountsTable = "user_accounts"
+
+	// UserAccountIDColumn is the name of column which stores user account identifier.
+	UserAccountIDColumn = "account_id"
+
+	// EmailAddressColumn is the name of column which stores encrypted email address.
+	EmailAddressColumn = "email_address"
+
+	// EmailAddressIndexColumn is the name of column which stores blind index of email address.
+	EmailAddressIndexColumn = "email_address_index"
+)
+
 // UserAccountService represents a service for managing UserAccount data.
+//
+// UserAccount.EmailAddress is stored encrypted and bound to the user account, so it is looked up by its blind index.
+// Email address which was stored before encryption was enabled has no blind index, it is read as is until
+// worker.ColumnEncryptor encrypts it.
 type UserAccountService struct {
 	preparer Preparer
+
+	generator     banking.IdentifierGenerator
+	timer         banking.Timer
+	secretFactory banking.SecretFactory
+	blindIndexer  banking.BlindIndexer
 }
 
 // NewUserAccountService returns a new UserAccountService instance.
-func NewUserAccountService(preparer Preparer) *UserAccountService {
+func NewUserAccountService(
+	preparer Preparer,
+	generator banking.IdentifierGenerator,
+	timer banking.Timer,
+	secretFactory banking.SecretFactory,
+	blindIndexer banking.BlindIndexer,
+) *UserAccountService {
 	return &UserAccountService{
 		preparer: preparer,
+
+		generator:     generator,
+		timer:         timer,
+		secretFactory: secretFactory,
+		blindIndexer:  blindIndexer,
 	}
 }
 
@@ -32,8 +72,19 @@ func (svc *UserAccountService) FindUserAccountByEmailAddress(
 	*banking.UserAccount,
 	error,
 ) {
-	pred := squirrel.Eq{
-		"email_address": emailAddress,
+	index, err := svc.emailAddressIndex(ctx, emailAddress)
+	if err != nil {
+		return nil, errors.Wrap(err, "find user account by email address")
+	}
+
+	pred := squirrel.Or{
+		squirrel.Eq{
+			EmailAddressIndexColumn: index,
+		},
+		squirrel.Eq{
+			EmailAddressIndexColumn: nil,
+			EmailAddressColumn:      emailAddress,
+		},
 	}
 
 	account, err := svc.findUserAccount(ctx, pred)
@@ -65,9 +116,7 @@ func (svc *UserAccountService) FindUserAccountByUserName(
 }
 
 func (svc *UserAccountService) findUserAccount(ctx context.Context, pred interface{}) (*banking.UserAccount, error) {
-	query, args, err := squirrel.Select("account_id", "username", "email_address", "password_hash",
-		"user_id", "created_at", "updated_at").
-		From("user_accounts").
+	query, args, err := svc.selectUserAccounts().
 		Where(pred).
 		Limit(1).
 		ToSql()
@@ -75,33 +124,60 @@ func (svc *UserAccountService) findUserAccount(ctx context.Context, pred interfa
 		return nil, errors.Wrap(err, "find user account")
 	}
 
-	stmt, err := svc.preparer.PrepareContext(ctx, query)
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
 	if err != nil {
 		return nil, errors.Wrap(err, "find user account")
 	}
 
-	defer func(ctx context.Context, stmt Stmt) {
-		if closeErr := stmt.Close(ctx); closeErr != nil {
-			err = closeErr
-		}
-	}(ctx, stmt)
+	defer stmt.Close(ctx)
 
+	account, err := svc.scanUserAccount(stmt.QueryRowContext(ctx, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(banking.ErrUserAccountDoesNotExist, "find user account")
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "find user account")
+	}
+
+	return account, nil
+}
+
+func (svc *UserAccountService) selectUserAccounts() squirrel.SelectBuilder {
+	return squirrel.Select("account_id", "username", EmailAddressColumn, EmailAddressIndexColumn, "password_hash",
+		"user_id", "disabled_at", "created_at", "updated_at").
+		From(UserAccountsTable)
+}
+
+func (svc *UserAccountService) scanUserAccount(scanner squirrel.RowScanner) (*banking.UserAccount, error) {
 	var (
 		account = &banking.UserAccount{
 			User: &banking.User{},
 		}
-		createdAt int64
-		updatedAt sql.NullInt64
+		emailAddress      string
+		emailAddressIndex sql.NullString
+		disabledAt        sql.NullInt64
+		createdAt         int64
+		updatedAt         sql.NullInt64
 	)
 
-	err = stmt.QueryRowContext(ctx, args...).Scan(&account.ID, &account.UserName, &account.EmailAddress,
-		&account.PasswordHash, &account.User.ID, &createdAt, &updatedAt)
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, errors.Wrap(banking.ErrUserAccountDoesNotExist, "find user account")
+	err := scanner.Scan(&account.ID, &account.UserName, &emailAddress, &emailAddressIndex, &account.PasswordHash,
+		&account.User.ID, &disabledAt, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "scan user account")
 	}
 
-	if err != nil {
-		return nil, errors.Wrap(err, "find user account")
+	// Email address without blind index is plaintext which has not been encrypted yet.
+	account.EmailAddress = emailAddress
+
+	if emailAddressIndex.Valid {
+		secret := bankingsql.NewSecretString(nil, svc.secretFactory, emailAddressAssociatedData(account.ID))
+
+		if err = secret.Scan(emailAddress); err != nil {
+			return nil, errors.Wrap(err, "scan user account")
+		}
+
+		account.EmailAddress = secret.DecryptedString()
 	}
 
 	account.CreatedAt = time.Unix(0, banking.MillisecondsToNanoseconds(createdAt))
@@ -110,5 +186,350 @@ func (svc *UserAccountService) findUserAccount(ctx context.Context, pred interfa
 		account.UpdateAt = time.Unix(0, banking.MillisecondsToNanoseconds(updatedAt.Int64))
 	}
 
+	if disabledAt.Valid {
+		account.DisabledAt = time.Unix(0, banking.MillisecondsToNanoseconds(disabledAt.Int64))
+	}
+
+	return account, nil
+}
+
+// UpdateUserAccountPasswordHash replaces UserAccount.PasswordHash of the UserAccount with specified identifier.
+func (svc *UserAccountService) UpdateUserAccountPasswordHash(
+	ctx context.Context,
+	id banking.ID,
+	passwordHash string,
+) error {
+	updatedAt, err := svc.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "update user account password hash")
+	}
+
+	query, args, err := squirrel.Update(UserAccountsTable).
+		Set("password_hash", passwordHash).
+		Set("updated_at", banking.TimeToMilliseconds(updatedAt)).
+		Where(squirrel.Eq{
+			"account_id": id.String(),
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "update user account password hash")
+	}
+
+	affected, err := svc.exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "update user account password hash")
+	}
+
+	if affected == 0 {
+		return errors.Wrap(banking.ErrUserAccountDoesNotExist, "update user account password hash")
+	}
+
+	return nil
+}
+
+// CreateUserAccount stores a new UserAccount with already hashed password. UserAccount.ID and UserAccount.CreatedAt
+// are set on success.
+// Return banking.ErrUserAccountAlreadyExists if username or email address is already used.
+func (svc *UserAccountService) CreateUserAccount(ctx context.Context, account *banking.UserAccount) error {
+	id, err := svc.generator.GenerateIdentifier(ctx)
+	if err != nil {
+		return errors.Wrap(err, "create user account")
+	}
+
+	createdAt, err := svc.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "create user account")
+	}
+
+	if err = svc.checkPlaintextEmailAddress(ctx, id, account.EmailAddress); err != nil {
+		return errors.Wrap(err, "create user account")
+	}
+
+	emailAddress, index, err := svc.encryptEmailAddress(ctx, id, account.EmailAddress)
+	if err != nil {
+		return errors.Wrap(err, "create user account")
+	}
+
+	query, args, err := squirrel.Insert(UserAccountsTable).
+		Columns("account_id", "username", EmailAddressColumn, EmailAddressIndexColumn, "password_hash", "user_id",
+			"created_at").
+		Values(id.String(), account.UserName, emailAddress, index, account.PasswordHash, account.User.ID.String(),
+			banking.TimeToMilliseconds(createdAt)).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "create user account")
+	}
+
+	if _, err = svc.exec(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "create user account")
+	}
+
+	account.ID, account.CreatedAt = id, createdAt
+
+	return nil
+}
+
+// FindUserAccountByID returns UserAccount by UserAccount.ID.
+func (svc *UserAccountService) FindUserAccountByID(ctx context.Context, id banking.ID) (*banking.UserAccount, error) {
+	pred := squirrel.Eq{
+		"account_id": id.String(),
+	}
+
+	account, err := svc.findUserAccount(ctx, pred)
+	if err != nil {
+		return nil, errors.Wrap(err, "find user account by id")
+	}
+
 	return account, nil
 }
+
+// FindUserAccounts returns the page of user accounts in order of creation.
+func (svc *UserAccountService) FindUserAccounts(
+	ctx context.Context,
+	opts banking.FindOptions,
+) (
+	[]*banking.UserAccount,
+	error,
+) {
+	query, args, err := svc.selectUserAccounts().
+		OrderBy("row_id ASC").
+		Limit(opts.Limit()).
+		Offset(opts.Offset()).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "find user accounts")
+	}
+
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "find user accounts")
+	}
+
+	defer stmt.Close(ctx)
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "find user accounts")
+	}
+
+	defer rows.Close()
+
+	aa := make([]*banking.UserAccount, 0, opts.Limit())
+
+	for rows.Next() {
+		account, err := svc.scanUserAccount(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "find user accounts")
+		}
+
+		aa = append(aa, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "find user accounts")
+	}
+
+	return aa, nil
+}
+
+// UpdateUserAccount replaces UserAccount.UserName and UserAccount.EmailAddress. UserAccount.UpdateAt is set on
+// success.
+// Return banking.ErrUserAccountAlreadyExists if username or email address is already used.
+func (svc *UserAccountService) UpdateUserAccount(ctx context.Context, account *banking.UserAccount) error {
+	updatedAt, err := svc.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "update user account")
+	}
+
+	if err = svc.checkPlaintextEmailAddress(ctx, account.ID, account.EmailAddress); err != nil {
+		return errors.Wrap(err, "update user account")
+	}
+
+	emailAddress, index, err := svc.encryptEmailAddress(ctx, account.ID, account.EmailAddress)
+	if err != nil {
+		return errors.Wrap(err, "update user account")
+	}
+
+	query, args, err := squirrel.Update(UserAccountsTable).
+		Set("username", account.UserName).
+		Set(EmailAddressColumn, emailAddress).
+		Set(EmailAddressIndexColumn, index).
+		Set("updated_at", banking.TimeToMilliseconds(updatedAt)).
+		Where(squirrel.Eq{
+			"account_id": account.ID.String(),
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "update user account")
+	}
+
+	affected, err := svc.exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "update user account")
+	}
+
+	// MySQL does not count rows which values were not changed, so user account existence has to be checked.
+	if affected == 0 {
+		if _, err = svc.FindUserAccountByID(ctx, account.ID); err != nil {
+			return errors.Wrap(err, "update user account")
+		}
+	}
+
+	account.UpdateAt = updatedAt
+
+	return nil
+}
+
+// DisableUserAccount forbids sign in to the UserAccount with specified identifier. Disabled user account is kept, so
+// its history is not lost.
+func (svc *UserAccountService) DisableUserAccount(ctx context.Context, id banking.ID) error {
+	now, err := svc.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "disable user account")
+	}
+
+	query, args, err := squirrel.Update(UserAccountsTable).
+		Set("disabled_at", banking.TimeToMilliseconds(now)).
+		Set("updated_at", banking.TimeToMilliseconds(now)).
+		Where(squirrel.Eq{
+			"account_id":  id.String(),
+			"disabled_at": nil,
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "disable user account")
+	}
+
+	affected, err := svc.exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "disable user account")
+	}
+
+	// Nothing was updated, so user account does not exist or it is already disabled. The latter is not an error.
+	if affected == 0 {
+		if _, err = svc.FindUserAccountByID(ctx, id); err != nil {
+			return errors.Wrap(err, "disable user account")
+		}
+	}
+
+	return nil
+}
+
+// checkPlaintextEmailAddress returns banking.ErrUserAccountAlreadyExists if email address is used by the other user
+// account in plaintext. Plaintext email address has no blind index, so unique index could not catch the duplicate and
+// the column encryption job would fail on it. Email address is never stored in plaintext again, so it could not appear
+// between check and write; if it is encrypted meanwhile, unique index catches the duplicate.
+func (svc *UserAccountService) checkPlaintextEmailAddress(
+	ctx context.Context,
+	id banking.ID,
+	emailAddress string,
+) error {
+	query, args, err := squirrel.Select(UserAccountIDColumn).
+		From(UserAccountsTable).
+		Where(squirrel.Eq{
+			EmailAddressColumn:      emailAddress,
+			EmailAddressIndexColumn: nil,
+		}).
+		Where(squirrel.NotEq{
+			UserAccountIDColumn: id.String(),
+		}).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "check plaintext email address")
+	}
+
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return errors.Wrap(err, "check plaintext email address")
+	}
+
+	defer stmt.Close(ctx)
+
+	var accountID string
+
+	err = stmt.QueryRowContext(ctx, args...).Scan(&accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "check plaintext email address")
+	}
+
+	return errors.Wrap(banking.ErrUserAccountAlreadyExists, "check plaintext email address")
+}
+
+// encryptEmailAddress returns encrypted email address which is bound to the user account and its blind index.
+func (svc *UserAccountService) encryptEmailAddress(
+	ctx context.Context,
+	id banking.ID,
+	emailAddress string,
+) (
+	*bankingsql.SecretString,
+	string,
+	error,
+) {
+	ad := emailAddressAssociatedData(id)
+
+	secret, err := svc.secretFactory.CreateBoundFromDecryptedData(ctx, bytes.NewBufferString(emailAddress), ad)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "encrypt email address")
+	}
+
+	index, err := svc.emailAddressIndex(ctx, emailAddress)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "encrypt email address")
+	}
+
+	return bankingsql.NewSecretString(secret, svc.secretFactory, ad), index, nil
+}
+
+func (svc *UserAccountService) emailAddressIndex(ctx context.Context, emailAddress string) (string, error) {
+	index, err := svc.blindIndexer.BlindIndex(ctx, UserAccountsTable, EmailAddressColumn, emailAddress)
+	if err != nil {
+		return "", errors.Wrap(err, "email address index")
+	}
+
+	return index, nil
+}
+
+// emailAddressAssociatedData returns associated data which email address of the user account is bound to.
+func emailAddressAssociatedData(id banking.ID) banking.AssociatedData {
+	return banking.AssociatedData{
+		Table:    UserAccountsTable,
+		Column:   EmailAddressColumn,
+		RecordID: id.String(),
+	}
+}
+
+func (svc *UserAccountService) exec(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	defer stmt.Close(ctx)
+
+	res, err := stmt.ExecContext(ctx, args...)
+	if isDuplicateEntry(err) {
+		return 0, errors.Wrap(banking.ErrUserAccountAlreadyExists, "exec")
+	}
+
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	return affected, nil
+}
+
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDupEntry
+}
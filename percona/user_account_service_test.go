@@ -1 +1,320 @@
-package percona
+package percona_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/morozovcookie/agat-banking/percona"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// encryptedSecret is the secret which encrypted string is marked with prefix.
+type encryptedSecret string
+
+func (s encryptedSecret) String() string          { return "[SECRET]" }
+func (s encryptedSecret) EncryptedString() string { return "enc:" + string(s) }
+func (s encryptedSecret) DecryptedString() string { return string(s) }
+
+type encryptedSecretFactory struct {
+	banking.SecretFactory
+}
+
+func (encryptedSecretFactory) CreateBoundFromDecryptedData(
+	_ context.Context,
+	r io.Reader,
+	_ banking.AssociatedData,
+) (
+	banking.SecretString,
+	error,
+) {
+	bb, err := ioutil.ReadAll(r)
+
+	return encryptedSecret(bb), err
+}
+
+type prefixBlindIndexer struct{}
+
+func (prefixBlindIndexer) BlindIndex(_ context.Context, _, _, value string) (string, error) {
+	return "idx:" + value, nil
+}
+
+// accountRow is the user_accounts table row, plaintext email address has empty index.
+type accountRow struct {
+	id           string
+	emailAddress string
+	index        string
+}
+
+// accountsTable is the in-memory user_accounts table which supports only queries of user account writing. It has the
+// same unique index on email address index as the real one.
+type accountsTable struct {
+	rows []*accountRow
+}
+
+func (table *accountsTable) handle(query string, args []driver.Value) (*fakeResult, error) {
+	switch {
+	case strings.HasPrefix(query, "SELECT account_id FROM user_accounts WHERE email_address = ? "+
+		"AND email_address_index IS NULL AND account_id <> ?"):
+		res := &fakeResult{
+			columns:  []string{"account_id"},
+			rows:     nil,
+			affected: 0,
+		}
+
+		for _, row := range table.rows {
+			if row.emailAddress == args[0].(string) && row.index == "" && row.id != args[1].(string) {
+				res.rows = append(res.rows, []driver.Value{row.id})
+			}
+		}
+
+		return res, nil
+	case strings.HasPrefix(query, "INSERT INTO user_accounts"):
+		return table.write(&accountRow{
+			id:           args[0].(string),
+			emailAddress: args[2].(string),
+			index:        args[3].(string),
+		})
+	case strings.HasPrefix(query, "UPDATE user_accounts SET username = ?, email_address = ?, email_address_index = ?"):
+		return table.write(&accountRow{
+			id:           args[4].(string),
+			emailAddress: args[1].(string),
+			index:        args[2].(string),
+		})
+	}
+
+	return nil, errors.Errorf("unexpected query %q", query)
+}
+
+func (table *accountsTable) write(account *accountRow) (*fakeResult, error) {
+	var existing *accountRow
+
+	for _, row := range table.rows {
+		if row.id == account.id {
+			existing = row
+
+			continue
+		}
+
+		if row.index != "" && row.index == account.index {
+			return nil, &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}
+		}
+	}
+
+	if existing == nil {
+		table.rows = append(table.rows, account)
+	} else {
+		*existing = *account
+	}
+
+	return &fakeResult{
+		columns:  nil,
+		rows:     nil,
+		affected: 1,
+	}, nil
+}
+
+func newAccountsTable() *accountsTable {
+	return &accountsTable{
+		rows: []*accountRow{
+			{id: "legacy", emailAddress: "legacy@example.com", index: ""},
+			{id: "encrypted", emailAddress: "enc:encrypted@example.com", index: "idx:encrypted@example.com"},
+		},
+	}
+}
+
+func newUserAccountService(table *accountsTable) *percona.UserAccountService {
+	generator := mock.NewIdentifierGenerator()
+	generator.On("GenerateIdentifier").
+		Return(banking.ID("created"), nil)
+
+	timer := mock.NewTimer()
+	timer.On("Time").
+		Return(time.Date(2021, time.November, 1, 12, 0, 0, 0, time.UTC), nil)
+
+	return percona.NewUserAccountService(newDBPreparer(table.handle), generator, timer, encryptedSecretFactory{},
+		prefixBlindIndexer{})
+}
+
+func TestUserAccountService_CreateUserAccount(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		emailAddress string
+	}
+	type wants struct {
+		err  error
+		rows int
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "new email address",
+				enabled: true,
+			},
+			args: args{
+				emailAddress: "new@example.com",
+			},
+			wants: wants{
+				err:  nil,
+				rows: 3,
+			},
+		},
+		{
+			meta: meta{
+				name:    "email address is used in plaintext",
+				enabled: true,
+			},
+			args: args{
+				emailAddress: "legacy@example.com",
+			},
+			wants: wants{
+				err:  banking.ErrUserAccountAlreadyExists,
+				rows: 2,
+			},
+		},
+		{
+			meta: meta{
+				name:    "email address is used encrypted",
+				enabled: true,
+			},
+			args: args{
+				emailAddress: "encrypted@example.com",
+			},
+			wants: wants{
+				err:  banking.ErrUserAccountAlreadyExists,
+				rows: 2,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			table := newAccountsTable()
+
+			err := newUserAccountService(table).CreateUserAccount(context.Background(), &banking.UserAccount{
+				UserName:     "john",
+				EmailAddress: tt.args.emailAddress,
+				PasswordHash: "hash",
+				User:         &banking.User{ID: "user"},
+			})
+			if tt.wants.err != nil {
+				assert.True(t, errors.Is(err, tt.wants.err), err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Len(t, table.rows, tt.wants.rows)
+		})
+	}
+}
+
+func TestUserAccountService_UpdateUserAccount(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		id           banking.ID
+		emailAddress string
+	}
+	type wants struct {
+		err          error
+		emailAddress string
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "own plaintext email address is kept",
+				enabled: true,
+			},
+			args: args{
+				id:           "legacy",
+				emailAddress: "legacy@example.com",
+			},
+			wants: wants{
+				err:          nil,
+				emailAddress: "enc:legacy@example.com",
+			},
+		},
+		{
+			meta: meta{
+				name:    "email address is used in plaintext by other account",
+				enabled: true,
+			},
+			args: args{
+				id:           "encrypted",
+				emailAddress: "legacy@example.com",
+			},
+			wants: wants{
+				err:          banking.ErrUserAccountAlreadyExists,
+				emailAddress: "enc:encrypted@example.com",
+			},
+		},
+		{
+			meta: meta{
+				name:    "email address is used encrypted by other account",
+				enabled: true,
+			},
+			args: args{
+				id:           "legacy",
+				emailAddress: "encrypted@example.com",
+			},
+			wants: wants{
+				err:          banking.ErrUserAccountAlreadyExists,
+				emailAddress: "legacy@example.com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			table := newAccountsTable()
+
+			err := newUserAccountService(table).UpdateUserAccount(context.Background(), &banking.UserAccount{
+				ID:           tt.args.id,
+				UserName:     "john",
+				EmailAddress: tt.args.emailAddress,
+			})
+			if tt.wants.err != nil {
+				assert.True(t, errors.Is(err, tt.wants.err), err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			for _, row := range table.rows {
+				if row.id == tt.args.id.String() {
+					assert.Equal(t, tt.wants.emailAddress, row.emailAddress)
+				}
+			}
+		})
+	}
+}
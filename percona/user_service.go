@@ -0,0 +1,247 @@
+package percona
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Masterminds/squirrel"
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+var _ banking.UserService = (*UserService)(nil)
+
+// UserService represents a service for managing User data.
+type UserService struct {
+	preparer Preparer
+
+	generator banking.IdentifierGenerator
+	timer     banking.Timer
+}
+
+// NewUserService returns a new UserService instance.
+func NewUserService(preparer Preparer, generator banking.IdentifierGenerator, timer banking.Timer) *UserService {
+	return &UserService{
+		preparer: preparer,
+
+		generator: generator,
+		timer:     timer,
+	}
+}
+
+// CreateUser stores a new User. User.ID and User.CreatedAt are set on success.
+func (svc *UserService) CreateUser(ctx context.Context, user *banking.User) error {
+	id, err := svc.generator.GenerateIdentifier(ctx)
+	if err != nil {
+		return errors.Wrap(err, "create user")
+	}
+
+	createdAt, err := svc.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "create user")
+	}
+
+	query, args, err := squirrel.Insert("users").
+		Columns("user_id", "first_name", "last_name", "created_at").
+		Values(id.String(), user.FirstName, user.LastName, banking.TimeToMilliseconds(createdAt)).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "create user")
+	}
+
+	if _, err = svc.exec(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "create user")
+	}
+
+	user.ID, user.CreatedAt = id, createdAt
+
+	return nil
+}
+
+// FindUserByID returns User by User.ID.
+func (svc *UserService) FindUserByID(ctx context.Context, id banking.ID) (*banking.User, error) {
+	query, args, err := svc.selectUsers().
+		Where(squirrel.Eq{
+			"user_id": id.String(),
+		}).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "find user by id")
+	}
+
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "find user by id")
+	}
+
+	defer stmt.Close(ctx)
+
+	user, err := scanUser(stmt.QueryRowContext(ctx, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(banking.ErrUserDoesNotExist, "find user by id")
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "find user by id")
+	}
+
+	return user, nil
+}
+
+// FindUsers returns the page of users in order of creation.
+func (svc *UserService) FindUsers(ctx context.Context, opts banking.FindOptions) ([]*banking.User, error) {
+	query, args, err := svc.selectUsers().
+		OrderBy("row_id ASC").
+		Limit(opts.Limit()).
+		Offset(opts.Offset()).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "find users")
+	}
+
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "find users")
+	}
+
+	defer stmt.Close(ctx)
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "find users")
+	}
+
+	defer rows.Close()
+
+	uu := make([]*banking.User, 0, opts.Limit())
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "find users")
+		}
+
+		uu = append(uu, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "find users")
+	}
+
+	return uu, nil
+}
+
+// UpdateUser replaces User.FirstName and User.LastName. User.UpdatedAt is set on success.
+func (svc *UserService) UpdateUser(ctx context.Context, user *banking.User) error {
+	updatedAt, err := svc.timer.Time(ctx)
+	if err != nil {
+		return errors.Wrap(err, "update user")
+	}
+
+	query, args, err := squirrel.Update("users").
+		Set("first_name", user.FirstName).
+		Set("last_name", user.LastName).
+		Set("updated_at", banking.TimeToMilliseconds(updatedAt)).
+		Where(squirrel.Eq{
+			"user_id": user.ID.String(),
+		}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "update user")
+	}
+
+	affected, err := svc.exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "update user")
+	}
+
+	// MySQL does not count rows which values were not changed, so user existence has to be checked.
+	if affected == 0 {
+		if _, err = svc.FindUserByID(ctx, user.ID); err != nil {
+			return errors.Wrap(err, "update user")
+		}
+	}
+
+	user.UpdatedAt = updatedAt
+
+	return nil
+}
+
+// DeleteUser removes User with specified identifier.
+// Return banking.ErrUserHasAccounts if User still owns user accounts.
+func (svc *UserService) DeleteUser(ctx context.Context, id banking.ID) error {
+	// Accounts are checked in the same statement, so account could not be created for the user between check and
+	// delete.
+	query, args, err := squirrel.Delete("users").
+		Where(squirrel.Eq{
+			"user_id": id.String(),
+		}).
+		Where("NOT EXISTS (SELECT 1 FROM user_accounts WHERE user_accounts.user_id = ?)", id.String()).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "delete user")
+	}
+
+	affected, err := svc.exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "delete user")
+	}
+
+	if affected != 0 {
+		return nil
+	}
+
+	// Nothing was deleted, FindUserByID will tell whether user does not exist or it has accounts.
+	if _, err = svc.FindUserByID(ctx, id); err != nil {
+		return errors.Wrap(err, "delete user")
+	}
+
+	return errors.Wrap(banking.ErrUserHasAccounts, "delete user")
+}
+
+func (svc *UserService) selectUsers() squirrel.SelectBuilder {
+	return squirrel.Select("user_id", "first_name", "last_name", "created_at", "updated_at").
+		From("users")
+}
+
+func (svc *UserService) exec(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	stmt, err := preparerFromContext(ctx, svc.preparer).PrepareContext(ctx, query)
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	defer stmt.Close(ctx)
+
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "exec")
+	}
+
+	return affected, nil
+}
+
+func scanUser(scanner squirrel.RowScanner) (*banking.User, error) {
+	var (
+		user      = new(banking.User)
+		createdAt int64
+		updatedAt sql.NullInt64
+	)
+
+	if err := scanner.Scan(&user.ID, &user.FirstName, &user.LastName, &createdAt, &updatedAt); err != nil {
+		return nil, errors.Wrap(err, "scan user")
+	}
+
+	user.CreatedAt = banking.MillisecondsToTime(createdAt)
+
+	if updatedAt.Valid {
+		user.UpdatedAt = banking.MillisecondsToTime(updatedAt.Int64)
+	}
+
+	return user, nil
+}
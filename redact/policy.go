@@ -0,0 +1,138 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+// Redacted is the value which replaces sensitive information with PolicyRedacted.
+const Redacted = "[REDACTED]"
+
+// fingerprintPrefix is the prefix of fingerprint, so it could not be confused with the real value.
+const fingerprintPrefix = "fp:"
+
+// fingerprintLength is the number of HMAC bytes which are kept in fingerprint.
+const fingerprintLength = 8
+
+// ErrUnknownPolicy will be raised when policy name could not be parsed.
+var ErrUnknownPolicy = errors.New("unknown policy")
+
+// Policy represents an enum which describes how sensitive information is written to logs and traces.
+type Policy int32
+
+const (
+	// PolicyRedacted replaces sensitive information with Redacted. It is the default policy.
+	PolicyRedacted Policy = iota
+
+	// PolicyFingerprint replaces sensitive information with keyed hash, so the same value could be correlated
+	// across log records and spans without being revealed.
+	PolicyFingerprint
+
+	// PolicyFull keeps sensitive information as is. It must be used only in development.
+	PolicyFull
+)
+
+func (p Policy) String() string {
+	if p == PolicyFingerprint {
+		return "fingerprint"
+	}
+
+	if p == PolicyFull {
+		return "full"
+	}
+
+	return "redacted"
+}
+
+// ParsePolicy returns Policy by its name.
+func ParsePolicy(name string) (Policy, error) {
+	for _, p := range []Policy{PolicyRedacted, PolicyFingerprint, PolicyFull} {
+		if p.String() == name {
+			return p, nil
+		}
+	}
+
+	return PolicyRedacted, errors.Wrapf(ErrUnknownPolicy, "parse policy %q", name)
+}
+
+// policy is the global policy which is used by logging and tracing decorators.
+var policy int32
+
+// SetPolicy replaces the global policy.
+func SetPolicy(p Policy) {
+	atomic.StoreInt32(&policy, int32(p))
+}
+
+// CurrentPolicy returns the global policy.
+func CurrentPolicy() Policy {
+	return Policy(atomic.LoadInt32(&policy))
+}
+
+// String returns sensitive value according to the global policy.
+func String(value string) string {
+	return apply(CurrentPolicy(), func() string { return value })
+}
+
+// Secret returns SecretString according to the global policy. Secret is decrypted only when policy requires it.
+func Secret(secret banking.SecretString) string {
+	if secret == nil {
+		return ""
+	}
+
+	return apply(CurrentPolicy(), secret.DecryptedString)
+}
+
+// IsRedacted reports whether value was already replaced according to PolicyRedacted or PolicyFingerprint, so it
+// must not be replaced again.
+func IsRedacted(value string) bool {
+	return value == Redacted || strings.HasPrefix(value, fingerprintPrefix)
+}
+
+func apply(p Policy, value func() string) string {
+	if p == PolicyFingerprint {
+		return Fingerprint(value())
+	}
+
+	if p == PolicyFull {
+		return value()
+	}
+
+	return Redacted
+}
+
+var (
+	fingerprintKeyOnce sync.Once
+	fingerprintKey     []byte
+)
+
+// Fingerprint returns the keyed hash of value. Key is generated once per process, so fingerprints are stable within
+// a single replica and could not be brute forced for low entropy values like email addresses.
+func Fingerprint(value string) string {
+	fingerprintKeyOnce.Do(func() {
+		key := make([]byte, sha256.Size)
+
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return
+		}
+
+		fingerprintKey = key
+	})
+
+	if fingerprintKey == nil {
+		return Redacted
+	}
+
+	mac := hmac.New(sha256.New, fingerprintKey)
+	_, _ = mac.Write([]byte(value))
+
+	return fingerprintPrefix + hex.EncodeToString(mac.Sum(nil)[:fingerprintLength])
+}
@@ -0,0 +1,252 @@
+package redact_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/morozovcookie/agat-banking/redact"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePolicy(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		name string
+	}
+	type wants struct {
+		policy redact.Policy
+		err    error
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "redacted",
+				enabled: true,
+			},
+			args: args{
+				name: "redacted",
+			},
+			wants: wants{
+				policy: redact.PolicyRedacted,
+				err:    nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "fingerprint",
+				enabled: true,
+			},
+			args: args{
+				name: "fingerprint",
+			},
+			wants: wants{
+				policy: redact.PolicyFingerprint,
+				err:    nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "full",
+				enabled: true,
+			},
+			args: args{
+				name: "full",
+			},
+			wants: wants{
+				policy: redact.PolicyFull,
+				err:    nil,
+			},
+		},
+		{
+			meta: meta{
+				name:    "unknown",
+				enabled: true,
+			},
+			args: args{
+				name: "plain",
+			},
+			wants: wants{
+				policy: redact.PolicyRedacted,
+				err:    redact.ErrUnknownPolicy,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			policy, err := redact.ParsePolicy(tt.args.name)
+
+			assert.Equal(t, tt.wants.policy, policy)
+			assert.True(t, errors.Is(err, tt.wants.err))
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		policy redact.Policy
+		value  string
+	}
+	type wants struct {
+		check func(t *testing.T, value, redacted string)
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "redacted",
+				enabled: true,
+			},
+			args: args{
+				policy: redact.PolicyRedacted,
+				value:  "user@example.com",
+			},
+			wants: wants{
+				check: func(t *testing.T, _, redacted string) {
+					assert.Equal(t, redact.Redacted, redacted)
+				},
+			},
+		},
+		{
+			meta: meta{
+				name:    "fingerprint",
+				enabled: true,
+			},
+			args: args{
+				policy: redact.PolicyFingerprint,
+				value:  "user@example.com",
+			},
+			wants: wants{
+				check: func(t *testing.T, value, redacted string) {
+					assert.True(t, strings.HasPrefix(redacted, "fp:"))
+					assert.NotContains(t, redacted, value)
+					assert.Equal(t, redact.Fingerprint(value), redacted)
+					assert.NotEqual(t, redact.Fingerprint("admin@example.com"), redacted)
+				},
+			},
+		},
+		{
+			meta: meta{
+				name:    "full",
+				enabled: true,
+			},
+			args: args{
+				policy: redact.PolicyFull,
+				value:  "user@example.com",
+			},
+			wants: wants{
+				check: func(t *testing.T, value, redacted string) {
+					assert.Equal(t, value, redacted)
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			redact.SetPolicy(tt.args.policy)
+			defer redact.SetPolicy(redact.PolicyRedacted)
+
+			tt.wants.check(t, tt.args.value, redact.String(tt.args.value))
+		})
+	}
+}
+
+func TestSecret(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		policy              redact.Policy
+		setupSecretStringFn func() *mock.SecretString
+	}
+	type wants struct {
+		value string
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "redacted without decryption",
+				enabled: true,
+			},
+			args: args{
+				policy: redact.PolicyRedacted,
+				setupSecretStringFn: func() *mock.SecretString {
+					return mock.NewSecretString()
+				},
+			},
+			wants: wants{
+				value: redact.Redacted,
+			},
+		},
+		{
+			meta: meta{
+				name:    "full",
+				enabled: true,
+			},
+			args: args{
+				policy: redact.PolicyFull,
+				setupSecretStringFn: func() *mock.SecretString {
+					ss := mock.NewSecretString()
+
+					ss.On("DecryptedString").
+						Return("decrypted string")
+
+					return ss
+				},
+			},
+			wants: wants{
+				value: "decrypted string",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			redact.SetPolicy(tt.args.policy)
+			defer redact.SetPolicy(redact.PolicyRedacted)
+
+			ss := tt.args.setupSecretStringFn()
+
+			assert.Equal(t, tt.wants.value, redact.Secret(ss))
+
+			ss.AssertExpectations(t)
+		})
+	}
+}
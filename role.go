@@ -0,0 +1,212 @@
+package banking
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrRoleUnknown will be raised when role is not one of the predefined roles.
+	ErrRoleUnknown = errors.New("role unknown")
+
+	// ErrPermissionDenied will be raised when none of the user account roles grants the required permission.
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// RolesClaim is the name of access token private claim which contains the user account roles.
+const RolesClaim = "roles"
+
+// Permission represents an action which could be performed with the kind of objects. It is written as
+// "<object>:<action>" (e.g. "ledger:write").
+type Permission string
+
+const (
+	// PermissionUsersRead allows to find users.
+	PermissionUsersRead Permission = "users:read"
+
+	// PermissionUsersWrite allows to create, update and delete users.
+	PermissionUsersWrite Permission = "users:write"
+
+	// PermissionAccountsRead allows to find user accounts.
+	PermissionAccountsRead Permission = "accounts:read"
+
+	// PermissionAccountsWrite allows to create, update and disable user accounts.
+	PermissionAccountsWrite Permission = "accounts:write"
+
+	// PermissionRolesRead allows to find roles which are assigned to user accounts.
+	PermissionRolesRead Permission = "roles:read"
+
+	// PermissionRolesWrite allows to assign and revoke roles.
+	PermissionRolesWrite Permission = "roles:write"
+
+	// PermissionLockoutsWrite allows to unlock user accounts.
+	PermissionLockoutsWrite Permission = "lockouts:write"
+
+	// PermissionCashWrite allows to register cash receipts and disbursements.
+	PermissionCashWrite Permission = "cash:write"
+
+	// PermissionLedgerRead allows to find ledger entries.
+	PermissionLedgerRead Permission = "ledger:read"
+
+	// PermissionLedgerWrite allows to post and correct ledger entries.
+	PermissionLedgerWrite Permission = "ledger:write"
+
+	// PermissionReportsRead allows to build financial reports.
+	PermissionReportsRead Permission = "reports:read"
+
+	// PermissionAuditRead allows to find audit records.
+	PermissionAuditRead Permission = "audit:read"
+)
+
+func (p Permission) String() string {
+	return string(p)
+}
+
+// Role represents a named set of permissions which is assigned to user account.
+type Role string
+
+const (
+	// RoleAdmin manages users, user accounts and their roles, but has no access to the money.
+	RoleAdmin Role = "admin"
+
+	// RoleCashier registers cash operations and could see the ledger, but could not correct it.
+	RoleCashier Role = "cashier"
+
+	// RoleAccountant keeps the ledger and builds reports.
+	RoleAccountant Role = "accountant"
+
+	// RoleAuditor could see everything, but could not change anything.
+	RoleAuditor Role = "auditor"
+)
+
+// rolePermissions contains permissions which are granted by each role. Roles are kept separate on purpose: nobody
+// but accountant could change the ledger and nobody but auditor could see the audit records.
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionUsersRead,
+		PermissionUsersWrite,
+		PermissionAccountsRead,
+		PermissionAccountsWrite,
+		PermissionRolesRead,
+		PermissionRolesWrite,
+		PermissionLockoutsWrite,
+	},
+	RoleCashier: {
+		PermissionCashWrite,
+		PermissionLedgerRead,
+	},
+	RoleAccountant: {
+		PermissionLedgerRead,
+		PermissionLedgerWrite,
+		PermissionReportsRead,
+	},
+	RoleAuditor: {
+		PermissionUsersRead,
+		PermissionAccountsRead,
+		PermissionRolesRead,
+		PermissionLedgerRead,
+		PermissionReportsRead,
+		PermissionAuditRead,
+	},
+}
+
+// ParseRole returns Role by its name.
+func ParseRole(name string) (Role, error) {
+	if _, ok := rolePermissions[Role(name)]; !ok {
+		return "", errors.Wrapf(ErrRoleUnknown, "parse role %q", name)
+	}
+
+	return Role(name), nil
+}
+
+func (r Role) String() string {
+	return string(r)
+}
+
+// Permissions returns permissions which are granted by role. Unknown role grants nothing.
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+// HasPermission returns true if role grants the permission.
+func (r Role) HasPermission(permission Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Roles represents a set of roles which is assigned to user account.
+type Roles []Role
+
+// HasPermission returns true if any of roles grants the permission.
+func (rr Roles) HasPermission(permission Permission) bool {
+	for _, role := range rr {
+		if role.HasPermission(permission) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Strings returns role names, so they could be placed into the token claim.
+func (rr Roles) Strings() []string {
+	names := make([]string, 0, len(rr))
+
+	for _, role := range rr {
+		names = append(names, role.String())
+	}
+
+	return names
+}
+
+// RolesFromToken returns roles from the token claim. Unknown roles are skipped, so removed role could not grant any
+// permission even if it is still present in the token which was issued earlier.
+func RolesFromToken(token Token) Roles {
+	value, ok := token.Claim(RolesClaim)
+	if !ok {
+		return nil
+	}
+
+	var names []string
+
+	switch vv := value.(type) {
+	case []string:
+		names = vv
+	case []interface{}:
+		for _, v := range vv {
+			if name, ok := v.(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	roles := make(Roles, 0, len(names))
+
+	for _, name := range names {
+		if role, err := ParseRole(name); err == nil {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
+
+// RoleService represents a service for managing roles which are assigned to user accounts.
+type RoleService interface {
+	// FindUserAccountRoles returns roles which are assigned to the UserAccount with specified identifier.
+	FindUserAccountRoles(ctx context.Context, accountID ID) (Roles, error)
+
+	// AssignRole grants role to the UserAccount with specified identifier. Assigning the same role twice is not an
+	// error.
+	AssignRole(ctx context.Context, accountID ID, role Role) error
+
+	// RevokeRole takes role away from the UserAccount with specified identifier. Revoking role which is not assigned is
+	// not an error.
+	RevokeRole(ctx context.Context, accountID ID, role Role) error
+}
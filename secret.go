@@ -2,6 +2,7 @@ package banking
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
 	"io"
 )
@@ -24,4 +25,112 @@ type SecretFactory interface {
 
 	// CreateFromDecryptedData creates SecretString object from decrypted data.
 	CreateFromDecryptedData(ctx context.Context, r io.Reader) (SecretString, error)
+
+	// CreateBoundFromEncryptedData creates SecretString object from encrypted data which was bound to the associated
+	// data. Decryption fails if associated data does not match the one which data was encrypted with.
+	CreateBoundFromEncryptedData(ctx context.Context, r io.Reader, ad AssociatedData) (SecretString, error)
+
+	// CreateBoundFromDecryptedData creates SecretString object from decrypted data and binds encrypted data to the
+	// associated data, so encrypted value could not be copied into another column or record.
+	CreateBoundFromDecryptedData(ctx context.Context, r io.Reader, ad AssociatedData) (SecretString, error)
+}
+
+// AssociatedData represents the place which encrypted secret is stored in. It is authenticated, but not encrypted,
+// along with the secret. The zero value means that secret is not bound to any place.
+type AssociatedData struct {
+	// Table is the name of table which secret is stored in.
+	Table string
+
+	// Column is the name of column which secret is stored in.
+	Column string
+
+	// RecordID is the identifier of record which secret belongs to.
+	RecordID string
+}
+
+// Bytes returns the unambiguous binary representation of associated data: every field is prefixed by its length.
+// Returns nil for the zero value, so unbound secrets stay compatible with secrets which were encrypted before.
+func (ad AssociatedData) Bytes() []byte {
+	if ad == (AssociatedData{}) {
+		return nil
+	}
+
+	const lengthSize = 4
+
+	bb := make([]byte, 0, 3*lengthSize+len(ad.Table)+len(ad.Column)+len(ad.RecordID))
+
+	for _, field := range []string{ad.Table, ad.Column, ad.RecordID} {
+		var length [lengthSize]byte
+
+		binary.BigEndian.PutUint32(length[:], uint32(len(field)))
+
+		bb = append(append(bb, length[:]...), field...)
+	}
+
+	return bb
+}
+
+// SecretRewrapper represents a service which re-encrypts stored secrets after encryption key rotation.
+type SecretRewrapper interface {
+	// NeedsRewrap returns true if encrypted data was not encrypted by the current primary key.
+	NeedsRewrap(encrypted string) bool
+
+	// Rewrap decrypts data by any known key and encrypts it again by the current primary key. Associated data must
+	// match the one which data was encrypted with.
+	Rewrap(ctx context.Context, encrypted string, ad AssociatedData) (string, error)
+}
+
+// EncryptedValue represents the encrypted value which is stored in the single row of encrypted column.
+type EncryptedValue struct {
+	// RowID is the identifier of row which value is stored in.
+	RowID uint64
+
+	// Value is the encrypted data.
+	Value string
+
+	// AssociatedData is the associated data which value is bound to. It is the zero value for unbound column.
+	AssociatedData AssociatedData
+}
+
+// EncryptedColumnService represents a service for re-encrypting values of the single encrypted column.
+type EncryptedColumnService interface {
+	// FindEncryptedValues returns the page of values which are stored in rows with identifier greater than afterRowID,
+	// in order of row identifier.
+	FindEncryptedValues(ctx context.Context, afterRowID uint64, opts FindOptions) ([]EncryptedValue, error)
+
+	// ReplaceEncryptedValue replaces value only if it was not changed since it was read. Returns false if value was
+	// changed concurrently.
+	ReplaceEncryptedValue(ctx context.Context, value EncryptedValue, encrypted string) (bool, error)
+}
+
+// PlaintextValue represents the value which was stored in column before column encryption was enabled.
+type PlaintextValue struct {
+	// RowID is the identifier of row which value is stored in.
+	RowID uint64
+
+	// Value is the plaintext data.
+	Value string
+
+	// AssociatedData is the associated data which value has to be bound to. It is the zero value for unbound column.
+	AssociatedData AssociatedData
+}
+
+// PlaintextColumnService represents a service for encrypting values which were stored in column before column
+// encryption was enabled. Such values are recognized by the missing blind index.
+type PlaintextColumnService interface {
+	// FindPlaintextValues returns the page of plaintext values which are stored in rows with identifier greater than
+	// afterRowID, in order of row identifier.
+	FindPlaintextValues(ctx context.Context, afterRowID uint64, opts FindOptions) ([]PlaintextValue, error)
+
+	// EncryptPlaintextValue replaces plaintext value by encrypted one and sets up its blind index, only if value was
+	// not changed since it was read. Returns false if value was changed concurrently.
+	EncryptPlaintextValue(ctx context.Context, value PlaintextValue, encrypted string, blindIndex string) (bool, error)
+}
+
+// BlindIndexer represents a service which computes keyed digest of secret, so encrypted column could be looked up by
+// equality without decryption.
+type BlindIndexer interface {
+	// BlindIndex returns the digest of value. Digest depends on table and column, so the same value has different
+	// digests in different columns.
+	BlindIndex(ctx context.Context, table, column, value string) (string, error)
 }
@@ -0,0 +1,98 @@
+package sql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+var (
+	_ banking.SecretString = (*SecretString)(nil)
+	_ sql.Scanner          = (*SecretString)(nil)
+	_ driver.Valuer        = (*SecretString)(nil)
+
+	// ErrUnsupportedType will be raised when column value could not be scanned into SecretString.
+	ErrUnsupportedType = errors.New("unsupported type")
+)
+
+// SecretString represents an object that stores sensitive information in database column. Only encrypted string is
+// written into column, and value which is read from column is decrypted by SecretFactory.
+type SecretString struct {
+	wrapped       banking.SecretString
+	secretFactory banking.SecretFactory
+	ad            banking.AssociatedData
+}
+
+// NewSecretString returns a new SecretString instance. Encrypted value is bound to associated data, pass the zero
+// value for unbound column.
+func NewSecretString(
+	wrapped banking.SecretString,
+	secretFactory banking.SecretFactory,
+	ad banking.AssociatedData,
+) *SecretString {
+	return &SecretString{
+		wrapped:       wrapped,
+		secretFactory: secretFactory,
+		ad:            ad,
+	}
+}
+
+// Scan decrypts the column value. NULL is scanned into empty SecretString.
+func (ss *SecretString) Scan(src interface{}) error {
+	var encrypted []byte
+
+	switch value := src.(type) {
+	case nil:
+		ss.wrapped = nil
+
+		return nil
+	case string:
+		encrypted = []byte(value)
+	case []byte:
+		encrypted = value
+	default:
+		return errors.Wrapf(ErrUnsupportedType, "scan SecretString: %T", src)
+	}
+
+	wrapped, err := ss.secretFactory.CreateBoundFromEncryptedData(context.Background(), bytes.NewBuffer(encrypted),
+		ss.ad)
+	if err != nil {
+		return errors.Wrap(err, "scan SecretString")
+	}
+
+	ss.wrapped = wrapped
+
+	return nil
+}
+
+// Value returns the encrypted string. Empty SecretString is stored as NULL.
+func (ss *SecretString) Value() (driver.Value, error) {
+	if ss.wrapped == nil {
+		return nil, nil
+	}
+
+	return ss.wrapped.EncryptedString(), nil
+}
+
+func (ss *SecretString) String() string {
+	return ss.wrapped.String()
+}
+
+// IsEmpty reports whether sensitive information was not set (e.g. column value was NULL).
+func (ss *SecretString) IsEmpty() bool {
+	return ss.wrapped == nil
+}
+
+// EncryptedString returns sensitive information with encrypted string.
+func (ss *SecretString) EncryptedString() string {
+	return ss.wrapped.EncryptedString()
+}
+
+// DecryptedString returns sensitive information with decrypted string.
+func (ss *SecretString) DecryptedString() string {
+	return ss.wrapped.DecryptedString()
+}
@@ -0,0 +1,180 @@
+package sql_test
+
+import (
+	"bytes"
+	"testing"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/morozovcookie/agat-banking/sql"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+var testAssociatedData = banking.AssociatedData{
+	Table:    "user_accounts",
+	Column:   "email_address",
+	RecordID: "1s8cy82tkt0s2ejaz4nlq3a0i",
+}
+
+func TestSecretString_Scan(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		setupSecretFactoryFn func() *mock.SecretFactory
+	}
+	type args struct {
+		src interface{}
+	}
+	type wants struct {
+		empty           bool
+		decryptedString string
+
+		err bool
+	}
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "pass",
+				enabled: true,
+			},
+			fields: fields{
+				setupSecretFactoryFn: func() *mock.SecretFactory {
+					ss := mock.NewSecretString()
+
+					ss.On("DecryptedString").
+						Return("john.doe@example.com")
+
+					factory := mock.NewSecretFactory()
+
+					factory.On("CreateBoundFromEncryptedData", bytes.NewBufferString("encrypted"),
+						testAssociatedData).
+						Return(ss, (error)(nil))
+
+					return factory
+				},
+			},
+			args: args{
+				src: []byte("encrypted"),
+			},
+			wants: wants{
+				empty:           false,
+				decryptedString: "john.doe@example.com",
+
+				err: false,
+			},
+		},
+		{
+			meta: meta{
+				name:    "null",
+				enabled: true,
+			},
+			fields: fields{
+				setupSecretFactoryFn: mock.NewSecretFactory,
+			},
+			args: args{
+				src: nil,
+			},
+			wants: wants{
+				empty:           true,
+				decryptedString: "",
+
+				err: false,
+			},
+		},
+		{
+			meta: meta{
+				name:    "mismatched associated data",
+				enabled: true,
+			},
+			fields: fields{
+				setupSecretFactoryFn: func() *mock.SecretFactory {
+					factory := mock.NewSecretFactory()
+
+					factory.On("CreateBoundFromEncryptedData", testifymock.Anything, testAssociatedData).
+						Return((*mock.SecretString)(nil), errors.New("cipher: message authentication failed"))
+
+					return factory
+				},
+			},
+			args: args{
+				src: "encrypted",
+			},
+			wants: wants{
+				empty:           true,
+				decryptedString: "",
+
+				err: true,
+			},
+		},
+		{
+			meta: meta{
+				name:    "unsupported type",
+				enabled: true,
+			},
+			fields: fields{
+				setupSecretFactoryFn: mock.NewSecretFactory,
+			},
+			args: args{
+				src: int64(1),
+			},
+			wants: wants{
+				empty:           true,
+				decryptedString: "",
+
+				err: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			factory := tt.fields.setupSecretFactoryFn()
+
+			ss := sql.NewSecretString(nil, factory, testAssociatedData)
+
+			err := ss.Scan(tt.args.src)
+			assert.Equal(t, tt.wants.err, err != nil)
+
+			if err == nil {
+				assert.Equal(t, tt.wants.empty, ss.IsEmpty())
+			}
+
+			if !ss.IsEmpty() {
+				assert.Equal(t, tt.wants.decryptedString, ss.DecryptedString())
+			}
+
+			factory.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSecretString_Value(t *testing.T) {
+	ss := mock.NewSecretString()
+
+	ss.On("EncryptedString").
+		Return("encrypted")
+
+	value, err := sql.NewSecretString(ss, nil, testAssociatedData).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "encrypted", value)
+
+	value, err = sql.NewSecretString(nil, nil, testAssociatedData).Value()
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	ss.AssertExpectations(t)
+}
@@ -9,8 +9,39 @@ import (
 	"github.com/pkg/errors"
 )
 
-// ErrTokenDoesNotExist will be raised when token could not be found.
-var ErrTokenDoesNotExist = errors.New("token does not exist")
+var (
+	// ErrTokenDoesNotExist will be raised when token could not be found.
+	ErrTokenDoesNotExist = errors.New("token does not exist")
+
+	// ErrTokenReused will be raised when token which was already rotated is presented again. It means that token
+	// could be stolen, so every token from the same rotation family should be revoked.
+	ErrTokenReused = errors.New("token reused")
+
+	// ErrTokenMalformed will be raised when token could not be decoded.
+	ErrTokenMalformed = errors.New("token malformed")
+
+	// ErrTokenInvalidSignature will be raised when token signature could not be verified.
+	ErrTokenInvalidSignature = errors.New("token invalid signature")
+
+	// ErrTokenExpired will be raised when token expiration time has passed.
+	ErrTokenExpired = errors.New("token expired")
+
+	// ErrTokenNotYetValid will be raised when token is used before its not before time or before it was issued.
+	ErrTokenNotYetValid = errors.New("token not yet valid")
+
+	// ErrTokenInvalidIssuer will be raised when token was issued by unexpected issuer.
+	ErrTokenInvalidIssuer = errors.New("token invalid issuer")
+
+	// ErrTokenInvalidAudience will be raised when token was not issued for the expected audience.
+	ErrTokenInvalidAudience = errors.New("token invalid audience")
+
+	// ErrTokenReservedClaim will be raised when registered or service claim is set as private claim.
+	ErrTokenReservedClaim = errors.New("token reserved claim")
+
+	// ErrTokenWrongType will be raised when token type is not the same as expected (e.g. refresh token was used
+	// instead of access token).
+	ErrTokenWrongType = errors.New("token wrong type")
+)
 
 // TokenType represents an enum which describes a possible types for token.
 type TokenType int
@@ -52,6 +83,15 @@ type Token interface {
 	// Account returns the subject of token.
 	Account() *UserAccount
 
+	// Issuer returns the principal that issued the token.
+	Issuer() string
+
+	// Audience returns the recipients that the token is intended for.
+	Audience() []string
+
+	// Claim returns the private claim value (e.g. roles or session identifier).
+	Claim(name string) (interface{}, bool)
+
 	// IssuedAt returns the UTC time when token was issued.
 	IssuedAt() time.Time
 
@@ -77,6 +117,20 @@ type TokenBuilder interface {
 	// WithAccount sets up the subject of token.
 	WithAccount(sub *UserAccount) TokenBuilder
 
+	// WithIssuer sets up the principal that issued the token.
+	WithIssuer(iss string) TokenBuilder
+
+	// WithAudience sets up the recipients that the token is intended for.
+	WithAudience(aud ...string) TokenBuilder
+
+	// WithClaim sets up the private claim (e.g. roles or session identifier). Registered claims could not be set up this
+	// way, Build will return ErrTokenReservedClaim.
+	WithClaim(name string, value interface{}) TokenBuilder
+
+	// WithLifetime sets up the time duration which token will be valid since it did issue. It overrides the default
+	// lifetime for the single token, but is ignored if expiration was set up explicitly.
+	WithLifetime(lifetime time.Duration) TokenBuilder
+
 	// WithIssuedAt sets up the UTC time when token was issued.
 	WithIssuedAt(iat time.Time) TokenBuilder
 
@@ -103,6 +157,8 @@ type TokenBuilderCreator interface {
 // TokenParser represents a service for parsing token.
 type TokenParser interface {
 	// ParseToken parse and returns a Token.
+	// Return ErrTokenMalformed, ErrTokenInvalidSignature, ErrTokenExpired, ErrTokenNotYetValid, ErrTokenWrongType,
+	// ErrTokenInvalidIssuer or ErrTokenInvalidAudience if token could not be accepted.
 	ParseToken(ctx context.Context, r io.Reader) (Token, error)
 }
 
@@ -116,8 +172,27 @@ type TokenService interface {
 	ExpireToken(ctx context.Context, id ID) (Token, error)
 
 	// FindTokenByID returns a single Token.
+	// Return ErrTokenReused if Token was already rotated.
 	FindTokenByID(ctx context.Context, id ID) (Token, error)
 
+	// RotateToken expires Token with specified identifier and stores the next Token from the same rotation family.
+	// Return ErrTokenReused if Token was already rotated.
+	RotateToken(ctx context.Context, id ID, next Token) error
+
+	// ExpireTokenFamily expires every Token from the same rotation family as Token with specified identifier.
+	ExpireTokenFamily(ctx context.Context, id ID) error
+
+	// ExpireUserAccountTokens expires every Token of the user account with specified identifier.
+	ExpireUserAccountTokens(ctx context.Context, accountID ID) error
+
+	// ExpireTokensIssuedBefore expires every Token which was issued before specified time (e.g. when signing key was
+	// compromised).
+	ExpireTokensIssuedBefore(ctx context.Context, before time.Time) error
+
+	// FindUserAccountTokens returns active tokens of the user account with specified identifier, the most recently
+	// issued first.
+	FindUserAccountTokens(ctx context.Context, accountID ID, opts FindOptions) ([]Token, error)
+
 	// RemoveExpiredTokens removes expired tokens.
 	// Return tokens list after remove.
 	RemoveExpiredTokens(ctx context.Context, opts FindOptions) ([]Token, error)
@@ -0,0 +1,13 @@
+package banking
+
+import (
+	"context"
+)
+
+// TransactionManager represents a service for running multi-step operations atomically.
+type TransactionManager interface {
+	// WithinTransaction calls fn with context which carries the active transaction. Every service call made with this
+	// context is a part of transaction, which is committed when fn returns nil and is rolled back otherwise. Nested
+	// calls are isolated with savepoints.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
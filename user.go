@@ -1,7 +1,18 @@
 package banking
 
 import (
+	"context"
 	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrUserDoesNotExist will be raised when user could not be found.
+	ErrUserDoesNotExist = errors.New("user does not exist")
+
+	// ErrUserHasAccounts will be raised when user could not be deleted because it still owns user accounts.
+	ErrUserHasAccounts = errors.New("user has accounts")
 )
 
 // User represents an information set about user.
@@ -21,3 +32,22 @@ type User struct {
 	// UpdatedAt is the time when user was updated.
 	UpdatedAt time.Time
 }
+
+// UserService represents a service for managing User data.
+type UserService interface {
+	// CreateUser stores a new User. User.ID and User.CreatedAt are set on success.
+	CreateUser(ctx context.Context, user *User) error
+
+	// FindUserByID returns User by User.ID.
+	FindUserByID(ctx context.Context, id ID) (*User, error)
+
+	// FindUsers returns the page of users in order of creation.
+	FindUsers(ctx context.Context, opts FindOptions) ([]*User, error)
+
+	// UpdateUser replaces User.FirstName and User.LastName. User.UpdatedAt is set on success.
+	UpdateUser(ctx context.Context, user *User) error
+
+	// DeleteUser removes User with specified identifier.
+	// Return ErrUserHasAccounts if User still owns user accounts.
+	DeleteUser(ctx context.Context, id ID) error
+}
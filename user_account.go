@@ -7,8 +7,17 @@ import (
 	"github.com/pkg/errors"
 )
 
-// ErrUserAccountDoesNotExist will be raised when user account could not be found.
-var ErrUserAccountDoesNotExist = errors.New("user account does not exist")
+var (
+	// ErrUserAccountDoesNotExist will be raised when user account could not be found.
+	ErrUserAccountDoesNotExist = errors.New("user account does not exist")
+
+	// ErrUserAccountAlreadyExists will be raised when user account with the same username or email address already
+	// exists.
+	ErrUserAccountAlreadyExists = errors.New("user account already exists")
+
+	// ErrUserAccountDisabled will be raised when disabled user account is used for sign in.
+	ErrUserAccountDisabled = errors.New("user account disabled")
+)
 
 // UserAccount represents a user account information.
 type UserAccount struct {
@@ -32,11 +41,28 @@ type UserAccount struct {
 
 	// UpdatedAt is the time when user account was updated.
 	UpdateAt time.Time
+
+	// DisabledAt is the time when user account was disabled, it is zero for active user account.
+	DisabledAt time.Time
+}
+
+// IsDisabled returns true if user account could not be used for sign in.
+func (ua *UserAccount) IsDisabled() bool {
+	return !ua.DisabledAt.IsZero()
 }
 
 // ComparePassword returns true if passed password value and stored password value are the same.
-func (ua *UserAccount) ComparePassword(password SecretString) bool {
-	return false
+func (ua *UserAccount) ComparePassword(ctx context.Context, hasher PasswordHasher, password SecretString) (bool, error) {
+	if password == nil {
+		return false, nil
+	}
+
+	isSame, err := hasher.ComparePassword(ctx, ua.PasswordHash, password)
+	if err != nil {
+		return false, errors.Wrap(err, "compare password")
+	}
+
+	return isSame, nil
 }
 
 // UserAccountService represents a service for managing UserAccount data.
@@ -46,4 +72,27 @@ type UserAccountService interface {
 
 	// FindUserAccountByUserName returns UserAccount by UserAccount.UserName.
 	FindUserAccountByUserName(ctx context.Context, userName string) (*UserAccount, error)
+
+	// UpdateUserAccountPasswordHash replaces UserAccount.PasswordHash of the UserAccount with specified identifier.
+	UpdateUserAccountPasswordHash(ctx context.Context, id ID, passwordHash string) error
+
+	// CreateUserAccount stores a new UserAccount with already hashed password. UserAccount.ID and
+	// UserAccount.CreatedAt are set on success.
+	// Return ErrUserAccountAlreadyExists if username or email address is already used.
+	CreateUserAccount(ctx context.Context, account *UserAccount) error
+
+	// FindUserAccountByID returns UserAccount by UserAccount.ID.
+	FindUserAccountByID(ctx context.Context, id ID) (*UserAccount, error)
+
+	// FindUserAccounts returns the page of user accounts in order of creation.
+	FindUserAccounts(ctx context.Context, opts FindOptions) ([]*UserAccount, error)
+
+	// UpdateUserAccount replaces UserAccount.UserName and UserAccount.EmailAddress. UserAccount.UpdateAt is set on
+	// success.
+	// Return ErrUserAccountAlreadyExists if username or email address is already used.
+	UpdateUserAccount(ctx context.Context, account *UserAccount) error
+
+	// DisableUserAccount forbids sign in to the UserAccount with specified identifier. Disabled user account is kept,
+	// so its history is not lost.
+	DisableUserAccount(ctx context.Context, id ID) error
 }
@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+var _ Job = (*ColumnEncryptor)(nil)
+
+// ColumnEncryptor represents a job which encrypts values that were stored before column encryption was enabled and
+// sets up their blind indexes. When the job reports that nothing was encrypted, every value of column is encrypted.
+type ColumnEncryptor struct {
+	secretFactory banking.SecretFactory
+	blindIndexer  banking.BlindIndexer
+	columns       []PlaintextColumn
+
+	batchSize uint64
+}
+
+// PlaintextColumn represents the column which values are encrypted by ColumnEncryptor.
+type PlaintextColumn struct {
+	// Table is the name of table, it is used for blind index computing.
+	Table string
+
+	// Column is the name of column, it is used for blind index computing.
+	Column string
+
+	// Service is the service for reading and replacing column values.
+	Service banking.PlaintextColumnService
+}
+
+// NewColumnEncryptor returns a new ColumnEncryptor instance.
+func NewColumnEncryptor(
+	secretFactory banking.SecretFactory,
+	blindIndexer banking.BlindIndexer,
+	columns []PlaintextColumn,
+	opts ...ColumnEncryptorOption,
+) *ColumnEncryptor {
+	encryptor := &ColumnEncryptor{
+		secretFactory: secretFactory,
+		blindIndexer:  blindIndexer,
+		columns:       columns,
+
+		batchSize: DefaultEncryptionBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt.apply(encryptor)
+	}
+
+	return encryptor
+}
+
+// Run walks through every column batch by batch and encrypts plaintext values. Value which was changed concurrently
+// is skipped, because it has been already encrypted.
+func (e *ColumnEncryptor) Run(ctx context.Context) (int64, error) {
+	var encrypted int64
+
+	for _, column := range e.columns {
+		n, err := e.encryptColumn(ctx, column)
+		encrypted += n
+
+		if err != nil {
+			return encrypted, errors.Wrap(err, "encrypt columns")
+		}
+	}
+
+	return encrypted, nil
+}
+
+func (e *ColumnEncryptor) encryptColumn(ctx context.Context, column PlaintextColumn) (int64, error) {
+	var (
+		opts       = banking.NewFindOptions(e.batchSize, 0)
+		afterRowID uint64
+		encrypted  int64
+	)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return encrypted, errors.Wrap(err, "encrypt column")
+		}
+
+		vv, err := column.Service.FindPlaintextValues(ctx, afterRowID, opts)
+		if err != nil {
+			return encrypted, errors.Wrap(err, "encrypt column")
+		}
+
+		for _, value := range vv {
+			afterRowID = value.RowID
+
+			replaced, err := e.encryptValue(ctx, column, value)
+			if err != nil {
+				return encrypted, errors.Wrapf(err, "encrypt column: row %d", value.RowID)
+			}
+
+			if replaced {
+				encrypted++
+			}
+		}
+
+		if uint64(len(vv)) < opts.Limit() {
+			return encrypted, nil
+		}
+	}
+}
+
+func (e *ColumnEncryptor) encryptValue(
+	ctx context.Context,
+	column PlaintextColumn,
+	value banking.PlaintextValue,
+) (
+	bool,
+	error,
+) {
+	secret, err := e.secretFactory.CreateBoundFromDecryptedData(ctx, bytes.NewBufferString(value.Value),
+		value.AssociatedData)
+	if err != nil {
+		return false, errors.Wrap(err, "encrypt value")
+	}
+
+	blindIndex, err := e.blindIndexer.BlindIndex(ctx, column.Table, column.Column, value.Value)
+	if err != nil {
+		return false, errors.Wrap(err, "encrypt value")
+	}
+
+	replaced, err := column.Service.EncryptPlaintextValue(ctx, value, secret.EncryptedString(), blindIndex)
+	if err != nil {
+		return false, errors.Wrap(err, "encrypt value")
+	}
+
+	return replaced, nil
+}
@@ -0,0 +1,26 @@
+package worker
+
+import (
+	banking "github.com/morozovcookie/agat-banking"
+)
+
+// ColumnEncryptorOption represents an option for configure ColumnEncryptor instance.
+type ColumnEncryptorOption interface {
+	apply(e *ColumnEncryptor)
+}
+
+type columnEncryptorOptionFunc func(e *ColumnEncryptor)
+
+func (fn columnEncryptorOptionFunc) apply(e *ColumnEncryptor) {
+	fn(e)
+}
+
+// DefaultEncryptionBatchSize is the default number of rows which are read at once.
+const DefaultEncryptionBatchSize = banking.MaxPageSize
+
+// WithEncryptionBatchSize sets up the number of rows which are read at once. It is limited by banking.MaxPageSize.
+func WithEncryptionBatchSize(size uint64) ColumnEncryptorOption {
+	return columnEncryptorOptionFunc(func(e *ColumnEncryptor) {
+		e.batchSize = size
+	})
+}
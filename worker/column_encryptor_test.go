@@ -0,0 +1,140 @@
+package worker_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/mock"
+	"github.com/morozovcookie/agat-banking/worker"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSecretFactory struct {
+	banking.SecretFactory
+}
+
+func (fakeSecretFactory) CreateBoundFromDecryptedData(
+	_ context.Context,
+	r io.Reader,
+	ad banking.AssociatedData,
+) (
+	banking.SecretString,
+	error,
+) {
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, err
+	}
+
+	ss := mock.NewSecretString()
+
+	ss.On("EncryptedString").
+		Return("enc:" + ad.RecordID + ":" + buf.String())
+
+	return ss, nil
+}
+
+type fakeBlindIndexer struct{}
+
+func (fakeBlindIndexer) BlindIndex(_ context.Context, table, column, value string) (string, error) {
+	return table + "." + column + ":" + value, nil
+}
+
+type plaintextRow struct {
+	value      string
+	blindIndex string
+}
+
+type fakePlaintextColumnService struct {
+	rows map[uint64]*plaintextRow
+	ids  []uint64
+}
+
+func (svc *fakePlaintextColumnService) FindPlaintextValues(
+	_ context.Context,
+	afterRowID uint64,
+	opts banking.FindOptions,
+) (
+	[]banking.PlaintextValue,
+	error,
+) {
+	vv := make([]banking.PlaintextValue, 0, opts.Limit())
+
+	for _, id := range svc.ids {
+		row := svc.rows[id]
+
+		if id > afterRowID && row.blindIndex == "" && uint64(len(vv)) < opts.Limit() {
+			vv = append(vv, banking.PlaintextValue{
+				RowID: id,
+				Value: row.value,
+				AssociatedData: banking.AssociatedData{
+					Table:    "user_accounts",
+					Column:   "email_address",
+					RecordID: row.value[:1],
+				},
+			})
+		}
+	}
+
+	return vv, nil
+}
+
+func (svc *fakePlaintextColumnService) EncryptPlaintextValue(
+	_ context.Context,
+	value banking.PlaintextValue,
+	encrypted string,
+	blindIndex string,
+) (
+	bool,
+	error,
+) {
+	row := svc.rows[value.RowID]
+	if row.value != value.Value || row.blindIndex != "" {
+		return false, nil
+	}
+
+	row.value, row.blindIndex = encrypted, blindIndex
+
+	return true, nil
+}
+
+func TestColumnEncryptor_Run(t *testing.T) {
+	column := &fakePlaintextColumnService{
+		rows: map[uint64]*plaintextRow{
+			1: {value: "a@example.com", blindIndex: ""},
+			2: {value: "enc:b:b@example.com", blindIndex: "user_accounts.email_address:b@example.com"},
+			3: {value: "c@example.com", blindIndex: ""},
+			5: {value: "d@example.com", blindIndex: ""},
+		},
+		ids: []uint64{1, 2, 3, 5},
+	}
+
+	encryptor := worker.NewColumnEncryptor(fakeSecretFactory{}, fakeBlindIndexer{}, []worker.PlaintextColumn{
+		{
+			Table:   "user_accounts",
+			Column:  "email_address",
+			Service: column,
+		},
+	}, worker.WithEncryptionBatchSize(2))
+
+	encrypted, err := encryptor.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), encrypted)
+
+	assert.Equal(t, &plaintextRow{
+		value:      "enc:a:a@example.com",
+		blindIndex: "user_accounts.email_address:a@example.com",
+	}, column.rows[1])
+	assert.Equal(t, &plaintextRow{
+		value:      "enc:d:d@example.com",
+		blindIndex: "user_accounts.email_address:d@example.com",
+	}, column.rows[5])
+
+	// Nothing is left, so the next run does nothing.
+	encrypted, err = encryptor.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), encrypted)
+}
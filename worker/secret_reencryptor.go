@@ -0,0 +1,101 @@
+package worker
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+var _ Job = (*SecretReencryptor)(nil)
+
+// SecretReencryptor represents a job which re-encrypts stored secrets by the primary key after key rotation. When
+// the job reports that nothing was re-encrypted, retiring key could be removed from keyring.
+type SecretReencryptor struct {
+	rewrapper banking.SecretRewrapper
+	columns   []banking.EncryptedColumnService
+
+	batchSize uint64
+}
+
+// NewSecretReencryptor returns a new SecretReencryptor instance.
+func NewSecretReencryptor(
+	rewrapper banking.SecretRewrapper,
+	columns []banking.EncryptedColumnService,
+	opts ...SecretReencryptorOption,
+) *SecretReencryptor {
+	reencryptor := &SecretReencryptor{
+		rewrapper: rewrapper,
+		columns:   columns,
+
+		batchSize: DefaultReencryptionBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt.apply(reencryptor)
+	}
+
+	return reencryptor
+}
+
+// Run walks through every encrypted column batch by batch and rewraps values which were not encrypted by the primary
+// key. Value which was changed concurrently is skipped, because it has been already encrypted by the primary key.
+func (r *SecretReencryptor) Run(ctx context.Context) (int64, error) {
+	var rewrapped int64
+
+	for _, column := range r.columns {
+		n, err := r.reencryptColumn(ctx, column)
+		rewrapped += n
+
+		if err != nil {
+			return rewrapped, errors.Wrap(err, "re-encrypt secrets")
+		}
+	}
+
+	return rewrapped, nil
+}
+
+func (r *SecretReencryptor) reencryptColumn(ctx context.Context, column banking.EncryptedColumnService) (int64, error) {
+	var (
+		opts       = banking.NewFindOptions(r.batchSize, 0)
+		afterRowID uint64
+		rewrapped  int64
+	)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return rewrapped, errors.Wrap(err, "re-encrypt column")
+		}
+
+		vv, err := column.FindEncryptedValues(ctx, afterRowID, opts)
+		if err != nil {
+			return rewrapped, errors.Wrap(err, "re-encrypt column")
+		}
+
+		for _, value := range vv {
+			afterRowID = value.RowID
+
+			if !r.rewrapper.NeedsRewrap(value.Value) {
+				continue
+			}
+
+			encrypted, err := r.rewrapper.Rewrap(ctx, value.Value, value.AssociatedData)
+			if err != nil {
+				return rewrapped, errors.Wrapf(err, "re-encrypt column: row %d", value.RowID)
+			}
+
+			replaced, err := column.ReplaceEncryptedValue(ctx, value, encrypted)
+			if err != nil {
+				return rewrapped, errors.Wrap(err, "re-encrypt column")
+			}
+
+			if replaced {
+				rewrapped++
+			}
+		}
+
+		if uint64(len(vv)) < opts.Limit() {
+			return rewrapped, nil
+		}
+	}
+}
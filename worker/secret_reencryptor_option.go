@@ -0,0 +1,26 @@
+package worker
+
+import (
+	banking "github.com/morozovcookie/agat-banking"
+)
+
+// SecretReencryptorOption represents an option for configure SecretReencryptor instance.
+type SecretReencryptorOption interface {
+	apply(r *SecretReencryptor)
+}
+
+type secretReencryptorOptionFunc func(r *SecretReencryptor)
+
+func (fn secretReencryptorOptionFunc) apply(r *SecretReencryptor) {
+	fn(r)
+}
+
+// DefaultReencryptionBatchSize is the default number of rows which are read at once.
+const DefaultReencryptionBatchSize = banking.MaxPageSize
+
+// WithReencryptionBatchSize sets up the number of rows which are read at once. It is limited by banking.MaxPageSize.
+func WithReencryptionBatchSize(size uint64) SecretReencryptorOption {
+	return secretReencryptorOptionFunc(func(r *SecretReencryptor) {
+		r.batchSize = size
+	})
+}
@@ -0,0 +1,161 @@
+package worker_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/worker"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSecretRewrapper struct{}
+
+func (fakeSecretRewrapper) NeedsRewrap(encrypted string) bool {
+	return strings.HasPrefix(encrypted, "old:")
+}
+
+func (fakeSecretRewrapper) Rewrap(_ context.Context, encrypted string, _ banking.AssociatedData) (string, error) {
+	return "new:" + strings.TrimPrefix(encrypted, "old:"), nil
+}
+
+type fakeEncryptedColumnService struct {
+	rows []banking.EncryptedValue
+
+	// changed is the value which is replaced concurrently right after it was read.
+	changed uint64
+}
+
+func (svc *fakeEncryptedColumnService) FindEncryptedValues(
+	_ context.Context,
+	afterRowID uint64,
+	opts banking.FindOptions,
+) (
+	[]banking.EncryptedValue,
+	error,
+) {
+	vv := make([]banking.EncryptedValue, 0, opts.Limit())
+
+	for _, row := range svc.rows {
+		if row.RowID > afterRowID && uint64(len(vv)) < opts.Limit() {
+			vv = append(vv, row)
+		}
+	}
+
+	return vv, nil
+}
+
+func (svc *fakeEncryptedColumnService) ReplaceEncryptedValue(
+	_ context.Context,
+	value banking.EncryptedValue,
+	encrypted string,
+) (
+	bool,
+	error,
+) {
+	if value.RowID == svc.changed {
+		return false, nil
+	}
+
+	for i := range svc.rows {
+		if svc.rows[i].RowID == value.RowID && svc.rows[i].Value == value.Value {
+			svc.rows[i].Value = encrypted
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func TestSecretReencryptor_Run(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		rows    []banking.EncryptedValue
+		changed uint64
+	}
+	type wants struct {
+		rewrapped int64
+		rows      []banking.EncryptedValue
+		err       bool
+	}
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "rewrap values across batches",
+				enabled: true,
+			},
+			fields: fields{
+				rows: []banking.EncryptedValue{
+					{RowID: 1, Value: "old:a"},
+					{RowID: 2, Value: "new:b"},
+					{RowID: 4, Value: "old:c"},
+				},
+				changed: 0,
+			},
+			wants: wants{
+				rewrapped: 2,
+				rows: []banking.EncryptedValue{
+					{RowID: 1, Value: "new:a"},
+					{RowID: 2, Value: "new:b"},
+					{RowID: 4, Value: "new:c"},
+				},
+				err: false,
+			},
+		},
+		{
+			meta: meta{
+				name:    "skip concurrently changed value",
+				enabled: true,
+			},
+			fields: fields{
+				rows: []banking.EncryptedValue{
+					{RowID: 1, Value: "old:a"},
+					{RowID: 2, Value: "old:b"},
+				},
+				changed: 2,
+			},
+			wants: wants{
+				rewrapped: 1,
+				rows: []banking.EncryptedValue{
+					{RowID: 1, Value: "new:a"},
+					{RowID: 2, Value: "old:b"},
+				},
+				err: false,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.meta.name, func(t *testing.T) {
+			if !test.meta.enabled {
+				t.SkipNow()
+			}
+
+			column := &fakeEncryptedColumnService{
+				rows:    test.fields.rows,
+				changed: test.fields.changed,
+			}
+
+			reencryptor := worker.NewSecretReencryptor(fakeSecretRewrapper{},
+				[]banking.EncryptedColumnService{column}, worker.WithReencryptionBatchSize(2))
+
+			rewrapped, err := reencryptor.Run(context.Background())
+
+			assert.Equal(t, test.wants.err, err != nil)
+			assert.Equal(t, test.wants.rewrapped, rewrapped)
+			assert.Equal(t, test.wants.rows, column.rows)
+		})
+	}
+}
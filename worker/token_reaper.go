@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/pkg/errors"
+)
+
+var _ Job = (*TokenReaper)(nil)
+
+// TokenReaper represents a job which removes expired tokens.
+type TokenReaper struct {
+	tokenService banking.TokenService
+
+	batchSize uint64
+}
+
+// NewTokenReaper returns a new TokenReaper instance.
+func NewTokenReaper(tokenService banking.TokenService, opts ...TokenReaperOption) *TokenReaper {
+	reaper := &TokenReaper{
+		tokenService: tokenService,
+
+		batchSize: DefaultReaperBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt.apply(reaper)
+	}
+
+	return reaper
+}
+
+// Run removes expired tokens batch by batch until none of them remains. Short batches keep row locks short, so
+// tokens could be refreshed while reaper is running.
+func (r *TokenReaper) Run(ctx context.Context) (int64, error) {
+	var (
+		opts    = banking.NewFindOptions(r.batchSize, 0)
+		removed int64
+	)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return removed, errors.Wrap(err, "reap expired tokens")
+		}
+
+		tt, err := r.tokenService.RemoveExpiredTokens(ctx, opts)
+		if err != nil {
+			return removed, errors.Wrap(err, "reap expired tokens")
+		}
+
+		removed += int64(len(tt))
+
+		if uint64(len(tt)) < opts.Limit() {
+			return removed, nil
+		}
+	}
+}
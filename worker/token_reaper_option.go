@@ -0,0 +1,26 @@
+package worker
+
+import (
+	banking "github.com/morozovcookie/agat-banking"
+)
+
+// TokenReaperOption represents an option for configure TokenReaper instance.
+type TokenReaperOption interface {
+	apply(r *TokenReaper)
+}
+
+type tokenReaperOptionFunc func(r *TokenReaper)
+
+func (fn tokenReaperOptionFunc) apply(r *TokenReaper) {
+	fn(r)
+}
+
+// DefaultReaperBatchSize is the default number of tokens which are removed at once.
+const DefaultReaperBatchSize = banking.MaxPageSize
+
+// WithReaperBatchSize sets up the number of tokens which are removed at once. It is limited by banking.MaxPageSize.
+func WithReaperBatchSize(size uint64) TokenReaperOption {
+	return tokenReaperOptionFunc(func(r *TokenReaper) {
+		r.batchSize = size
+	})
+}
@@ -0,0 +1,127 @@
+package worker_test
+
+import (
+	"context"
+	"testing"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/worker"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTokenService struct {
+	banking.TokenService
+
+	batches [][]banking.Token
+	err     error
+	calls   int
+}
+
+func (svc *fakeTokenService) RemoveExpiredTokens(
+	_ context.Context,
+	_ banking.FindOptions,
+) (
+	[]banking.Token,
+	error,
+) {
+	if svc.calls == len(svc.batches) {
+		return nil, svc.err
+	}
+
+	svc.calls++
+
+	return svc.batches[svc.calls-1], nil
+}
+
+func TestTokenReaper_Run(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type fields struct {
+		batches [][]banking.Token
+		err     error
+	}
+	type wants struct {
+		removed int64
+		calls   int
+		err     bool
+	}
+
+	tests := []struct {
+		meta   meta
+		fields fields
+		wants  wants
+	}{
+		{
+			meta: meta{
+				name:    "stop on short batch",
+				enabled: true,
+			},
+			fields: fields{
+				batches: [][]banking.Token{make([]banking.Token, 2), make([]banking.Token, 2), make([]banking.Token, 1)},
+				err:     nil,
+			},
+			wants: wants{
+				removed: 5,
+				calls:   3,
+				err:     false,
+			},
+		},
+		{
+			meta: meta{
+				name:    "nothing to remove",
+				enabled: true,
+			},
+			fields: fields{
+				batches: [][]banking.Token{make([]banking.Token, 0)},
+				err:     nil,
+			},
+			wants: wants{
+				removed: 0,
+				calls:   1,
+				err:     false,
+			},
+		},
+		{
+			meta: meta{
+				name:    "error after full batch",
+				enabled: true,
+			},
+			fields: fields{
+				batches: [][]banking.Token{make([]banking.Token, 2)},
+				err:     errors.New("connection refused"),
+			},
+			wants: wants{
+				removed: 2,
+				calls:   1,
+				err:     true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.meta.name, func(t *testing.T) {
+			if !test.meta.enabled {
+				t.SkipNow()
+			}
+
+			svc := &fakeTokenService{
+				TokenService: nil,
+
+				batches: test.fields.batches,
+				err:     test.fields.err,
+				calls:   0,
+			}
+
+			removed, err := worker.NewTokenReaper(svc, worker.WithReaperBatchSize(2)).Run(context.Background())
+
+			assert.Equal(t, test.wants.err, err != nil)
+			assert.Equal(t, test.wants.removed, removed)
+			assert.Equal(t, test.wants.calls, svc.calls)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Job represents a unit of background work.
+type Job interface {
+	// Run does the work and returns the number of processed items.
+	Run(ctx context.Context) (int64, error)
+}
+
+// JobFunc is an adapter to allow the use of ordinary function as Job.
+type JobFunc func(ctx context.Context) (int64, error)
+
+// Run calls fn(ctx).
+func (fn JobFunc) Run(ctx context.Context) (int64, error) {
+	return fn(ctx)
+}
+
+// Lease represents a lock which is shared between service replicas, so only one of them runs the job.
+type Lease interface {
+	// Acquire tries to acquire the lease without waiting and returns true if it is held by this replica.
+	Acquire(ctx context.Context) (bool, error)
+
+	// Release releases the lease if it is held by this replica.
+	Release(ctx context.Context) error
+}
+
+// Worker represents a service which runs the job on interval while it holds the lease.
+type Worker struct {
+	job   Job
+	lease Lease
+
+	interval     time.Duration
+	errorHandler func(ctx context.Context, err error)
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// NewWorker returns a new Worker instance.
+func NewWorker(job Job, lease Lease, opts ...WorkerOption) *Worker {
+	w := &Worker{
+		job:   job,
+		lease: lease,
+
+		interval:     DefaultInterval,
+		errorHandler: func(ctx context.Context, err error) {},
+
+		stop:     make(chan struct{}),
+		stopOnce: sync.Once{},
+		done:     make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt.apply(w)
+	}
+
+	return w
+}
+
+// Start runs the job immediately and then on every interval until context is canceled or Stop is called. The lease is
+// released before return, so other replica could take over.
+func (w *Worker) Start(ctx context.Context) error {
+	defer close(w.done)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-w.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			if err := w.lease.Release(context.Background()); err != nil {
+				return errors.Wrap(err, "start worker")
+			}
+
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop cancels the running job and waits until worker is stopped.
+func (w *Worker) Stop(ctx context.Context) error {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "stop worker")
+	}
+}
+
+func (w *Worker) runOnce(ctx context.Context) {
+	acquired, err := w.lease.Acquire(ctx)
+	if err != nil {
+		w.errorHandler(ctx, err)
+
+		return
+	}
+
+	if !acquired {
+		return
+	}
+
+	if _, err = w.job.Run(ctx); err != nil && ctx.Err() == nil {
+		w.errorHandler(ctx, err)
+	}
+}
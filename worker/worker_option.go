@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"context"
+	"time"
+)
+
+// WorkerOption represents an option for configure Worker instance.
+type WorkerOption interface {
+	apply(w *Worker)
+}
+
+type workerOptionFunc func(w *Worker)
+
+func (fn workerOptionFunc) apply(w *Worker) {
+	fn(w)
+}
+
+// DefaultInterval is the default time between job runs.
+const DefaultInterval = time.Minute * 10
+
+// WithInterval sets up the time between job runs.
+func WithInterval(interval time.Duration) WorkerOption {
+	return workerOptionFunc(func(w *Worker) {
+		w.interval = interval
+	})
+}
+
+// WithErrorHandler sets up the function which is called with errors of lease and job, because worker could not return
+// them to anyone.
+func WithErrorHandler(fn func(ctx context.Context, err error)) WorkerOption {
+	return workerOptionFunc(func(w *Worker) {
+		w.errorHandler = fn
+	})
+}
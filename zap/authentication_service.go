@@ -4,6 +4,7 @@ import (
 	"context"
 
 	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/redact"
 	"go.uber.org/zap"
 )
 
@@ -38,13 +39,13 @@ func (svc *AuthenticationService) AuthenticateUserByEmail(
 
 	accessToken, refreshToken, err := svc.wrapped.AuthenticateUserByEmail(ctx, email, password)
 
-	logger.Debug("authenticate user by email", zap.String("email", email), zap.Error(err),
-		zap.Stringer("password", password), zap.Stringer("access_token", accessToken),
-		zap.Stringer("refresh_token", refreshToken))
+	logger.Debug("authenticate user by email", zap.String("email", redact.String(email)), zap.Error(err),
+		SecretString("password", password), Token("access_token", accessToken),
+		Token("refresh_token", refreshToken))
 
 	if err != nil {
-		logger.Error("authenticate user by email", zap.String("email", email), zap.Error(err),
-			zap.Stringer("password", password))
+		logger.Error("authenticate user by email", zap.String("email", redact.String(email)), zap.Error(err),
+			SecretString("password", password))
 
 		return nil, nil, err // nolint:wrapcheck
 	}
@@ -68,15 +69,74 @@ func (svc *AuthenticationService) AuthenticateUserByUsername(
 	accessToken, refreshToken, err := svc.wrapped.AuthenticateUserByUsername(ctx, username, password)
 
 	logger.Debug("authenticate user by username", zap.String("username", username), zap.Error(err),
-		zap.Stringer("password", password), zap.Stringer("access_token", accessToken),
-		zap.Stringer("refresh_token", refreshToken))
+		SecretString("password", password), Token("access_token", accessToken),
+		Token("refresh_token", refreshToken))
 
 	if err != nil {
 		logger.Error("authenticate user by username", zap.String("username", username), zap.Error(err),
-			zap.Stringer("password", password))
+			SecretString("password", password))
 
 		return nil, nil, err // nolint:wrapcheck
 	}
 
 	return accessToken, refreshToken, nil
 }
+
+// RefreshToken exchanges refresh token to the new pair of access and refresh tokens.
+func (svc *AuthenticationService) RefreshToken(
+	ctx context.Context,
+	refreshToken banking.SecretString,
+) (
+	banking.Token,
+	banking.Token,
+	error,
+) {
+	logger := svc.loggerCreator.CreateLogger(ctx, "AuthenticationService", "RefreshToken")
+
+	accessToken, nextRefreshToken, err := svc.wrapped.RefreshToken(ctx, refreshToken)
+
+	logger.Debug("refresh token", SecretString("refresh_token", refreshToken), zap.Error(err),
+		Token("access_token", accessToken), Token("next_refresh_token", nextRefreshToken))
+
+	if err != nil {
+		logger.Error("refresh token", SecretString("refresh_token", refreshToken), zap.Error(err))
+
+		return nil, nil, err // nolint:wrapcheck
+	}
+
+	return accessToken, nextRefreshToken, nil
+}
+
+// SignOut expires refresh token, so it could not be used for retrieving new tokens pair anymore.
+func (svc *AuthenticationService) SignOut(ctx context.Context, refreshToken banking.SecretString) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "AuthenticationService", "SignOut")
+
+	err := svc.wrapped.SignOut(ctx, refreshToken)
+
+	logger.Debug("sign out", SecretString("refresh_token", refreshToken), zap.Error(err))
+
+	if err != nil {
+		logger.Error("sign out", SecretString("refresh_token", refreshToken), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// SignOutEverywhere expires every refresh token of the user account which refresh token belongs to.
+func (svc *AuthenticationService) SignOutEverywhere(ctx context.Context, refreshToken banking.SecretString) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "AuthenticationService", "SignOutEverywhere")
+
+	err := svc.wrapped.SignOutEverywhere(ctx, refreshToken)
+
+	logger.Debug("sign out everywhere", SecretString("refresh_token", refreshToken), zap.Error(err))
+
+	if err != nil {
+		logger.Error("sign out everywhere", SecretString("refresh_token", refreshToken), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
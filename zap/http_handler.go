@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/morozovcookie/agat-banking/redact"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
@@ -63,7 +64,7 @@ func recoverRequest(logger *zap.Logger, next http.Handler) http.Handler {
 				}
 			}
 
-			httpRequest, _ := httputil.DumpRequest(r, false)
+			httpRequest := dumpRequest(r)
 			if brokenPipe {
 				logger.Error(r.URL.Path,
 					zap.Any("error", err),
@@ -85,6 +86,37 @@ func recoverRequest(logger *zap.Logger, next http.Handler) http.Handler {
 	})
 }
 
+// sensitiveHeaders is the list of headers which carry credentials, so they are written according to the redaction
+// policy when request is dumped.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// dumpRequest returns request headers without credentials in clear text.
+func dumpRequest(r *http.Request) []byte {
+	clone := r.Clone(r.Context())
+
+	for _, header := range sensitiveHeaders {
+		values := clone.Header.Values(header)
+		if len(values) == 0 {
+			continue
+		}
+
+		clone.Header.Del(header)
+
+		for _, value := range values {
+			clone.Header.Add(header, redact.String(value))
+		}
+	}
+
+	httpRequest, _ := httputil.DumpRequest(clone, false)
+
+	return httpRequest
+}
+
 func logRequest(logger *zap.Logger, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var (
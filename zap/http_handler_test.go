@@ -0,0 +1,128 @@
+package zap_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/morozovcookie/agat-banking/redact"
+	bankingzap "github.com/morozovcookie/agat-banking/zap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type observedLoggerCreator struct {
+	logger *zap.Logger
+}
+
+func (creator *observedLoggerCreator) CreateLogger(_ context.Context, _, _ string) *zap.Logger {
+	return creator.logger
+}
+
+func TestHTTPHandler_ServeHTTP(t *testing.T) {
+	type meta struct {
+		name    string
+		enabled bool
+	}
+	type args struct {
+		policy  redact.Policy
+		headers map[string]string
+	}
+	type wants struct {
+		contains    []string
+		notContains []string
+	}
+
+	tests := []struct {
+		meta  meta
+		args  args
+		wants wants
+	}{
+		{
+			meta: meta{
+				name:    "credentials are redacted after panic",
+				enabled: true,
+			},
+			args: args{
+				policy: redact.PolicyRedacted,
+				headers: map[string]string{
+					"Authorization": "Bearer access-token",
+					"Cookie":        "refresh_token=refresh-token",
+					"User-Agent":    "test-agent",
+				},
+			},
+			wants: wants{
+				contains:    []string{"Authorization: " + redact.Redacted, "Cookie: " + redact.Redacted, "test-agent"},
+				notContains: []string{"access-token", "refresh-token"},
+			},
+		},
+		{
+			meta: meta{
+				name:    "credentials are kept with full policy",
+				enabled: true,
+			},
+			args: args{
+				policy: redact.PolicyFull,
+				headers: map[string]string{
+					"Authorization": "Bearer access-token",
+				},
+			},
+			wants: wants{
+				contains:    []string{"Authorization: Bearer access-token"},
+				notContains: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.meta.name, func(t *testing.T) {
+			if !tt.meta.enabled {
+				t.SkipNow()
+			}
+
+			redact.SetPolicy(tt.args.policy)
+			defer redact.SetPolicy(redact.PolicyRedacted)
+
+			var (
+				core, logs = observer.New(zapcore.DebugLevel)
+				creator    = &observedLoggerCreator{logger: zap.New(core)}
+
+				panicking = http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+					panic("decode request")
+				})
+			)
+
+			var (
+				w = httptest.NewRecorder()
+				r = httptest.NewRequest(http.MethodPost, "/api/v1/auth/signin", nil)
+			)
+
+			for name, value := range tt.args.headers {
+				r.Header.Set(name, value)
+			}
+
+			bankingzap.NewHTTPHandler(panicking, creator).ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+			assert.Equal(t, "Bearer access-token", r.Header.Get("Authorization"))
+
+			entries := logs.FilterMessage("[Recovery from panic]").All()
+			require.Len(t, entries, 1)
+
+			request, ok := entries[0].ContextMap()["request"].(string)
+			require.True(t, ok)
+
+			for _, value := range tt.wants.contains {
+				assert.Contains(t, request, value)
+			}
+
+			for _, value := range tt.wants.notContains {
+				assert.NotContains(t, request, value)
+			}
+		})
+	}
+}
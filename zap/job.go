@@ -0,0 +1,43 @@
+package zap
+
+import (
+	"context"
+
+	"github.com/morozovcookie/agat-banking/worker"
+	"go.uber.org/zap"
+)
+
+var _ worker.Job = (*Job)(nil)
+
+// Job represents a unit of background work.
+type Job struct {
+	loggerCreator LoggerCreator
+	name          string
+	wrapped       worker.Job
+}
+
+// NewJob returns a new Job instance.
+func NewJob(creator LoggerCreator, name string, job worker.Job) *Job {
+	return &Job{
+		loggerCreator: creator,
+		name:          name,
+		wrapped:       job,
+	}
+}
+
+// Run does the work and returns the number of processed items.
+func (job *Job) Run(ctx context.Context) (int64, error) {
+	logger := job.loggerCreator.CreateLogger(ctx, job.name, "Run")
+
+	processed, err := job.wrapped.Run(ctx)
+
+	logger.Debug("run", zap.Int64("processed", processed), zap.Error(err))
+
+	if err != nil {
+		logger.Error("run", zap.Int64("processed", processed), zap.Error(err))
+
+		return processed, err // nolint:wrapcheck
+	}
+
+	return processed, nil
+}
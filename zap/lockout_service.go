@@ -0,0 +1,81 @@
+package zap
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"go.uber.org/zap"
+)
+
+var _ banking.LockoutService = (*LockoutService)(nil)
+
+// LockoutService represents a service for tracking failed sign in attempts.
+type LockoutService struct {
+	loggerCreator LoggerCreator
+	wrapped       banking.LockoutService
+}
+
+// NewLockoutService returns a new LockoutService instance.
+func NewLockoutService(creator LoggerCreator, svc banking.LockoutService) *LockoutService {
+	return &LockoutService{
+		loggerCreator: creator,
+		wrapped:       svc,
+	}
+}
+
+// RegisterAttempt counts the sign in attempt before password comparison.
+func (svc *LockoutService) RegisterAttempt(ctx context.Context, subject banking.LockoutSubject) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "LockoutService", "RegisterAttempt")
+
+	err := svc.wrapped.RegisterAttempt(ctx, subject)
+
+	logger.Debug("register attempt", zap.Stringer("scope", subject.Scope), zap.String("key", subject.Key),
+		zap.Error(err))
+
+	if err != nil {
+		logger.Error("register attempt", zap.Stringer("scope", subject.Scope), zap.String("key", subject.Key),
+			zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// ForgetAttempt uncounts the registered attempt which has not failed.
+func (svc *LockoutService) ForgetAttempt(ctx context.Context, subject banking.LockoutSubject) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "LockoutService", "ForgetAttempt")
+
+	err := svc.wrapped.ForgetAttempt(ctx, subject)
+
+	logger.Debug("forget attempt", zap.Stringer("scope", subject.Scope), zap.String("key", subject.Key),
+		zap.Error(err))
+
+	if err != nil {
+		logger.Error("forget attempt", zap.Stringer("scope", subject.Scope), zap.String("key", subject.Key),
+			zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// ResetFailedAttempts removes lockout and forgets failed sign in attempts.
+func (svc *LockoutService) ResetFailedAttempts(ctx context.Context, subject banking.LockoutSubject) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "LockoutService", "ResetFailedAttempts")
+
+	err := svc.wrapped.ResetFailedAttempts(ctx, subject)
+
+	logger.Debug("reset failed attempts", zap.Stringer("scope", subject.Scope),
+		zap.String("key", subject.Key), zap.Error(err))
+
+	if err != nil {
+		logger.Error("reset failed attempts", zap.Stringer("scope", subject.Scope),
+			zap.String("key", subject.Key), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
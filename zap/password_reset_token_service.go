@@ -0,0 +1,95 @@
+package zap
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"go.uber.org/zap"
+)
+
+var _ banking.PasswordResetTokenService = (*PasswordResetTokenService)(nil)
+
+// PasswordResetTokenService represents a service for managing PasswordResetToken data.
+type PasswordResetTokenService struct {
+	loggerCreator LoggerCreator
+	wrapped       banking.PasswordResetTokenService
+}
+
+// NewPasswordResetTokenService returns a new PasswordResetTokenService instance.
+func NewPasswordResetTokenService(
+	creator LoggerCreator,
+	svc banking.PasswordResetTokenService,
+) *PasswordResetTokenService {
+	return &PasswordResetTokenService{
+		loggerCreator: creator,
+		wrapped:       svc,
+	}
+}
+
+// StorePasswordResetToken stores a new PasswordResetToken.
+func (svc *PasswordResetTokenService) StorePasswordResetToken(
+	ctx context.Context,
+	token *banking.PasswordResetToken,
+) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "PasswordResetTokenService", "StorePasswordResetToken")
+
+	err := svc.wrapped.StorePasswordResetToken(ctx, token)
+
+	logger.Debug("store password reset token", zap.Stringer("id", token.ID),
+		zap.Stringer("account_id", token.Account.ID), zap.Time("expires_at", token.ExpiresAt), zap.Error(err))
+
+	if err != nil {
+		logger.Error("store password reset token", zap.Stringer("id", token.ID),
+			zap.Stringer("account_id", token.Account.ID), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// FindPasswordResetTokenByHash returns PasswordResetToken with specified hash which could be redeemed.
+func (svc *PasswordResetTokenService) FindPasswordResetTokenByHash(
+	ctx context.Context,
+	tokenHash string,
+) (
+	*banking.PasswordResetToken,
+	error,
+) {
+	logger := svc.loggerCreator.CreateLogger(ctx, "PasswordResetTokenService", "FindPasswordResetTokenByHash")
+
+	token, err := svc.wrapped.FindPasswordResetTokenByHash(ctx, tokenHash)
+
+	logger.Debug("find password reset token by hash", PasswordResetToken("token", token), zap.Error(err))
+
+	if err != nil {
+		logger.Error("find password reset token by hash", zap.Error(err))
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	return token, nil
+}
+
+// RedeemPasswordResetToken marks PasswordResetToken with specified hash as used and returns it.
+func (svc *PasswordResetTokenService) RedeemPasswordResetToken(
+	ctx context.Context,
+	tokenHash string,
+) (
+	*banking.PasswordResetToken,
+	error,
+) {
+	logger := svc.loggerCreator.CreateLogger(ctx, "PasswordResetTokenService", "RedeemPasswordResetToken")
+
+	token, err := svc.wrapped.RedeemPasswordResetToken(ctx, tokenHash)
+
+	logger.Debug("redeem password reset token", PasswordResetToken("token", token), zap.Error(err))
+
+	if err != nil {
+		logger.Error("redeem password reset token", zap.Error(err))
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	return token, nil
+}
@@ -0,0 +1,97 @@
+package zap
+
+import (
+	"context"
+	"time"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"go.uber.org/zap"
+)
+
+var _ banking.PasswordService = (*PasswordService)(nil)
+
+// PasswordService represents a service for changing user account passwords.
+type PasswordService struct {
+	loggerCreator LoggerCreator
+	wrapped       banking.PasswordService
+}
+
+// NewPasswordService returns a new PasswordService instance.
+func NewPasswordService(creator LoggerCreator, svc banking.PasswordService) *PasswordService {
+	return &PasswordService{
+		loggerCreator: creator,
+		wrapped:       svc,
+	}
+}
+
+// ChangePassword replaces password of the UserAccount with specified identifier if current password is correct.
+func (svc *PasswordService) ChangePassword(
+	ctx context.Context,
+	accountID banking.ID,
+	currentPassword banking.SecretString,
+	newPassword banking.SecretString,
+) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "PasswordService", "ChangePassword")
+
+	err := svc.wrapped.ChangePassword(ctx, accountID, currentPassword, newPassword)
+
+	logger.Debug("change password", zap.Stringer("account_id", accountID),
+		SecretString("current_password", currentPassword), SecretString("new_password", newPassword),
+		zap.Error(err))
+
+	if err != nil {
+		logger.Error("change password", zap.Stringer("account_id", accountID), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// IssuePasswordResetToken returns a new single-use secret which allows to reset password of the UserAccount with
+// specified identifier.
+func (svc *PasswordService) IssuePasswordResetToken(
+	ctx context.Context,
+	accountID banking.ID,
+) (
+	banking.SecretString,
+	time.Time,
+	error,
+) {
+	logger := svc.loggerCreator.CreateLogger(ctx, "PasswordService", "IssuePasswordResetToken")
+
+	resetToken, expiresAt, err := svc.wrapped.IssuePasswordResetToken(ctx, accountID)
+
+	logger.Debug("issue password reset token", zap.Stringer("account_id", accountID),
+		SecretString("reset_token", resetToken), zap.Time("expires_at", expiresAt), zap.Error(err))
+
+	if err != nil {
+		logger.Error("issue password reset token", zap.Stringer("account_id", accountID), zap.Error(err))
+
+		return nil, time.Time{}, err // nolint:wrapcheck
+	}
+
+	return resetToken, expiresAt, nil
+}
+
+// ResetPassword replaces password of the UserAccount which password reset token was issued for.
+func (svc *PasswordService) ResetPassword(
+	ctx context.Context,
+	resetToken banking.SecretString,
+	newPassword banking.SecretString,
+) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "PasswordService", "ResetPassword")
+
+	err := svc.wrapped.ResetPassword(ctx, resetToken, newPassword)
+
+	logger.Debug("reset password", SecretString("reset_token", resetToken),
+		SecretString("new_password", newPassword), zap.Error(err))
+
+	if err != nil {
+		logger.Error("reset password", SecretString("reset_token", resetToken), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package zap
+
+import (
+	"fmt"
+	"time"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/redact"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	_ zapcore.ObjectMarshaler = (*secretStringMarshaler)(nil)
+	_ zapcore.ObjectMarshaler = (*tokenMarshaler)(nil)
+	_ zapcore.ArrayMarshaler  = (tokensMarshaler)(nil)
+	_ zapcore.ObjectMarshaler = (*userAccountMarshaler)(nil)
+	_ zapcore.ObjectMarshaler = (*passwordResetTokenMarshaler)(nil)
+	_ zapcore.ArrayMarshaler  = (argsMarshaler)(nil)
+)
+
+// SecretString constructs a field which writes SecretString according to the redaction policy.
+func SecretString(key string, secret banking.SecretString) zap.Field {
+	if secret == nil {
+		return zap.Skip()
+	}
+
+	return zap.Object(key, &secretStringMarshaler{secret: secret})
+}
+
+// Token constructs a field which writes only Token identifier, type and expiration. Token value is added only if the
+// redaction policy is not redacted.
+func Token(key string, token banking.Token) zap.Field {
+	if token == nil {
+		return zap.Skip()
+	}
+
+	return zap.Object(key, &tokenMarshaler{token: token})
+}
+
+// Tokens constructs a field which writes the list of tokens as Token does.
+func Tokens(key string, tt []banking.Token) zap.Field {
+	return zap.Array(key, tokensMarshaler(tt))
+}
+
+// UserAccount constructs a field which writes UserAccount without password hash. Email address is written according
+// to the redaction policy.
+func UserAccount(key string, account *banking.UserAccount) zap.Field {
+	if account == nil {
+		return zap.Skip()
+	}
+
+	return zap.Object(key, &userAccountMarshaler{account: account})
+}
+
+// PasswordResetToken constructs a field which writes PasswordResetToken without token hash and user account details.
+func PasswordResetToken(key string, token *banking.PasswordResetToken) zap.Field {
+	if token == nil {
+		return zap.Skip()
+	}
+
+	return zap.Object(key, &passwordResetTokenMarshaler{token: token})
+}
+
+// Args constructs a field which writes query arguments. Numbers, booleans and times are written as is, every other
+// argument could be sensitive (e.g. email address or password hash), so it is written according to the redaction
+// policy.
+func Args(key string, args []interface{}) zap.Field {
+	return zap.Array(key, argsMarshaler(args))
+}
+
+type secretStringMarshaler struct {
+	secret banking.SecretString
+}
+
+func (m *secretStringMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("value", redact.Secret(m.secret))
+
+	return nil
+}
+
+type tokenMarshaler struct {
+	token banking.Token
+}
+
+func (m *tokenMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("id", m.token.ID().String())
+	enc.AddString("type", m.token.Type().String())
+	enc.AddTime("expiration", m.token.Expiration())
+
+	if redact.CurrentPolicy() != redact.PolicyRedacted {
+		enc.AddString("value", redact.Secret(m.token.SecretString()))
+	}
+
+	return nil
+}
+
+type tokensMarshaler []banking.Token
+
+func (m tokensMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, token := range m {
+		if token == nil {
+			continue
+		}
+
+		if err := enc.AppendObject(&tokenMarshaler{token: token}); err != nil {
+			return err // nolint:wrapcheck
+		}
+	}
+
+	return nil
+}
+
+type userAccountMarshaler struct {
+	account *banking.UserAccount
+}
+
+func (m *userAccountMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("id", m.account.ID.String())
+	enc.AddString("username", m.account.UserName)
+	enc.AddString("email_address", redact.String(m.account.EmailAddress))
+
+	if m.account.User != nil {
+		enc.AddString("user_id", m.account.User.ID.String())
+	}
+
+	enc.AddTime("created_at", m.account.CreatedAt)
+
+	if m.account.IsDisabled() {
+		enc.AddTime("disabled_at", m.account.DisabledAt)
+	}
+
+	return nil
+}
+
+type passwordResetTokenMarshaler struct {
+	token *banking.PasswordResetToken
+}
+
+func (m *passwordResetTokenMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("id", m.token.ID.String())
+
+	if m.token.Account != nil {
+		enc.AddString("account_id", m.token.Account.ID.String())
+	}
+
+	enc.AddTime("expires_at", m.token.ExpiresAt)
+
+	if !m.token.RedeemedAt.IsZero() {
+		enc.AddTime("redeemed_at", m.token.RedeemedAt)
+	}
+
+	return nil
+}
+
+type argsMarshaler []interface{}
+
+func (m argsMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, arg := range m {
+		switch value := arg.(type) {
+		case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64,
+			time.Time:
+			if err := enc.AppendReflected(value); err != nil {
+				return err // nolint:wrapcheck
+			}
+		case []byte:
+			enc.AppendString(redact.String(string(value)))
+		default:
+			enc.AppendString(redact.String(fmt.Sprint(value)))
+		}
+	}
+
+	return nil
+}
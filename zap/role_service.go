@@ -0,0 +1,78 @@
+package zap
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"go.uber.org/zap"
+)
+
+var _ banking.RoleService = (*RoleService)(nil)
+
+// RoleService represents a service for managing roles which are assigned to user accounts.
+type RoleService struct {
+	loggerCreator LoggerCreator
+	wrapped       banking.RoleService
+}
+
+// NewRoleService returns a new RoleService instance.
+func NewRoleService(creator LoggerCreator, svc banking.RoleService) *RoleService {
+	return &RoleService{
+		loggerCreator: creator,
+		wrapped:       svc,
+	}
+}
+
+// FindUserAccountRoles returns roles which are assigned to the UserAccount with specified identifier.
+func (svc *RoleService) FindUserAccountRoles(ctx context.Context, accountID banking.ID) (banking.Roles, error) {
+	logger := svc.loggerCreator.CreateLogger(ctx, "RoleService", "FindUserAccountRoles")
+
+	roles, err := svc.wrapped.FindUserAccountRoles(ctx, accountID)
+
+	logger.Debug("find user account roles", zap.Stringer("account_id", accountID),
+		zap.Strings("roles", roles.Strings()), zap.Error(err))
+
+	if err != nil {
+		logger.Error("find user account roles", zap.Stringer("account_id", accountID), zap.Error(err))
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	return roles, nil
+}
+
+// AssignRole grants role to the UserAccount with specified identifier.
+func (svc *RoleService) AssignRole(ctx context.Context, accountID banking.ID, role banking.Role) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "RoleService", "AssignRole")
+
+	err := svc.wrapped.AssignRole(ctx, accountID, role)
+
+	logger.Debug("assign role", zap.Stringer("account_id", accountID), zap.Stringer("role", role), zap.Error(err))
+
+	if err != nil {
+		logger.Error("assign role", zap.Stringer("account_id", accountID), zap.Stringer("role", role),
+			zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// RevokeRole takes role away from the UserAccount with specified identifier.
+func (svc *RoleService) RevokeRole(ctx context.Context, accountID banking.ID, role banking.Role) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "RoleService", "RevokeRole")
+
+	err := svc.wrapped.RevokeRole(ctx, accountID, role)
+
+	logger.Debug("revoke role", zap.Stringer("account_id", accountID), zap.Stringer("role", role), zap.Error(err))
+
+	if err != nil {
+		logger.Error("revoke role", zap.Stringer("account_id", accountID), zap.Stringer("role", role),
+			zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
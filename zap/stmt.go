@@ -31,10 +31,10 @@ func (stmt *stmt) ExecContext(ctx context.Context, args ...interface{}) (sql.Res
 
 	res, err := stmt.wrapped.ExecContext(ctx, args...)
 
-	logger.Debug("exec", zap.String("query", stmt.query), zap.Any("args", args), zap.Error(err))
+	logger.Debug("exec", zap.String("query", stmt.query), Args("args", args), zap.Error(err))
 
 	if err != nil {
-		logger.Error("exec", zap.String("query", stmt.query), zap.Any("args", args), zap.Error(err))
+		logger.Error("exec", zap.String("query", stmt.query), Args("args", args), zap.Error(err))
 
 		return nil, err // nolint:wrapcheck
 	}
@@ -49,10 +49,10 @@ func (stmt *stmt) QueryContext(ctx context.Context, args ...interface{}) (*sql.R
 
 	rr, err := stmt.wrapped.QueryContext(ctx, args...)
 
-	logger.Debug("query", zap.String("query", stmt.query), zap.Any("args", args), zap.Error(err))
+	logger.Debug("query", zap.String("query", stmt.query), Args("args", args), zap.Error(err))
 
 	if err != nil {
-		logger.Error("query", zap.String("query", stmt.query), zap.Any("args", args), zap.Error(err))
+		logger.Error("query", zap.String("query", stmt.query), Args("args", args), zap.Error(err))
 
 		return nil, err // nolint:wrapcheck
 	}
@@ -66,7 +66,7 @@ func (stmt *stmt) QueryRowContext(ctx context.Context, args ...interface{}) *sql
 
 	row := stmt.wrapped.QueryRowContext(ctx, args...)
 
-	logger.Debug("query row", zap.String("query", stmt.query), zap.Any("args", args))
+	logger.Debug("query row", zap.String("query", stmt.query), Args("args", args))
 
 	return row
 }
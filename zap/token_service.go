@@ -2,6 +2,7 @@ package zap
 
 import (
 	"context"
+	"time"
 
 	banking "github.com/morozovcookie/agat-banking"
 	"go.uber.org/zap"
@@ -29,10 +30,10 @@ func (svc *TokenService) StoreToken(ctx context.Context, token banking.Token) er
 
 	err := svc.wrapped.StoreToken(ctx, token)
 
-	logger.Debug("store token", zap.Stringer("token", token), zap.Error(err))
+	logger.Debug("store token", Token("token", token), zap.Error(err))
 
 	if err != nil {
-		logger.Error("store token", zap.Stringer("token", token), zap.Error(err))
+		logger.Error("store token", Token("token", token), zap.Error(err))
 
 		return err // nolint:wrapcheck
 	}
@@ -47,10 +48,10 @@ func (svc *TokenService) ExpireToken(ctx context.Context, id banking.ID) (bankin
 
 	token, err := svc.wrapped.ExpireToken(ctx, id)
 
-	logger.Debug("expire token", zap.Stringer("id", id), zap.Stringer("token", token), zap.Error(err))
+	logger.Debug("expire token", zap.Stringer("id", id), Token("token", token), zap.Error(err))
 
 	if err != nil {
-		logger.Error("expire token", zap.Stringer("id", id), zap.Stringer("token", token),
+		logger.Error("expire token", zap.Stringer("id", id), Token("token", token),
 			zap.Error(err))
 
 		return nil, err // nolint:wrapcheck
@@ -65,11 +66,11 @@ func (svc *TokenService) FindTokenByID(ctx context.Context, id banking.ID) (bank
 
 	token, err := svc.wrapped.FindTokenByID(ctx, id)
 
-	logger.Debug("find token by id", zap.Stringer("id", id), zap.Stringer("token", token),
+	logger.Debug("find token by id", zap.Stringer("id", id), Token("token", token),
 		zap.Error(err))
 
 	if err != nil {
-		logger.Error("find token by id", zap.Stringer("id", id), zap.Stringer("token", token),
+		logger.Error("find token by id", zap.Stringer("id", id), Token("token", token),
 			zap.Error(err))
 
 		return nil, err // nolint:wrapcheck
@@ -78,6 +79,102 @@ func (svc *TokenService) FindTokenByID(ctx context.Context, id banking.ID) (bank
 	return token, nil
 }
 
+// RotateToken expires Token with specified identifier and stores the next Token from the same rotation family.
+func (svc *TokenService) RotateToken(ctx context.Context, id banking.ID, next banking.Token) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "TokenService", "RotateToken")
+
+	err := svc.wrapped.RotateToken(ctx, id, next)
+
+	logger.Debug("rotate token", zap.Stringer("id", id), Token("next", next), zap.Error(err))
+
+	if err != nil {
+		logger.Error("rotate token", zap.Stringer("id", id), Token("next", next), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// ExpireTokenFamily expires every Token from the same rotation family as Token with specified identifier.
+func (svc *TokenService) ExpireTokenFamily(ctx context.Context, id banking.ID) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "TokenService", "ExpireTokenFamily")
+
+	err := svc.wrapped.ExpireTokenFamily(ctx, id)
+
+	logger.Debug("expire token family", zap.Stringer("id", id), zap.Error(err))
+
+	if err != nil {
+		logger.Error("expire token family", zap.Stringer("id", id), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// ExpireUserAccountTokens expires every Token of the user account with specified identifier.
+func (svc *TokenService) ExpireUserAccountTokens(ctx context.Context, accountID banking.ID) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "TokenService", "ExpireUserAccountTokens")
+
+	err := svc.wrapped.ExpireUserAccountTokens(ctx, accountID)
+
+	logger.Debug("expire user account tokens", zap.Stringer("account_id", accountID), zap.Error(err))
+
+	if err != nil {
+		logger.Error("expire user account tokens", zap.Stringer("account_id", accountID), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// ExpireTokensIssuedBefore expires every Token which was issued before specified time (e.g. when signing key was
+// compromised).
+func (svc *TokenService) ExpireTokensIssuedBefore(ctx context.Context, before time.Time) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "TokenService", "ExpireTokensIssuedBefore")
+
+	err := svc.wrapped.ExpireTokensIssuedBefore(ctx, before)
+
+	logger.Debug("expire tokens issued before", zap.Time("before", before), zap.Error(err))
+
+	if err != nil {
+		logger.Error("expire tokens issued before", zap.Time("before", before), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// FindUserAccountTokens returns active tokens of the user account with specified identifier, the most recently
+// issued first.
+func (svc *TokenService) FindUserAccountTokens(
+	ctx context.Context,
+	accountID banking.ID,
+	opts banking.FindOptions,
+) (
+	[]banking.Token,
+	error,
+) {
+	logger := svc.loggerCreator.CreateLogger(ctx, "TokenService", "FindUserAccountTokens")
+
+	tt, err := svc.wrapped.FindUserAccountTokens(ctx, accountID, opts)
+
+	logger.Debug("find user account tokens", zap.Stringer("account_id", accountID), zap.Any("opts", opts),
+		Tokens("tokens", tt), zap.Error(err))
+
+	if err != nil {
+		logger.Error("find user account tokens", zap.Stringer("account_id", accountID), zap.Any("opts", opts),
+			zap.Error(err))
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	return tt, nil
+}
+
 // RemoveExpiredTokens removes expired tokens.
 // Return tokens list after remove.
 func (svc *TokenService) RemoveExpiredTokens(ctx context.Context, opts banking.FindOptions) ([]banking.Token, error) {
@@ -85,10 +182,10 @@ func (svc *TokenService) RemoveExpiredTokens(ctx context.Context, opts banking.F
 
 	tt, err := svc.wrapped.RemoveExpiredTokens(ctx, opts)
 
-	logger.Debug("remove expired tokens", zap.Any("opts", opts), zap.Any("tokens", tt), zap.Error(err))
+	logger.Debug("remove expired tokens", zap.Any("opts", opts), Tokens("tokens", tt), zap.Error(err))
 
 	if err != nil {
-		logger.Error("remove expired tokens", zap.Any("opts", opts), zap.Any("tokens", tt),
+		logger.Error("remove expired tokens", zap.Any("opts", opts), Tokens("tokens", tt),
 			zap.Error(err))
 
 		return nil, err // nolint:wrapcheck
@@ -39,6 +39,23 @@ func (tx *tx) PrepareContext(ctx context.Context, query string) (percona.Stmt, e
 	return newStmt(tx.loggerCreator, res, query), nil
 }
 
+// ExecContext executes a query without arguments by the text protocol.
+func (tx *tx) ExecContext(ctx context.Context, query string) error {
+	logger := tx.loggerCreator.CreateLogger(ctx, "Tx", "ExecContext")
+
+	err := tx.wrapped.ExecContext(ctx, query)
+
+	logger.Debug("exec", zap.String("query", query), zap.Error(err))
+
+	if err != nil {
+		logger.Error("exec", zap.String("query", query), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
 // Commit commits the transaction.
 func (tx *tx) Commit(ctx context.Context) error {
 	logger := tx.loggerCreator.CreateLogger(ctx, "Tx", "Commit")
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	banking "github.com/morozovcookie/agat-banking"
+	"github.com/morozovcookie/agat-banking/redact"
 	"go.uber.org/zap"
 )
 
@@ -36,11 +37,11 @@ func (svc *UserAccountService) FindUserAccountByEmailAddress(
 
 	account, err := svc.wrapped.FindUserAccountByEmailAddress(ctx, emailAddress)
 
-	logger.Debug("find user account by email address", zap.String("email", emailAddress), zap.Error(err),
-		zap.Any("account", account))
+	logger.Debug("find user account by email address", zap.String("email", redact.String(emailAddress)), zap.Error(err),
+		UserAccount("account", account))
 
 	if err != nil {
-		logger.Error("find user account by email", zap.String("email", emailAddress), zap.Error(err))
+		logger.Error("find user account by email", zap.String("email", redact.String(emailAddress)), zap.Error(err))
 
 		return nil, err // nolint:wrapcheck
 	}
@@ -62,7 +63,7 @@ func (svc *UserAccountService) FindUserAccountByUserName(
 	account, err := svc.wrapped.FindUserAccountByUserName(ctx, userName)
 
 	logger.Debug("find user account by username", zap.String("username", userName), zap.Error(err),
-		zap.Any("account", account))
+		UserAccount("account", account))
 
 	if err != nil {
 		logger.Error("find user account by username", zap.String("username", userName), zap.Error(err))
@@ -72,3 +73,124 @@ func (svc *UserAccountService) FindUserAccountByUserName(
 
 	return account, nil
 }
+
+// UpdateUserAccountPasswordHash replaces UserAccount.PasswordHash of the UserAccount with specified identifier.
+func (svc *UserAccountService) UpdateUserAccountPasswordHash(
+	ctx context.Context,
+	id banking.ID,
+	passwordHash string,
+) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "UserAccountService",
+		"UpdateUserAccountPasswordHash")
+
+	err := svc.wrapped.UpdateUserAccountPasswordHash(ctx, id, passwordHash)
+
+	logger.Debug("update user account password hash", zap.Stringer("id", id), zap.Error(err))
+
+	if err != nil {
+		logger.Error("update user account password hash", zap.Stringer("id", id), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// CreateUserAccount stores a new UserAccount with already hashed password. UserAccount.ID and UserAccount.CreatedAt
+// are set on success.
+// Return banking.ErrUserAccountAlreadyExists if username or email address is already used.
+func (svc *UserAccountService) CreateUserAccount(ctx context.Context, account *banking.UserAccount) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "UserAccountService", "CreateUserAccount")
+
+	err := svc.wrapped.CreateUserAccount(ctx, account)
+
+	logger.Debug("create user account", zap.String("username", account.UserName), zap.Stringer("id", account.ID),
+		zap.Error(err))
+
+	if err != nil {
+		logger.Error("create user account", zap.String("username", account.UserName), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// FindUserAccountByID returns UserAccount by UserAccount.ID.
+func (svc *UserAccountService) FindUserAccountByID(ctx context.Context, id banking.ID) (*banking.UserAccount, error) {
+	logger := svc.loggerCreator.CreateLogger(ctx, "UserAccountService", "FindUserAccountByID")
+
+	account, err := svc.wrapped.FindUserAccountByID(ctx, id)
+
+	logger.Debug("find user account by id", zap.Stringer("id", id), zap.Error(err), UserAccount("account", account))
+
+	if err != nil {
+		logger.Error("find user account by id", zap.Stringer("id", id), zap.Error(err))
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	return account, nil
+}
+
+// FindUserAccounts returns the page of user accounts in order of creation.
+func (svc *UserAccountService) FindUserAccounts(
+	ctx context.Context,
+	opts banking.FindOptions,
+) (
+	[]*banking.UserAccount,
+	error,
+) {
+	logger := svc.loggerCreator.CreateLogger(ctx, "UserAccountService", "FindUserAccounts")
+
+	aa, err := svc.wrapped.FindUserAccounts(ctx, opts)
+
+	logger.Debug("find user accounts", zap.Any("opts", opts), zap.Int("count", len(aa)), zap.Error(err))
+
+	if err != nil {
+		logger.Error("find user accounts", zap.Any("opts", opts), zap.Error(err))
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	return aa, nil
+}
+
+// UpdateUserAccount replaces UserAccount.UserName and UserAccount.EmailAddress. UserAccount.UpdateAt is set on
+// success.
+// Return banking.ErrUserAccountAlreadyExists if username or email address is already used.
+func (svc *UserAccountService) UpdateUserAccount(ctx context.Context, account *banking.UserAccount) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "UserAccountService", "UpdateUserAccount")
+
+	err := svc.wrapped.UpdateUserAccount(ctx, account)
+
+	logger.Debug("update user account", zap.Stringer("id", account.ID), zap.String("username", account.UserName),
+		zap.Error(err))
+
+	if err != nil {
+		logger.Error("update user account", zap.Stringer("id", account.ID), zap.String("username", account.UserName),
+			zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// DisableUserAccount forbids sign in to the UserAccount with specified identifier. Disabled user account is kept, so
+// its history is not lost.
+func (svc *UserAccountService) DisableUserAccount(ctx context.Context, id banking.ID) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "UserAccountService", "DisableUserAccount")
+
+	err := svc.wrapped.DisableUserAccount(ctx, id)
+
+	logger.Debug("disable user account", zap.Stringer("id", id), zap.Error(err))
+
+	if err != nil {
+		logger.Error("disable user account", zap.Stringer("id", id), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
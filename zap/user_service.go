@@ -0,0 +1,110 @@
+package zap
+
+import (
+	"context"
+
+	banking "github.com/morozovcookie/agat-banking"
+	"go.uber.org/zap"
+)
+
+var _ banking.UserService = (*UserService)(nil)
+
+// UserService represents a service for managing User data.
+type UserService struct {
+	loggerCreator LoggerCreator
+	wrapped       banking.UserService
+}
+
+// NewUserService returns a new UserService instance.
+func NewUserService(creator LoggerCreator, svc banking.UserService) *UserService {
+	return &UserService{
+		loggerCreator: creator,
+		wrapped:       svc,
+	}
+}
+
+// CreateUser stores a new User. User.ID and User.CreatedAt are set on success.
+func (svc *UserService) CreateUser(ctx context.Context, user *banking.User) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "UserService", "CreateUser")
+
+	err := svc.wrapped.CreateUser(ctx, user)
+
+	logger.Debug("create user", zap.Any("user", user), zap.Error(err))
+
+	if err != nil {
+		logger.Error("create user", zap.Any("user", user), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// FindUserByID returns User by User.ID.
+func (svc *UserService) FindUserByID(ctx context.Context, id banking.ID) (*banking.User, error) {
+	logger := svc.loggerCreator.CreateLogger(ctx, "UserService", "FindUserByID")
+
+	user, err := svc.wrapped.FindUserByID(ctx, id)
+
+	logger.Debug("find user by id", zap.Stringer("id", id), zap.Any("user", user), zap.Error(err))
+
+	if err != nil {
+		logger.Error("find user by id", zap.Stringer("id", id), zap.Error(err))
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	return user, nil
+}
+
+// FindUsers returns the page of users in order of creation.
+func (svc *UserService) FindUsers(ctx context.Context, opts banking.FindOptions) ([]*banking.User, error) {
+	logger := svc.loggerCreator.CreateLogger(ctx, "UserService", "FindUsers")
+
+	uu, err := svc.wrapped.FindUsers(ctx, opts)
+
+	logger.Debug("find users", zap.Any("opts", opts), zap.Any("users", uu), zap.Error(err))
+
+	if err != nil {
+		logger.Error("find users", zap.Any("opts", opts), zap.Error(err))
+
+		return nil, err // nolint:wrapcheck
+	}
+
+	return uu, nil
+}
+
+// UpdateUser replaces User.FirstName and User.LastName. User.UpdatedAt is set on success.
+func (svc *UserService) UpdateUser(ctx context.Context, user *banking.User) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "UserService", "UpdateUser")
+
+	err := svc.wrapped.UpdateUser(ctx, user)
+
+	logger.Debug("update user", zap.Any("user", user), zap.Error(err))
+
+	if err != nil {
+		logger.Error("update user", zap.Any("user", user), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}
+
+// DeleteUser removes User with specified identifier.
+// Return banking.ErrUserHasAccounts if User still owns user accounts.
+func (svc *UserService) DeleteUser(ctx context.Context, id banking.ID) error {
+	logger := svc.loggerCreator.CreateLogger(ctx, "UserService", "DeleteUser")
+
+	err := svc.wrapped.DeleteUser(ctx, id)
+
+	logger.Debug("delete user", zap.Stringer("id", id), zap.Error(err))
+
+	if err != nil {
+		logger.Error("delete user", zap.Stringer("id", id), zap.Error(err))
+
+		return err // nolint:wrapcheck
+	}
+
+	return nil
+}